@@ -0,0 +1,75 @@
+// cni-health runs next to CNI plugin installers on each node. It periodically verifies that plugin
+// binaries are installed as shipped by the image and that configuration files exist, and reports
+// the result as a condition of its own pod.
+package main
+
+import (
+	"context"
+	"log"
+	"os"
+	"time"
+
+	"github.com/spf13/pflag"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+
+	"github.com/kubevirt/cluster-network-addons-operator/pkg/cnihealth"
+)
+
+func main() {
+	binaryFlags := pflag.StringArray("binary", nil, "plugin binary to verify, given as <source>=<installed>")
+	configs := pflag.StringArray("config", nil, "configuration file which has to exist")
+	interval := pflag.Duration("interval", 30*time.Second, "interval between checks")
+	pflag.Parse()
+
+	binaries := []cnihealth.Binary{}
+	for _, binaryFlag := range *binaryFlags {
+		binary, err := cnihealth.ParseBinary(binaryFlag)
+		if err != nil {
+			log.Fatal(err)
+		}
+		binaries = append(binaries, binary)
+	}
+
+	podName, podNamespace := os.Getenv("POD_NAME"), os.Getenv("POD_NAMESPACE")
+	if podName == "" || podNamespace == "" {
+		log.Fatal("POD_NAME and POD_NAMESPACE have to be set")
+	}
+
+	config, err := rest.InClusterConfig()
+	if err != nil {
+		log.Fatalf("failed to get cluster config: %v", err)
+	}
+	client, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		log.Fatalf("failed to create client: %v", err)
+	}
+
+	// The condition is patched only when it changes, failed patches are retried on the next check
+	var reported *corev1.PodCondition
+	for {
+		result := cnihealth.Check(binaries, *configs)
+		if reported == nil || reported.Reason != result.Reason || reported.Message != result.Message {
+			condition := cnihealth.Condition(result, reported, metav1.Now())
+			if err := patchCondition(client, podNamespace, podName, condition); err != nil {
+				log.Printf("failed to report CNI health: %v", err)
+			} else {
+				log.Printf("reported CNI health %s: %s %s", condition.Status, condition.Reason, condition.Message)
+				reported = &condition
+			}
+		}
+		time.Sleep(*interval)
+	}
+}
+
+func patchCondition(client kubernetes.Interface, namespace, name string, condition corev1.PodCondition) error {
+	patch, err := cnihealth.ConditionPatch(condition)
+	if err != nil {
+		return err
+	}
+	_, err = client.CoreV1().Pods(namespace).Patch(context.TODO(), name, types.StrategicMergePatchType, patch, metav1.PatchOptions{}, "status")
+	return err
+}
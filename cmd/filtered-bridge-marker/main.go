@@ -0,0 +1,84 @@
+// filtered-bridge-marker exposes bridges of its node as node resources, limited to the bridges
+// passing the configured filter. It replaces bridge-marker when the exposed bridges are filtered.
+package main
+
+import (
+	"context"
+	"log"
+	"regexp"
+	"time"
+
+	"github.com/spf13/pflag"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+
+	"github.com/kubevirt/cluster-network-addons-operator/pkg/bridgemarker"
+)
+
+const sysClassNet = "/sys/class/net"
+
+func main() {
+	nodeName := pflag.String("node-name", "", "name of the node the bridges belong to")
+	updateInterval := pflag.Duration("update-interval", time.Minute, "interval between updates of node resources")
+	allowed := pflag.StringArray("allowed-bridge", nil, "bridge exposed as a node resource, all bridges are exposed if not set")
+	denied := pflag.StringArray("denied-bridge", nil, "bridge never exposed as a node resource")
+	nameRegex := pflag.String("bridge-name-regex", "", "regular expression names of exposed bridges have to match")
+	pflag.Parse()
+
+	if *nodeName == "" {
+		log.Fatal("--node-name has to be set")
+	}
+	filter := bridgemarker.Filter{Allowed: *allowed, Denied: *denied}
+	if *nameRegex != "" {
+		var err error
+		if filter.NameRegex, err = regexp.Compile(*nameRegex); err != nil {
+			log.Fatalf("failed to parse --bridge-name-regex: %v", err)
+		}
+	}
+
+	config, err := rest.InClusterConfig()
+	if err != nil {
+		log.Fatalf("failed to get cluster config: %v", err)
+	}
+	client, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		log.Fatalf("failed to create client: %v", err)
+	}
+
+	// Failed updates are retried on the next interval
+	for {
+		if err := update(client, *nodeName, filter); err != nil {
+			log.Printf("failed to update bridge resources of node %s: %v", *nodeName, err)
+		}
+		time.Sleep(*updateInterval)
+	}
+}
+
+func update(client kubernetes.Interface, nodeName string, filter bridgemarker.Filter) error {
+	bridges, err := bridgemarker.Bridges(sysClassNet)
+	if err != nil {
+		return err
+	}
+	exposed := []string{}
+	for _, bridge := range bridges {
+		if filter.Exposes(bridge) {
+			exposed = append(exposed, bridge)
+		}
+	}
+
+	node, err := client.CoreV1().Nodes().Get(context.TODO(), nodeName, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	patch, err := bridgemarker.CapacityPatch(node, exposed)
+	if err != nil || patch == nil {
+		return err
+	}
+	if _, err := client.CoreV1().Nodes().Patch(context.TODO(), nodeName, types.JSONPatchType, patch, metav1.PatchOptions{}, "status"); err != nil {
+		return err
+	}
+	log.Printf("exposed bridges %v of node %s", exposed, nodeName)
+	return nil
+}
@@ -0,0 +1,32 @@
+//go:build faultinjection
+// +build faultinjection
+
+package main
+
+import (
+	"log"
+	"os"
+	"time"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	"github.com/kubevirt/cluster-network-addons-operator/pkg/faultinjection"
+)
+
+// injectFaults makes clients of the manager inject faults into calls of the API server, as set by
+// FAULT_INJECTION, e.g. FAULT_INJECTION=conflict=0.1,timeout=0.05
+func injectFaults(options *manager.Options) error {
+	faultsSpec, found := os.LookupEnv("FAULT_INJECTION")
+	if !found {
+		return nil
+	}
+	faults, err := faultinjection.ParseFaults(faultsSpec)
+	if err != nil {
+		return errors.Wrap(err, "failed to parse FAULT_INJECTION")
+	}
+	seed := time.Now().UnixNano()
+	log.Printf("injecting faults %q into API calls, seed %d", faultsSpec, seed)
+	options.NewClient = faultinjection.NewClientFunc(seed, faults...)
+	return nil
+}
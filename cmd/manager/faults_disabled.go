@@ -0,0 +1,20 @@
+//go:build !faultinjection
+// +build !faultinjection
+
+package main
+
+import (
+	"log"
+	"os"
+
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+// injectFaults is available only in builds with the faultinjection tag, released operators never
+// inject faults
+func injectFaults(_ *manager.Options) error {
+	if _, found := os.LookupEnv("FAULT_INJECTION"); found {
+		log.Print("FAULT_INJECTION is ignored, the operator is built without the faultinjection tag")
+	}
+	return nil
+}
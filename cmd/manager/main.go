@@ -6,8 +6,10 @@ import (
 	"os"
 	"runtime"
 
+	osconfv1 "github.com/openshift/api/config/v1"
 	osv1 "github.com/openshift/api/operator/v1"
 	"github.com/spf13/pflag"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	apiruntime "k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
@@ -18,6 +20,7 @@ import (
 
 	cnaov1 "github.com/kubevirt/cluster-network-addons-operator/pkg/apis/networkaddonsoperator/v1"
 	cnaov1alpha1 "github.com/kubevirt/cluster-network-addons-operator/pkg/apis/networkaddonsoperator/v1alpha1"
+	"github.com/kubevirt/cluster-network-addons-operator/pkg/components"
 	"github.com/kubevirt/cluster-network-addons-operator/pkg/controller"
 	"github.com/kubevirt/cluster-network-addons-operator/pkg/monitoring"
 	"github.com/kubevirt/cluster-network-addons-operator/pkg/util/k8s"
@@ -29,6 +32,7 @@ var (
 
 func init() {
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	utilruntime.Must(extv1.AddToScheme(scheme))
 
 	utilruntime.Must(cnaov1.AddToScheme(scheme))
 	utilruntime.Must(cnaov1alpha1.AddToScheme(scheme))
@@ -61,13 +65,24 @@ func main() {
 		os.Exit(1)
 	}
 
-	// Create a new Cmd to provide shared dependencies and start components
-	mgr, err := manager.New(cfg, manager.Options{
+	options := manager.Options{
 		Scheme:             scheme,
 		Namespace:          namespace,
 		MetricsBindAddress: monitoring.GetMetricsAddress(),
 		MapperProvider:     k8s.NewDynamicRESTMapper,
-	})
+		// A single replica reconciles components and issues webhook certificates
+		LeaderElection:          true,
+		LeaderElectionID:        components.Name + "-lock",
+		LeaderElectionNamespace: os.Getenv("OPERATOR_NAMESPACE"),
+	}
+
+	if err := injectFaults(&options); err != nil {
+		log.Printf("failed to set up fault injection: %v", err)
+		os.Exit(1)
+	}
+
+	// Create a new Cmd to provide shared dependencies and start components
+	mgr, err := manager.New(cfg, options)
 	if err != nil {
 		log.Printf("failed to instantiate new operator manager: %v", err)
 		os.Exit(1)
@@ -80,6 +95,11 @@ func main() {
 		os.Exit(1)
 	}
 
+	if err := osconfv1.Install(mgr.GetScheme()); err != nil {
+		log.Printf("failed adding openshift config scheme to the client: %v", err)
+		os.Exit(1)
+	}
+
 	// Setup all Controllers
 	if err := controller.AddToManager(mgr); err != nil {
 		log.Printf("failed setting up operator controllers: %v", err)
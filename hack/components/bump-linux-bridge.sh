@@ -45,8 +45,10 @@ RUN mkdir -p ${LINUX_BRIDGE_TAR_CONTAINER_DIR}
 RUN microdnf install -y findutils
 COPY --from=builder ${LINUX_BRIDGE_PATH}/bin/bridge ${LINUX_BRIDGE_TAR_CONTAINER_DIR}/bridge
 COPY --from=builder ${LINUX_BRIDGE_PATH}/bin/tuning ${LINUX_BRIDGE_TAR_CONTAINER_DIR}/tuning
+COPY --from=builder ${LINUX_BRIDGE_PATH}/bin/host-local ${LINUX_BRIDGE_TAR_CONTAINER_DIR}/host-local
 RUN sha256sum ${LINUX_BRIDGE_TAR_CONTAINER_DIR}/bridge >${LINUX_BRIDGE_TAR_CONTAINER_DIR}/bridge.checksum
 RUN sha256sum ${LINUX_BRIDGE_TAR_CONTAINER_DIR}/tuning >${LINUX_BRIDGE_TAR_CONTAINER_DIR}/tuning.checksum
+RUN sha256sum ${LINUX_BRIDGE_TAR_CONTAINER_DIR}/host-local >${LINUX_BRIDGE_TAR_CONTAINER_DIR}/host-local.checksum
 EOF
     ${OCI_BIN} build -t ${LINUX_BRIDGE_IMAGE_TAGGED} .
 )
@@ -58,12 +60,5 @@ echo 'Push the image to KubeVirt repo'
     fi
 )
 
-if [[ -n "$(docker-utils::check_image_exists "${LINUX_BRIDGE_IMAGE}" "${LINUX_BRIDGE_TAG}")" ]]; then
-    LINUX_BRIDGE_IMAGE_DIGEST="$(docker-utils::get_image_digest "${LINUX_BRIDGE_IMAGE_TAGGED}" "${LINUX_BRIDGE_IMAGE}")"
-else
-    LINUX_BRIDGE_IMAGE_DIGEST=${LINUX_BRIDGE_IMAGE_TAGGED}
-fi
-
 echo 'Update linux-bridge references under CNAO'
-sed -i -r "s#\"${LINUX_BRIDGE_IMAGE}(@sha256)?:.*\"#\"${LINUX_BRIDGE_IMAGE_DIGEST}\"#" pkg/components/components.go
-sed -i -r "s#\"${LINUX_BRIDGE_IMAGE}(@sha256)?:.*\"#\"${LINUX_BRIDGE_IMAGE_DIGEST}\"#" test/releases/${CNAO_VERSION}.go
+docker-utils::update_image_references "${LINUX_BRIDGE_IMAGE}" "${LINUX_BRIDGE_TAG}" -allow-missing
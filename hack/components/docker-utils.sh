@@ -2,30 +2,24 @@
 
 set -xo pipefail
 
-# The get_image_digest function retrieves the image digest from the registry, according to the
-# image name (registry:tag format).
+# The update_image_references function resolves the image tag to its digest in the registry and
+# updates the default image of the component and the containers of the release with it.
+# The image has to be available for all platforms of IMAGE_PLATFORMS (comma separated,
+# linux/amd64 by default). If COSIGN_KEY is set, the image signature is verified by it.
 #
 # Parameters:
-# 1. image name in registry:tag format
-# 2. image name without the tag
-#
-# Returns
-# The image digest
-#
-function docker-utils::get_image_digest() {
-  echo "${2}@$(${OCI_BIN} run --rm quay.io/skopeo/stable:latest inspect "docker://${1}" | jq -r '.Digest')"
-}
-
-# The check_image_exists function checks if an image already exists in the registry.
-#
-# Parameters:
-# 1. image name in registry:tag format
+# 1. image name without the tag
 # 2. image tag
-#
-# returns the image tag if found; else, returns an empty result
-#
-function docker-utils::check_image_exists() {
-  ${OCI_BIN} run --rm quay.io/skopeo/stable:latest list-tags "docker://${1}" | grep "\"${2}\""
+# 3. additional arguments of tools/image-bumper, e.g. -allow-missing
+#
+function docker-utils::update_image_references() {
+  ${GO:-go} run ./tools/image-bumper \
+    -image "${1}" \
+    -tag "${2}" \
+    -platforms "${IMAGE_PLATFORMS:-linux/amd64}" \
+    ${COSIGN_KEY:+-cosign-key "${COSIGN_KEY}"} \
+    -release-file "test/releases/${CNAO_VERSION}.go" \
+    "${@:3}"
 }
 
 # The determine_cri_bin function checks which CRI is used.
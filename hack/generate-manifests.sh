@@ -6,6 +6,8 @@ DEPLOY_DIR="${DEPLOY_DIR:-${PROJECT_ROOT}/manifests}"
 CONTAINER_PREFIX="${CONTAINER_PREFIX:-quay.io/kubevirt}"
 CONTAINER_TAG="${CONTAINER_TAG:-latest}"
 IMAGE_PULL_POLICY="${IMAGE_PULL_POLICY:-Always}"
+# Comma separated namespaces, besides the operator namespace, components may be deployed to
+COMPONENT_NAMESPACES="${COMPONENT_NAMESPACES:-}"
 
 templates=$(cd ${PROJECT_ROOT}/templates && find . -type f -name "*.yaml.in")
 for template in $templates; do
@@ -26,6 +28,8 @@ for template in $templates; do
 		--container-tag=${CONTAINER_TAG} \
 		--image-pull-policy=${IMAGE_PULL_POLICY} \
 		--kube-rbac-proxy-image=${KUBE_RBAC_PROXY_IMAGE} \
+		--data-dir=${PROJECT_ROOT}/data \
+		--component-namespaces=${COMPONENT_NAMESPACES} \
 		--input-file=${infile} \
 	)
 	if [[ ! -z "$rendered" ]]; then
@@ -0,0 +1,10 @@
+#!/bin/bash -xe
+
+destination=$1
+version=$2
+tarball=kubebuilder-tools-$version-linux-amd64.tar.gz
+url=https://storage.googleapis.com/kubebuilder-tools
+
+mkdir -p $destination
+curl -L $url/$tarball -o $destination/$tarball
+tar -xf $destination/$tarball -C $destination
@@ -18,9 +18,8 @@ sed -i "s/\(.*kubectl apply.*\)${prefixed_previous_version}\(.*\)/\1${prefixed_r
 sed -i "s/\(.*startingCSV.*\)${prefixed_previous_version}\(.*\)/\1${prefixed_released_version}\2/g" README.md
 
 echo 'Generating new release for workflow e2e tests'
-cp test/releases/99.0.0.go test/releases/${released_version}.go
+VERSION=${released_version} make gen-release
 git add test/releases/${released_version}.go
-sed -i "s/Version: \"99.0.0\",/Version: \"${released_version}\",/" test/releases/${released_version}.go
 
 echo 'Bump versions in Makefile'
 sed -i "s/VERSION_REPLACES ?= .*/VERSION_REPLACES ?= ${released_version}/" Makefile
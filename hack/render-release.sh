@@ -0,0 +1,20 @@
+#!/usr/bin/env bash
+
+set -eo pipefail
+
+# Renders objects of a previous release with its own code and templates. The release is checked out
+# to a temporary worktree, where tools/release-renderer of the working tree is built.
+#
+# Usage: hack/render-release.sh <git ref of the release> [release-renderer arguments]
+
+release_ref=${1:?release git ref is required, e.g. v0.80.0}
+
+release_dir=$(mktemp -d)
+trap "git worktree remove --force ${release_dir} >/dev/null 2>&1 || rm -rf ${release_dir}" EXIT
+
+git worktree add --quiet --detach "${release_dir}" "${release_ref}"
+mkdir -p "${release_dir}/tools/release-renderer"
+cp tools/release-renderer/release-renderer.go "${release_dir}/tools/release-renderer/"
+
+echo "# Objects rendered by hack/render-release.sh from ${release_ref}"
+(cd "${release_dir}" && GOFLAGS=-mod=vendor ${GO:-go} run ./tools/release-renderer "${@:2}")
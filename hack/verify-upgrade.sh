@@ -0,0 +1,35 @@
+#!/usr/bin/env bash
+
+set -eo pipefail
+
+# Verifies offline that the operator of the working tree upgrades objects of a previous release,
+# reporting leftovers and changes of immutable fields. Objects of the previous release are rendered by
+# its own code and templates.
+#
+# Usage: hack/verify-upgrade.sh <git ref of the previous release> [upgrade-verifier arguments]
+
+previous_ref=${1:?previous release git ref is required, e.g. v0.80.0}
+shift
+
+# The previous release renders objects for the spec it ran and the same cluster
+spec=""
+from_spec=""
+openshift=""
+args=("$@")
+for ((i = 0; i < ${#args[@]}; i++)); do
+    case ${args[i]} in
+        -spec=*) spec=${args[i]#-spec=} ;;
+        -spec) spec=${args[i + 1]} ;;
+        -from-spec=*) from_spec=${args[i]#-from-spec=} ;;
+        -from-spec) from_spec=${args[i + 1]} ;;
+        -openshift) openshift=-openshift ;;
+    esac
+done
+from_spec=${from_spec:-${spec}}
+
+previous_objects=$(mktemp)
+trap "rm -f ${previous_objects}" EXIT
+
+./hack/render-release.sh "${previous_ref}" ${from_spec:+-spec "$(realpath "${from_spec}")"} ${openshift} > "${previous_objects}"
+
+${GO:-go} run ./tools/upgrade-verifier -from-objects "${previous_objects}" -to-data-dir data "$@"
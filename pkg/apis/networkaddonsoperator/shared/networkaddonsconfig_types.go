@@ -2,6 +2,7 @@ package shared
 
 import (
 	ocpv1 "github.com/openshift/api/config/v1"
+	operatorv1 "github.com/openshift/api/operator/v1"
 	conditionsv1 "github.com/openshift/custom-resource-status/conditions/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -9,27 +10,60 @@ import (
 
 // NetworkAddonsConfigSpec defines the desired state of NetworkAddonsConfig
 type NetworkAddonsConfigSpec struct {
-	Multus                 *Multus                   `json:"multus,omitempty"`
-	LinuxBridge            *LinuxBridge              `json:"linuxBridge,omitempty"`
-	Ovs                    *Ovs                      `json:"ovs,omitempty"`
-	KubeMacPool            *KubeMacPool              `json:"kubeMacPool,omitempty"`
+	Multus      *Multus      `json:"multus,omitempty"`
+	LinuxBridge *LinuxBridge `json:"linuxBridge,omitempty"`
+	Ovs         *Ovs         `json:"ovs,omitempty"`
+	KubeMacPool *KubeMacPool `json:"kubeMacPool,omitempty"`
+	// +kubebuilder:validation:Enum=Always;Never;IfNotPresent
 	ImagePullPolicy        corev1.PullPolicy         `json:"imagePullPolicy,omitempty"`
-	NMState                *NMState                  `json:"nmstate,omitempty"`
 	MacvtapCni             *MacvtapCni               `json:"macvtap,omitempty"`
 	SelfSignConfiguration  *SelfSignConfiguration    `json:"selfSignConfiguration,omitempty"`
 	PlacementConfiguration *PlacementConfiguration   `json:"placementConfiguration,omitempty"`
 	TLSSecurityProfile     *ocpv1.TLSSecurityProfile `json:"tlsSecurityProfile,omitempty"`
+	// CNIConfigDir defines the directory on nodes where the container runtime looks for CNI configuration, detected if empty
+	CNIConfigDir string `json:"cniConfigDir,omitempty"`
+	// CNIBinDir defines the directory on nodes where the container runtime looks for CNI plugin binaries, detected if empty
+	CNIBinDir string `json:"cniBinDir,omitempty"`
+	// Proxy defines proxy configuration of components, the cluster-wide proxy is used on OpenShift if empty
+	Proxy *Proxy `json:"proxy,omitempty"`
+	// ManagementState defines whether the operator manages components, it leaves them as they are if Unmanaged and removes them if Removed, Managed if empty
+	// +kubebuilder:validation:Enum=Managed;Unmanaged;Removed
+	ManagementState operatorv1.ManagementState `json:"managementState,omitempty"`
+}
+
+// Proxy defines how components reach services outside of the cluster
+type Proxy struct {
+	// HTTPProxy defines the URL of the proxy for HTTP requests
+	HTTPProxy string `json:"httpProxy,omitempty"`
+	// HTTPSProxy defines the URL of the proxy for HTTPS requests
+	HTTPSProxy string `json:"httpsProxy,omitempty"`
+	// NoProxy defines a comma-separated list of hostnames and CIDRs the proxy is not used for
+	NoProxy string `json:"noProxy,omitempty"`
+	// TrustedCA defines the name of a ConfigMap in the components namespace holding additional trusted CA certificates under the ca-bundle.crt key
+	TrustedCA string `json:"trustedCA,omitempty"`
 }
 
 // SelfSignConfiguration defines self sign configuration
+// +kubebuilder:validation:XValidation:rule="has(self.caRotateInterval) && has(self.caOverlapInterval) && has(self.certRotateInterval) && has(self.certOverlapInterval)",message="caRotateInterval, caOverlapInterval, certRotateInterval and certOverlapInterval have to be set"
+// +kubebuilder:validation:XValidation:rule="!has(self.caRotateInterval) || duration(self.caRotateInterval) > duration('0s')",message="caRotateInterval duration has to be > 0"
+// +kubebuilder:validation:XValidation:rule="!has(self.caOverlapInterval) || duration(self.caOverlapInterval) > duration('0s')",message="caOverlapInterval duration has to be > 0"
+// +kubebuilder:validation:XValidation:rule="!has(self.certRotateInterval) || duration(self.certRotateInterval) > duration('0s')",message="certRotateInterval duration has to be > 0"
+// +kubebuilder:validation:XValidation:rule="!has(self.certOverlapInterval) || duration(self.certOverlapInterval) > duration('0s')",message="certOverlapInterval duration has to be > 0"
+// +kubebuilder:validation:XValidation:rule="!has(self.caRotateInterval) || !has(self.caOverlapInterval) || duration(self.caOverlapInterval) <= duration(self.caRotateInterval)",message="caOverlapInterval has to be <= caRotateInterval"
+// +kubebuilder:validation:XValidation:rule="!has(self.caRotateInterval) || !has(self.certRotateInterval) || duration(self.certRotateInterval) <= duration(self.caRotateInterval)",message="certRotateInterval has to be <= caRotateInterval"
+// +kubebuilder:validation:XValidation:rule="!has(self.certRotateInterval) || !has(self.certOverlapInterval) || duration(self.certOverlapInterval) <= duration(self.certRotateInterval)",message="certOverlapInterval has to be <= certRotateInterval"
 type SelfSignConfiguration struct {
 	// CARotateInterval defines duration for CA expiration
+	// +kubebuilder:validation:Pattern=`^([0-9]+(\.[0-9]+)?(ns|us|µs|ms|s|m|h))+$`
 	CARotateInterval string `json:"caRotateInterval,omitempty"`
 	// CAOverlapInterval defines the duration where expired CA certificate can overlap with new one, in order to allow fluent CA rotation transitioning
+	// +kubebuilder:validation:Pattern=`^([0-9]+(\.[0-9]+)?(ns|us|µs|ms|s|m|h))+$`
 	CAOverlapInterval string `json:"caOverlapInterval,omitempty"`
 	// CertRotateInterval defines duration for of service certificate expiration
+	// +kubebuilder:validation:Pattern=`^([0-9]+(\.[0-9]+)?(ns|us|µs|ms|s|m|h))+$`
 	CertRotateInterval string `json:"certRotateInterval,omitempty"`
 	// CertOverlapInterval defines the duration where expired service certificate can overlap with new one, in order to allow fluent service rotation transitioning
+	// +kubebuilder:validation:Pattern=`^([0-9]+(\.[0-9]+)?(ns|us|µs|ms|s|m|h))+$`
 	CertOverlapInterval string `json:"certOverlapInterval,omitempty"`
 }
 
@@ -47,28 +81,90 @@ type Placement struct {
 	Tolerations  []corev1.Toleration `json:"tolerations,omitempty"`
 }
 
+// ComponentNamespace defines the namespace a component is deployed to, the operand namespace is used if empty
+// +kubebuilder:validation:MaxLength=63
+// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+type ComponentNamespace string
+
 // Multus plugin enables attaching multiple network interfaces to Pods in Kubernetes
-type Multus struct{}
+type Multus struct {
+	Namespace ComponentNamespace `json:"namespace,omitempty"`
+}
 
 // LinuxBridge plugin allows users to create a bridge and add the host and the container to it
-type LinuxBridge struct{}
+type LinuxBridge struct {
+	Namespace ComponentNamespace `json:"namespace,omitempty"`
+	// BridgeMarker defines configuration of the bridge-marker exposing node bridges as node resources
+	BridgeMarker *BridgeMarker `json:"bridgeMarker,omitempty"`
+	// InstallAuxiliaryPlugins defines whether tuning and host-local plugins are installed alongside the bridge plugin
+	InstallAuxiliaryPlugins *bool `json:"installAuxiliaryPlugins,omitempty"`
+}
+
+// BridgeMarker defines which bridges are exposed as node resources and how often
+type BridgeMarker struct {
+	// UpdateInterval defines the duration between updates of node bridge resources
+	// +kubebuilder:validation:Pattern=`^([0-9]+(\.[0-9]+)?(ns|us|µs|ms|s|m|h))+$`
+	UpdateInterval string `json:"updateInterval,omitempty"`
+	// AllowedBridges defines the list of bridges exposed as node resources, all bridges are exposed if empty
+	AllowedBridges []string `json:"allowedBridges,omitempty"`
+	// DeniedBridges defines the list of bridges never exposed as node resources
+	DeniedBridges []string `json:"deniedBridges,omitempty"`
+	// BridgeNameRegex defines a regular expression bridge names have to match to be exposed as node resources
+	BridgeNameRegex string `json:"bridgeNameRegex,omitempty"`
+}
 
 // Ovs plugin allows users to define Kubernetes networks on top of Open vSwitch bridges available on nodes
-type Ovs struct{}
+type Ovs struct {
+	Namespace ComponentNamespace `json:"namespace,omitempty"`
+	// Marker defines configuration of the marker exposing Open vSwitch bridges as node resources
+	Marker *OvsMarker `json:"marker,omitempty"`
+	// OvsNodesOnly defines whether ovs-cni is deployed only on nodes labeled as running Open vSwitch
+	OvsNodesOnly bool `json:"ovsNodesOnly,omitempty"`
+}
 
-// NMState is a declarative node network configuration driven through Kubernetes API
-type NMState struct{}
+// OvsMarker defines how the marker connects to Open vSwitch and how often it reports
+type OvsMarker struct {
+	// OvsSocket defines the path of the Open vSwitch database socket on nodes
+	OvsSocket string `json:"ovsSocket,omitempty"`
+	// UpdateInterval defines the duration between updates of node Open vSwitch bridge resources
+	// +kubebuilder:validation:Pattern=`^([0-9]+(\.[0-9]+)?(ns|us|µs|ms|s|m|h))+$`
+	UpdateInterval string `json:"updateInterval,omitempty"`
+	// HealthcheckInterval defines the duration between marker connectivity checks of the Open vSwitch database
+	// +kubebuilder:validation:Pattern=`^([0-9]+(\.[0-9]+)?(ns|us|µs|ms|s|m|h))+$`
+	HealthcheckInterval string `json:"healthcheckInterval,omitempty"`
+}
 
 // KubeMacPool plugin manages MAC allocation to Pods and VMs in Kubernetes
+// +kubebuilder:validation:XValidation:rule="has(self.rangeStart) == has(self.rangeEnd)",message="both or none of rangeStart and rangeEnd have to be set"
 type KubeMacPool struct {
+	Namespace ComponentNamespace `json:"namespace,omitempty"`
 	// RangeStart defines the first mac in range
+	// +kubebuilder:validation:Pattern=`^([0-9A-Fa-f]{2}[:-]){5}[0-9A-Fa-f]{2}$`
 	RangeStart string `json:"rangeStart,omitempty"`
 	// RangeEnd defines the last mac in range
+	// +kubebuilder:validation:Pattern=`^([0-9A-Fa-f]{2}[:-]){5}[0-9A-Fa-f]{2}$`
 	RangeEnd string `json:"rangeEnd,omitempty"`
 }
 
 // MacvtapCni plugin allows users to define Kubernetes networks on top of existing host interfaces
-type MacvtapCni struct{}
+type MacvtapCni struct {
+	Namespace ComponentNamespace `json:"namespace,omitempty"`
+	// DevicePluginResources defines the macvtap resources exposed by the device plugin, all host interfaces are exposed with defaults if empty
+	DevicePluginResources []MacvtapResource `json:"devicePluginResources,omitempty"`
+}
+
+// MacvtapResource defines a macvtap resource exposed as node resource by the macvtap device plugin
+type MacvtapResource struct {
+	// Name defines the name of the resource, it is exposed as macvtap.network.kubevirt.io/<name>
+	Name string `json:"name"`
+	// LowerDevice defines the host interface macvtap interfaces are created on top of
+	LowerDevice string `json:"lowerDevice"`
+	// Mode defines the macvtap mode, one of bridge, vepa, private or passthru
+	// +kubebuilder:validation:Enum=bridge;vepa;private;passthru
+	Mode string `json:"mode,omitempty"`
+	// Capacity defines the number of macvtap interfaces available on top of the lower device
+	Capacity int `json:"capacity,omitempty"`
+}
 
 // NetworkAddonsConfigStatus defines the observed state of NetworkAddonsConfig
 type NetworkAddonsConfigStatus struct {
@@ -77,6 +173,31 @@ type NetworkAddonsConfigStatus struct {
 	TargetVersion   string                   `json:"targetVersion,omitempty"`
 	Conditions      []conditionsv1.Condition `json:"conditions,omitempty"  patchStrategy:"merge" patchMergeKey:"type"`
 	Containers      []Container              `json:"containers,omitempty"`
+	CNIDirectories  *CNIDirectories          `json:"cniDirectories,omitempty"`
+	// AppliedRevision is the revision of the configuration applied by the operator, it can be rolled back to
+	AppliedRevision int64 `json:"appliedRevision,omitempty"`
+	// Certificates reports expiry of webhook serving certificates and CA bundles deployed by the operator
+	Certificates []CertificateStatus `json:"certificates,omitempty"`
+}
+
+// CertificateStatus reports expiry of a certificate kept by an object deployed by the operator
+type CertificateStatus struct {
+	// Kind is the kind of the object, a Secret keeping a serving certificate or a webhook configuration keeping a CA bundle
+	Kind string `json:"kind"`
+	// Namespace is the namespace of the object, it is empty for cluster-scoped objects
+	Namespace string `json:"namespace,omitempty"`
+	// Name is the name of the object
+	Name string `json:"name"`
+	// NotAfter is the time the certificate expires, a CA bundle expires with its newest CA
+	NotAfter metav1.Time `json:"notAfter"`
+}
+
+// CNIDirectories defines the CNI directories on nodes used by deployed components
+type CNIDirectories struct {
+	// ConfigDir is the directory CNI configuration is installed to
+	ConfigDir string `json:"configDir"`
+	// BinDir is the directory CNI plugin binaries are installed to
+	BinDir string `json:"binDir"`
 }
 
 type Container struct {
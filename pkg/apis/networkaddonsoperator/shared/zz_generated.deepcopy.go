@@ -27,6 +27,62 @@ import (
 	v1 "k8s.io/api/core/v1"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BridgeMarker) DeepCopyInto(out *BridgeMarker) {
+	*out = *in
+	if in.AllowedBridges != nil {
+		in, out := &in.AllowedBridges, &out.AllowedBridges
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.DeniedBridges != nil {
+		in, out := &in.DeniedBridges, &out.DeniedBridges
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BridgeMarker.
+func (in *BridgeMarker) DeepCopy() *BridgeMarker {
+	if in == nil {
+		return nil
+	}
+	out := new(BridgeMarker)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CNIDirectories) DeepCopyInto(out *CNIDirectories) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CNIDirectories.
+func (in *CNIDirectories) DeepCopy() *CNIDirectories {
+	if in == nil {
+		return nil
+	}
+	out := new(CNIDirectories)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CertificateStatus) DeepCopyInto(out *CertificateStatus) {
+	*out = *in
+	in.NotAfter.DeepCopyInto(&out.NotAfter)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertificateStatus.
+func (in *CertificateStatus) DeepCopy() *CertificateStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CertificateStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Container) DeepCopyInto(out *Container) {
 	*out = *in
@@ -60,6 +116,16 @@ func (in *KubeMacPool) DeepCopy() *KubeMacPool {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *LinuxBridge) DeepCopyInto(out *LinuxBridge) {
 	*out = *in
+	if in.BridgeMarker != nil {
+		in, out := &in.BridgeMarker, &out.BridgeMarker
+		*out = new(BridgeMarker)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.InstallAuxiliaryPlugins != nil {
+		in, out := &in.InstallAuxiliaryPlugins, &out.InstallAuxiliaryPlugins
+		*out = new(bool)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LinuxBridge.
@@ -75,6 +141,11 @@ func (in *LinuxBridge) DeepCopy() *LinuxBridge {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MacvtapCni) DeepCopyInto(out *MacvtapCni) {
 	*out = *in
+	if in.DevicePluginResources != nil {
+		in, out := &in.DevicePluginResources, &out.DevicePluginResources
+		*out = make([]MacvtapResource, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MacvtapCni.
@@ -88,31 +159,31 @@ func (in *MacvtapCni) DeepCopy() *MacvtapCni {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Multus) DeepCopyInto(out *Multus) {
+func (in *MacvtapResource) DeepCopyInto(out *MacvtapResource) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Multus.
-func (in *Multus) DeepCopy() *Multus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MacvtapResource.
+func (in *MacvtapResource) DeepCopy() *MacvtapResource {
 	if in == nil {
 		return nil
 	}
-	out := new(Multus)
+	out := new(MacvtapResource)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NMState) DeepCopyInto(out *NMState) {
+func (in *Multus) DeepCopyInto(out *Multus) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NMState.
-func (in *NMState) DeepCopy() *NMState {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Multus.
+func (in *Multus) DeepCopy() *Multus {
 	if in == nil {
 		return nil
 	}
-	out := new(NMState)
+	out := new(Multus)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -147,27 +218,22 @@ func (in *NetworkAddonsConfigSpec) DeepCopyInto(out *NetworkAddonsConfigSpec) {
 	if in.LinuxBridge != nil {
 		in, out := &in.LinuxBridge, &out.LinuxBridge
 		*out = new(LinuxBridge)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 	if in.Ovs != nil {
 		in, out := &in.Ovs, &out.Ovs
 		*out = new(Ovs)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 	if in.KubeMacPool != nil {
 		in, out := &in.KubeMacPool, &out.KubeMacPool
 		*out = new(KubeMacPool)
 		**out = **in
 	}
-	if in.NMState != nil {
-		in, out := &in.NMState, &out.NMState
-		*out = new(NMState)
-		**out = **in
-	}
 	if in.MacvtapCni != nil {
 		in, out := &in.MacvtapCni, &out.MacvtapCni
 		*out = new(MacvtapCni)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 	if in.SelfSignConfiguration != nil {
 		in, out := &in.SelfSignConfiguration, &out.SelfSignConfiguration
@@ -184,6 +250,11 @@ func (in *NetworkAddonsConfigSpec) DeepCopyInto(out *NetworkAddonsConfigSpec) {
 		*out = new(configv1.TLSSecurityProfile)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Proxy != nil {
+		in, out := &in.Proxy, &out.Proxy
+		*out = new(Proxy)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkAddonsConfigSpec.
@@ -211,6 +282,18 @@ func (in *NetworkAddonsConfigStatus) DeepCopyInto(out *NetworkAddonsConfigStatus
 		*out = make([]Container, len(*in))
 		copy(*out, *in)
 	}
+	if in.CNIDirectories != nil {
+		in, out := &in.CNIDirectories, &out.CNIDirectories
+		*out = new(CNIDirectories)
+		**out = **in
+	}
+	if in.Certificates != nil {
+		in, out := &in.Certificates, &out.Certificates
+		*out = make([]CertificateStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkAddonsConfigStatus.
@@ -226,6 +309,11 @@ func (in *NetworkAddonsConfigStatus) DeepCopy() *NetworkAddonsConfigStatus {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Ovs) DeepCopyInto(out *Ovs) {
 	*out = *in
+	if in.Marker != nil {
+		in, out := &in.Marker, &out.Marker
+		*out = new(OvsMarker)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Ovs.
@@ -238,6 +326,21 @@ func (in *Ovs) DeepCopy() *Ovs {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OvsMarker) DeepCopyInto(out *OvsMarker) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OvsMarker.
+func (in *OvsMarker) DeepCopy() *OvsMarker {
+	if in == nil {
+		return nil
+	}
+	out := new(OvsMarker)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Placement) DeepCopyInto(out *Placement) {
 	*out = *in
@@ -293,6 +396,21 @@ func (in *PlacementConfiguration) DeepCopy() *PlacementConfiguration {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Proxy) DeepCopyInto(out *Proxy) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Proxy.
+func (in *Proxy) DeepCopy() *Proxy {
+	if in == nil {
+		return nil
+	}
+	out := new(Proxy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SelfSignConfiguration) DeepCopyInto(out *SelfSignConfiguration) {
 	*out = *in
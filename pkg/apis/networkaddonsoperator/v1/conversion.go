@@ -0,0 +1,4 @@
+package v1
+
+// Hub marks v1 as the version other versions of NetworkAddonsConfig are converted through
+func (*NetworkAddonsConfig) Hub() {}
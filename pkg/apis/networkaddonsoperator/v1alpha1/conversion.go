@@ -0,0 +1,279 @@
+package v1alpha1
+
+import (
+	"encoding/json"
+	"fmt"
+
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	"github.com/kubevirt/cluster-network-addons-operator/pkg/apis/networkaddonsoperator/shared"
+	cnaov1 "github.com/kubevirt/cluster-network-addons-operator/pkg/apis/networkaddonsoperator/v1"
+)
+
+// ConversionDataAnnotation keeps the v1 spec and status of objects converted to v1alpha1 when
+// v1alpha1 cannot represent them, and the v1alpha1 fields v1 does not have of objects converted to
+// v1. They are restored once the object is converted back.
+const ConversionDataAnnotation = "networkaddonsoperator.network.kubevirt.io/conversion-data"
+
+// conversionData is the content of ConversionDataAnnotation
+type conversionData struct {
+	// Spec and Status are kept on v1alpha1 objects
+	Spec   *shared.NetworkAddonsConfigSpec   `json:"spec,omitempty"`
+	Status *shared.NetworkAddonsConfigStatus `json:"status,omitempty"`
+	// NMState is kept on v1 objects
+	NMState *NMState `json:"nmstate,omitempty"`
+}
+
+// ConvertTo converts this NetworkAddonsConfig to the Hub version (v1)
+func (src *NetworkAddonsConfig) ConvertTo(dstRaw conversion.Hub) error {
+	dst, ok := dstRaw.(*cnaov1.NetworkAddonsConfig)
+	if !ok {
+		return fmt.Errorf("unsupported conversion of NetworkAddonsConfig to %T", dstRaw)
+	}
+
+	in := src.DeepCopy()
+	restored := &conversionData{}
+	if err := popConversionData(&in.ObjectMeta, restored); err != nil {
+		return err
+	}
+
+	dst.ObjectMeta = in.ObjectMeta
+	dst.Spec = convertSpecToV1(in.Spec)
+	dst.Status = convertStatusToV1(in.Status)
+	if restored.Spec != nil {
+		restoreSpecV1(&dst.Spec, restored.Spec)
+	}
+	if restored.Status != nil {
+		restoreStatusV1(&dst.Status, restored.Status)
+	}
+
+	// Keep what v1 cannot represent, an object read through v1 and updated back must not lose it.
+	// Converting back to v1alpha1 removes the annotation.
+	if in.Spec.NMState != nil {
+		return pushConversionData(&dst.ObjectMeta, &conversionData{NMState: in.Spec.NMState})
+	}
+	return nil
+}
+
+// ConvertFrom converts from the Hub version (v1) to this version
+func (dst *NetworkAddonsConfig) ConvertFrom(srcRaw conversion.Hub) error {
+	src, ok := srcRaw.(*cnaov1.NetworkAddonsConfig)
+	if !ok {
+		return fmt.Errorf("unsupported conversion of %T to NetworkAddonsConfig", srcRaw)
+	}
+
+	in := src.DeepCopy()
+	restored := &conversionData{}
+	if err := popConversionData(&in.ObjectMeta, restored); err != nil {
+		return err
+	}
+
+	dst.ObjectMeta = in.ObjectMeta
+	dst.Spec = convertSpecFromV1(in.Spec)
+	dst.Status = convertStatusFromV1(in.Status)
+	dst.Spec.NMState = restored.NMState
+
+	// Keep what v1alpha1 cannot represent, an object read through v1alpha1 and updated back must
+	// not lose it. Converting back to v1 removes the annotation.
+	if !apiequality.Semantic.DeepEqual(convertSpecToV1(dst.Spec), in.Spec) ||
+		!apiequality.Semantic.DeepEqual(convertStatusToV1(dst.Status), in.Status) {
+		return pushConversionData(&dst.ObjectMeta, &conversionData{Spec: &in.Spec, Status: &in.Status})
+	}
+	return nil
+}
+
+// pushConversionData stores data in the conversion data annotation of the object
+func pushConversionData(meta *metav1.ObjectMeta, data *conversionData) error {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("failed to marshal conversion data: %v", err)
+	}
+	if meta.Annotations == nil {
+		meta.Annotations = map[string]string{}
+	}
+	meta.Annotations[ConversionDataAnnotation] = string(raw)
+	return nil
+}
+
+// popConversionData removes the conversion data annotation from the object and decodes it into
+// data, data is left untouched if the object has no such annotation
+func popConversionData(meta *metav1.ObjectMeta, data *conversionData) error {
+	raw, found := meta.Annotations[ConversionDataAnnotation]
+	if !found {
+		return nil
+	}
+	delete(meta.Annotations, ConversionDataAnnotation)
+	if len(meta.Annotations) == 0 {
+		meta.Annotations = nil
+	}
+	if err := json.Unmarshal([]byte(raw), data); err != nil {
+		return fmt.Errorf("failed to unmarshal conversion data: %v", err)
+	}
+	return nil
+}
+
+// restoreSpecV1 restores fields v1alpha1 cannot represent of components still present in the spec
+func restoreSpecV1(spec, restored *shared.NetworkAddonsConfigSpec) {
+	spec.CNIConfigDir = restored.CNIConfigDir
+	spec.CNIBinDir = restored.CNIBinDir
+	spec.Proxy = restored.Proxy
+	spec.ManagementState = restored.ManagementState
+	if spec.Multus != nil && restored.Multus != nil {
+		spec.Multus = restored.Multus
+	}
+	if spec.LinuxBridge != nil && restored.LinuxBridge != nil {
+		spec.LinuxBridge = restored.LinuxBridge
+	}
+	if spec.Ovs != nil && restored.Ovs != nil {
+		spec.Ovs = restored.Ovs
+	}
+	if spec.KubeMacPool != nil && restored.KubeMacPool != nil {
+		spec.KubeMacPool.Namespace = restored.KubeMacPool.Namespace
+	}
+	if spec.MacvtapCni != nil && restored.MacvtapCni != nil {
+		spec.MacvtapCni = restored.MacvtapCni
+	}
+}
+
+// restoreStatusV1 restores fields of the status v1alpha1 cannot represent
+func restoreStatusV1(status, restored *shared.NetworkAddonsConfigStatus) {
+	status.CNIDirectories = restored.CNIDirectories
+	status.AppliedRevision = restored.AppliedRevision
+	status.Certificates = restored.Certificates
+}
+
+func convertSpecToV1(in NetworkAddonsConfigSpec) shared.NetworkAddonsConfigSpec {
+	out := shared.NetworkAddonsConfigSpec{
+		ImagePullPolicy:    in.ImagePullPolicy,
+		TLSSecurityProfile: in.TLSSecurityProfile,
+	}
+	if in.Multus != nil {
+		out.Multus = &shared.Multus{}
+	}
+	if in.LinuxBridge != nil {
+		out.LinuxBridge = &shared.LinuxBridge{}
+	}
+	if in.Ovs != nil {
+		out.Ovs = &shared.Ovs{}
+	}
+	if in.KubeMacPool != nil {
+		out.KubeMacPool = &shared.KubeMacPool{
+			RangeStart: in.KubeMacPool.RangeStart,
+			RangeEnd:   in.KubeMacPool.RangeEnd,
+		}
+	}
+	if in.MacvtapCni != nil {
+		out.MacvtapCni = &shared.MacvtapCni{}
+	}
+	if in.SelfSignConfiguration != nil {
+		out.SelfSignConfiguration = &shared.SelfSignConfiguration{
+			CARotateInterval:    in.SelfSignConfiguration.CARotateInterval,
+			CAOverlapInterval:   in.SelfSignConfiguration.CAOverlapInterval,
+			CertRotateInterval:  in.SelfSignConfiguration.CertRotateInterval,
+			CertOverlapInterval: in.SelfSignConfiguration.CertOverlapInterval,
+		}
+	}
+	if in.PlacementConfiguration != nil {
+		out.PlacementConfiguration = &shared.PlacementConfiguration{
+			Infra:     convertPlacementToV1(in.PlacementConfiguration.Infra),
+			Workloads: convertPlacementToV1(in.PlacementConfiguration.Workloads),
+		}
+	}
+	return out
+}
+
+func convertSpecFromV1(in shared.NetworkAddonsConfigSpec) NetworkAddonsConfigSpec {
+	out := NetworkAddonsConfigSpec{
+		ImagePullPolicy:    in.ImagePullPolicy,
+		TLSSecurityProfile: in.TLSSecurityProfile,
+	}
+	if in.Multus != nil {
+		out.Multus = &Multus{}
+	}
+	if in.LinuxBridge != nil {
+		out.LinuxBridge = &LinuxBridge{}
+	}
+	if in.Ovs != nil {
+		out.Ovs = &Ovs{}
+	}
+	if in.KubeMacPool != nil {
+		out.KubeMacPool = &KubeMacPool{
+			RangeStart: in.KubeMacPool.RangeStart,
+			RangeEnd:   in.KubeMacPool.RangeEnd,
+		}
+	}
+	if in.MacvtapCni != nil {
+		out.MacvtapCni = &MacvtapCni{}
+	}
+	if in.SelfSignConfiguration != nil {
+		out.SelfSignConfiguration = &SelfSignConfiguration{
+			CARotateInterval:    in.SelfSignConfiguration.CARotateInterval,
+			CAOverlapInterval:   in.SelfSignConfiguration.CAOverlapInterval,
+			CertRotateInterval:  in.SelfSignConfiguration.CertRotateInterval,
+			CertOverlapInterval: in.SelfSignConfiguration.CertOverlapInterval,
+		}
+	}
+	if in.PlacementConfiguration != nil {
+		out.PlacementConfiguration = &PlacementConfiguration{
+			Infra:     convertPlacementFromV1(in.PlacementConfiguration.Infra),
+			Workloads: convertPlacementFromV1(in.PlacementConfiguration.Workloads),
+		}
+	}
+	return out
+}
+
+func convertPlacementToV1(in *Placement) *shared.Placement {
+	if in == nil {
+		return nil
+	}
+	return &shared.Placement{
+		NodeSelector: in.NodeSelector,
+		Affinity:     in.Affinity,
+		Tolerations:  in.Tolerations,
+	}
+}
+
+func convertPlacementFromV1(in *shared.Placement) *Placement {
+	if in == nil {
+		return nil
+	}
+	return &Placement{
+		NodeSelector: in.NodeSelector,
+		Affinity:     in.Affinity,
+		Tolerations:  in.Tolerations,
+	}
+}
+
+func convertStatusToV1(in NetworkAddonsConfigStatus) shared.NetworkAddonsConfigStatus {
+	out := shared.NetworkAddonsConfigStatus{
+		OperatorVersion: in.OperatorVersion,
+		ObservedVersion: in.ObservedVersion,
+		TargetVersion:   in.TargetVersion,
+		Conditions:      in.Conditions,
+	}
+	if in.Containers != nil {
+		out.Containers = []shared.Container{}
+		for _, container := range in.Containers {
+			out.Containers = append(out.Containers, shared.Container(container))
+		}
+	}
+	return out
+}
+
+func convertStatusFromV1(in shared.NetworkAddonsConfigStatus) NetworkAddonsConfigStatus {
+	out := NetworkAddonsConfigStatus{
+		OperatorVersion: in.OperatorVersion,
+		ObservedVersion: in.ObservedVersion,
+		TargetVersion:   in.TargetVersion,
+		Conditions:      in.Conditions,
+	}
+	if in.Containers != nil {
+		out.Containers = []Container{}
+		for _, container := range in.Containers {
+			out.Containers = append(out.Containers, Container(container))
+		}
+	}
+	return out
+}
@@ -0,0 +1,123 @@
+package v1alpha1_test
+
+import (
+	fuzz "github.com/google/gofuzz"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+
+	"github.com/kubevirt/cluster-network-addons-operator/pkg/apis/networkaddonsoperator/shared"
+	cnaov1 "github.com/kubevirt/cluster-network-addons-operator/pkg/apis/networkaddonsoperator/v1"
+	cnaov1alpha1 "github.com/kubevirt/cluster-network-addons-operator/pkg/apis/networkaddonsoperator/v1alpha1"
+)
+
+const fuzzIterations = 1000
+
+var _ = Describe("NetworkAddonsConfig conversion", func() {
+	var fuzzer *fuzz.Fuzzer
+	BeforeEach(func() {
+		fuzzer = fuzz.New().NilChance(0.2).NumElements(0, 3)
+	})
+
+	It("should round-trip v1alpha1 through v1", func() {
+		for i := 0; i < fuzzIterations; i++ {
+			original := &cnaov1alpha1.NetworkAddonsConfig{}
+			fuzzer.Fuzz(&original.ObjectMeta)
+			fuzzer.Fuzz(&original.Spec)
+			fuzzer.Fuzz(&original.Status)
+
+			hub := &cnaov1.NetworkAddonsConfig{}
+			Expect(original.DeepCopy().ConvertTo(hub)).To(Succeed())
+			converted := &cnaov1alpha1.NetworkAddonsConfig{}
+			Expect(converted.ConvertFrom(hub)).To(Succeed())
+
+			Expect(apiequality.Semantic.DeepEqual(original, converted)).To(BeTrue(), "round-trip of %+v resulted in %+v", original, converted)
+		}
+	})
+
+	It("should round-trip nmstate of v1alpha1 through v1", func() {
+		for i := 0; i < fuzzIterations; i++ {
+			original := &cnaov1alpha1.NetworkAddonsConfig{}
+			fuzzer.Fuzz(&original.ObjectMeta)
+			fuzzer.Fuzz(&original.Spec)
+			original.Spec.NMState = &cnaov1alpha1.NMState{}
+
+			hub := &cnaov1.NetworkAddonsConfig{}
+			Expect(original.DeepCopy().ConvertTo(hub)).To(Succeed())
+			Expect(hub.Annotations).To(HaveKey(cnaov1alpha1.ConversionDataAnnotation))
+			converted := &cnaov1alpha1.NetworkAddonsConfig{}
+			Expect(converted.ConvertFrom(hub)).To(Succeed())
+
+			Expect(apiequality.Semantic.DeepEqual(original, converted)).To(BeTrue(), "round-trip of %+v resulted in %+v", original, converted)
+		}
+	})
+
+	It("should round-trip v1 through v1alpha1", func() {
+		for i := 0; i < fuzzIterations; i++ {
+			original := &cnaov1.NetworkAddonsConfig{}
+			fuzzer.Fuzz(&original.ObjectMeta)
+			fuzzer.Fuzz(&original.Spec)
+			fuzzer.Fuzz(&original.Status)
+
+			spoke := &cnaov1alpha1.NetworkAddonsConfig{}
+			Expect(spoke.ConvertFrom(original.DeepCopy())).To(Succeed())
+			converted := &cnaov1.NetworkAddonsConfig{}
+			Expect(spoke.ConvertTo(converted)).To(Succeed())
+
+			Expect(apiequality.Semantic.DeepEqual(original, converted)).To(BeTrue(), "round-trip of %+v resulted in %+v", original, converted)
+		}
+	})
+
+	It("should not keep conversion data of v1 objects v1alpha1 can represent", func() {
+		for i := 0; i < fuzzIterations; i++ {
+			original := &cnaov1alpha1.NetworkAddonsConfig{}
+			fuzzer.Fuzz(&original.Spec)
+			fuzzer.Fuzz(&original.Status)
+
+			hub := &cnaov1.NetworkAddonsConfig{}
+			Expect(original.DeepCopy().ConvertTo(hub)).To(Succeed())
+			converted := &cnaov1alpha1.NetworkAddonsConfig{}
+			Expect(converted.ConvertFrom(hub)).To(Succeed())
+
+			Expect(converted.Annotations).ToNot(HaveKey(cnaov1alpha1.ConversionDataAnnotation), "v1alpha1 can represent %+v", hub.Spec)
+		}
+	})
+
+	It("should keep v1 fields when a v1alpha1 client updates the object", func() {
+		original := &cnaov1.NetworkAddonsConfig{}
+		original.Spec.CNIBinDir = "/var/lib/cni/bin"
+		original.Spec.KubeMacPool = &shared.KubeMacPool{Namespace: "kubemacpool"}
+
+		spoke := &cnaov1alpha1.NetworkAddonsConfig{}
+		Expect(spoke.ConvertFrom(original.DeepCopy())).To(Succeed())
+		Expect(spoke.Annotations).To(HaveKey(cnaov1alpha1.ConversionDataAnnotation))
+		spoke.Spec.KubeMacPool.RangeStart = "02:00:00:00:00:00"
+		spoke.Spec.KubeMacPool.RangeEnd = "02:00:00:00:00:ff"
+
+		converted := &cnaov1.NetworkAddonsConfig{}
+		Expect(spoke.ConvertTo(converted)).To(Succeed())
+		Expect(converted.Annotations).To(BeEmpty())
+		Expect(converted.Spec.CNIBinDir).To(Equal("/var/lib/cni/bin"))
+		Expect(converted.Spec.KubeMacPool).To(Equal(&shared.KubeMacPool{
+			Namespace:  "kubemacpool",
+			RangeStart: "02:00:00:00:00:00",
+			RangeEnd:   "02:00:00:00:00:ff",
+		}))
+	})
+
+	It("should keep nmstate when a v1 client updates the object", func() {
+		original := &cnaov1alpha1.NetworkAddonsConfig{}
+		original.Spec.NMState = &cnaov1alpha1.NMState{}
+		original.Spec.KubeMacPool = &cnaov1alpha1.KubeMacPool{}
+
+		hub := &cnaov1.NetworkAddonsConfig{}
+		Expect(original.DeepCopy().ConvertTo(hub)).To(Succeed())
+		hub.Spec.KubeMacPool.Namespace = "kubemacpool"
+
+		converted := &cnaov1alpha1.NetworkAddonsConfig{}
+		Expect(converted.ConvertFrom(hub)).To(Succeed())
+		Expect(converted.Spec.NMState).To(Equal(&cnaov1alpha1.NMState{}))
+		Expect(converted.Spec.KubeMacPool).To(Equal(&cnaov1alpha1.KubeMacPool{}))
+	})
+})
@@ -1,9 +1,10 @@
 package v1alpha1
 
 import (
+	ocpv1 "github.com/openshift/api/config/v1"
+	conditionsv1 "github.com/openshift/custom-resource-status/conditions/v1"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-
-	"github.com/kubevirt/cluster-network-addons-operator/pkg/apis/networkaddonsoperator/shared"
 )
 
 // +kubebuilder:object:root=true
@@ -16,8 +17,8 @@ type NetworkAddonsConfig struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
 
-	Spec   shared.NetworkAddonsConfigSpec   `json:"spec,omitempty"`
-	Status shared.NetworkAddonsConfigStatus `json:"status,omitempty"`
+	Spec   NetworkAddonsConfigSpec   `json:"spec,omitempty"`
+	Status NetworkAddonsConfigStatus `json:"status,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -32,3 +33,87 @@ type NetworkAddonsConfigList struct {
 func init() {
 	SchemeBuilder.Register(&NetworkAddonsConfig{}, &NetworkAddonsConfigList{})
 }
+
+// Types below describe the deprecated v1alpha1 API as it was released and are not supposed to
+// change anymore. They are converted to and from the v1 API, which is free to evolve. Fields of v1
+// that v1alpha1 cannot represent are kept in an annotation during conversion.
+
+// NetworkAddonsConfigSpec defines the desired state of NetworkAddonsConfig
+type NetworkAddonsConfigSpec struct {
+	Multus                 *Multus                   `json:"multus,omitempty"`
+	LinuxBridge            *LinuxBridge              `json:"linuxBridge,omitempty"`
+	Ovs                    *Ovs                      `json:"ovs,omitempty"`
+	KubeMacPool            *KubeMacPool              `json:"kubeMacPool,omitempty"`
+	ImagePullPolicy        corev1.PullPolicy         `json:"imagePullPolicy,omitempty"`
+	NMState                *NMState                  `json:"nmstate,omitempty"`
+	MacvtapCni             *MacvtapCni               `json:"macvtap,omitempty"`
+	SelfSignConfiguration  *SelfSignConfiguration    `json:"selfSignConfiguration,omitempty"`
+	PlacementConfiguration *PlacementConfiguration   `json:"placementConfiguration,omitempty"`
+	TLSSecurityProfile     *ocpv1.TLSSecurityProfile `json:"tlsSecurityProfile,omitempty"`
+}
+
+// SelfSignConfiguration defines self sign configuration
+type SelfSignConfiguration struct {
+	// CARotateInterval defines duration for CA expiration
+	CARotateInterval string `json:"caRotateInterval,omitempty"`
+	// CAOverlapInterval defines the duration where expired CA certificate can overlap with new one, in order to allow fluent CA rotation transitioning
+	CAOverlapInterval string `json:"caOverlapInterval,omitempty"`
+	// CertRotateInterval defines duration for of service certificate expiration
+	CertRotateInterval string `json:"certRotateInterval,omitempty"`
+	// CertOverlapInterval defines the duration where expired service certificate can overlap with new one, in order to allow fluent service rotation transitioning
+	CertOverlapInterval string `json:"certOverlapInterval,omitempty"`
+}
+
+// PlacementConfiguration defines node placement configuration
+type PlacementConfiguration struct {
+	// Infra defines placement configuration for control-plane nodes
+	Infra *Placement `json:"infra,omitempty"`
+	// Workloads defines placement configuration for worker nodes
+	Workloads *Placement `json:"workloads,omitempty"`
+}
+
+type Placement struct {
+	NodeSelector map[string]string   `json:"nodeSelector,omitempty"`
+	Affinity     corev1.Affinity     `json:"affinity,omitempty"`
+	Tolerations  []corev1.Toleration `json:"tolerations,omitempty"`
+}
+
+// Multus plugin enables attaching multiple network interfaces to Pods in Kubernetes
+type Multus struct{}
+
+// LinuxBridge plugin allows users to create a bridge and add the host and the container to it
+type LinuxBridge struct{}
+
+// Ovs plugin allows users to define Kubernetes networks on top of Open vSwitch bridges available on nodes
+type Ovs struct{}
+
+// NMState is a declarative node network configuration driven through Kubernetes API, it is not
+// installed by the operator anymore and v1 does not have it
+type NMState struct{}
+
+// KubeMacPool plugin manages MAC allocation to Pods and VMs in Kubernetes
+type KubeMacPool struct {
+	// RangeStart defines the first mac in range
+	RangeStart string `json:"rangeStart,omitempty"`
+	// RangeEnd defines the last mac in range
+	RangeEnd string `json:"rangeEnd,omitempty"`
+}
+
+// MacvtapCni plugin allows users to define Kubernetes networks on top of existing host interfaces
+type MacvtapCni struct{}
+
+// NetworkAddonsConfigStatus defines the observed state of NetworkAddonsConfig
+type NetworkAddonsConfigStatus struct {
+	OperatorVersion string                   `json:"operatorVersion,omitempty"`
+	ObservedVersion string                   `json:"observedVersion,omitempty"`
+	TargetVersion   string                   `json:"targetVersion,omitempty"`
+	Conditions      []conditionsv1.Condition `json:"conditions,omitempty"  patchStrategy:"merge" patchMergeKey:"type"`
+	Containers      []Container              `json:"containers,omitempty"`
+}
+
+type Container struct {
+	ParentKind string `json:"parentKind"`
+	ParentName string `json:"parentName"`
+	Name       string `json:"name"`
+	Image      string `json:"image"`
+}
@@ -4,6 +4,8 @@ import (
 	"github.com/pkg/errors"
 
 	unstructured "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/kubevirt/cluster-network-addons-operator/pkg/names"
 )
 
 // MergeMetadataForUpdate merges the read-only fields of metadata.
@@ -42,6 +44,10 @@ func MergeObjectForUpdate(current, updated *unstructured.Unstructured) error {
 		return err
 	}
 
+	if err := MergeInjectedConfigMapForUpdate(current, updated); err != nil {
+		return err
+	}
+
 	// For all object types, merge metadata.
 	// Run this last, in case any of the more specific merge logic has
 	// changed "updated"
@@ -116,6 +122,27 @@ func MergeServiceAccountForUpdate(current, updated *unstructured.Unstructured) e
 	return nil
 }
 
+// MergeInjectedConfigMapForUpdate copies data from current to updated for ConfigMaps
+// whose content is injected by OpenShift, such as the trusted CA bundle.
+func MergeInjectedConfigMapForUpdate(current, updated *unstructured.Unstructured) error {
+	gvk := updated.GroupVersionKind()
+	if gvk.Group == "" && gvk.Kind == "ConfigMap" {
+		if _, injected := updated.GetLabels()[names.TRUSTED_CA_BUNDLE_INJECT_LABEL_KEY]; !injected {
+			return nil
+		}
+
+		curData, ok, err := unstructured.NestedStringMap(current.Object, "data")
+		if err != nil {
+			return err
+		}
+
+		if ok {
+			return unstructured.SetNestedStringMap(updated.Object, curData, "data")
+		}
+	}
+	return nil
+}
+
 func indexWebhooksByName(configuration *unstructured.Unstructured) (map[string]map[string]interface{}, error) {
 	webhooks, found, err := unstructured.NestedSlice(configuration.Object, "webhooks")
 	if err != nil {
@@ -178,6 +178,64 @@ metadata:
 		})
 	})
 
+	Context("when merging a ConfigMap with injected trusted CA bundle", func() {
+		cur := k8s.UnstructuredFromYaml(`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm1
+  labels:
+    config.openshift.io/inject-trusted-cabundle: "true"
+data:
+  ca-bundle.crt: injected`)
+
+		upd := k8s.UnstructuredFromYaml(`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm1
+  labels:
+    config.openshift.io/inject-trusted-cabundle: "true"`)
+
+		It("should successfully merge", func() {
+			// this mutates updated
+			err := apply.MergeObjectForUpdate(cur, upd)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should keep the injected data", func() {
+			data, ok, err := unstructured.NestedStringMap(upd.Object, "data")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ok).To(BeTrue())
+			Expect(data).To(Equal(map[string]string{"ca-bundle.crt": "injected"}))
+		})
+	})
+
+	Context("when merging a regular ConfigMap", func() {
+		cur := k8s.UnstructuredFromYaml(`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm1
+data:
+  foo: cur`)
+
+		upd := k8s.UnstructuredFromYaml(`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm1
+data:
+  foo: upd`)
+
+		It("should use data of the updating ConfigMap", func() {
+			Expect(apply.MergeObjectForUpdate(cur, upd)).To(Succeed())
+			data, _, err := unstructured.NestedStringMap(upd.Object, "data")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(data).To(Equal(map[string]string{"foo": "upd"}))
+		})
+	})
+
 	Context("when merging an empty Deployment into an empty Deployment", func() {
 		cur := k8s.UnstructuredFromYaml(`
 apiVersion: apps/v1
@@ -0,0 +1,120 @@
+// Package bridgemarker exposes bridges of a node as node resources the way bridge-marker does. It is
+// used in place of bridge-marker when the exposed bridges are filtered, which bridge-marker does not
+// support.
+package bridgemarker
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// ResourcePrefix is the prefix of node resources representing bridges, shared with bridge-marker
+	ResourcePrefix = "bridge.network.kubevirt.io/"
+
+	// resourceCapacity is the capacity bridge-marker advertises for each bridge
+	resourceCapacity = "1k"
+)
+
+// Filter selects the bridges exposed as node resources
+type Filter struct {
+	// Allowed lists the only bridges exposed, all bridges are exposed if empty
+	Allowed []string
+	// Denied lists bridges never exposed
+	Denied []string
+	// NameRegex has to match names of exposed bridges if set
+	NameRegex *regexp.Regexp
+}
+
+// Exposes reports whether the bridge passes the filter
+func (f Filter) Exposes(bridge string) bool {
+	if contains(f.Denied, bridge) {
+		return false
+	}
+	if len(f.Allowed) > 0 && !contains(f.Allowed, bridge) {
+		return false
+	}
+	if f.NameRegex != nil && !f.NameRegex.MatchString(bridge) {
+		return false
+	}
+	return true
+}
+
+// Bridges lists bridges of the node, sysClassNet is the /sys/class/net directory of its network
+// namespace
+func Bridges(sysClassNet string) ([]string, error) {
+	entries, err := os.ReadDir(sysClassNet)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list network interfaces")
+	}
+
+	bridges := []string{}
+	for _, entry := range entries {
+		// only bridges have the bridge attributes directory
+		if _, err := os.Stat(filepath.Join(sysClassNet, entry.Name(), "bridge")); err == nil {
+			bridges = append(bridges, entry.Name())
+		}
+	}
+	return bridges, nil
+}
+
+type patchOperation struct {
+	Op    string `json:"op"`
+	Path  string `json:"path"`
+	Value string `json:"value,omitempty"`
+}
+
+// CapacityPatch composes a JSON patch of the node status which makes the given bridges the only
+// bridges exposed by the node, it returns nil if the node exposes them already
+func CapacityPatch(node *corev1.Node, bridges []string) ([]byte, error) {
+	exposed := map[string]bool{}
+	for _, bridge := range bridges {
+		exposed[bridge] = true
+	}
+
+	operations := []patchOperation{}
+	reported := []string{}
+	for resource := range node.Status.Capacity {
+		if bridge := strings.TrimPrefix(string(resource), ResourcePrefix); bridge != string(resource) {
+			reported = append(reported, bridge)
+		}
+	}
+	sort.Strings(reported)
+	for _, bridge := range reported {
+		if !exposed[bridge] {
+			operations = append(operations, patchOperation{Op: "remove", Path: capacityPath(bridge)})
+		}
+	}
+	for _, bridge := range bridges {
+		if !contains(reported, bridge) {
+			operations = append(operations, patchOperation{Op: "add", Path: capacityPath(bridge), Value: resourceCapacity})
+		}
+	}
+
+	if len(operations) == 0 {
+		return nil, nil
+	}
+	return json.Marshal(operations)
+}
+
+// capacityPath is the JSON pointer of the bridge resource in the node capacity
+func capacityPath(bridge string) string {
+	resource := strings.NewReplacer("~", "~0", "/", "~1").Replace(ResourcePrefix + bridge)
+	return "/status/capacity/" + resource
+}
+
+func contains(list []string, item string) bool {
+	for _, listItem := range list {
+		if listItem == item {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,13 @@
+package bridgemarker_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestBridgeMarker(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Bridge Marker Suite")
+}
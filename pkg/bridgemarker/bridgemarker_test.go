@@ -0,0 +1,83 @@
+package bridgemarker_test
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/kubevirt/cluster-network-addons-operator/pkg/bridgemarker"
+)
+
+var _ = Describe("Bridge marker", func() {
+	type filterCase struct {
+		filter  bridgemarker.Filter
+		exposed []string
+	}
+	DescribeTable("filter",
+		func(c filterCase) {
+			exposed := []string{}
+			for _, bridge := range []string{"br0", "br1", "br-ex", "cni0"} {
+				if c.filter.Exposes(bridge) {
+					exposed = append(exposed, bridge)
+				}
+			}
+			Expect(exposed).To(Equal(c.exposed))
+		},
+		Entry("should expose all bridges when empty", filterCase{
+			exposed: []string{"br0", "br1", "br-ex", "cni0"},
+		}),
+		Entry("should expose only allowed bridges", filterCase{
+			filter:  bridgemarker.Filter{Allowed: []string{"br1", "cni0"}},
+			exposed: []string{"br1", "cni0"},
+		}),
+		Entry("should not expose denied bridges", filterCase{
+			filter:  bridgemarker.Filter{Denied: []string{"br-ex"}},
+			exposed: []string{"br0", "br1", "cni0"},
+		}),
+		Entry("should expose bridges matching the regex and not denied", filterCase{
+			filter:  bridgemarker.Filter{Denied: []string{"br1"}, NameRegex: regexp.MustCompile("^br[0-9]+$")},
+			exposed: []string{"br0"},
+		}),
+	)
+
+	It("should list only bridges of the node", func() {
+		sysClassNet := GinkgoT().TempDir()
+		Expect(os.MkdirAll(filepath.Join(sysClassNet, "br0", "bridge"), 0755)).To(Succeed())
+		Expect(os.MkdirAll(filepath.Join(sysClassNet, "eth0"), 0755)).To(Succeed())
+		Expect(os.MkdirAll(filepath.Join(sysClassNet, "br-ex", "bridge"), 0755)).To(Succeed())
+
+		Expect(bridgemarker.Bridges(sysClassNet)).To(ConsistOf("br0", "br-ex"))
+	})
+
+	Describe("capacity patch", func() {
+		node := func(bridges ...string) *corev1.Node {
+			node := &corev1.Node{}
+			node.Status.Capacity = corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("4")}
+			for _, bridge := range bridges {
+				node.Status.Capacity[corev1.ResourceName(bridgemarker.ResourcePrefix+bridge)] = resource.MustParse("1k")
+			}
+			return node
+		}
+
+		It("should add new bridges and remove bridges not exposed anymore", func() {
+			patch, err := bridgemarker.CapacityPatch(node("br0", "br-ex"), []string{"br0", "br1"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(patch).To(MatchJSON(`[
+				{"op": "remove", "path": "/status/capacity/bridge.network.kubevirt.io~1br-ex"},
+				{"op": "add", "path": "/status/capacity/bridge.network.kubevirt.io~1br1", "value": "1k"}
+			]`))
+		})
+
+		It("should not patch a node which already exposes the bridges", func() {
+			patch, err := bridgemarker.CapacityPatch(node("br0"), []string{"br0"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(patch).To(BeNil())
+		})
+	})
+})
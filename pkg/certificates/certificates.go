@@ -0,0 +1,295 @@
+// Package certificates inspects webhook serving certificates and CA bundles deployed by the operator
+package certificates
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	cnao "github.com/kubevirt/cluster-network-addons-operator/pkg/apis/networkaddonsoperator/shared"
+)
+
+const (
+	secretKind                         = "Secret"
+	mutatingWebhookConfigurationKind   = "MutatingWebhookConfiguration"
+	validatingWebhookConfigurationKind = "ValidatingWebhookConfiguration"
+)
+
+// Source is an object keeping certificates, a Secret keeping a serving certificate or a webhook
+// configuration keeping CA bundles
+type Source struct {
+	Kind      string
+	Namespace string
+	Name      string
+	// Rotation is how the issuer of the certificates rotates them
+	Rotation Rotation
+}
+
+// Rotation describes when the issuer replaces a certificate: Overlap before it expires or, if
+// Overlap is not set, once the Threshold portion of its lifetime passes
+type Rotation struct {
+	Overlap   time.Duration
+	Threshold float64
+}
+
+// rotationTime returns the time the certificate is due to be replaced
+func (r Rotation) rotationTime(cert *x509.Certificate) time.Time {
+	if r.Overlap > 0 {
+		return cert.NotAfter.Add(-r.Overlap)
+	}
+	lifetime := cert.NotAfter.Sub(cert.NotBefore)
+	return cert.NotBefore.Add(time.Duration(float64(lifetime) * r.Threshold))
+}
+
+// SecretSource returns the source of a Secret keeping a serving certificate
+func SecretSource(namespace, name string, rotation Rotation) Source {
+	return Source{Kind: secretKind, Namespace: namespace, Name: name, Rotation: rotation}
+}
+
+// MutatingWebhookConfigurationSource returns the source of a mutating webhook configuration
+// keeping CA bundles
+func MutatingWebhookConfigurationSource(name string, rotation Rotation) Source {
+	return Source{Kind: mutatingWebhookConfigurationKind, Name: name, Rotation: rotation}
+}
+
+// ValidatingWebhookConfigurationSource returns the source of a validating webhook configuration
+// keeping CA bundles
+func ValidatingWebhookConfigurationSource(name string, rotation Rotation) Source {
+	return Source{Kind: validatingWebhookConfigurationKind, Name: name, Rotation: rotation}
+}
+
+// IsSecret checks whether the source is a Secret keeping a serving certificate
+func (s Source) IsSecret() bool {
+	return s.Kind == secretKind
+}
+
+// SourcesForObjects lists known sources kept by objs, webhook configurations among objs and
+// Secrets mounted by Deployments among objs. Known sources are matched by kind and name, Secrets
+// are reported in the namespace of the Deployment mounting them.
+func SourcesForObjects(objs []*unstructured.Unstructured, known []Source) ([]Source, error) {
+	sources := []Source{}
+	found := map[Source]bool{}
+	add := func(kind, namespace, name string) {
+		for _, source := range known {
+			if source.Kind != kind || source.Name != name {
+				continue
+			}
+			source.Namespace = namespace
+			if !found[source] {
+				found[source] = true
+				sources = append(sources, source)
+			}
+		}
+	}
+
+	for _, obj := range objs {
+		gvk := obj.GroupVersionKind()
+		if gvk.Group == admissionregistrationv1.GroupName && (gvk.Kind == mutatingWebhookConfigurationKind || gvk.Kind == validatingWebhookConfigurationKind) {
+			add(gvk.Kind, "", obj.GetName())
+		} else if gvk.Group == appsv1.GroupName && gvk.Kind == "Deployment" {
+			deployment := &appsv1.Deployment{}
+			if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, deployment); err != nil {
+				return nil, errors.Wrapf(err, "failed to convert Deployment %s/%s", obj.GetNamespace(), obj.GetName())
+			}
+			for _, volume := range deployment.Spec.Template.Spec.Volumes {
+				if volume.Secret != nil {
+					add(secretKind, deployment.Namespace, volume.Secret.SecretName)
+				}
+			}
+		}
+	}
+	return sources, nil
+}
+
+// PolicyRules returns rules needed to inspect and rotate certificates of Secret sources, access
+// to other sources is granted with the objects deployed by the operator
+func PolicyRules(sources []Source) []rbacv1.PolicyRule {
+	secretNames := []string{}
+	found := map[string]bool{}
+	for _, source := range sources {
+		if source.IsSecret() && !found[source.Name] {
+			found[source.Name] = true
+			secretNames = append(secretNames, source.Name)
+		}
+	}
+	if len(secretNames) == 0 {
+		return nil
+	}
+	sort.Strings(secretNames)
+	return []rbacv1.PolicyRule{
+		{
+			APIGroups:     []string{""},
+			Resources:     []string{"secrets"},
+			ResourceNames: secretNames,
+			Verbs:         []string{"get", "delete"},
+		},
+	}
+}
+
+// Certificate is the certificate kept by a source which expires first
+type Certificate struct {
+	Source   Source
+	NotAfter time.Time
+	// RotationTime is when the issuer is due to replace the certificate
+	RotationTime time.Time
+}
+
+// Statuses reports expiry of the certificates
+func Statuses(certificates []Certificate) []cnao.CertificateStatus {
+	statuses := []cnao.CertificateStatus{}
+	for _, certificate := range certificates {
+		statuses = append(statuses, cnao.CertificateStatus{
+			Kind:      certificate.Source.Kind,
+			Namespace: certificate.Source.Namespace,
+			Name:      certificate.Source.Name,
+			NotAfter:  metav1.NewTime(certificate.NotAfter),
+		})
+	}
+	return statuses
+}
+
+// Inspect reads certificates kept by sources. Sources which do not exist or do not keep any
+// certificate yet are skipped.
+func Inspect(ctx context.Context, reader k8sclient.Reader, sources []Source) ([]Certificate, error) {
+	certificates := []Certificate{}
+	for _, source := range sources {
+		cert, err := inspectSource(ctx, reader, source)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to inspect certificates of %s %s", source.Kind, objectName(source))
+		}
+		if cert != nil {
+			certificates = append(certificates, Certificate{
+				Source:       source,
+				NotAfter:     cert.NotAfter,
+				RotationTime: source.Rotation.rotationTime(cert),
+			})
+		}
+	}
+	return certificates, nil
+}
+
+// Rotate removes the Secret source, so the serving certificate is reissued by the component
+// managing it
+func Rotate(ctx context.Context, c k8sclient.Client, source Source) error {
+	if !source.IsSecret() {
+		return errors.Errorf("certificates of %s %s cannot be rotated", source.Kind, objectName(source))
+	}
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: source.Namespace, Name: source.Name}}
+	if err := c.Delete(ctx, secret); err != nil && !apierrors.IsNotFound(err) {
+		return errors.Wrapf(err, "failed to remove Secret %s", objectName(source))
+	}
+	return nil
+}
+
+// inspectSource returns the certificate of the source which expires first, nil is returned if
+// the source does not keep any
+func inspectSource(ctx context.Context, reader k8sclient.Reader, source Source) (*x509.Certificate, error) {
+	key := types.NamespacedName{Namespace: source.Namespace, Name: source.Name}
+	caBundles := [][]byte{}
+
+	switch source.Kind {
+	case secretKind:
+		secret := &corev1.Secret{}
+		if err := reader.Get(ctx, key, secret); err != nil {
+			return nil, ignoreNotFound(err)
+		}
+		certs, err := parseCertificates(secret.Data[corev1.TLSCertKey])
+		if err != nil || len(certs) == 0 {
+			return nil, err
+		}
+		return certs[0], nil
+	case mutatingWebhookConfigurationKind:
+		configuration := &admissionregistrationv1.MutatingWebhookConfiguration{}
+		if err := reader.Get(ctx, key, configuration); err != nil {
+			return nil, ignoreNotFound(err)
+		}
+		for _, webhook := range configuration.Webhooks {
+			caBundles = append(caBundles, webhook.ClientConfig.CABundle)
+		}
+	case validatingWebhookConfigurationKind:
+		configuration := &admissionregistrationv1.ValidatingWebhookConfiguration{}
+		if err := reader.Get(ctx, key, configuration); err != nil {
+			return nil, ignoreNotFound(err)
+		}
+		for _, webhook := range configuration.Webhooks {
+			caBundles = append(caBundles, webhook.ClientConfig.CABundle)
+		}
+	default:
+		return nil, errors.Errorf("unsupported kind %s", source.Kind)
+	}
+
+	return caBundlesExpiry(caBundles)
+}
+
+// caBundlesExpiry returns the CA of bundles which expires first, each bundle expires with its
+// newest CA
+func caBundlesExpiry(caBundles [][]byte) (*x509.Certificate, error) {
+	var expiring *x509.Certificate
+	for _, caBundle := range caBundles {
+		certs, err := parseCertificates(caBundle)
+		if err != nil {
+			return nil, err
+		}
+		if len(certs) == 0 {
+			continue
+		}
+
+		newest := certs[0]
+		for _, cert := range certs[1:] {
+			if cert.NotAfter.After(newest.NotAfter) {
+				newest = cert
+			}
+		}
+		if expiring == nil || newest.NotAfter.Before(expiring.NotAfter) {
+			expiring = newest
+		}
+	}
+	return expiring, nil
+}
+
+func parseCertificates(data []byte) ([]*x509.Certificate, error) {
+	certs := []*x509.Certificate{}
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			return certs, nil
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		certs = append(certs, cert)
+	}
+}
+
+func ignoreNotFound(err error) error {
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+func objectName(source Source) string {
+	if source.Namespace == "" {
+		return source.Name
+	}
+	return source.Namespace + "/" + source.Name
+}
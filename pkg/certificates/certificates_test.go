@@ -0,0 +1,158 @@
+package certificates_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kubevirt/cluster-network-addons-operator/pkg/certificates"
+	"github.com/kubevirt/cluster-network-addons-operator/pkg/util/k8s"
+)
+
+var _ = Describe("Testing webhook certificates", func() {
+	const namespace = "cluster-network-addons"
+
+	newCertificate := func(notAfter time.Time) []byte {
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		Expect(err).ToNot(HaveOccurred())
+		template := &x509.Certificate{
+			SerialNumber: big.NewInt(1),
+			Subject:      pkix.Name{CommonName: "test"},
+			NotBefore:    notAfter.Add(-10 * time.Hour),
+			NotAfter:     notAfter,
+		}
+		der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+		Expect(err).ToNot(HaveOccurred())
+		return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	}
+
+	toUnstructured := func(obj interface{}) *unstructured.Unstructured {
+		u, err := k8s.ToUnstructured(obj)
+		Expect(err).ToNot(HaveOccurred())
+		return u
+	}
+
+	secretRotation := certificates.Rotation{Overlap: time.Hour}
+	caRotation := certificates.Rotation{Overlap: 2 * time.Hour}
+
+	Context("when listing sources of deployed objects", func() {
+		known := []certificates.Source{
+			certificates.SecretSource("", "kubemacpool-service", secretRotation),
+			certificates.MutatingWebhookConfigurationSource("kubemacpool-mutator", caRotation),
+		}
+
+		It("should find known webhook configurations and Secrets mounted by Deployments", func() {
+			objs := []*unstructured.Unstructured{
+				toUnstructured(&admissionregistrationv1.MutatingWebhookConfiguration{
+					TypeMeta:   metav1.TypeMeta{APIVersion: "admissionregistration.k8s.io/v1", Kind: "MutatingWebhookConfiguration"},
+					ObjectMeta: metav1.ObjectMeta{Name: "kubemacpool-mutator"},
+				}),
+				toUnstructured(&appsv1.Deployment{
+					TypeMeta:   metav1.TypeMeta{APIVersion: "apps/v1", Kind: "Deployment"},
+					ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "kubemacpool-mac-controller-manager"},
+					Spec: appsv1.DeploymentSpec{
+						Template: corev1.PodTemplateSpec{
+							Spec: corev1.PodSpec{
+								Volumes: []corev1.Volume{
+									{Name: "tls-key-pair", VolumeSource: corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{SecretName: "kubemacpool-service"}}},
+									{Name: "credentials", VolumeSource: corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{SecretName: "credentials"}}},
+									{Name: "config", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}},
+								},
+							},
+						},
+					},
+				}),
+				toUnstructured(&admissionregistrationv1.ValidatingWebhookConfiguration{
+					TypeMeta:   metav1.TypeMeta{APIVersion: "admissionregistration.k8s.io/v1", Kind: "ValidatingWebhookConfiguration"},
+					ObjectMeta: metav1.ObjectMeta{Name: "unknown-validator"},
+				}),
+				toUnstructured(&corev1.ConfigMap{
+					TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "ConfigMap"},
+					ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "config"},
+				}),
+			}
+
+			sources, err := certificates.SourcesForObjects(objs, known)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(sources).To(ConsistOf(
+				certificates.MutatingWebhookConfigurationSource("kubemacpool-mutator", caRotation),
+				certificates.SecretSource(namespace, "kubemacpool-service", secretRotation),
+			), "Secrets and webhook configurations which are not known to keep webhook certificates should be left out")
+
+			Expect(certificates.PolicyRules(sources)).To(ConsistOf(HaveField("ResourceNames", ConsistOf("kubemacpool-service"))))
+		})
+	})
+
+	Context("when inspecting sources", func() {
+		It("should report the serving certificate and the newest CA of each bundle with their rotation", func() {
+			servingExpiry := time.Now().Add(24 * time.Hour).Truncate(time.Second)
+			oldCAExpiry := time.Now().Add(48 * time.Hour).Truncate(time.Second)
+			newCAExpiry := time.Now().Add(96 * time.Hour).Truncate(time.Second)
+
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "kubemacpool-service"},
+				Data:       map[string][]byte{corev1.TLSCertKey: newCertificate(servingExpiry)},
+			}
+			configuration := &admissionregistrationv1.ValidatingWebhookConfiguration{
+				ObjectMeta: metav1.ObjectMeta{Name: "validator"},
+				Webhooks: []admissionregistrationv1.ValidatingWebhook{
+					{
+						Name:         "validator.kubevirt.io",
+						ClientConfig: admissionregistrationv1.WebhookClientConfig{CABundle: append(newCertificate(oldCAExpiry), newCertificate(newCAExpiry)...)},
+					},
+				},
+			}
+			client := fake.NewClientBuilder().WithObjects(secret, configuration).Build()
+
+			inspected, err := certificates.Inspect(context.Background(), client, []certificates.Source{
+				certificates.SecretSource(namespace, "kubemacpool-service", secretRotation),
+				certificates.ValidatingWebhookConfigurationSource("validator", certificates.Rotation{Threshold: 0.8}),
+				certificates.SecretSource(namespace, "missing", secretRotation),
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(inspected).To(HaveLen(2))
+			Expect(inspected[0].Source.Kind).To(Equal("Secret"))
+			Expect(inspected[0].NotAfter).To(BeTemporally("==", servingExpiry))
+			Expect(inspected[0].RotationTime).To(BeTemporally("==", servingExpiry.Add(-time.Hour)), "the certificate should be rotated the overlap before it expires")
+			Expect(inspected[1].Source.Kind).To(Equal("ValidatingWebhookConfiguration"))
+			Expect(inspected[1].NotAfter).To(BeTemporally("==", newCAExpiry))
+			Expect(inspected[1].RotationTime).To(BeTemporally("==", newCAExpiry.Add(-2*time.Hour)), "the CA should be rotated once 80% of its 10 hours lifetime passes")
+
+			statuses := certificates.Statuses(inspected)
+			Expect(statuses).To(HaveLen(2))
+			Expect(statuses[0].Name).To(Equal("kubemacpool-service"))
+			Expect(statuses[0].NotAfter.Time).To(BeTemporally("==", servingExpiry))
+		})
+	})
+
+	Context("when rotating a Secret source", func() {
+		It("should remove the Secret so it is reissued", func() {
+			secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "kubemacpool-service"}}
+			client := fake.NewClientBuilder().WithObjects(secret).Build()
+
+			Expect(certificates.Rotate(context.Background(), client, certificates.SecretSource(namespace, "kubemacpool-service", secretRotation))).To(Succeed())
+			err := client.Get(context.Background(), types.NamespacedName{Namespace: namespace, Name: "kubemacpool-service"}, &corev1.Secret{})
+			Expect(apierrors.IsNotFound(err)).To(BeTrue())
+
+			Expect(certificates.Rotate(context.Background(), client, certificates.ValidatingWebhookConfigurationSource("validator", caRotation))).ToNot(Succeed())
+		})
+	})
+})
@@ -0,0 +1,145 @@
+package cnihealth
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kubevirt/cluster-network-addons-operator/pkg/names"
+)
+
+const (
+	// ReasonHealthy is reported when all binaries and configuration are in place
+	ReasonHealthy = "PluginsInstalled"
+	// ReasonSourceMissing is reported when the image does not ship a binary it should install
+	ReasonSourceMissing = "SourceBinaryMissing"
+	// ReasonBinaryMissing is reported when a binary is not installed on the node
+	ReasonBinaryMissing = "BinaryMissing"
+	// ReasonBinaryModified is reported when an installed binary differs from the one shipped by the image
+	ReasonBinaryModified = "BinaryModified"
+	// ReasonConfigurationMissing is reported when a configuration file is missing on the node
+	ReasonConfigurationMissing = "ConfigurationMissing"
+)
+
+// Binary is a CNI plugin binary shipped by the image and the path it is installed to on the node
+type Binary struct {
+	Source    string
+	Installed string
+}
+
+// ParseBinary parses a binary given as <source>=<installed>
+func ParseBinary(value string) (Binary, error) {
+	parts := strings.SplitN(value, "=", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return Binary{}, fmt.Errorf("binary %q has to be given as <source>=<installed>", value)
+	}
+	return Binary{Source: parts[0], Installed: parts[1]}, nil
+}
+
+// Result is the outcome of a check, Reason is the one of the first problem found and Message
+// lists all of them
+type Result struct {
+	Healthy bool
+	Reason  string
+	Message string
+}
+
+// Check verifies that binaries are installed as shipped by the image and configuration files exist
+func Check(binaries []Binary, configs []string) Result {
+	reason := ""
+	problems := []string{}
+	report := func(problemReason, problem string) {
+		if reason == "" {
+			reason = problemReason
+		}
+		problems = append(problems, problem)
+	}
+
+	for _, binary := range binaries {
+		source, err := checksum(binary.Source)
+		if err != nil {
+			report(ReasonSourceMissing, fmt.Sprintf("%s is not shipped by the image: %v", binary.Source, err))
+			continue
+		}
+		installed, err := checksum(binary.Installed)
+		if err != nil {
+			report(ReasonBinaryMissing, fmt.Sprintf("%s is not installed: %v", binary.Installed, err))
+			continue
+		}
+		if !bytes.Equal(source, installed) {
+			report(ReasonBinaryModified, fmt.Sprintf("%s differs from %s shipped by the image", binary.Installed, binary.Source))
+		}
+	}
+
+	for _, config := range configs {
+		if _, err := os.Stat(config); err != nil {
+			report(ReasonConfigurationMissing, fmt.Sprintf("%s is missing: %v", config, err))
+		}
+	}
+
+	if len(problems) > 0 {
+		return Result{Reason: reason, Message: strings.Join(problems, "; ")}
+	}
+	return Result{Healthy: true, Reason: ReasonHealthy}
+}
+
+func checksum(path string) ([]byte, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return nil, err
+	}
+	return hash.Sum(nil), nil
+}
+
+// Condition returns the pod condition reporting the result. The transition time of the current
+// condition is kept unless its status changes.
+func Condition(result Result, current *corev1.PodCondition, now metav1.Time) corev1.PodCondition {
+	condition := corev1.PodCondition{
+		Type:               names.CNI_HEALTH_CONDITION,
+		Status:             corev1.ConditionFalse,
+		Reason:             result.Reason,
+		Message:            result.Message,
+		LastProbeTime:      now,
+		LastTransitionTime: now,
+	}
+	if result.Healthy {
+		condition.Status = corev1.ConditionTrue
+	}
+	if current != nil && current.Status == condition.Status {
+		condition.LastTransitionTime = current.LastTransitionTime
+	}
+	return condition
+}
+
+// FindCondition returns the CNI health condition of the pod, nil is returned if it is not reported yet
+func FindCondition(pod *corev1.Pod) *corev1.PodCondition {
+	for i := range pod.Status.Conditions {
+		if pod.Status.Conditions[i].Type == names.CNI_HEALTH_CONDITION {
+			return &pod.Status.Conditions[i]
+		}
+	}
+	return nil
+}
+
+// ConditionPatch returns a strategic merge patch of pod status setting the condition, other
+// conditions of the pod are kept
+func ConditionPatch(condition corev1.PodCondition) ([]byte, error) {
+	return json.Marshal(map[string]interface{}{
+		"status": map[string]interface{}{
+			"conditions": []corev1.PodCondition{condition},
+		},
+	})
+}
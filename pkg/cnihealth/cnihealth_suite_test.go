@@ -0,0 +1,13 @@
+package cnihealth_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestCNIHealth(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "CNI Health Suite")
+}
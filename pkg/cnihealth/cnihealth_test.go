@@ -0,0 +1,99 @@
+package cnihealth_test
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kubevirt/cluster-network-addons-operator/pkg/cnihealth"
+	"github.com/kubevirt/cluster-network-addons-operator/pkg/names"
+)
+
+var _ = Describe("CNI health", func() {
+	var dir string
+	BeforeEach(func() {
+		dir = GinkgoT().TempDir()
+	})
+
+	writeFile := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		Expect(os.WriteFile(path, []byte(content), 0644)).To(Succeed())
+		return path
+	}
+
+	It("should report installed binaries and existing configuration as healthy", func() {
+		binary := cnihealth.Binary{Source: writeFile("source", "bridge"), Installed: writeFile("installed", "bridge")}
+		result := cnihealth.Check([]cnihealth.Binary{binary}, []string{writeFile("00-multus.conf", "{}")})
+		Expect(result).To(Equal(cnihealth.Result{Healthy: true, Reason: cnihealth.ReasonHealthy}))
+	})
+
+	It("should report a binary missing in the image", func() {
+		binary := cnihealth.Binary{Source: filepath.Join(dir, "source"), Installed: writeFile("installed", "bridge")}
+		result := cnihealth.Check([]cnihealth.Binary{binary}, nil)
+		Expect(result.Healthy).To(BeFalse())
+		Expect(result.Reason).To(Equal(cnihealth.ReasonSourceMissing))
+	})
+
+	It("should report a binary missing on the node", func() {
+		binary := cnihealth.Binary{Source: writeFile("source", "bridge"), Installed: filepath.Join(dir, "installed")}
+		result := cnihealth.Check([]cnihealth.Binary{binary}, nil)
+		Expect(result.Healthy).To(BeFalse())
+		Expect(result.Reason).To(Equal(cnihealth.ReasonBinaryMissing))
+	})
+
+	It("should report a modified binary and missing configuration", func() {
+		binary := cnihealth.Binary{Source: writeFile("source", "bridge"), Installed: writeFile("installed", "modified")}
+		config := filepath.Join(dir, "00-multus.conf")
+		result := cnihealth.Check([]cnihealth.Binary{binary}, []string{config})
+		Expect(result.Healthy).To(BeFalse())
+		Expect(result.Reason).To(Equal(cnihealth.ReasonBinaryModified))
+		Expect(result.Message).To(ContainSubstring(binary.Installed + " differs from " + binary.Source))
+		Expect(result.Message).To(ContainSubstring(config + " is missing"))
+	})
+
+	It("should parse binaries given as source=installed", func() {
+		Expect(cnihealth.ParseBinary("/bridge=/opt/cni/bin/cnv-bridge")).To(Equal(cnihealth.Binary{Source: "/bridge", Installed: "/opt/cni/bin/cnv-bridge"}))
+		_, err := cnihealth.ParseBinary("/bridge")
+		Expect(err).To(HaveOccurred())
+	})
+
+	Context("when the result is reported as a pod condition", func() {
+		now := metav1.NewTime(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+		before := metav1.NewTime(now.Add(-time.Hour))
+		unhealthy := cnihealth.Result{Reason: cnihealth.ReasonBinaryMissing, Message: "/opt/cni/bin/cnv-bridge is not installed"}
+
+		It("should keep the transition time while the status does not change", func() {
+			current := &corev1.PodCondition{Type: names.CNI_HEALTH_CONDITION, Status: corev1.ConditionFalse, LastTransitionTime: before}
+			condition := cnihealth.Condition(unhealthy, current, now)
+			Expect(condition.Status).To(Equal(corev1.ConditionFalse))
+			Expect(condition.LastTransitionTime).To(Equal(before))
+			Expect(condition.LastProbeTime).To(Equal(now))
+		})
+
+		It("should be found on the pod", func() {
+			condition := cnihealth.Condition(unhealthy, nil, now)
+			pod := &corev1.Pod{Status: corev1.PodStatus{Conditions: []corev1.PodCondition{{Type: corev1.PodReady}, condition}}}
+			Expect(cnihealth.FindCondition(pod)).To(Equal(&condition))
+			Expect(cnihealth.FindCondition(&corev1.Pod{})).To(BeNil())
+		})
+
+		It("should patch only the condition", func() {
+			patch, err := cnihealth.ConditionPatch(cnihealth.Condition(unhealthy, nil, now))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(patch)).To(MatchJSON(`{"status":{"conditions":[{
+				"type":"networkaddonsoperator.network.kubevirt.io/CNIPluginsHealthy",
+				"status":"False",
+				"reason":"BinaryMissing",
+				"message":"/opt/cni/bin/cnv-bridge is not installed",
+				"lastProbeTime":"2026-01-01T00:00:00Z",
+				"lastTransitionTime":"2026-01-01T00:00:00Z"
+			}]}}`))
+		})
+	})
+})
@@ -0,0 +1,171 @@
+package components
+
+import (
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	structuralschema "k8s.io/apiextensions-apiserver/pkg/apiserver/schema"
+	"k8s.io/apiextensions-apiserver/pkg/apiserver/schema/cel"
+	apiservervalidation "k8s.io/apiextensions-apiserver/pkg/apiserver/validation"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	cnao "github.com/kubevirt/cluster-network-addons-operator/pkg/apis/networkaddonsoperator/shared"
+	cnaov1 "github.com/kubevirt/cluster-network-addons-operator/pkg/apis/networkaddonsoperator/v1"
+)
+
+var _ = Describe("CRD", func() {
+	var crd *extv1.CustomResourceDefinition
+	BeforeEach(func() {
+		crd = GetCrd()
+	})
+
+	It("should serve v1 as the storage version and the deprecated v1alpha1", func() {
+		Expect(crd.Name).To(Equal("networkaddonsconfigs.networkaddonsoperator.network.kubevirt.io"))
+		Expect(crd.Spec.Scope).To(Equal(extv1.ClusterScoped))
+		Expect(crd.Spec.Versions).To(HaveLen(2))
+
+		Expect(crd.Spec.Versions[0].Name).To(Equal("v1"))
+		Expect(crd.Spec.Versions[0].Storage).To(BeTrue())
+		Expect(crd.Spec.Versions[0].Subresources.Status).NotTo(BeNil())
+
+		Expect(crd.Spec.Versions[1].Name).To(Equal("v1alpha1"))
+		Expect(crd.Spec.Versions[1].Storage).To(BeFalse())
+		Expect(crd.Spec.Versions[1].Deprecated).To(BeTrue())
+	})
+
+	// Transition rules are not evaluated on creation, nor for values set for the first time
+	It("should not use transition rules", func() {
+		for _, version := range crd.Spec.Versions {
+			forEachValidationRule(version.Schema.OpenAPIV3Schema, func(rule extv1.ValidationRule) {
+				Expect(rule.Rule).NotTo(ContainSubstring("oldSelf"), "version %s", version.Name)
+			})
+		}
+	})
+
+	Context("when validating the v1 schema", func() {
+		var validate func(cnao.NetworkAddonsConfigSpec) field.ErrorList
+		BeforeEach(func() {
+			internal := &apiextensions.JSONSchemaProps{}
+			Expect(extv1.Convert_v1_JSONSchemaProps_To_apiextensions_JSONSchemaProps(crd.Spec.Versions[0].Schema.OpenAPIV3Schema, internal, nil)).To(Succeed())
+
+			schemaValidator, _, err := apiservervalidation.NewSchemaValidator(&apiextensions.CustomResourceValidation{OpenAPIV3Schema: internal})
+			Expect(err).NotTo(HaveOccurred())
+			structural, err := structuralschema.NewStructural(internal)
+			Expect(err).NotTo(HaveOccurred())
+			celValidator := cel.NewValidator(structural)
+
+			validate = func(spec cnao.NetworkAddonsConfigSpec) field.ErrorList {
+				config := &cnaov1.NetworkAddonsConfig{
+					TypeMeta:   metav1.TypeMeta{APIVersion: cnaov1.GroupVersion.String(), Kind: "NetworkAddonsConfig"},
+					ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+					Spec:       spec,
+				}
+				obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(config)
+				Expect(err).NotTo(HaveOccurred())
+
+				errs := apiservervalidation.ValidateCustomResource(nil, obj, schemaValidator)
+				return append(errs, celValidator.Validate(nil, structural, obj)...)
+			}
+		})
+
+		selfSignConfiguration := func(caRotate, caOverlap, certRotate, certOverlap string) *cnao.SelfSignConfiguration {
+			return &cnao.SelfSignConfiguration{
+				CARotateInterval:    caRotate,
+				CAOverlapInterval:   caOverlap,
+				CertRotateInterval:  certRotate,
+				CertOverlapInterval: certOverlap,
+			}
+		}
+
+		It("should accept a valid configuration", func() {
+			Expect(validate(cnao.NetworkAddonsConfigSpec{
+				ImagePullPolicy:       corev1.PullAlways,
+				KubeMacPool:           &cnao.KubeMacPool{RangeStart: "02:00:00:00:00:00", RangeEnd: "02:FF:FF:FF:FF:FF"},
+				SelfSignConfiguration: selfSignConfiguration("168h", "24h", "24h", "8h"),
+				LinuxBridge:           &cnao.LinuxBridge{BridgeMarker: &cnao.BridgeMarker{UpdateInterval: "1m30s"}},
+				Ovs:                   &cnao.Ovs{Marker: &cnao.OvsMarker{UpdateInterval: "1m", HealthcheckInterval: "500ms"}},
+				MacvtapCni: &cnao.MacvtapCni{DevicePluginResources: []cnao.MacvtapResource{
+					{Name: "dataplane", LowerDevice: "eth0", Mode: "bridge"},
+				}},
+			})).To(BeEmpty())
+		})
+
+		DescribeTable("should reject an invalid configuration", func(spec cnao.NetworkAddonsConfigSpec, expectedError string) {
+			errs := validate(spec)
+			Expect(errs).NotTo(BeEmpty())
+			Expect(errs.ToAggregate().Error()).To(ContainSubstring(expectedError))
+		},
+			Entry("with unknown imagePullPolicy",
+				cnao.NetworkAddonsConfigSpec{ImagePullPolicy: "Sometimes"},
+				"spec.imagePullPolicy",
+			),
+			Entry("with malformed MAC address",
+				cnao.NetworkAddonsConfigSpec{KubeMacPool: &cnao.KubeMacPool{RangeStart: "this:aint:right", RangeEnd: "02:FF:FF:FF:FF:FF"}},
+				"spec.kubeMacPool.rangeStart",
+			),
+			Entry("with only one end of the MAC range",
+				cnao.NetworkAddonsConfigSpec{KubeMacPool: &cnao.KubeMacPool{RangeStart: "02:00:00:00:00:00"}},
+				"both or none of rangeStart and rangeEnd have to be set",
+			),
+			Entry("with malformed duration",
+				cnao.NetworkAddonsConfigSpec{LinuxBridge: &cnao.LinuxBridge{BridgeMarker: &cnao.BridgeMarker{UpdateInterval: "1 minute"}}},
+				"spec.linuxBridge.bridgeMarker.updateInterval",
+			),
+			Entry("with unknown macvtap mode",
+				cnao.NetworkAddonsConfigSpec{MacvtapCni: &cnao.MacvtapCni{DevicePluginResources: []cnao.MacvtapResource{
+					{Name: "dataplane", LowerDevice: "eth0", Mode: "foo"},
+				}}},
+				"spec.macvtap.devicePluginResources.mode",
+			),
+			Entry("with malformed component namespace",
+				cnao.NetworkAddonsConfigSpec{LinuxBridge: &cnao.LinuxBridge{Namespace: "Bridges"}},
+				"spec.linuxBridge.namespace",
+			),
+			Entry("with too long component namespace",
+				cnao.NetworkAddonsConfigSpec{KubeMacPool: &cnao.KubeMacPool{Namespace: cnao.ComponentNamespace(strings.Repeat("a", 64))}},
+				"spec.kubeMacPool.namespace",
+			),
+			Entry("with incomplete selfSignConfiguration",
+				cnao.NetworkAddonsConfigSpec{SelfSignConfiguration: selfSignConfiguration("168h", "24h", "", "8h")},
+				"caRotateInterval, caOverlapInterval, certRotateInterval and certOverlapInterval have to be set",
+			),
+			Entry("with zero selfSignConfiguration duration",
+				cnao.NetworkAddonsConfigSpec{SelfSignConfiguration: selfSignConfiguration("168h", "24h", "24h", "0s")},
+				"certOverlapInterval duration has to be > 0",
+			),
+			Entry("with caOverlapInterval longer than caRotateInterval",
+				cnao.NetworkAddonsConfigSpec{SelfSignConfiguration: selfSignConfiguration("24h", "48h", "24h", "8h")},
+				"caOverlapInterval has to be <= caRotateInterval",
+			),
+			Entry("with certRotateInterval longer than caRotateInterval",
+				cnao.NetworkAddonsConfigSpec{SelfSignConfiguration: selfSignConfiguration("24h", "8h", "48h", "8h")},
+				"certRotateInterval has to be <= caRotateInterval",
+			),
+			Entry("with certOverlapInterval longer than certRotateInterval",
+				cnao.NetworkAddonsConfigSpec{SelfSignConfiguration: selfSignConfiguration("168h", "24h", "8h", "24h")},
+				"certOverlapInterval has to be <= certRotateInterval",
+			),
+		)
+	})
+})
+
+// forEachValidationRule calls f with every CEL validation rule of the schema and its descendants
+func forEachValidationRule(schema *extv1.JSONSchemaProps, f func(extv1.ValidationRule)) {
+	for _, rule := range schema.XValidations {
+		f(rule)
+	}
+	for _, property := range schema.Properties {
+		property := property
+		forEachValidationRule(&property, f)
+	}
+	if schema.Items != nil && schema.Items.Schema != nil {
+		forEachValidationRule(schema.Items.Schema, f)
+	}
+}
@@ -0,0 +1,110 @@
+package components
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+)
+
+// kindResourceOverrides lists kinds whose resource name cannot be guessed from the kind
+var kindResourceOverrides = map[schema.GroupKind]string{
+	{Group: "security.openshift.io", Kind: "SecurityContextConstraints"}: "securitycontextconstraints",
+}
+
+// unnamedVerbs cannot be restricted by resource names
+var unnamedVerbs = []string{"create"}
+
+// namedVerbs are used by the operator to apply and remove objects, they are restricted to names of
+// objects managed by the operator
+var namedVerbs = []string{"get", "update", "delete"}
+
+// roleVerbs allow the operator to manage roles of components and their bindings without holding
+// the permissions granted by these roles itself
+var roleVerbs = []string{"bind", "escalate"}
+
+// roleResources are resources of roles which can be bound by role bindings
+var roleResources = map[schema.GroupResource]bool{
+	{Group: rbacv1.GroupName, Resource: "clusterroles"}: true,
+	{Group: rbacv1.GroupName, Resource: "roles"}:        true,
+}
+
+// PolicyRulesForObjects computes rules the operator needs in order to apply and remove the given
+// objects. Names of the objects are used to restrict rules whenever possible. Objects read through
+// the operator cache are covered by rules of GetClusterRole.
+func PolicyRulesForObjects(objs []*unstructured.Unstructured) []rbacv1.PolicyRule {
+	namesByGroupResource := map[schema.GroupResource]map[string]bool{}
+
+	for _, obj := range objs {
+		groupResource := ResourceForKind(obj.GroupVersionKind())
+		if namesByGroupResource[groupResource] == nil {
+			namesByGroupResource[groupResource] = map[string]bool{}
+		}
+		namesByGroupResource[groupResource][obj.GetName()] = true
+	}
+
+	groupResources := []schema.GroupResource{}
+	for groupResource := range namesByGroupResource {
+		groupResources = append(groupResources, groupResource)
+	}
+	sort.Slice(groupResources, func(i, j int) bool {
+		return groupResources[i].String() < groupResources[j].String()
+	})
+
+	rules := []rbacv1.PolicyRule{}
+	for _, groupResource := range groupResources {
+		rules = append(rules, rbacv1.PolicyRule{
+			APIGroups: []string{groupResource.Group},
+			Resources: []string{groupResource.Resource},
+			Verbs:     unnamedVerbs,
+		})
+
+		names := []string{}
+		for name := range namesByGroupResource[groupResource] {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		verbs := namedVerbs
+		if roleResources[groupResource] {
+			verbs = append(append([]string{}, namedVerbs...), roleVerbs...)
+		}
+		rules = append(rules, rbacv1.PolicyRule{
+			APIGroups:     []string{groupResource.Group},
+			Resources:     []string{groupResource.Resource},
+			ResourceNames: names,
+			Verbs:         verbs,
+		})
+	}
+
+	return rules
+}
+
+// ResourceForKind returns the resource serving objects of the given kind
+func ResourceForKind(gvk schema.GroupVersionKind) schema.GroupResource {
+	if resource, exists := kindResourceOverrides[gvk.GroupKind()]; exists {
+		return schema.GroupResource{Group: gvk.Group, Resource: resource}
+	}
+	plural, _ := meta.UnsafeGuessKindToResource(gvk)
+	return schema.GroupResource{Group: gvk.Group, Resource: strings.ToLower(plural.Resource)}
+}
+
+func appendUniqueRules(rules []rbacv1.PolicyRule, additionalRules ...rbacv1.PolicyRule) []rbacv1.PolicyRule {
+	for _, additionalRule := range additionalRules {
+		found := false
+		for _, rule := range rules {
+			if reflect.DeepEqual(rule, additionalRule) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			rules = append(rules, additionalRule)
+		}
+	}
+	return rules
+}
@@ -0,0 +1,123 @@
+package components
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/kubevirt/cluster-network-addons-operator/pkg/util/k8s"
+)
+
+var _ = Describe("RBAC", func() {
+	Context("When PolicyRulesForObjects is called", func() {
+		objs := []*unstructured.Unstructured{
+			k8s.UnstructuredFromYaml(`
+apiVersion: apps/v1
+kind: DaemonSet
+metadata:
+  name: ds-b
+  namespace: ns`),
+			k8s.UnstructuredFromYaml(`
+apiVersion: apps/v1
+kind: DaemonSet
+metadata:
+  name: ds-a
+  namespace: ns`),
+			k8s.UnstructuredFromYaml(`
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: ns`),
+			k8s.UnstructuredFromYaml(`
+apiVersion: security.openshift.io/v1
+kind: SecurityContextConstraints
+metadata:
+  name: scc`),
+			k8s.UnstructuredFromYaml(`
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRole
+metadata:
+  name: cr
+rules:
+- apiGroups: [""]
+  resources: ["pods"]
+  verbs: ["get"]
+- apiGroups: ["apps"]
+  resources: ["daemonsets"]
+  verbs: ["get", "list", "watch", "create"]`),
+		}
+
+		var rules []rbacv1.PolicyRule
+		BeforeEach(func() {
+			rules = PolicyRulesForObjects(objs)
+		})
+
+		It("Should restrict modification of objects to their names", func() {
+			Expect(rules).To(ContainElement(rbacv1.PolicyRule{
+				APIGroups:     []string{"apps"},
+				Resources:     []string{"daemonsets"},
+				ResourceNames: []string{"ds-a", "ds-b"},
+				Verbs:         []string{"get", "update", "delete"},
+			}))
+			Expect(rules).To(ContainElement(rbacv1.PolicyRule{
+				APIGroups: []string{"apps"},
+				Resources: []string{"daemonsets"},
+				Verbs:     []string{"create"},
+			}))
+		})
+
+		It("Should use resource names of kinds that cannot be guessed", func() {
+			Expect(rules).To(ContainElement(rbacv1.PolicyRule{
+				APIGroups:     []string{"security.openshift.io"},
+				Resources:     []string{"securitycontextconstraints"},
+				ResourceNames: []string{"scc"},
+				Verbs:         []string{"get", "update", "delete"},
+			}))
+		})
+
+		It("Should restrict modification of namespaces to their names", func() {
+			Expect(rules).To(ContainElement(rbacv1.PolicyRule{
+				APIGroups:     []string{""},
+				Resources:     []string{"namespaces"},
+				ResourceNames: []string{"ns"},
+				Verbs:         []string{"get", "update", "delete"},
+			}))
+		})
+
+		It("Should allow binding managed roles instead of holding permissions they grant", func() {
+			Expect(rules).To(ContainElement(rbacv1.PolicyRule{
+				APIGroups:     []string{"rbac.authorization.k8s.io"},
+				Resources:     []string{"clusterroles"},
+				ResourceNames: []string{"cr"},
+				Verbs:         []string{"get", "update", "delete", "bind", "escalate"},
+			}))
+			for _, rule := range rules {
+				Expect(rule.Resources).NotTo(ContainElement("pods"))
+			}
+			Expect(rules).To(HaveLen(8))
+		})
+	})
+
+	Context("When GetClusterRole is called", func() {
+		It("Should not grant everything", func() {
+			for _, rule := range GetClusterRole("ns", nil).Rules {
+				Expect(rule.Resources).NotTo(ContainElement("*"))
+				Expect(rule.Verbs).NotTo(ContainElement("*"))
+			}
+		})
+
+		It("Should not allow modification of unnamed namespaces", func() {
+			for _, rule := range GetClusterRole("ns", nil).Rules {
+				if len(rule.ResourceNames) == 0 && rule.APIGroups[0] == "" {
+					for _, resource := range rule.Resources {
+						if resource == "namespaces" {
+							Expect(rule.Verbs).To(ConsistOf("list", "watch"))
+						}
+					}
+				}
+			}
+		})
+	})
+})
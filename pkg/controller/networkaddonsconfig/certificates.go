@@ -0,0 +1,85 @@
+package networkaddonsconfig
+
+import (
+	"context"
+	"log"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	cnao "github.com/kubevirt/cluster-network-addons-operator/pkg/apis/networkaddonsoperator/shared"
+	cnaov1 "github.com/kubevirt/cluster-network-addons-operator/pkg/apis/networkaddonsoperator/v1"
+	"github.com/kubevirt/cluster-network-addons-operator/pkg/certificates"
+	"github.com/kubevirt/cluster-network-addons-operator/pkg/names"
+	"github.com/kubevirt/cluster-network-addons-operator/pkg/network"
+	"github.com/kubevirt/cluster-network-addons-operator/pkg/webhook"
+)
+
+// certificateSources lists objects keeping certificates of the operator webhooks and of known
+// component webhooks among deployed objects
+func certificateSources(namespace string, conf *cnao.NetworkAddonsConfigSpec, objs []*unstructured.Unstructured) ([]certificates.Source, error) {
+	known, err := network.KubeMacPoolCertificateSources(conf)
+	if err != nil {
+		return nil, err
+	}
+	sources, err := certificates.SourcesForObjects(objs, known)
+	if err != nil {
+		return nil, err
+	}
+	operatorRotation := certificates.Rotation{Threshold: webhook.RotationThreshold}
+	return append([]certificates.Source{
+		certificates.SecretSource(namespace, names.WEBHOOK_CERT_SECRET, operatorRotation),
+		certificates.ValidatingWebhookConfigurationSource(names.VALIDATING_WEBHOOK_CONFIGURATION, operatorRotation),
+	}, sources...), nil
+}
+
+// rotateCertificatesRequested returns whether NetworkAddonsConfig asks for immediate rotation of
+// webhook certificates
+func rotateCertificatesRequested(networkAddonsConfig *cnaov1.NetworkAddonsConfig) bool {
+	_, requested := networkAddonsConfig.GetAnnotations()[names.ROTATE_CERTIFICATES_ANNOTATION]
+	return requested
+}
+
+// rotateCertificates forces reissuing of serving certificates kept in Secret sources and drops
+// the request. Certificates of the operator are rotated by its webhook server once their Secret
+// is annotated, other Secrets are removed and reissued by the component managing them.
+func rotateCertificates(ctx context.Context, c k8sclient.Client, namespace string, networkAddonsConfig *cnaov1.NetworkAddonsConfig, sources []certificates.Source) error {
+	operatorSecret := types.NamespacedName{Namespace: namespace, Name: names.WEBHOOK_CERT_SECRET}
+	for _, source := range sources {
+		if !source.IsSecret() {
+			continue
+		}
+		if source.Namespace == operatorSecret.Namespace && source.Name == operatorSecret.Name {
+			if err := requestOperatorCertificatesRotation(ctx, c, operatorSecret); err != nil {
+				return err
+			}
+			continue
+		}
+		log.Printf("rotating certificates kept in Secret %s/%s", source.Namespace, source.Name)
+		if err := certificates.Rotate(ctx, c, source); err != nil {
+			return err
+		}
+	}
+
+	patch := k8sclient.MergeFrom(networkAddonsConfig.DeepCopy())
+	annotations := networkAddonsConfig.GetAnnotations()
+	delete(annotations, names.ROTATE_CERTIFICATES_ANNOTATION)
+	networkAddonsConfig.SetAnnotations(annotations)
+	return c.Patch(ctx, networkAddonsConfig, patch)
+}
+
+func requestOperatorCertificatesRotation(ctx context.Context, c k8sclient.Client, key types.NamespacedName) error {
+	log.Printf("rotating certificates kept in Secret %s", key)
+	secret := &corev1.Secret{}
+	secret.Namespace, secret.Name = key.Namespace, key.Name
+	patch := k8sclient.RawPatch(types.MergePatchType, []byte(`{"metadata":{"annotations":{"`+names.ROTATE_CERTIFICATES_ANNOTATION+`":""}}}`))
+	// Certificates not issued yet are fresh anyway
+	if err := c.Patch(ctx, secret, patch); err != nil && !apierrors.IsNotFound(err) {
+		return errors.Wrapf(err, "failed to request rotation of certificates kept in Secret %s", key)
+	}
+	return nil
+}
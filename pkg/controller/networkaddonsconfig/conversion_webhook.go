@@ -0,0 +1,20 @@
+package networkaddonsconfig
+
+import (
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/conversion"
+
+	"github.com/kubevirt/cluster-network-addons-operator/pkg/names"
+	"github.com/kubevirt/cluster-network-addons-operator/pkg/webhook"
+)
+
+// addConversionWebhook serves conversion between NetworkAddonsConfig versions, the deprecated
+// v1alpha1 is converted through v1
+func addConversionWebhook(mgr manager.Manager, server *webhook.Server) error {
+	handler := &conversion.Webhook{}
+	if err := handler.InjectScheme(mgr.GetScheme()); err != nil {
+		return err
+	}
+	server.RegisterConversionWebhook(names.CONVERSION_WEBHOOK_PATH, names.NETWORK_ADDONS_CONFIG_CRD, handler)
+	return nil
+}
@@ -0,0 +1,87 @@
+package networkaddonsconfig
+
+import (
+	"context"
+	"log"
+
+	osv1 "github.com/openshift/api/operator/v1"
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	cnao "github.com/kubevirt/cluster-network-addons-operator/pkg/apis/networkaddonsoperator/shared"
+	"github.com/kubevirt/cluster-network-addons-operator/pkg/controller/statusmanager"
+	"github.com/kubevirt/cluster-network-addons-operator/pkg/names"
+	"github.com/kubevirt/cluster-network-addons-operator/pkg/network"
+)
+
+// cleanUpHosts starts removal of host artifacts left by removed components and returns DaemonSets
+// of cleanups in progress. Cleanups are dropped once they finish on all nodes, or when their
+// component is requested again.
+func (r *ReconcileNetworkAddonsConfig) cleanUpHosts(networkAddonsConfigStorageVersion metav1.Object, networkAddonsConfig *cnao.NetworkAddonsConfig, openshiftNetworkConfig *osv1.Network, prevs []*cnao.NetworkAddonsConfigSpec) ([]types.NamespacedName, error) {
+	objs := []*unstructured.Unstructured{}
+	for _, prev := range prevs {
+		prevObjs, err := network.RenderHostCleanup(prev, &networkAddonsConfig.Spec, ManifestPath, openshiftNetworkConfig, r.clusterInfo)
+		if err != nil {
+			log.Printf("failed to render host cleanup: %v", err)
+			return nil, errors.Wrap(err, "failed to render host cleanup")
+		}
+		objs = append(objs, prevObjs...)
+	}
+	if err := updateObjectsLabels(networkAddonsConfig.GetLabels(), objs); err != nil {
+		log.Printf("failed to update host cleanup labels: %v", err)
+		return nil, errors.Wrap(err, "failed to update host cleanup labels")
+	}
+	if err := r.applyObjects(networkAddonsConfigStorageVersion, objs); err != nil {
+		return nil, err
+	}
+
+	// Cleanups just created may not be in the cache yet
+	daemonSets := &appsv1.DaemonSetList{}
+	if err := r.client.List(context.TODO(), daemonSets, k8sclient.HasLabels{names.HOST_CLEANUP_LABEL_KEY}); err != nil {
+		log.Printf("failed to list host cleanups: %v", err)
+		return nil, errors.Wrap(err, "failed to list host cleanups")
+	}
+	for _, obj := range objs {
+		if obj.GetKind() == "DaemonSet" && !containsDaemonSet(daemonSets.Items, obj.GetNamespace(), obj.GetName()) {
+			daemonSets.Items = append(daemonSets.Items, appsv1.DaemonSet{ObjectMeta: metav1.ObjectMeta{
+				Namespace:  obj.GetNamespace(),
+				Name:       obj.GetName(),
+				Generation: 1,
+				Labels:     obj.GetLabels(),
+			}})
+		}
+	}
+
+	inProgress := []types.NamespacedName{}
+	for i := range daemonSets.Items {
+		ds := &daemonSets.Items[i]
+		component := ds.Labels[names.HOST_CLEANUP_LABEL_KEY]
+		if statusmanager.HostCleanupComplete(ds) || network.IsHostComponentDeployed(component, &networkAddonsConfig.Spec, openshiftNetworkConfig) {
+			if err := r.deleteOwnedObjects(network.HostCleanupObjects(component, r.clusterInfo)); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		inProgress = append(inProgress, types.NamespacedName{Namespace: ds.Namespace, Name: ds.Name})
+	}
+	return inProgress, nil
+}
+
+func containsDaemonSet(daemonSets []appsv1.DaemonSet, namespace, name string) bool {
+	for _, ds := range daemonSets {
+		if ds.Namespace == namespace && ds.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Track host cleanups in progress, so NetworkAddonsConfig is not reported Available before they finish
+func (r *ReconcileNetworkAddonsConfig) trackHostCleanups(daemonSets []types.NamespacedName) {
+	r.statusManager.SetHostCleanups(daemonSets)
+	r.podReconciler.SetHostCleanups(daemonSets)
+}
@@ -37,6 +37,7 @@ import (
 	cnaov1 "github.com/kubevirt/cluster-network-addons-operator/pkg/apis/networkaddonsoperator/v1"
 	cnaov1alpha1 "github.com/kubevirt/cluster-network-addons-operator/pkg/apis/networkaddonsoperator/v1alpha1"
 	"github.com/kubevirt/cluster-network-addons-operator/pkg/apply"
+	"github.com/kubevirt/cluster-network-addons-operator/pkg/certificates"
 	"github.com/kubevirt/cluster-network-addons-operator/pkg/controller/statusmanager"
 	"github.com/kubevirt/cluster-network-addons-operator/pkg/eventemitter"
 	"github.com/kubevirt/cluster-network-addons-operator/pkg/monitoring"
@@ -49,6 +50,10 @@ import (
 // ManifestPath is the path to the manifest templates
 const ManifestPath = "./data"
 
+// pendingRevisionCheckInterval is how often availability of moved components and progress of host
+// cleanups are checked while completion of replaced revisions is postponed
+const pendingRevisionCheckInterval = 10 * time.Second
+
 var operatorNamespace string
 var operatorVersion string
 var operatorVersionLabel string
@@ -102,7 +107,30 @@ func Add(mgr manager.Manager) error {
 	}
 	clusterInfo.MonitoringAvailable = addMonitorServiceResources
 
-	return add(mgr, newReconciler(mgr, namespace, clusterInfo))
+	proxyConfigAvailable, err := isProxyConfigAvailable(clientset)
+	if err != nil {
+		return fmt.Errorf("failed to check for availability of cluster-wide proxy configuration: %v", err)
+	}
+	clusterInfo.ProxyConfigAvailable = proxyConfigAvailable
+
+	webhookServer := newWebhookServer(mgr, namespace)
+	if err := addValidatingWebhook(mgr, webhookServer, namespace); err != nil {
+		return fmt.Errorf("failed to add validating webhook: %v", err)
+	}
+	if err := addConversionWebhook(mgr, webhookServer); err != nil {
+		return fmt.Errorf("failed to add conversion webhook: %v", err)
+	}
+	if err := mgr.Add(webhookServer); err != nil {
+		return fmt.Errorf("failed to add webhook server: %v", err)
+	}
+	if err := mgr.Add(webhookServer.Issuer()); err != nil {
+		return fmt.Errorf("failed to add webhook certificates issuer: %v", err)
+	}
+
+	if err := add(mgr, newReconciler(mgr, namespace, clusterInfo)); err != nil {
+		return err
+	}
+	return addOvsNodes(mgr, newOvsNodesReconciler(mgr))
 }
 
 // newReconciler returns a new ReconcileNetworkAddonsConfig
@@ -110,9 +138,10 @@ func newReconciler(mgr manager.Manager, namespace string, clusterInfo *network.C
 	// Status manager is shared between both reconcilers and it is used to update conditions of
 	// NetworkAddonsConfig.State. NetworkAddonsConfig reconciler updates it with progress of rendering
 	// and applying of manifests. Pods reconciler updates it with progress of deployed pods.
-	statusManager := statusmanager.New(mgr, names.OPERATOR_CONFIG)
+	statusManager := statusmanager.New(mgr, names.OPERATOR_CONFIG, clusterInfo.OpenShift4)
 	return &ReconcileNetworkAddonsConfig{
 		client:        mgr.GetClient(),
+		apiReader:     mgr.GetAPIReader(),
 		scheme:        mgr.GetScheme(),
 		namespace:     namespace,
 		podReconciler: newPodReconciler(statusManager, mgr),
@@ -132,7 +161,7 @@ func add(mgr manager.Manager, r *ReconcileNetworkAddonsConfig) error {
 
 	// Create custom predicate for NetworkAddonsConfig watcher. This makes sure that Status field
 	// updates will not trigger reconciling of the object. Reconciliation is trigger only if
-	// Spec fields differ.
+	// Spec fields differ, a rollback or a rotation of certificates is requested.
 	pred := predicate.Funcs{
 		UpdateFunc: func(e event.UpdateEvent) bool {
 			oldConfig, err := runtimeObjectToNetworkAddonsConfig(e.ObjectOld)
@@ -145,7 +174,9 @@ func add(mgr manager.Manager, r *ReconcileNetworkAddonsConfig) error {
 				log.Printf("Failed to convert runtime.Object to NetworkAddonsConfig: %v", err)
 				return false
 			}
-			return !reflect.DeepEqual(oldConfig.Spec, newConfig.Spec)
+			return !reflect.DeepEqual(oldConfig.Spec, newConfig.Spec) ||
+				oldConfig.GetAnnotations()[names.ROLLBACK_ANNOTATION] != newConfig.GetAnnotations()[names.ROLLBACK_ANNOTATION] ||
+				oldConfig.GetAnnotations()[names.ROTATE_CERTIFICATES_ANNOTATION] != newConfig.GetAnnotations()[names.ROTATE_CERTIFICATES_ANNOTATION]
 		},
 	}
 
@@ -157,6 +188,13 @@ func add(mgr manager.Manager, r *ReconcileNetworkAddonsConfig) error {
 		return err
 	}
 
+	// Watch for changes of the cluster-wide proxy, so they are passed to components
+	if r.clusterInfo.ProxyConfigAvailable {
+		if err := c.Watch(&source.Kind{Type: &osconfv1.Proxy{}}, handler.EnqueueRequestsFromMapFunc(requestForConfig), proxyPredicate); err != nil {
+			return err
+		}
+	}
+
 	// Create a new controller for Pod resources, this will be used to track state of deployed components
 	c, err = controller.New("pod-controller", mgr, controller.Options{Reconciler: r.podReconciler})
 	if err != nil {
@@ -173,16 +211,40 @@ func add(mgr manager.Manager, r *ReconcileNetworkAddonsConfig) error {
 		return err
 	}
 
+	// CNI plugin health is reported by DaemonSet pods, their status does not change the DaemonSet
+	err = c.Watch(&source.Kind{Type: &v1.Pod{}}, &handler.EnqueueRequestForOwner{OwnerType: &appsv1.DaemonSet{}, IsController: true}, cniHealthPredicate)
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
+// addOvsNodes adds a new Controller labeling nodes running Open vSwitch to mgr
+func addOvsNodes(mgr manager.Manager, r *ReconcileOvsNodes) error {
+	c, err := controller.New("ovs-nodes-controller", mgr, controller.Options{Reconciler: r})
+	if err != nil {
+		return err
+	}
+
+	// Nodes are labeled when they join the cluster or their network provider changes
+	if err := c.Watch(&source.Kind{Type: &v1.Node{}}, &handler.EnqueueRequestForObject{}, ovsNodesPredicate); err != nil {
+		return err
+	}
+
+	// All nodes are relabeled when ovs-cni is restricted to Open vSwitch nodes or released from the restriction
+	return c.Watch(&source.Kind{Type: &cnaov1.NetworkAddonsConfig{}}, handler.EnqueueRequestsFromMapFunc(r.requestsForAllNodes), predicate.GenerationChangedPredicate{})
+}
+
 var _ reconcile.Reconciler = &ReconcileNetworkAddonsConfig{}
 
 // ReconcileNetworkAddonsConfig reconciles a NetworkAddonsConfig object
 type ReconcileNetworkAddonsConfig struct {
 	// This client, initialized using mgr.Client() above, is a split client
 	// that reads objects from the cache and writes to the apiserver
-	client        client.Client
+	client client.Client
+	// Secrets are not cached, certificates are read directly from the apiserver
+	apiReader     client.Reader
 	scheme        *runtime.Scheme
 	namespace     string
 	podReconciler *ReconcilePods
@@ -210,6 +272,19 @@ func (r *ReconcileNetworkAddonsConfig) Reconcile(ctx context.Context, request re
 		r.clusterInfo.IsSingleReplica = isSingleReplica
 	}
 
+	if r.clusterInfo.ProxyConfigAvailable {
+		proxy, err := getOpenShiftProxy(r.client)
+		if err != nil {
+			log.Printf("failed to read cluster-wide proxy configuration: %v", err)
+		} else {
+			r.clusterInfo.Proxy = proxy
+		}
+	}
+
+	if !r.clusterInfo.OpenShift4 {
+		r.detectCNIDirectories()
+	}
+
 	// Fetch the NetworkAddonsConfig instance
 	networkAddonsConfigStorageVersion := &cnaov1.NetworkAddonsConfig{}
 	err := r.client.Get(context.TODO(), request.NamespacedName, networkAddonsConfigStorageVersion)
@@ -241,6 +316,13 @@ func (r *ReconcileNetworkAddonsConfig) Reconcile(ctx context.Context, request re
 		return reconcile.Result{}, err
 	}
 
+	// Leave components as they are while they are not managed, the config is reconciled again once
+	// its management state changes
+	if network.IsUnmanaged(&networkAddonsConfig.Spec) {
+		log.Print("ignoring NetworkAddonsConfig in Unmanaged management state")
+		return reconcile.Result{}, nil
+	}
+
 	// Convert to a canonicalized form
 	network.Canonicalize(&networkAddonsConfig.Spec)
 
@@ -253,6 +335,21 @@ func (r *ReconcileNetworkAddonsConfig) Reconcile(ctx context.Context, request re
 		return reconcile.Result{}, err
 	}
 
+	// Replace the spec with a previously applied revision if requested, the rolled back spec is
+	// reconciled once the change is observed
+	if rollbackRequested(networkAddonsConfigStorageVersion) {
+		if err := rollback(context.TODO(), r.client, r.namespace, networkAddonsConfigStorageVersion, openshiftNetworkConfig); err != nil {
+			log.Printf("failed to roll back NetworkAddonsConfig: %v", err)
+			r.statusManager.SetFailing(statusmanager.OperatorConfig, "FailedToRollback", err.Error())
+			if isRollbackRefused(err) {
+				// Retrying would not help, the request is reconciled again once it is changed
+				return reconcile.Result{}, nil
+			}
+			return reconcile.Result{}, err
+		}
+		return reconcile.Result{Requeue: true}, nil
+	}
+
 	// Validate the configuration
 	if err := network.Validate(&networkAddonsConfig.Spec, openshiftNetworkConfig); err != nil {
 		log.Printf("failed to validate NetworkConfig.Spec: %v", err)
@@ -260,6 +357,11 @@ func (r *ReconcileNetworkAddonsConfig) Reconcile(ctx context.Context, request re
 		r.statusManager.SetFailing(statusmanager.OperatorConfig, "FailedToValidate", err.Error())
 		return reconcile.Result{}, err
 	}
+
+	// Components of a config in the Removed management state are removed as if they were dropped
+	// from the spec
+	network.DropRemovedComponents(&networkAddonsConfig.Spec)
+
 	prev, err := r.getPreviousConfigSpec(networkAddonsConfig)
 	if err != nil {
 		// If failed, set NetworkAddonsConfig to failing and requeue
@@ -268,14 +370,20 @@ func (r *ReconcileNetworkAddonsConfig) Reconcile(ctx context.Context, request re
 	}
 
 	// Canonicalize and validate NetworkAddonsConfig, finally render objects of requested components
-	objs, err := r.renderObjectsV1(networkAddonsConfig, openshiftNetworkConfig)
+	objs, history, err := r.renderObjectsV1(networkAddonsConfig, openshiftNetworkConfig)
 	if err != nil {
 		// If failed, set NetworkAddonsConfig to failing and requeue
 		r.statusManager.SetFailing(statusmanager.OperatorConfig, "FailedToRender", err.Error())
 		return reconcile.Result{}, err
 	}
 
-	objsToRemove, err := r.renderObjectsToDelete(networkAddonsConfig, openshiftNetworkConfig, prev)
+	// Clean up after every revision replaced by the applied one, until the cleanup succeeds
+	replaced := replacedRevisionSpecs(history)
+	if len(replaced) == 0 && prev != nil {
+		replaced = append(replaced, prev)
+	}
+
+	objsToRemove, err := r.renderObjectsToDelete(networkAddonsConfig, openshiftNetworkConfig, replaced, objs)
 	if err != nil {
 		// If failed, set NetworkAddonsConfig to failing and requeue
 		r.statusManager.SetFailing(statusmanager.OperatorConfig, "FailedToRenderDelete", err.Error())
@@ -293,13 +401,72 @@ func (r *ReconcileNetworkAddonsConfig) Reconcile(ctx context.Context, request re
 	// Track state of all deployed pods
 	r.trackDeployedObjects(objs, networkAddonsConfig.GetGeneration())
 
-	// Delete generated objsToRemove on Kubernetes API server
-	err = r.deleteOwnedObjects(objsToRemove)
+	// Expose the revision of the applied configuration
+	r.statusManager.SetAppliedRevision(history[len(history)-1].Revision)
+
+	// Expose CNI directories the components were installed to
+	r.statusManager.SetCNIDirectories(network.CNIDirectories(&networkAddonsConfig.Spec, r.clusterInfo))
+
+	// Objects of components moved to another namespace are removed only once their workloads
+	// are available in the new namespace, so the components keep serving while they move
+	movedAvailable, err := r.movedWorkloadsAvailable(network.MovedNamespaces(replaced, &networkAddonsConfig.Spec), objs)
 	if err != nil {
 		// If failed, set NetworkAddonsConfig to failing and requeue
-		r.statusManager.SetFailing(statusmanager.OperatorConfig, "FailedToDeleteObjects", err.Error())
+		r.statusManager.SetFailing(statusmanager.OperatorConfig, "FailedToCheckMovedComponents", err.Error())
+		return reconcile.Result{}, err
+	}
+	hostCleanupsInProgress := false
+	if movedAvailable {
+		// Delete generated objsToRemove on Kubernetes API server
+		err = r.deleteOwnedObjects(objsToRemove)
+		if err != nil {
+			// If failed, set NetworkAddonsConfig to failing and requeue
+			r.statusManager.SetFailing(statusmanager.OperatorConfig, "FailedToDeleteObjects", err.Error())
+			return reconcile.Result{}, err
+		}
+
+		// Remove CNI binaries and configuration left on nodes by removed components
+		hostCleanups, err := r.cleanUpHosts(networkAddonsConfigStorageVersion, networkAddonsConfig, openshiftNetworkConfig, replaced)
+		if err != nil {
+			// If failed, set NetworkAddonsConfig to failing and requeue
+			r.statusManager.SetFailing(statusmanager.OperatorConfig, "FailedToCleanUpHosts", err.Error())
+			return reconcile.Result{}, err
+		}
+		r.trackHostCleanups(hostCleanups)
+		hostCleanupsInProgress = len(hostCleanups) > 0
+
+		// Replaced revisions stay pending until their host cleanups succeed, so the cleanups are
+		// rendered again if their DaemonSets get lost meanwhile
+		if hostCleanupsInProgress {
+			log.Print("postponing completion of replaced revisions until host cleanups finish")
+		} else if err := r.completeAppliedRevisions(networkAddonsConfigStorageVersion, networkAddonsConfig, history); err != nil {
+			// If failed, set NetworkAddonsConfig to failing and requeue
+			r.statusManager.SetFailing(statusmanager.OperatorConfig, "FailedToCompleteRevision", err.Error())
+			return reconcile.Result{}, err
+		}
+	} else {
+		log.Print("postponing removal of replaced objects until moved components are available")
+	}
+
+	// Rotate webhook certificates if requested and expose their expiry
+	certificateSources, err := certificateSources(r.namespace, &networkAddonsConfig.Spec, objs)
+	if err != nil {
+		r.statusManager.SetFailing(statusmanager.OperatorConfig, "FailedToListCertificates", err.Error())
+		return reconcile.Result{}, err
+	}
+	if rotateCertificatesRequested(networkAddonsConfigStorageVersion) {
+		if err := rotateCertificates(context.TODO(), r.client, r.namespace, networkAddonsConfigStorageVersion, certificateSources); err != nil {
+			log.Printf("failed to rotate certificates: %v", err)
+			r.statusManager.SetFailing(statusmanager.OperatorConfig, "FailedToRotateCertificates", err.Error())
+			return reconcile.Result{}, err
+		}
+	}
+	inspectedCertificates, err := certificates.Inspect(context.TODO(), r.apiReader, certificateSources)
+	if err != nil {
+		r.statusManager.SetFailing(statusmanager.OperatorConfig, "FailedToInspectCertificates", err.Error())
 		return reconcile.Result{}, err
 	}
+	r.statusManager.SetCertificates(certificates.Statuses(inspectedCertificates))
 
 	// Everything went smooth, remove failures from NetworkAddonsConfig if there are any from
 	// previous runs.
@@ -314,6 +481,12 @@ func (r *ReconcileNetworkAddonsConfig) Reconcile(ctx context.Context, request re
 
 	if r.clusterInfo.MonitoringAvailable {
 		monitoring.TrackMonitoredComponents(&networkAddonsConfig.Spec, r.statusManager)
+		monitoring.TrackCertificates(inspectedCertificates)
+	}
+
+	// Check moved components and host cleanups again soon, replaced revisions are waiting for them
+	if !movedAvailable || hostCleanupsInProgress {
+		return reconcile.Result{RequeueAfter: pendingRevisionCheckInterval}, nil
 	}
 
 	// Kubernetes sometimes fails to apply objects while we remove and recreate
@@ -333,28 +506,44 @@ func (r *ReconcileNetworkAddonsConfig) ConvertNetworkAddonsConfigV1ToShared(netw
 }
 
 // Render objects for all desired components
-func (r *ReconcileNetworkAddonsConfig) renderObjectsV1(networkAddonsConfig *cnao.NetworkAddonsConfig, openshiftNetworkConfig *osv1.Network) ([]*unstructured.Unstructured, error) {
+func (r *ReconcileNetworkAddonsConfig) renderObjectsV1(networkAddonsConfig *cnao.NetworkAddonsConfig, openshiftNetworkConfig *osv1.Network) ([]*unstructured.Unstructured, []appliedRevision, error) {
 	// Generate the objects
 	objs, err := network.Render(&networkAddonsConfig.Spec, ManifestPath, openshiftNetworkConfig, r.clusterInfo)
 	if err != nil {
 		log.Printf("failed to render: %v", err)
 		err = errors.Wrapf(err, "failed to render")
-		return objs, err
+		return objs, nil, err
 	}
 
 	// Perform any special object changes that are impossible to do with regular Apply. e.g. Remove outdated objects
 	// and objects that cannot be modified by Apply method due to incompatible changes.
 	if err := network.SpecialCleanUp(&networkAddonsConfig.Spec, r.client, r.clusterInfo); err != nil {
 		log.Printf("failed to Clean Up outdated objects: %v", err)
-		return objs, err
+		return objs, nil, err
+	}
+
+	// Record the applied configuration in the revision history, a new revision is created when
+	// the spec or the objects rendered from it change
+	history, err := getAppliedHistory(context.TODO(), r.client, networkAddonsConfig.Name, r.namespace)
+	if err != nil {
+		log.Printf("failed to retrieve applied revision history: %v", err)
+		err = errors.Wrapf(err, "failed to retrieve applied revision history")
+		return objs, nil, err
+	}
+	digest, err := renderedDigest(objs)
+	if err != nil {
+		log.Printf("failed to compute digest of rendered objects: %v", err)
+		err = errors.Wrapf(err, "failed to compute digest of rendered objects")
+		return objs, nil, err
 	}
+	history = appendAppliedRevision(history, &networkAddonsConfig.Spec, digest, time.Now())
 
 	// The first object we create should be the record of our applied configuration
-	applied, err := appliedConfiguration(networkAddonsConfig, r.namespace)
+	applied, err := appliedConfiguration(networkAddonsConfig, r.namespace, history)
 	if err != nil {
 		log.Printf("failed to render applied: %v", err)
 		err = errors.Wrapf(err, "failed to render applied")
-		return objs, err
+		return objs, nil, err
 	}
 	objs = append([]*unstructured.Unstructured{applied}, objs...)
 
@@ -362,16 +551,41 @@ func (r *ReconcileNetworkAddonsConfig) renderObjectsV1(networkAddonsConfig *cnao
 	if err != nil {
 		log.Printf("failed to update objects labels: %v", err)
 		err = errors.Wrapf(err, "failed to update objects labels")
-		return objs, err
+		return objs, nil, err
 	}
 
-	return objs, nil
+	return objs, history, nil
+}
+
+// Record that revisions replaced by the applied one were cleaned up
+func (r *ReconcileNetworkAddonsConfig) completeAppliedRevisions(networkAddonsConfigStorageVersion metav1.Object, networkAddonsConfig *cnao.NetworkAddonsConfig, history []appliedRevision) error {
+	if !history[len(history)-1].Pending {
+		return nil
+	}
+
+	applied, err := appliedConfiguration(networkAddonsConfig, r.namespace, completeAppliedRevisions(history))
+	if err != nil {
+		log.Printf("failed to render applied: %v", err)
+		return errors.Wrapf(err, "failed to render applied")
+	}
+	objs := []*unstructured.Unstructured{applied}
+	if err := updateObjectsLabels(networkAddonsConfig.GetLabels(), objs); err != nil {
+		log.Printf("failed to update applied labels: %v", err)
+		return errors.Wrapf(err, "failed to update applied labels")
+	}
+	return r.applyObjects(networkAddonsConfigStorageVersion, objs)
 }
 
 // Validate and returns the previous configuration spec
 func (r *ReconcileNetworkAddonsConfig) getPreviousConfigSpec(networkAddonsConfig *cnao.NetworkAddonsConfig) (*cnao.NetworkAddonsConfigSpec, error) {
+	return getPreviousConfigSpec(context.TODO(), r.client, r.namespace, networkAddonsConfig.ObjectMeta.Name, &networkAddonsConfig.Spec)
+}
+
+// getPreviousConfigSpec retrieves the previously applied configuration, fills defaults of spec
+// based on it and checks whether the change from it to spec is safe
+func getPreviousConfigSpec(ctx context.Context, c k8sclient.Client, namespace, name string, spec *cnao.NetworkAddonsConfigSpec) (*cnao.NetworkAddonsConfigSpec, error) {
 	// Retrieve the previously applied operator configuration
-	prev, err := getAppliedConfiguration(context.TODO(), r.client, networkAddonsConfig.ObjectMeta.Name, r.namespace)
+	prev, err := getAppliedConfiguration(ctx, c, name, namespace)
 	if err != nil {
 		log.Printf("failed to retrieve previously applied configuration: %v", err)
 		err = errors.Wrapf(err, "failed to retrieve previously applied configuration")
@@ -379,7 +593,7 @@ func (r *ReconcileNetworkAddonsConfig) getPreviousConfigSpec(networkAddonsConfig
 	}
 
 	// Fill all defaults explicitly
-	if err := network.FillDefaults(&networkAddonsConfig.Spec, prev); err != nil {
+	if err := network.FillDefaults(spec, prev); err != nil {
 		log.Printf("failed to fill defaults: %v", err)
 		err = errors.Wrapf(err, "failed to fill defaults")
 		return nil, err
@@ -390,7 +604,7 @@ func (r *ReconcileNetworkAddonsConfig) getPreviousConfigSpec(networkAddonsConfig
 	if prev != nil {
 		// We may need to fill defaults here -- sort of as a poor-man's
 		// upconversion scheme -- if we add additional fields to the config.
-		err = network.IsChangeSafe(prev, &networkAddonsConfig.Spec)
+		err = network.IsChangeSafe(prev, spec)
 		if err != nil {
 			log.Printf("not applying unsafe change: %v", err)
 			err = errors.Wrapf(err, "not applying unsafe change")
@@ -401,13 +615,17 @@ func (r *ReconcileNetworkAddonsConfig) getPreviousConfigSpec(networkAddonsConfig
 	return prev, nil
 }
 
-// Generate the removal object list
-func (r *ReconcileNetworkAddonsConfig) renderObjectsToDelete(networkAddonsConfig *cnao.NetworkAddonsConfig, openshiftNetworkConfig *osv1.Network, prev *cnao.NetworkAddonsConfigSpec) ([]*unstructured.Unstructured, error) {
-	objsToRemove, err := network.RenderObjsToRemove(prev, &networkAddonsConfig.Spec, ManifestPath, openshiftNetworkConfig, r.clusterInfo)
-	if err != nil {
-		log.Printf("failed to render for removal: %v", err)
-		err = errors.Wrapf(err, "failed to render for removal")
-		return objsToRemove, err
+// Generate the removal object list of all replaced configurations
+func (r *ReconcileNetworkAddonsConfig) renderObjectsToDelete(networkAddonsConfig *cnao.NetworkAddonsConfig, openshiftNetworkConfig *osv1.Network, prevs []*cnao.NetworkAddonsConfigSpec, objs []*unstructured.Unstructured) ([]*unstructured.Unstructured, error) {
+	objsToRemove := []*unstructured.Unstructured{}
+	for _, prev := range prevs {
+		prevObjsToRemove, err := network.RenderObjsToRemove(prev, &networkAddonsConfig.Spec, objs, ManifestPath, openshiftNetworkConfig, r.clusterInfo)
+		if err != nil {
+			log.Printf("failed to render for removal: %v", err)
+			err = errors.Wrapf(err, "failed to render for removal")
+			return objsToRemove, err
+		}
+		objsToRemove = append(objsToRemove, prevObjsToRemove...)
 	}
 
 	return objsToRemove, nil
@@ -417,6 +635,16 @@ func (r *ReconcileNetworkAddonsConfig) renderObjectsToDelete(networkAddonsConfig
 // are removed when NetworkAddonsConfig config is
 func (r *ReconcileNetworkAddonsConfig) applyObjects(networkAddonsConfig metav1.Object, objs []*unstructured.Unstructured) error {
 	for _, obj := range objs {
+		// Leave namespaces the operator did not create as they are, they belong to the administrator
+		isForeign, err := isForeignNamespace(context.TODO(), r.client, obj)
+		if err != nil {
+			log.Printf("could not check ownership of (%s) %s: %v", obj.GroupVersionKind(), obj.GetName(), err)
+			return errors.Wrapf(err, "could not check ownership of (%s) %s", obj.GroupVersionKind(), obj.GetName())
+		}
+		if isForeign {
+			continue
+		}
+
 		// Mark the object to be GC'd if the owner is deleted.
 		// Don't set owner reference on namespaces if they are used by the operator itself
 		// Don't set owner reference on CRDs, they should survive removal of the operator
@@ -442,6 +670,51 @@ func (r *ReconcileNetworkAddonsConfig) applyObjects(networkAddonsConfig metav1.O
 	return nil
 }
 
+// movedWorkloadsAvailable checks whether all rendered DaemonSets and Deployments in the given
+// namespaces, which components were moved to, are available
+func (r *ReconcileNetworkAddonsConfig) movedWorkloadsAvailable(namespaces []string, objs []*unstructured.Unstructured) (bool, error) {
+	moved := map[string]bool{}
+	for _, namespace := range namespaces {
+		moved[namespace] = true
+	}
+
+	for _, obj := range objs {
+		if obj.GetAPIVersion() != "apps/v1" || !moved[obj.GetNamespace()] {
+			continue
+		}
+		key := types.NamespacedName{Namespace: obj.GetNamespace(), Name: obj.GetName()}
+
+		var available bool
+		switch obj.GetKind() {
+		case "DaemonSet":
+			ds := &appsv1.DaemonSet{}
+			if err := r.client.Get(context.TODO(), key, ds); err != nil {
+				if apierrors.IsNotFound(err) {
+					return false, nil
+				}
+				return false, errors.Wrapf(err, "could not get DaemonSet %s", key)
+			}
+			available = statusmanager.DaemonSetAvailable(ds)
+		case "Deployment":
+			dep := &appsv1.Deployment{}
+			if err := r.client.Get(context.TODO(), key, dep); err != nil {
+				if apierrors.IsNotFound(err) {
+					return false, nil
+				}
+				return false, errors.Wrapf(err, "could not get Deployment %s", key)
+			}
+			available = statusmanager.DeploymentAvailable(dep)
+		default:
+			continue
+		}
+		if !available {
+			log.Printf("moved %s %s is not available yet", obj.GetKind(), key)
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
 // Delete removed objects
 func (r *ReconcileNetworkAddonsConfig) deleteOwnedObjects(objs []*unstructured.Unstructured) error {
 	for _, obj := range objs {
@@ -466,30 +739,20 @@ func (r *ReconcileNetworkAddonsConfig) trackDeployedObjects(objs []*unstructured
 	for _, obj := range objs {
 		if obj.GetAPIVersion() == "apps/v1" && obj.GetKind() == "DaemonSet" {
 			daemonSets = append(daemonSets, types.NamespacedName{Namespace: obj.GetNamespace(), Name: obj.GetName()})
-
-			daemonSet, err := unstructuredToDaemonSet(obj)
-			if err != nil {
-				log.Printf("Failed to detect images used in DaemonSet %q: %v", obj.GetName(), err)
-				continue
-			}
-
-			containers = append(containers, collectContainersInfo(obj.GetKind(), daemonSet.GetName(), daemonSet.Spec.Template.Spec.InitContainers)...)
-			containers = append(containers, collectContainersInfo(obj.GetKind(), daemonSet.GetName(), daemonSet.Spec.Template.Spec.Containers)...)
 		} else if obj.GetAPIVersion() == "apps/v1" && obj.GetKind() == "Deployment" {
 			deployments = append(deployments, types.NamespacedName{Namespace: obj.GetNamespace(), Name: obj.GetName()})
+		}
 
-			deployment, err := unstructuredToDeployment(obj)
-			if err != nil {
-				log.Printf("Failed to detect images used in Deployment %q: %v", obj.GetName(), err)
-				continue
-			}
-
-			containers = append(containers, collectContainersInfo(obj.GetKind(), deployment.GetName(), deployment.Spec.Template.Spec.InitContainers)...)
-			containers = append(containers, collectContainersInfo(obj.GetKind(), deployment.GetName(), deployment.Spec.Template.Spec.Containers)...)
+		objContainers, err := network.ObjectContainers(obj)
+		if err != nil {
+			log.Printf("Failed to detect images used in %s %q: %v", obj.GetKind(), obj.GetName(), err)
+			continue
 		}
+		containers = append(containers, objContainers...)
 	}
 
 	r.statusManager.SetAttributes(daemonSets, deployments, containers, generation)
+	r.statusManager.SetRelatedObjects(objs)
 
 	allResources := []types.NamespacedName{}
 	allResources = append(allResources, daemonSets...)
@@ -504,6 +767,9 @@ func (r *ReconcileNetworkAddonsConfig) stopTrackingObjects() {
 	r.statusManager.SetAttributes([]types.NamespacedName{}, []types.NamespacedName{}, []cnao.Container{}, -1)
 
 	r.podReconciler.SetResources([]types.NamespacedName{})
+	r.trackHostCleanups([]types.NamespacedName{})
+	r.statusManager.SetCNIDirectories(nil)
+	r.statusManager.SetRelatedObjects(nil)
 
 	// Trigger status manager to notice the change
 	r.statusManager.SetFromPods()
@@ -572,21 +838,6 @@ func updateObjectTemplateLabels(obj *unstructured.Unstructured, labels map[strin
 	return nil
 }
 
-func collectContainersInfo(parentKind string, parentName string, containers []v1.Container) []cnao.Container {
-	containersInfo := []cnao.Container{}
-
-	for _, container := range containers {
-		containersInfo = append(containersInfo, cnao.Container{
-			ParentKind: parentKind,
-			ParentName: parentName,
-			Image:      container.Image,
-			Name:       container.Name,
-		})
-	}
-
-	return containersInfo
-}
-
 func getOpenShiftNetworkConfig(ctx context.Context, c k8sclient.Client) (*osv1.Network, error) {
 	nc := &osv1.Network{}
 
@@ -609,10 +860,35 @@ func isRunningOnOpenShift4(c kubernetes.Interface) (bool, error) {
 	return isResourceAvailable(c, "networks", "operator.openshift.io", "v1")
 }
 
+// detectCNIDirectories looks for CNI directories of the Kubernetes distribution running on the cluster. It is
+// repeated on each reconcile, so nodes joining the cluster later are considered. Directories detected before
+// are kept if the detection fails, so components are not moved between directories of different nodes.
+func (r *ReconcileNetworkAddonsConfig) detectCNIDirectories() {
+	nodes := &v1.NodeList{}
+	if err := r.client.List(context.TODO(), nodes); err != nil {
+		log.Printf("failed to list nodes for CNI directories detection: %v", err)
+		return
+	}
+
+	cniDirectories, distribution, err := network.DetectCNIDirectories(nodes.Items)
+	if err != nil {
+		log.Printf("failed to detect CNI directories, keeping the previous ones: %v", err)
+		return
+	}
+	if cniDirectories != nil && !reflect.DeepEqual(cniDirectories, r.clusterInfo.CNIDirectories) {
+		log.Printf("Running on %s, using CNI configuration directory %s and binary directory %s", distribution, cniDirectories.ConfigDir, cniDirectories.BinDir)
+	}
+	r.clusterInfo.CNIDirectories = cniDirectories
+}
+
 func isSCCAvailable(c kubernetes.Interface) (bool, error) {
 	return isResourceAvailable(c, "securitycontextconstraints", "security.openshift.io", "v1")
 }
 
+func isProxyConfigAvailable(c kubernetes.Interface) (bool, error) {
+	return isResourceAvailable(c, "proxies", "config.openshift.io", "v1")
+}
+
 // isMonitoringAvailable checks if we can deploy the monitoring component
 func IsMonitoringAvailable(c kubernetes.Interface) (bool, error) {
 	prometheusRuleResourceAvailable, err := isResourceAvailable(c, "customresourcedefinitions/prometheusrules.monitoring.coreos.com", "apiextensions.k8s.io", "v1")
@@ -661,22 +937,6 @@ func runtimeObjectToNetworkAddonsConfig(obj runtime.Object) (*cnao.NetworkAddons
 	return networkAddonsConfig, nil
 }
 
-func unstructuredToDaemonSet(obj *unstructured.Unstructured) (*appsv1.DaemonSet, error) {
-	daemonSet := &appsv1.DaemonSet{}
-	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, daemonSet); err != nil {
-		return nil, err
-	}
-	return daemonSet, nil
-}
-
-func unstructuredToDeployment(obj *unstructured.Unstructured) (*appsv1.Deployment, error) {
-	deployment := &appsv1.Deployment{}
-	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, deployment); err != nil {
-		return nil, err
-	}
-	return deployment, nil
-}
-
 func isOpenshiftSingleReplica(c k8sclient.Client) (bool, error) {
 	infraConfig := &osconfv1.Infrastructure{}
 	if err := c.Get(context.TODO(), types.NamespacedName{Name: "cluster"}, infraConfig); err != nil {
@@ -686,7 +946,75 @@ func isOpenshiftSingleReplica(c k8sclient.Client) (bool, error) {
 	return infraConfig.Status.InfrastructureTopology == osconfv1.SingleReplicaTopologyMode, nil
 }
 
+// proxyPredicate filters out updates of the cluster-wide proxy not changing the effective configuration.
+// The effective proxy is reported in the status, so the generation cannot be relied on.
+var proxyPredicate = predicate.Funcs{
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		oldProxy, oldOk := e.ObjectOld.(*osconfv1.Proxy)
+		newProxy, newOk := e.ObjectNew.(*osconfv1.Proxy)
+		if !oldOk || !newOk {
+			return true
+		}
+		return !reflect.DeepEqual(oldProxy.Status, newProxy.Status) || oldProxy.Spec.TrustedCA != newProxy.Spec.TrustedCA
+	},
+}
+
+// requestForConfig requests reconciliation of the NetworkAddonsConfig
+func requestForConfig(client.Object) []reconcile.Request {
+	return []reconcile.Request{{NamespacedName: types.NamespacedName{Name: names.OPERATOR_CONFIG}}}
+}
+
+// getOpenShiftProxy reads the effective cluster-wide proxy configuration, nil is returned when no proxy is
+// configured. If the cluster sets a trusted CA, its bundle is injected into a ConfigMap rendered by the operator.
+func getOpenShiftProxy(c k8sclient.Client) (*cnao.Proxy, error) {
+	proxyConfig := &osconfv1.Proxy{}
+	if err := c.Get(context.TODO(), types.NamespacedName{Name: "cluster"}, proxyConfig); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	proxy := &cnao.Proxy{
+		HTTPProxy:  proxyConfig.Status.HTTPProxy,
+		HTTPSProxy: proxyConfig.Status.HTTPSProxy,
+		NoProxy:    proxyConfig.Status.NoProxy,
+	}
+	if proxyConfig.Spec.TrustedCA.Name != "" {
+		proxy.TrustedCA = names.TRUSTED_CA_BUNDLE_CONFIGMAP
+	}
+	if *proxy == (cnao.Proxy{}) {
+		return nil, nil
+	}
+	return proxy, nil
+}
+
 func isOperatorNamespace(obj *unstructured.Unstructured) bool {
 	const namespaceKind = "Namespace"
 	return obj.GetKind() == namespaceKind && obj.GetName() == operatorNamespace
 }
+
+// isForeignNamespace checks whether the object is an existing namespace not created by the operator,
+// e.g. a namespace prepared by the cluster administrator for components. The operator namespace is
+// never foreign, it is maintained by the operator without an owner.
+func isForeignNamespace(ctx context.Context, c k8sclient.Client, obj *unstructured.Unstructured) (bool, error) {
+	const namespaceKind = "Namespace"
+	if obj.GetKind() != namespaceKind || isOperatorNamespace(obj) {
+		return false, nil
+	}
+
+	existing := &v1.Namespace{}
+	err := c.Get(ctx, types.NamespacedName{Name: obj.GetName()}, existing)
+	if apierrors.IsNotFound(err) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	for _, owner := range existing.GetOwnerReferences() {
+		if owner.Kind == "NetworkAddonsConfig" {
+			return false, nil
+		}
+	}
+	return true, nil
+}
@@ -1,15 +1,24 @@
 package networkaddonsconfig
 
 import (
+	"context"
 	"fmt"
 
+	osconfv1 "github.com/openshift/api/config/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
+	cnao "github.com/kubevirt/cluster-network-addons-operator/pkg/apis/networkaddonsoperator/shared"
 	"github.com/kubevirt/cluster-network-addons-operator/pkg/names"
 	"github.com/kubevirt/cluster-network-addons-operator/pkg/render"
+	"github.com/kubevirt/cluster-network-addons-operator/pkg/util/k8s"
 )
 
 type checkUnit struct {
@@ -161,3 +170,74 @@ func checkLabels(labels map[string]string, units []checkUnit) {
 		}
 	}
 }
+
+var _ = Describe("Namespace ownership", func() {
+	var client k8sclient.Client
+	BeforeEach(func() {
+		owned := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+			Name:            "owned",
+			OwnerReferences: []metav1.OwnerReference{{APIVersion: "networkaddonsoperator.network.kubevirt.io/v1", Kind: "NetworkAddonsConfig", Name: names.OPERATOR_CONFIG, UID: "uid"}},
+		}}
+		foreign := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "foreign"}}
+		client = fake.NewClientBuilder().WithObjects(owned, foreign).Build()
+	})
+
+	namespace := func(name string) *unstructured.Unstructured {
+		return k8s.UnstructuredFromYaml(fmt.Sprintf("apiVersion: v1\nkind: Namespace\nmetadata:\n  name: %s", name))
+	}
+
+	It("Should not adopt existing namespaces created by others", func() {
+		Expect(isForeignNamespace(context.TODO(), client, namespace("foreign"))).To(BeTrue())
+	})
+
+	It("Should keep owning namespaces created by the operator", func() {
+		Expect(isForeignNamespace(context.TODO(), client, namespace("owned"))).To(BeFalse())
+	})
+
+	It("Should own namespaces it is about to create", func() {
+		Expect(isForeignNamespace(context.TODO(), client, namespace("missing"))).To(BeFalse())
+	})
+
+	It("Should keep maintaining the operator namespace", func() {
+		defer func(namespace string) { operatorNamespace = namespace }(operatorNamespace)
+		operatorNamespace = "foreign"
+		Expect(isForeignNamespace(context.TODO(), client, namespace("foreign"))).To(BeFalse())
+	})
+
+	It("Should ignore other kinds", func() {
+		configMap := k8s.UnstructuredFromYaml("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: foreign\n  namespace: foreign")
+		Expect(isForeignNamespace(context.TODO(), client, configMap)).To(BeFalse())
+	})
+})
+
+var _ = Describe("Cluster-wide proxy", func() {
+	newClient := func(proxies ...k8sclient.Object) k8sclient.Client {
+		scheme := runtime.NewScheme()
+		Expect(osconfv1.Install(scheme)).To(Succeed())
+		return fake.NewClientBuilder().WithScheme(scheme).WithObjects(proxies...).Build()
+	}
+
+	It("Should ignore a missing proxy", func() {
+		Expect(getOpenShiftProxy(newClient())).To(BeNil())
+	})
+
+	It("Should ignore a proxy without configuration", func() {
+		Expect(getOpenShiftProxy(newClient(&osconfv1.Proxy{ObjectMeta: metav1.ObjectMeta{Name: "cluster"}}))).To(BeNil())
+	})
+
+	It("Should not request the trusted CA bundle unless the proxy sets one", func() {
+		proxy := &osconfv1.Proxy{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+			Status:     osconfv1.ProxyStatus{HTTPProxy: "http://proxy:3128"},
+		}
+		Expect(getOpenShiftProxy(newClient(proxy))).To(Equal(&cnao.Proxy{HTTPProxy: "http://proxy:3128"}))
+	})
+
+	It("Should request the trusted CA bundle when the proxy sets one", func() {
+		proxy := &osconfv1.Proxy{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+			Spec:       osconfv1.ProxySpec{TrustedCA: osconfv1.ConfigMapNameReference{Name: "user-ca-bundle"}},
+		}
+		Expect(getOpenShiftProxy(newClient(proxy))).To(Equal(&cnao.Proxy{TrustedCA: names.TRUSTED_CA_BUNDLE_CONFIGMAP}))
+	})
+})
@@ -0,0 +1,130 @@
+package networkaddonsconfig
+
+import (
+	"context"
+	"log"
+	"reflect"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	cnaov1 "github.com/kubevirt/cluster-network-addons-operator/pkg/apis/networkaddonsoperator/v1"
+	"github.com/kubevirt/cluster-network-addons-operator/pkg/names"
+	"github.com/kubevirt/cluster-network-addons-operator/pkg/network"
+)
+
+// newOvsNodesReconciler returns a new reconcile.Reconciler
+func newOvsNodesReconciler(mgr manager.Manager) *ReconcileOvsNodes {
+	return &ReconcileOvsNodes{
+		client: mgr.GetClient(),
+	}
+}
+
+var _ reconcile.Reconciler = &ReconcileOvsNodes{}
+
+// ReconcileOvsNodes labels nodes running Open vSwitch, so ovs-cni can be scheduled only on them. Labels added
+// by the operator are removed once they are not needed, labels set by administrators are kept.
+type ReconcileOvsNodes struct {
+	client client.Client
+}
+
+// ovsNodesPredicate filters out node updates not affecting detection of Open vSwitch, such as heartbeats
+var ovsNodesPredicate = predicate.Funcs{
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		return !reflect.DeepEqual(e.ObjectOld.GetLabels(), e.ObjectNew.GetLabels()) ||
+			!reflect.DeepEqual(e.ObjectOld.GetAnnotations(), e.ObjectNew.GetAnnotations())
+	},
+	DeleteFunc: func(event.DeleteEvent) bool {
+		return false
+	},
+}
+
+// requestsForAllNodes requests reconciliation of all nodes, used once the NetworkAddonsConfig changes
+func (r *ReconcileOvsNodes) requestsForAllNodes(client.Object) []reconcile.Request {
+	nodes := &v1.NodeList{}
+	if err := r.client.List(context.TODO(), nodes); err != nil {
+		log.Printf("failed to list nodes for Open vSwitch detection: %v", err)
+		return nil
+	}
+	requests := make([]reconcile.Request, 0, len(nodes.Items))
+	for _, node := range nodes.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: node.GetName()}})
+	}
+	return requests
+}
+
+// Reconcile adds or removes the Open vSwitch label of the node
+func (r *ReconcileOvsNodes) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+	node := &v1.Node{}
+	if err := r.client.Get(ctx, request.NamespacedName, node); err != nil {
+		return reconcile.Result{}, client.IgnoreNotFound(err)
+	}
+
+	ovsNodesOnly, err := r.ovsNodesOnly(ctx)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	_, labeledByOperator := node.GetAnnotations()[names.OVS_NODE_LABELED_ANNOTATION]
+	labeled := node.GetLabels()[names.OVS_NODE_LABEL_KEY] == names.OVS_NODE_LABEL_VALUE
+	ovsNode := network.IsOvsNode(node)
+
+	patch := client.MergeFrom(node.DeepCopy())
+	switch {
+	case ovsNodesOnly && ovsNode && !labeled:
+		log.Printf("Labeling node %s as running Open vSwitch", node.GetName())
+		setNodeMetadata(node, names.OVS_NODE_LABEL_VALUE, "true")
+	case labeledByOperator && (!ovsNodesOnly || !ovsNode):
+		log.Printf("Removing Open vSwitch label from node %s", node.GetName())
+		setNodeMetadata(node, "", "")
+	default:
+		return reconcile.Result{}, nil
+	}
+
+	return reconcile.Result{}, r.client.Patch(ctx, node, patch)
+}
+
+// ovsNodesOnly checks whether ovs-cni is requested to be scheduled only on Open vSwitch nodes
+func (r *ReconcileOvsNodes) ovsNodesOnly(ctx context.Context) (bool, error) {
+	config := &cnaov1.NetworkAddonsConfig{}
+	if err := r.client.Get(ctx, types.NamespacedName{Name: names.OPERATOR_CONFIG}, config); err != nil {
+		if apierrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return config.GetDeletionTimestamp() == nil && config.Spec.Ovs != nil && config.Spec.Ovs.OvsNodesOnly, nil
+}
+
+// setNodeMetadata sets the Open vSwitch label and the annotation marking it as added by the operator, empty
+// values remove them
+func setNodeMetadata(node *v1.Node, label, annotation string) {
+	labels := node.GetLabels()
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	annotations := node.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+
+	if label == "" {
+		delete(labels, names.OVS_NODE_LABEL_KEY)
+	} else {
+		labels[names.OVS_NODE_LABEL_KEY] = label
+	}
+	if annotation == "" {
+		delete(annotations, names.OVS_NODE_LABELED_ANNOTATION)
+	} else {
+		annotations[names.OVS_NODE_LABELED_ANNOTATION] = annotation
+	}
+
+	node.SetLabels(labels)
+	node.SetAnnotations(annotations)
+}
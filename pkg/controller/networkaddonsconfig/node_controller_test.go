@@ -0,0 +1,111 @@
+package networkaddonsconfig
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	cnao "github.com/kubevirt/cluster-network-addons-operator/pkg/apis/networkaddonsoperator/shared"
+	cnaov1 "github.com/kubevirt/cluster-network-addons-operator/pkg/apis/networkaddonsoperator/v1"
+	"github.com/kubevirt/cluster-network-addons-operator/pkg/names"
+)
+
+var _ = Describe("Open vSwitch nodes reconciler", func() {
+	ovnAnnotations := map[string]string{"k8s.ovn.org/node-chassis-id": "id"}
+
+	var client k8sclient.Client
+	var r *ReconcileOvsNodes
+
+	newClient := func(ovsNodesOnly bool, nodes ...*corev1.Node) {
+		scheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		Expect(cnaov1.AddToScheme(scheme)).To(Succeed())
+		config := &cnaov1.NetworkAddonsConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: names.OPERATOR_CONFIG},
+			Spec:       cnao.NetworkAddonsConfigSpec{Ovs: &cnao.Ovs{OvsNodesOnly: ovsNodesOnly}},
+		}
+		builder := fake.NewClientBuilder().WithScheme(scheme).WithObjects(config)
+		for _, node := range nodes {
+			builder = builder.WithObjects(node)
+		}
+		client = builder.Build()
+		r = &ReconcileOvsNodes{client: client}
+	}
+
+	reconcileNode := func(name string) *corev1.Node {
+		_, err := r.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Name: name}})
+		Expect(err).ToNot(HaveOccurred())
+		node := &corev1.Node{}
+		Expect(client.Get(context.TODO(), types.NamespacedName{Name: name}, node)).To(Succeed())
+		return node
+	}
+
+	It("should label only nodes running Open vSwitch", func() {
+		newClient(true,
+			&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "ovn", Annotations: ovnAnnotations}},
+			&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "plain"}},
+		)
+		Expect(reconcileNode("ovn").GetLabels()).To(HaveKeyWithValue(names.OVS_NODE_LABEL_KEY, names.OVS_NODE_LABEL_VALUE))
+		Expect(reconcileNode("plain").GetLabels()).ToNot(HaveKey(names.OVS_NODE_LABEL_KEY))
+	})
+
+	It("should not label nodes unless ovs-cni is restricted to Open vSwitch nodes", func() {
+		newClient(false, &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "ovn", Annotations: ovnAnnotations}})
+		Expect(reconcileNode("ovn").GetLabels()).ToNot(HaveKey(names.OVS_NODE_LABEL_KEY))
+	})
+
+	It("should remove its label once the restriction is lifted", func() {
+		newClient(true, &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "ovn", Annotations: ovnAnnotations}})
+		reconcileNode("ovn")
+
+		config := &cnaov1.NetworkAddonsConfig{}
+		Expect(client.Get(context.TODO(), types.NamespacedName{Name: names.OPERATOR_CONFIG}, config)).To(Succeed())
+		config.Spec.Ovs.OvsNodesOnly = false
+		Expect(client.Update(context.TODO(), config)).To(Succeed())
+
+		node := reconcileNode("ovn")
+		Expect(node.GetLabels()).ToNot(HaveKey(names.OVS_NODE_LABEL_KEY))
+		Expect(node.GetAnnotations()).ToNot(HaveKey(names.OVS_NODE_LABELED_ANNOTATION))
+	})
+
+	It("should remove its label once the config is removed", func() {
+		newClient(true, &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "ovn", Annotations: ovnAnnotations}})
+		reconcileNode("ovn")
+
+		Expect(client.Delete(context.TODO(), &cnaov1.NetworkAddonsConfig{ObjectMeta: metav1.ObjectMeta{Name: names.OPERATOR_CONFIG}})).To(Succeed())
+		Expect(reconcileNode("ovn").GetLabels()).ToNot(HaveKey(names.OVS_NODE_LABEL_KEY))
+	})
+
+	It("should remove its label once the node stops running Open vSwitch", func() {
+		newClient(true, &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "ovn", Annotations: ovnAnnotations}})
+		node := reconcileNode("ovn")
+
+		delete(node.Annotations, "k8s.ovn.org/node-chassis-id")
+		Expect(client.Update(context.TODO(), node)).To(Succeed())
+		Expect(reconcileNode("ovn").GetLabels()).ToNot(HaveKey(names.OVS_NODE_LABEL_KEY))
+	})
+
+	It("should keep labels set by administrators", func() {
+		newClient(false, &corev1.Node{ObjectMeta: metav1.ObjectMeta{
+			Name:   "manual",
+			Labels: map[string]string{names.OVS_NODE_LABEL_KEY: names.OVS_NODE_LABEL_VALUE},
+		}})
+		Expect(reconcileNode("manual").GetLabels()).To(HaveKeyWithValue(names.OVS_NODE_LABEL_KEY, names.OVS_NODE_LABEL_VALUE))
+	})
+
+	It("should ignore removed nodes", func() {
+		newClient(true)
+		_, err := r.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Name: "removed"}})
+		Expect(err).ToNot(HaveOccurred())
+	})
+})
@@ -3,11 +3,16 @@ package networkaddonsconfig
 import (
 	"context"
 	"log"
+	"reflect"
 
+	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
+	"github.com/kubevirt/cluster-network-addons-operator/pkg/cnihealth"
 	"github.com/kubevirt/cluster-network-addons-operator/pkg/controller/statusmanager"
 	"github.com/kubevirt/cluster-network-addons-operator/pkg/eventemitter"
 )
@@ -26,6 +31,7 @@ var _ reconcile.Reconciler = &ReconcilePods{}
 type ReconcilePods struct {
 	statusManager *statusmanager.StatusManager
 	resources     []types.NamespacedName
+	hostCleanups  []types.NamespacedName
 	eventEmitter  eventemitter.EventEmitter
 }
 
@@ -34,10 +40,15 @@ func (r *ReconcilePods) SetResources(resources []types.NamespacedName) {
 	r.resources = resources
 }
 
+// SetHostCleanups updates DaemonSets removing host artifacts of removed components
+func (r *ReconcilePods) SetHostCleanups(hostCleanups []types.NamespacedName) {
+	r.hostCleanups = hostCleanups
+}
+
 // Reconcile updates the NetworkAddonsConfig.Status to match the current state of the
 // watched Deployments/DaemonSets
 func (r *ReconcilePods) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
-	for _, name := range r.resources {
+	for _, name := range append(append([]types.NamespacedName{}, r.resources...), r.hostCleanups...) {
 		if name.Namespace == request.Namespace && name.Name == request.Name {
 			log.Printf("Reconciling update to %s/%s\n", request.Namespace, request.Name)
 			r.eventEmitter.EmitModifiedForConfig()
@@ -47,3 +58,33 @@ func (r *ReconcilePods) Reconcile(ctx context.Context, request reconcile.Request
 	}
 	return reconcile.Result{}, nil
 }
+
+// cniHealthPredicate passes pods whose CNI health condition changes, their DaemonSet is reconciled then
+var cniHealthPredicate = predicate.Funcs{
+	CreateFunc: func(event.CreateEvent) bool {
+		return false
+	},
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		oldPod, oldOk := e.ObjectOld.(*v1.Pod)
+		newPod, newOk := e.ObjectNew.(*v1.Pod)
+		if !oldOk || !newOk {
+			return false
+		}
+		return !reflect.DeepEqual(cniHealthState(oldPod), cniHealthState(newPod))
+	},
+	DeleteFunc: func(event.DeleteEvent) bool {
+		return false
+	},
+	GenericFunc: func(event.GenericEvent) bool {
+		return false
+	},
+}
+
+// cniHealthState is the part of the CNI health condition the status is computed from, probe times are left out
+func cniHealthState(pod *v1.Pod) *v1.PodCondition {
+	condition := cnihealth.FindCondition(pod)
+	if condition == nil {
+		return nil
+	}
+	return &v1.PodCondition{Status: condition.Status, Reason: condition.Reason, Message: condition.Message}
+}
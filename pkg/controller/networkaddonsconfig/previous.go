@@ -2,7 +2,13 @@ package networkaddonsconfig
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
@@ -16,32 +22,171 @@ import (
 	k8sutil "github.com/kubevirt/cluster-network-addons-operator/pkg/util/k8s"
 )
 
-// GetAppliedConfiguration retrieves the configuration we applied.
-// Returns nil with no error if no previous configuration was observed.
-func getAppliedConfiguration(ctx context.Context, client k8sclient.Client, name string, namespace string) (*cnao.NetworkAddonsConfigSpec, error) {
+const (
+	// appliedKey keeps the last applied spec, it is read by operator versions without revision history
+	appliedKey = "applied"
+	// revisionsKey keeps the history of applied revisions
+	revisionsKey = "revisions"
+
+	// maxAppliedRevisions bounds the number of applied revisions kept in the history
+	maxAppliedRevisions = 10
+)
+
+// appliedRevision records a configuration applied by the operator
+type appliedRevision struct {
+	// Revision is increased with every change of the spec or of the rendered objects
+	Revision int64 `json:"revision"`
+	// Spec is the applied spec with all defaults filled
+	Spec cnao.NetworkAddonsConfigSpec `json:"spec"`
+	// OperatorVersion is the version of the operator which applied the revision
+	OperatorVersion string `json:"operatorVersion,omitempty"`
+	// Timestamp is the time the revision was applied at
+	Timestamp metav1.Time `json:"timestamp"`
+	// Digest identifies the objects rendered from the spec
+	Digest string `json:"digest,omitempty"`
+	// Pending is set until objects and host files of components removed or moved by the
+	// revision are cleaned up
+	Pending bool `json:"pending,omitempty"`
+}
+
+// getAppliedHistory retrieves the history of applied revisions, ordered from the oldest.
+// Returns an empty history with no error if no previous configuration was observed.
+func getAppliedHistory(ctx context.Context, client k8sclient.Client, name string, namespace string) ([]appliedRevision, error) {
 	cm := &corev1.ConfigMap{}
 	err := client.Get(ctx, types.NamespacedName{Name: names.APPLIED_PREFIX + name, Namespace: namespace}, cm)
 	if err != nil && apierrors.IsNotFound(err) {
-		return nil, nil
+		return []appliedRevision{}, nil
 	} else if err != nil {
 		return nil, err
 	}
 
-	spec := &cnao.NetworkAddonsConfigSpec{}
-	err = json.Unmarshal([]byte(cm.Data["applied"]), spec)
+	if revisions, found := cm.Data[revisionsKey]; found {
+		history := []appliedRevision{}
+		if err := json.Unmarshal([]byte(revisions), &history); err != nil {
+			return nil, err
+		}
+		return history, nil
+	}
+
+	// Configuration applied by an operator without revision history becomes the first revision
+	spec := cnao.NetworkAddonsConfigSpec{}
+	if err := json.Unmarshal([]byte(cm.Data[appliedKey]), &spec); err != nil {
+		return nil, err
+	}
+	return []appliedRevision{{Revision: 1, Spec: spec, Timestamp: cm.CreationTimestamp}}, nil
+}
+
+// GetAppliedConfiguration retrieves the configuration we applied.
+// Returns nil with no error if no previous configuration was observed.
+func getAppliedConfiguration(ctx context.Context, client k8sclient.Client, name string, namespace string) (*cnao.NetworkAddonsConfigSpec, error) {
+	history, err := getAppliedHistory(ctx, client, name, namespace)
 	if err != nil {
 		return nil, err
 	}
-	return spec, nil
+	if len(history) == 0 {
+		return nil, nil
+	}
+	return &history[len(history)-1].Spec, nil
+}
+
+// getAppliedRevision looks the given revision up in the history
+func getAppliedRevision(history []appliedRevision, revision int64) (*appliedRevision, error) {
+	available := []int64{}
+	for i := range history {
+		if history[i].Revision == revision {
+			return &history[i], nil
+		}
+		available = append(available, history[i].Revision)
+	}
+	return nil, fmt.Errorf("revision %d was not found in the applied history, available revisions are %v", revision, available)
+}
+
+// appendAppliedRevision records the spec and the digest of objects rendered from it in the history
+// unless they match the latest revision. A new revision is pending until objects of the revisions it
+// replaces are cleaned up. The oldest revisions are dropped to keep the history bounded.
+func appendAppliedRevision(history []appliedRevision, spec *cnao.NetworkAddonsConfigSpec, digest string, now time.Time) []appliedRevision {
+	if len(history) > 0 && reflect.DeepEqual(history[len(history)-1].Spec, *spec) && history[len(history)-1].Digest == digest {
+		return history
+	}
+
+	revision := int64(1)
+	if len(history) > 0 {
+		revision = history[len(history)-1].Revision + 1
+	}
+	history = append(history, appliedRevision{
+		Revision:        revision,
+		Spec:            *spec.DeepCopy(),
+		OperatorVersion: operatorVersion,
+		Timestamp:       metav1.NewTime(now),
+		Digest:          digest,
+		Pending:         len(history) > 0,
+	})
+
+	if len(history) > maxAppliedRevisions {
+		history = history[len(history)-maxAppliedRevisions:]
+	}
+	return history
+}
+
+// replacedRevisionSpecs returns specs of revisions whose objects may be left behind by the
+// pending latest revision: the last revision cleaned up and every pending one since
+func replacedRevisionSpecs(history []appliedRevision) []*cnao.NetworkAddonsConfigSpec {
+	specs := []*cnao.NetworkAddonsConfigSpec{}
+	if len(history) == 0 || !history[len(history)-1].Pending {
+		return specs
+	}
+	for i := len(history) - 2; i >= 0; i-- {
+		specs = append(specs, &history[i].Spec)
+		if !history[i].Pending {
+			break
+		}
+	}
+	return specs
+}
+
+// completeAppliedRevisions marks all revisions in the history as cleaned up
+func completeAppliedRevisions(history []appliedRevision) []appliedRevision {
+	completed := make([]appliedRevision, len(history))
+	for i := range history {
+		completed[i] = history[i]
+		completed[i].Pending = false
+	}
+	return completed
+}
+
+// renderedDigest identifies the rendered objects. It does not depend on the order of the objects,
+// objects rendered again from the same spec on the same cluster have the same digest.
+func renderedDigest(objs []*uns.Unstructured) (string, error) {
+	encoded := make([]string, 0, len(objs))
+	for _, obj := range objs {
+		// keys of unstructured objects are sorted when encoded
+		data, err := json.Marshal(obj.Object)
+		if err != nil {
+			return "", err
+		}
+		encoded = append(encoded, string(data))
+	}
+	sort.Strings(encoded)
+
+	hash := sha256.New()
+	for _, data := range encoded {
+		hash.Write([]byte(data))
+		hash.Write([]byte{'\n'})
+	}
+	return "sha256:" + hex.EncodeToString(hash.Sum(nil)), nil
 }
 
 // AppliedConfiguration renders the ConfigMap in which we store the configuration
-// we've applied.
-func appliedConfiguration(applied *cnao.NetworkAddonsConfig, namespace string) (*uns.Unstructured, error) {
+// we've applied together with the history of previously applied revisions.
+func appliedConfiguration(applied *cnao.NetworkAddonsConfig, namespace string, history []appliedRevision) (*uns.Unstructured, error) {
 	app, err := json.Marshal(applied.Spec)
 	if err != nil {
 		return nil, err
 	}
+	revisions, err := json.Marshal(history)
+	if err != nil {
+		return nil, err
+	}
 	cm := &corev1.ConfigMap{
 		TypeMeta: metav1.TypeMeta{
 			APIVersion: "v1",
@@ -52,7 +197,8 @@ func appliedConfiguration(applied *cnao.NetworkAddonsConfig, namespace string) (
 			Namespace: namespace,
 		},
 		Data: map[string]string{
-			"applied": string(app),
+			appliedKey:   string(app),
+			revisionsKey: string(revisions),
 		},
 	}
 
@@ -0,0 +1,176 @@
+package networkaddonsconfig
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	uns "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	cnao "github.com/kubevirt/cluster-network-addons-operator/pkg/apis/networkaddonsoperator/shared"
+	"github.com/kubevirt/cluster-network-addons-operator/pkg/names"
+	"github.com/kubevirt/cluster-network-addons-operator/pkg/network"
+)
+
+var _ = Describe("Applied revision history", func() {
+	const namespace = "cnao"
+	now := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// digestOf stands for the digest of objects rendered from the spec
+	digestOf := func(spec *cnao.NetworkAddonsConfigSpec) string {
+		return "sha256:" + string(spec.ImagePullPolicy) + spec.CNIBinDir
+	}
+	appendRevision := func(history []appliedRevision, spec *cnao.NetworkAddonsConfigSpec, now time.Time) []appliedRevision {
+		return appendAppliedRevision(history, spec, digestOf(spec), now)
+	}
+
+	Context("when a configuration is applied", func() {
+		alwaysSpec := &cnao.NetworkAddonsConfigSpec{ImagePullPolicy: corev1.PullAlways}
+		neverSpec := &cnao.NetworkAddonsConfigSpec{ImagePullPolicy: corev1.PullNever}
+
+		It("should start the history with the first revision", func() {
+			history := appendRevision([]appliedRevision{}, alwaysSpec, now)
+			Expect(history).To(HaveLen(1))
+			Expect(history[0].Revision).To(BeEquivalentTo(1))
+			Expect(history[0].Spec).To(Equal(*alwaysSpec))
+			Expect(history[0].Digest).To(Equal(digestOf(alwaysSpec)))
+			Expect(history[0].Pending).To(BeFalse())
+			Expect(history[0].Timestamp.Time).To(Equal(now))
+		})
+
+		It("should keep the history when nothing changed", func() {
+			history := appendRevision([]appliedRevision{}, alwaysSpec, now)
+			Expect(appendRevision(history, alwaysSpec.DeepCopy(), now.Add(time.Minute))).To(Equal(history))
+		})
+
+		It("should create a pending revision when the spec changes", func() {
+			history := appendRevision([]appliedRevision{}, alwaysSpec, now)
+			history = appendRevision(history, neverSpec, now.Add(time.Minute))
+			Expect(history).To(HaveLen(2))
+			Expect(history[1].Revision).To(BeEquivalentTo(2))
+			Expect(history[1].Spec).To(Equal(*neverSpec))
+			Expect(history[1].Digest).NotTo(Equal(history[0].Digest))
+			Expect(history[1].Pending).To(BeTrue())
+		})
+
+		It("should create a revision when objects rendered from the same spec change", func() {
+			history := appendRevision([]appliedRevision{}, alwaysSpec, now)
+			history = appendAppliedRevision(history, alwaysSpec, "sha256:upgraded", now.Add(time.Minute))
+			Expect(history).To(HaveLen(2))
+			Expect(history[1].Spec).To(Equal(*alwaysSpec))
+			Expect(history[1].Digest).To(Equal("sha256:upgraded"))
+		})
+
+		It("should clean up after all revisions since the last cleaned up one", func() {
+			history := appendRevision([]appliedRevision{}, alwaysSpec, now)
+			Expect(replacedRevisionSpecs(history)).To(BeEmpty())
+
+			history = appendRevision(history, neverSpec, now.Add(time.Minute))
+			Expect(replacedRevisionSpecs(history)).To(Equal([]*cnao.NetworkAddonsConfigSpec{&history[0].Spec}))
+
+			// Cleanup of the second revision failed and the spec changed again
+			history = appendRevision(history, alwaysSpec, now.Add(2*time.Minute))
+			Expect(replacedRevisionSpecs(history)).To(Equal([]*cnao.NetworkAddonsConfigSpec{&history[1].Spec, &history[0].Spec}))
+
+			history = completeAppliedRevisions(history)
+			Expect(replacedRevisionSpecs(history)).To(BeEmpty())
+			history = appendRevision(history, neverSpec, now.Add(3*time.Minute))
+			Expect(replacedRevisionSpecs(history)).To(Equal([]*cnao.NetworkAddonsConfigSpec{&history[2].Spec}))
+		})
+
+		It("should drop the oldest revisions when the history is full", func() {
+			history := []appliedRevision{}
+			for i := 0; i < maxAppliedRevisions+5; i++ {
+				spec := &cnao.NetworkAddonsConfigSpec{CNIBinDir: time.Duration(i).String()}
+				history = appendRevision(history, spec, now)
+			}
+			Expect(history).To(HaveLen(maxAppliedRevisions))
+			Expect(history[0].Revision).To(BeEquivalentTo(6))
+			Expect(history[maxAppliedRevisions-1].Revision).To(BeEquivalentTo(maxAppliedRevisions + 5))
+		})
+	})
+
+	Context("when the digest of rendered objects is computed", func() {
+		render := func(spec *cnao.NetworkAddonsConfigSpec) []*uns.Unstructured {
+			objs, err := network.Render(spec, "../../../data", nil, &network.ClusterInfo{})
+			ExpectWithOffset(1, err).NotTo(HaveOccurred())
+			return objs
+		}
+
+		var spec *cnao.NetworkAddonsConfigSpec
+		BeforeEach(func() {
+			spec = &cnao.NetworkAddonsConfigSpec{LinuxBridge: &cnao.LinuxBridge{}, KubeMacPool: &cnao.KubeMacPool{}, MacvtapCni: &cnao.MacvtapCni{}}
+			Expect(network.FillDefaults(spec, nil)).To(Succeed())
+		})
+
+		It("should not change when the spec is rendered again", func() {
+			digest, err := renderedDigest(render(spec))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(digest).To(HavePrefix("sha256:"))
+
+			objs := render(spec.DeepCopy())
+			for i, j := 0, len(objs)-1; i < j; i, j = i+1, j-1 {
+				objs[i], objs[j] = objs[j], objs[i]
+			}
+			Expect(renderedDigest(objs)).To(Equal(digest))
+		})
+
+		It("should change with the rendered objects", func() {
+			digest, err := renderedDigest(render(spec))
+			Expect(err).NotTo(HaveOccurred())
+
+			spec.LinuxBridge.BridgeMarker.UpdateInterval = "2m"
+			Expect(renderedDigest(render(spec))).NotTo(Equal(digest))
+		})
+	})
+
+	Context("when the history is stored", func() {
+		It("should read the stored history and the last applied configuration", func() {
+			history := appendRevision([]appliedRevision{}, &cnao.NetworkAddonsConfigSpec{ImagePullPolicy: corev1.PullAlways}, now)
+			history = appendRevision(history, &cnao.NetworkAddonsConfigSpec{ImagePullPolicy: corev1.PullNever}, now)
+			config := &cnao.NetworkAddonsConfig{
+				ObjectMeta: metav1.ObjectMeta{Name: names.OPERATOR_CONFIG},
+				Spec:       history[1].Spec,
+			}
+			obj, err := appliedConfiguration(config, namespace, history)
+			Expect(err).NotTo(HaveOccurred())
+			client := fake.NewClientBuilder().WithObjects(obj).Build()
+
+			stored, err := getAppliedHistory(context.TODO(), client, names.OPERATOR_CONFIG, namespace)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(stored).To(HaveLen(2))
+			Expect(stored[1].Spec).To(Equal(history[1].Spec))
+
+			applied, err := getAppliedConfiguration(context.TODO(), client, names.OPERATOR_CONFIG, namespace)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(*applied).To(Equal(history[1].Spec))
+		})
+
+		It("should treat configuration applied without history as the first revision", func() {
+			applied, err := json.Marshal(cnao.NetworkAddonsConfigSpec{ImagePullPolicy: corev1.PullAlways})
+			Expect(err).NotTo(HaveOccurred())
+			client := fake.NewClientBuilder().WithObjects(&corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: names.APPLIED_PREFIX + names.OPERATOR_CONFIG, Namespace: namespace},
+				Data:       map[string]string{"applied": string(applied)},
+			}).Build()
+
+			history, err := getAppliedHistory(context.TODO(), client, names.OPERATOR_CONFIG, namespace)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(history).To(HaveLen(1))
+			Expect(history[0].Revision).To(BeEquivalentTo(1))
+			Expect(history[0].Spec.ImagePullPolicy).To(Equal(corev1.PullAlways))
+		})
+
+		It("should return an empty history when nothing was applied", func() {
+			history, err := getAppliedHistory(context.TODO(), fake.NewClientBuilder().Build(), names.OPERATOR_CONFIG, namespace)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(history).To(BeEmpty())
+		})
+	})
+})
@@ -0,0 +1,167 @@
+package networkaddonsconfig
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	osconfv1 "github.com/openshift/api/config/v1"
+	osv1 "github.com/openshift/api/operator/v1"
+	conditionsv1 "github.com/openshift/custom-resource-status/conditions/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	cnao "github.com/kubevirt/cluster-network-addons-operator/pkg/apis/networkaddonsoperator/shared"
+	cnaov1 "github.com/kubevirt/cluster-network-addons-operator/pkg/apis/networkaddonsoperator/v1"
+	"github.com/kubevirt/cluster-network-addons-operator/pkg/faultinjection"
+	"github.com/kubevirt/cluster-network-addons-operator/pkg/names"
+	"github.com/kubevirt/cluster-network-addons-operator/pkg/network"
+)
+
+// fakeManager provides the reconciler with clients of a fake API server, other methods are not used
+type fakeManager struct {
+	manager.Manager
+	client client.Client
+}
+
+func (m *fakeManager) GetClient() client.Client    { return m.client }
+func (m *fakeManager) GetAPIReader() client.Reader { return m.client }
+func (m *fakeManager) GetScheme() *runtime.Scheme  { return m.client.Scheme() }
+func (m *fakeManager) GetEventRecorderFor(string) record.EventRecorder {
+	return record.NewFakeRecorder(1000)
+}
+
+var _ = Describe("Reconcile with injected faults", func() {
+	const (
+		namespace = "cluster-network-addons"
+		// reconcileAttempts bounds requeues of a failing reconciliation
+		reconcileAttempts = 20
+	)
+	configKey := types.NamespacedName{Name: names.OPERATOR_CONFIG}
+	bridgeMarkerKey := types.NamespacedName{Namespace: namespace, Name: "bridge-marker"}
+	macvtapKey := types.NamespacedName{Namespace: namespace, Name: "macvtap-cni"}
+
+	var base client.Client
+
+	BeforeEach(func() {
+		// The reconciler renders templates relative to the root directory
+		workingDir, err := os.Getwd()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(os.Chdir("../../..")).To(Succeed())
+		DeferCleanup(os.Chdir, workingDir)
+		for _, env := range []string{"OPERATOR_NAMESPACE", "OPERAND_NAMESPACE"} {
+			if previous, found := os.LookupEnv(env); found {
+				DeferCleanup(os.Setenv, env, previous)
+			} else {
+				DeferCleanup(os.Unsetenv, env)
+			}
+			Expect(os.Setenv(env, namespace)).To(Succeed())
+		}
+
+		scheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		Expect(cnaov1.AddToScheme(scheme)).To(Succeed())
+		Expect(osv1.Install(scheme)).To(Succeed())
+		Expect(osconfv1.Install(scheme)).To(Succeed())
+		config := &cnaov1.NetworkAddonsConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: names.OPERATOR_CONFIG, Generation: 1},
+			Spec:       cnao.NetworkAddonsConfigSpec{LinuxBridge: &cnao.LinuxBridge{}},
+		}
+		base = fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+			config,
+			&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: namespace}},
+		).Build()
+	})
+
+	// reconcileUntilConverged reconciles the config like the controller does, failed reconciliations are
+	// requeued
+	reconcileUntilConverged := func(r *ReconcileNetworkAddonsConfig) {
+		var err error
+		for i := 0; i < reconcileAttempts; i++ {
+			if _, err = r.Reconcile(context.TODO(), reconcile.Request{NamespacedName: configKey}); err == nil {
+				return
+			}
+		}
+		Fail(fmt.Sprintf("reconciliation did not converge: %v", err))
+	}
+
+	condition := func(conditionType conditionsv1.ConditionType) *conditionsv1.Condition {
+		config := &cnaov1.NetworkAddonsConfig{}
+		Expect(base.Get(context.TODO(), configKey, config)).To(Succeed())
+		return conditionsv1.FindStatusCondition(config.Status.Conditions, conditionType)
+	}
+
+	daemonSetExists := func(key types.NamespacedName) bool {
+		err := base.Get(context.TODO(), key, &appsv1.DaemonSet{})
+		Expect(client.IgnoreNotFound(err)).ToNot(HaveOccurred())
+		return err == nil
+	}
+
+	DescribeTable("should converge on the requested configuration",
+		func(faults ...faultinjection.Fault) {
+			c := faultinjection.NewClient(base, 0, faults...)
+			r := newReconciler(&fakeManager{client: c}, namespace, &network.ClusterInfo{})
+
+			By("Deploying the config")
+			reconcileUntilConverged(r)
+			Expect(daemonSetExists(bridgeMarkerKey)).To(BeTrue())
+			Expect(daemonSetExists(macvtapKey)).To(BeFalse())
+
+			By("Updating the config")
+			config := &cnaov1.NetworkAddonsConfig{}
+			Expect(base.Get(context.TODO(), configKey, config)).To(Succeed())
+			config.Spec.MacvtapCni = &cnao.MacvtapCni{}
+			config.Generation++
+			Expect(base.Update(context.TODO(), config)).To(Succeed())
+			reconcileUntilConverged(r)
+			Expect(daemonSetExists(macvtapKey)).To(BeTrue())
+
+			Expect(c.Injected()).To(BeNumerically(">", 0))
+
+			By("Checking the reported conditions")
+			Expect(condition(conditionsv1.ConditionDegraded).Status).To(Equal(corev1.ConditionFalse))
+			// DaemonSets of the fake API server have no nodes to schedule pods on, so they are available at once
+			Expect(condition(conditionsv1.ConditionProgressing).Status).To(Equal(corev1.ConditionFalse))
+			Expect(condition(conditionsv1.ConditionAvailable).Status).To(Equal(corev1.ConditionTrue))
+		},
+		Entry("despite conflicting updates",
+			faultinjection.Fault{Type: faultinjection.Conflict, Verbs: []faultinjection.Verb{faultinjection.Update}, Probability: 1, Count: 3}),
+		Entry("despite timeouts",
+			faultinjection.Fault{Type: faultinjection.Timeout, Probability: 0.2, Count: 10}),
+		Entry("despite objects reported missing while they exist",
+			faultinjection.Fault{Type: faultinjection.NotFound, Verbs: []faultinjection.Verb{faultinjection.Get}, Kinds: []string{"DaemonSet", "ConfigMap"}, Probability: 0.5, Count: 5}),
+		Entry("despite lost responses to writes",
+			faultinjection.Fault{Type: faultinjection.Partial, Verbs: []faultinjection.Verb{faultinjection.Create, faultinjection.Update}, Probability: 0.3, Count: 5}),
+		Entry("despite faults of all types",
+			faultinjection.Fault{Type: faultinjection.Conflict, Probability: 0.1, Count: 5},
+			faultinjection.Fault{Type: faultinjection.Timeout, Probability: 0.1, Count: 5},
+			faultinjection.Fault{Type: faultinjection.NotFound, Probability: 0.1, Count: 5},
+			faultinjection.Fault{Type: faultinjection.Partial, Probability: 0.1, Count: 5}),
+	)
+
+	It("should report a failure to apply objects until the API server recovers", func() {
+		c := faultinjection.NewClient(base, 0, faultinjection.Fault{Type: faultinjection.Timeout, Verbs: []faultinjection.Verb{faultinjection.Create}, Kinds: []string{"DaemonSet"}, Probability: 1, Count: 1})
+		r := newReconciler(&fakeManager{client: c}, namespace, &network.ClusterInfo{})
+
+		_, err := r.Reconcile(context.TODO(), reconcile.Request{NamespacedName: configKey})
+		Expect(err).To(HaveOccurred())
+		Expect(condition(conditionsv1.ConditionDegraded).Status).To(Equal(corev1.ConditionTrue))
+		Expect(condition(conditionsv1.ConditionDegraded).Reason).To(Equal("FailedToApply"))
+		Expect(condition(conditionsv1.ConditionAvailable).Status).To(Equal(corev1.ConditionFalse))
+
+		reconcileUntilConverged(r)
+		Expect(condition(conditionsv1.ConditionDegraded).Status).To(Equal(corev1.ConditionFalse))
+	})
+})
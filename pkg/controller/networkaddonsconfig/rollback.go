@@ -0,0 +1,76 @@
+package networkaddonsconfig
+
+import (
+	"context"
+	"log"
+	"reflect"
+	"strconv"
+
+	osv1 "github.com/openshift/api/operator/v1"
+	"github.com/pkg/errors"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	cnaov1 "github.com/kubevirt/cluster-network-addons-operator/pkg/apis/networkaddonsoperator/v1"
+	"github.com/kubevirt/cluster-network-addons-operator/pkg/names"
+	"github.com/kubevirt/cluster-network-addons-operator/pkg/network"
+)
+
+// rollbackRequested returns whether NetworkAddonsConfig asks for a rollback to an applied revision
+func rollbackRequested(networkAddonsConfig *cnaov1.NetworkAddonsConfig) bool {
+	_, requested := networkAddonsConfig.GetAnnotations()[names.ROLLBACK_ANNOTATION]
+	return requested
+}
+
+// rollbackRefusedError reports a rollback request that cannot succeed until it is changed
+type rollbackRefusedError struct {
+	error
+}
+
+func isRollbackRefused(err error) bool {
+	refused := &rollbackRefusedError{}
+	return errors.As(err, &refused)
+}
+
+// rollback replaces the spec of NetworkAddonsConfig with the spec of the requested applied
+// revision and drops the request. The revision has to pass the same validation and change
+// safety checks as any other change of the spec, it is then reconciled as usual.
+func rollback(ctx context.Context, c k8sclient.Client, namespace string, networkAddonsConfig *cnaov1.NetworkAddonsConfig, openshiftNetworkConfig *osv1.Network) error {
+	value := networkAddonsConfig.GetAnnotations()[names.ROLLBACK_ANNOTATION]
+	revisionNumber, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return &rollbackRefusedError{errors.Errorf("failed to parse the requested revision %q", value)}
+	}
+
+	history, err := getAppliedHistory(ctx, c, networkAddonsConfig.Name, namespace)
+	if err != nil {
+		return errors.Wrap(err, "failed to retrieve applied revision history")
+	}
+	revision, err := getAppliedRevision(history, revisionNumber)
+	if err != nil {
+		return &rollbackRefusedError{err}
+	}
+
+	spec := revision.Spec.DeepCopy()
+	network.Canonicalize(spec)
+	if err := network.Validate(spec, openshiftNetworkConfig); err != nil {
+		return &rollbackRefusedError{errors.Wrapf(err, "failed to validate NetworkConfig.Spec of revision %d", revisionNumber)}
+	}
+	prev := &history[len(history)-1].Spec
+	if err := network.FillDefaults(spec, prev); err != nil {
+		return &rollbackRefusedError{errors.Wrapf(err, "failed to fill defaults of revision %d", revisionNumber)}
+	}
+	if err := network.IsChangeSafe(prev, spec); err != nil {
+		return &rollbackRefusedError{errors.Wrapf(err, "failed to roll back to revision %d", revisionNumber)}
+	}
+
+	patch := k8sclient.MergeFrom(networkAddonsConfig.DeepCopy())
+	if !reflect.DeepEqual(networkAddonsConfig.Spec, revision.Spec) {
+		log.Printf("rolling back NetworkAddonsConfig to revision %d", revisionNumber)
+		networkAddonsConfig.Spec = *revision.Spec.DeepCopy()
+	}
+	annotations := networkAddonsConfig.GetAnnotations()
+	delete(annotations, names.ROLLBACK_ANNOTATION)
+	networkAddonsConfig.SetAnnotations(annotations)
+
+	return c.Patch(ctx, networkAddonsConfig, patch)
+}
@@ -0,0 +1,102 @@
+package networkaddonsconfig
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	cnao "github.com/kubevirt/cluster-network-addons-operator/pkg/apis/networkaddonsoperator/shared"
+	cnaov1 "github.com/kubevirt/cluster-network-addons-operator/pkg/apis/networkaddonsoperator/v1"
+	"github.com/kubevirt/cluster-network-addons-operator/pkg/names"
+)
+
+var _ = Describe("Rollback", func() {
+	const namespace = "cnao"
+
+	var client k8sclient.Client
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		Expect(cnaov1.AddToScheme(scheme)).To(Succeed())
+
+		history := []appliedRevision{}
+		for _, spec := range []cnao.NetworkAddonsConfigSpec{
+			{ImagePullPolicy: corev1.PullAlways, LinuxBridge: &cnao.LinuxBridge{}},
+			{ImagePullPolicy: corev1.PullNever, LinuxBridge: &cnao.LinuxBridge{}},
+			{ImagePullPolicy: corev1.PullNever, LinuxBridge: &cnao.LinuxBridge{}, Multus: &cnao.Multus{}},
+		} {
+			spec := spec
+			history = appendAppliedRevision(history, &spec, "", time.Now())
+		}
+		latest := history[len(history)-1].Spec
+		applied, err := appliedConfiguration(&cnao.NetworkAddonsConfig{ObjectMeta: metav1.ObjectMeta{Name: names.OPERATOR_CONFIG}}, namespace, history)
+		Expect(err).NotTo(HaveOccurred())
+
+		config := &cnaov1.NetworkAddonsConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: names.OPERATOR_CONFIG},
+			Spec:       latest,
+		}
+		client = fake.NewClientBuilder().WithScheme(scheme).WithObjects(applied, config).Build()
+	})
+
+	requestRollback := func(revision string) *cnaov1.NetworkAddonsConfig {
+		config := &cnaov1.NetworkAddonsConfig{}
+		Expect(client.Get(context.TODO(), types.NamespacedName{Name: names.OPERATOR_CONFIG}, config)).To(Succeed())
+		config.SetAnnotations(map[string]string{names.ROLLBACK_ANNOTATION: revision})
+		Expect(client.Update(context.TODO(), config)).To(Succeed())
+		Expect(rollbackRequested(config)).To(BeTrue())
+		return config
+	}
+
+	getConfig := func() *cnaov1.NetworkAddonsConfig {
+		config := &cnaov1.NetworkAddonsConfig{}
+		Expect(client.Get(context.TODO(), types.NamespacedName{Name: names.OPERATOR_CONFIG}, config)).To(Succeed())
+		return config
+	}
+
+	It("should replace the spec with the requested revision and drop the request", func() {
+		config := requestRollback("2")
+		Expect(rollback(context.TODO(), client, namespace, config, nil)).To(Succeed())
+
+		config = getConfig()
+		Expect(rollbackRequested(config)).To(BeFalse())
+		Expect(config.Spec.Multus).To(BeNil())
+		Expect(config.Spec.LinuxBridge).NotTo(BeNil())
+		Expect(config.Spec.ImagePullPolicy).To(Equal(corev1.PullNever))
+	})
+
+	It("should refuse a revision the change from the applied configuration is not safe to", func() {
+		config := requestRollback("1")
+		err := rollback(context.TODO(), client, namespace, config, nil)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("failed to roll back to revision 1"))
+		Expect(err.Error()).To(ContainSubstring("cannot modify ImagePullPolicy configuration once components were deployed"))
+		Expect(isRollbackRefused(err)).To(BeTrue())
+
+		config = getConfig()
+		Expect(rollbackRequested(config)).To(BeTrue())
+		Expect(config.Spec.Multus).NotTo(BeNil())
+	})
+
+	It("should refuse an unknown revision", func() {
+		config := requestRollback("42")
+		err := rollback(context.TODO(), client, namespace, config, nil)
+		Expect(err).To(MatchError("revision 42 was not found in the applied history, available revisions are [1 2 3]"))
+		Expect(isRollbackRefused(err)).To(BeTrue())
+	})
+
+	It("should refuse a malformed revision", func() {
+		config := requestRollback("latest")
+		Expect(rollback(context.TODO(), client, namespace, config, nil)).To(MatchError(`failed to parse the requested revision "latest"`))
+	})
+})
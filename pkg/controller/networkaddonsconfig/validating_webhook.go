@@ -0,0 +1,143 @@
+package networkaddonsconfig
+
+import (
+	"context"
+	"net/http"
+
+	ocpv1 "github.com/openshift/api/config/v1"
+	"github.com/pkg/errors"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	cnao "github.com/kubevirt/cluster-network-addons-operator/pkg/apis/networkaddonsoperator/shared"
+	cnaov1 "github.com/kubevirt/cluster-network-addons-operator/pkg/apis/networkaddonsoperator/v1"
+	cnaov1alpha1 "github.com/kubevirt/cluster-network-addons-operator/pkg/apis/networkaddonsoperator/v1alpha1"
+	"github.com/kubevirt/cluster-network-addons-operator/pkg/names"
+	"github.com/kubevirt/cluster-network-addons-operator/pkg/network"
+	"github.com/kubevirt/cluster-network-addons-operator/pkg/webhook"
+)
+
+const validatingWebhookPath = "/validate-networkaddonsconfig"
+
+// newWebhookServer creates the server of operator admission and conversion webhooks, its TLS
+// settings follow the security profile requested by NetworkAddonsConfig
+func newWebhookServer(mgr manager.Manager, namespace string) *webhook.Server {
+	return webhook.NewServer(mgr, namespace, func(ctx context.Context) (*ocpv1.TLSSecurityProfile, error) {
+		return getTLSSecurityProfile(ctx, mgr.GetClient())
+	})
+}
+
+// addValidatingWebhook serves a webhook rejecting NetworkAddonsConfig changes the reconciler
+// would refuse to apply
+func addValidatingWebhook(mgr manager.Manager, server *webhook.Server, namespace string) error {
+	handler, err := admission.StandaloneWebhook(
+		&admission.Webhook{Handler: &validator{client: mgr.GetClient(), namespace: namespace}},
+		admission.StandaloneOptions{Scheme: mgr.GetScheme()},
+	)
+	if err != nil {
+		return err
+	}
+	server.RegisterValidatingWebhook(validatingWebhookPath, validatingWebhook(), handler)
+	return nil
+}
+
+func validatingWebhook() admissionregistrationv1.ValidatingWebhook {
+	// The reconciler validates the configuration too, writes are not blocked while the operator is unavailable
+	failurePolicy := admissionregistrationv1.Ignore
+	sideEffects := admissionregistrationv1.SideEffectClassNone
+	return admissionregistrationv1.ValidatingWebhook{
+		Name: "networkaddonsconfig-validator." + cnaov1.GroupVersion.Group,
+		Rules: []admissionregistrationv1.RuleWithOperations{
+			{
+				Operations: []admissionregistrationv1.OperationType{
+					admissionregistrationv1.Create,
+					admissionregistrationv1.Update,
+				},
+				Rule: admissionregistrationv1.Rule{
+					APIGroups:   []string{cnaov1.GroupVersion.Group},
+					APIVersions: []string{cnaov1.GroupVersion.Version, cnaov1alpha1.GroupVersion.Version},
+					Resources:   []string{"networkaddonsconfigs"},
+				},
+			},
+		},
+		FailurePolicy:           &failurePolicy,
+		SideEffects:             &sideEffects,
+		AdmissionReviewVersions: []string{"v1"},
+	}
+}
+
+// getTLSSecurityProfile returns the TLS security profile requested by NetworkAddonsConfig,
+// nil is returned if it is not specified
+func getTLSSecurityProfile(ctx context.Context, c k8sclient.Client) (*ocpv1.TLSSecurityProfile, error) {
+	networkAddonsConfig := &cnaov1.NetworkAddonsConfig{}
+	err := c.Get(ctx, types.NamespacedName{Name: names.OPERATOR_CONFIG}, networkAddonsConfig)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return networkAddonsConfig.Spec.TLSSecurityProfile, nil
+}
+
+// validator runs the same validation, defaulting and change safety checks as the reconciler
+type validator struct {
+	client    k8sclient.Client
+	namespace string
+	decoder   *admission.Decoder
+}
+
+// InjectDecoder implements admission.DecoderInjector
+func (v *validator) InjectDecoder(decoder *admission.Decoder) error {
+	v.decoder = decoder
+	return nil
+}
+
+// Handle implements admission.Handler
+func (v *validator) Handle(ctx context.Context, req admission.Request) admission.Response {
+	// Configurations without the default name are ignored by the reconciler
+	if req.Name != names.OPERATOR_CONFIG {
+		return admission.Allowed("")
+	}
+
+	var spec cnao.NetworkAddonsConfigSpec
+	switch req.Kind.Version {
+	case cnaov1alpha1.GroupVersion.Version:
+		networkAddonsConfig := &cnaov1alpha1.NetworkAddonsConfig{}
+		if err := v.decoder.Decode(req, networkAddonsConfig); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+		hub := &cnaov1.NetworkAddonsConfig{}
+		if err := networkAddonsConfig.ConvertTo(hub); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+		spec = hub.Spec
+	default:
+		networkAddonsConfig := &cnaov1.NetworkAddonsConfig{}
+		if err := v.decoder.Decode(req, networkAddonsConfig); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+		spec = networkAddonsConfig.Spec
+	}
+
+	network.Canonicalize(&spec)
+
+	openshiftNetworkConfig, err := getOpenShiftNetworkConfig(ctx, v.client)
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, errors.Wrapf(err, "failed to load OpenShift NetworkConfig"))
+	}
+
+	if err := network.Validate(&spec, openshiftNetworkConfig); err != nil {
+		return admission.Denied(errors.Wrapf(err, "failed to validate NetworkConfig.Spec").Error())
+	}
+
+	if _, err := getPreviousConfigSpec(ctx, v.client, v.namespace, req.Name, &spec); err != nil {
+		return admission.Denied(err.Error())
+	}
+
+	return admission.Allowed("")
+}
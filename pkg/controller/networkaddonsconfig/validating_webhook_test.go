@@ -0,0 +1,122 @@
+package networkaddonsconfig
+
+import (
+	"context"
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	osv1 "github.com/openshift/api/operator/v1"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	cnao "github.com/kubevirt/cluster-network-addons-operator/pkg/apis/networkaddonsoperator/shared"
+	cnaov1 "github.com/kubevirt/cluster-network-addons-operator/pkg/apis/networkaddonsoperator/v1"
+	cnaov1alpha1 "github.com/kubevirt/cluster-network-addons-operator/pkg/apis/networkaddonsoperator/v1alpha1"
+	"github.com/kubevirt/cluster-network-addons-operator/pkg/names"
+)
+
+var _ = Describe("Validating webhook", func() {
+	const namespace = "cnao"
+
+	var scheme *runtime.Scheme
+	BeforeEach(func() {
+		scheme = runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		Expect(cnaov1.AddToScheme(scheme)).To(Succeed())
+		Expect(cnaov1alpha1.AddToScheme(scheme)).To(Succeed())
+		Expect(osv1.Install(scheme)).To(Succeed())
+	})
+
+	newValidator := func(objs ...runtime.Object) *validator {
+		decoder, err := admission.NewDecoder(scheme)
+		Expect(err).NotTo(HaveOccurred())
+		return &validator{
+			client:    fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(objs...).Build(),
+			namespace: namespace,
+			decoder:   decoder,
+		}
+	}
+
+	newRequest := func(obj runtime.Object, name, version string) admission.Request {
+		raw, err := json.Marshal(obj)
+		Expect(err).NotTo(HaveOccurred())
+		return admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+			Name:      name,
+			Kind:      metav1.GroupVersionKind{Group: cnaov1.GroupVersion.Group, Version: version, Kind: "NetworkAddonsConfig"},
+			Operation: admissionv1.Update,
+			Object:    runtime.RawExtension{Raw: raw},
+		}}
+	}
+
+	newV1Request := func(spec cnao.NetworkAddonsConfigSpec) admission.Request {
+		config := &cnaov1.NetworkAddonsConfig{
+			TypeMeta:   metav1.TypeMeta{APIVersion: cnaov1.GroupVersion.String(), Kind: "NetworkAddonsConfig"},
+			ObjectMeta: metav1.ObjectMeta{Name: names.OPERATOR_CONFIG},
+			Spec:       spec,
+		}
+		return newRequest(config, names.OPERATOR_CONFIG, cnaov1.GroupVersion.Version)
+	}
+
+	appliedConfiguration := func(spec cnao.NetworkAddonsConfigSpec) *corev1.ConfigMap {
+		applied, err := json.Marshal(spec)
+		Expect(err).NotTo(HaveOccurred())
+		return &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: names.APPLIED_PREFIX + names.OPERATOR_CONFIG, Namespace: namespace},
+			Data:       map[string]string{"applied": string(applied)},
+		}
+	}
+
+	It("should allow a valid configuration", func() {
+		response := newValidator().Handle(context.TODO(), newV1Request(cnao.NetworkAddonsConfigSpec{
+			LinuxBridge:     &cnao.LinuxBridge{},
+			ImagePullPolicy: corev1.PullAlways,
+		}))
+		Expect(response.Allowed).To(BeTrue())
+	})
+
+	It("should reject an invalid configuration with the reconciler message", func() {
+		response := newValidator().Handle(context.TODO(), newV1Request(cnao.NetworkAddonsConfigSpec{
+			ImagePullPolicy: "Sometimes",
+		}))
+		Expect(response.Allowed).To(BeFalse())
+		Expect(string(response.Result.Reason)).To(HavePrefix("failed to validate NetworkConfig.Spec: invalid configuration:"))
+		Expect(string(response.Result.Reason)).To(ContainSubstring("requested imagePullPolicy 'Sometimes' is not valid"))
+	})
+
+	It("should reject a change unsafe against the applied configuration", func() {
+		v := newValidator(appliedConfiguration(cnao.NetworkAddonsConfigSpec{ImagePullPolicy: corev1.PullAlways}))
+		response := v.Handle(context.TODO(), newV1Request(cnao.NetworkAddonsConfigSpec{ImagePullPolicy: corev1.PullIfNotPresent}))
+		Expect(response.Allowed).To(BeFalse())
+		Expect(string(response.Result.Reason)).To(ContainSubstring("not applying unsafe change"))
+		Expect(string(response.Result.Reason)).To(ContainSubstring("cannot modify ImagePullPolicy configuration once components were deployed"))
+	})
+
+	It("should fill defaults from the applied configuration before checking the change", func() {
+		v := newValidator(appliedConfiguration(cnao.NetworkAddonsConfigSpec{ImagePullPolicy: corev1.PullAlways}))
+		response := v.Handle(context.TODO(), newV1Request(cnao.NetworkAddonsConfigSpec{}))
+		Expect(response.Allowed).To(BeTrue())
+	})
+
+	It("should validate the v1alpha1 version too", func() {
+		config := &cnaov1alpha1.NetworkAddonsConfig{
+			TypeMeta:   metav1.TypeMeta{APIVersion: cnaov1alpha1.GroupVersion.String(), Kind: "NetworkAddonsConfig"},
+			ObjectMeta: metav1.ObjectMeta{Name: names.OPERATOR_CONFIG},
+			Spec:       cnaov1alpha1.NetworkAddonsConfigSpec{ImagePullPolicy: "Sometimes"},
+		}
+		response := newValidator().Handle(context.TODO(), newRequest(config, names.OPERATOR_CONFIG, cnaov1alpha1.GroupVersion.Version))
+		Expect(response.Allowed).To(BeFalse())
+	})
+
+	It("should ignore configurations the reconciler ignores", func() {
+		config := &cnaov1.NetworkAddonsConfig{Spec: cnao.NetworkAddonsConfigSpec{ImagePullPolicy: "Sometimes"}}
+		response := newValidator().Handle(context.TODO(), newRequest(config, "other", cnaov1.GroupVersion.Version))
+		Expect(response.Allowed).To(BeTrue())
+	})
+})
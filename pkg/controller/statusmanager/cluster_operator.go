@@ -0,0 +1,218 @@
+package statusmanager
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	osconfv1 "github.com/openshift/api/config/v1"
+	conditionsv1 "github.com/openshift/custom-resource-status/conditions/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+
+	cnao "github.com/kubevirt/cluster-network-addons-operator/pkg/apis/networkaddonsoperator/shared"
+	cnaov1 "github.com/kubevirt/cluster-network-addons-operator/pkg/apis/networkaddonsoperator/v1"
+	"github.com/kubevirt/cluster-network-addons-operator/pkg/components"
+	"github.com/kubevirt/cluster-network-addons-operator/pkg/names"
+)
+
+// clusterOperatorConditionTypes are reported on the ClusterOperator, Unknown is reported for
+// those not set on NetworkAddonsConfig yet
+var clusterOperatorConditionTypes = []conditionsv1.ConditionType{
+	conditionsv1.ConditionAvailable,
+	conditionsv1.ConditionProgressing,
+	conditionsv1.ConditionDegraded,
+}
+
+// SetRelatedObjects sets objects deployed by the operator, they are listed as related objects
+// of the ClusterOperator
+func (status *StatusManager) SetRelatedObjects(objs []*unstructured.Unstructured) {
+	status.mux.Lock()
+	defer status.mux.Unlock()
+	status.relatedObjects = relatedObjects(status.name, os.Getenv("OPERATOR_NAMESPACE"), objs)
+}
+
+func (status *StatusManager) getRelatedObjects() []osconfv1.ObjectReference {
+	status.mux.Lock()
+	defer status.mux.Unlock()
+	return status.relatedObjects
+}
+
+// syncClusterOperator mirrors the NetworkAddonsConfig status to the ClusterOperator, it is created if missing.
+// The ClusterOperator is owned by the NetworkAddonsConfig, so it is removed together with it.
+func (status *StatusManager) syncClusterOperator(config *cnaov1.NetworkAddonsConfig) error {
+	clusterOperator := &osconfv1.ClusterOperator{}
+	err := status.client.Get(context.TODO(), types.NamespacedName{Name: names.CLUSTER_OPERATOR}, clusterOperator)
+	if err != nil && errors.IsNotFound(err) {
+		clusterOperator = &osconfv1.ClusterOperator{ObjectMeta: metav1.ObjectMeta{
+			Name:            names.CLUSTER_OPERATOR,
+			OwnerReferences: []metav1.OwnerReference{*clusterOperatorOwner(config)},
+		}}
+		if err := status.client.Create(context.TODO(), clusterOperator); err != nil {
+			return fmt.Errorf("Failed to create ClusterOperator %q: %v", names.CLUSTER_OPERATOR, err)
+		}
+	} else if err != nil {
+		return fmt.Errorf("Failed to get ClusterOperator %q: %v", names.CLUSTER_OPERATOR, err)
+	} else if metav1.GetControllerOf(clusterOperator) == nil {
+		// Adopt a ClusterOperator created before it was owned, e.g. by a previous operator version
+		clusterOperator.OwnerReferences = append(clusterOperator.OwnerReferences, *clusterOperatorOwner(config))
+		if err := status.client.Update(context.TODO(), clusterOperator); err != nil {
+			return fmt.Errorf("Failed to set owner of ClusterOperator %q: %v", names.CLUSTER_OPERATOR, err)
+		}
+	}
+
+	clusterOperatorStatus := clusterOperatorStatus(&config.Status, clusterOperator.Status.Conditions, status.getRelatedObjects(), metav1.Now())
+	if equality.Semantic.DeepEqual(clusterOperator.Status, clusterOperatorStatus) {
+		return nil
+	}
+
+	clusterOperator.Status = clusterOperatorStatus
+	if err := status.client.Status().Update(context.TODO(), clusterOperator); err != nil {
+		return fmt.Errorf("Failed to update ClusterOperator %q Status: %v", names.CLUSTER_OPERATOR, err)
+	}
+	return nil
+}
+
+// clusterOperatorOwner references the NetworkAddonsConfig as the controller of the ClusterOperator
+func clusterOperatorOwner(config *cnaov1.NetworkAddonsConfig) *metav1.OwnerReference {
+	return metav1.NewControllerRef(config, cnaov1.GroupVersion.WithKind("NetworkAddonsConfig"))
+}
+
+// clusterOperatorStatus computes the ClusterOperator status from the NetworkAddonsConfig status.
+// Conditions keep their transition time from current ones unless their status changes.
+func clusterOperatorStatus(configStatus *cnao.NetworkAddonsConfigStatus, current []osconfv1.ClusterOperatorStatusCondition, relatedObjects []osconfv1.ObjectReference, now metav1.Time) osconfv1.ClusterOperatorStatus {
+	return osconfv1.ClusterOperatorStatus{
+		Conditions:     clusterOperatorConditions(configStatus.Conditions, current, now),
+		Versions:       clusterOperatorVersions(configStatus),
+		RelatedObjects: relatedObjects,
+	}
+}
+
+func clusterOperatorConditions(conditions []conditionsv1.Condition, current []osconfv1.ClusterOperatorStatusCondition, now metav1.Time) []osconfv1.ClusterOperatorStatusCondition {
+	clusterOperatorConditions := []osconfv1.ClusterOperatorStatusCondition{}
+	for _, conditionType := range clusterOperatorConditionTypes {
+		clusterOperatorCondition := osconfv1.ClusterOperatorStatusCondition{
+			Type:    osconfv1.ClusterStatusConditionType(conditionType),
+			Status:  osconfv1.ConditionUnknown,
+			Reason:  "NotReported",
+			Message: "NetworkAddonsConfig does not report this condition yet",
+		}
+		if condition := conditionsv1.FindStatusCondition(conditions, conditionType); condition != nil {
+			clusterOperatorCondition.Status = osconfv1.ConditionStatus(condition.Status)
+			clusterOperatorCondition.Reason = condition.Reason
+			clusterOperatorCondition.Message = condition.Message
+		}
+
+		clusterOperatorCondition.LastTransitionTime = now
+		for _, currentCondition := range current {
+			if currentCondition.Type == clusterOperatorCondition.Type && currentCondition.Status == clusterOperatorCondition.Status {
+				clusterOperatorCondition.LastTransitionTime = currentCondition.LastTransitionTime
+			}
+		}
+
+		clusterOperatorConditions = append(clusterOperatorConditions, clusterOperatorCondition)
+	}
+	return clusterOperatorConditions
+}
+
+// clusterOperatorVersions reports the operator version once it is rolled out, followed by versions
+// of operand images
+func clusterOperatorVersions(configStatus *cnao.NetworkAddonsConfigStatus) []osconfv1.OperandVersion {
+	versions := []osconfv1.OperandVersion{}
+	if configStatus.ObservedVersion != "" {
+		versions = append(versions, osconfv1.OperandVersion{Name: "operator", Version: configStatus.ObservedVersion})
+	}
+
+	operandVersions := []osconfv1.OperandVersion{}
+	found := map[osconfv1.OperandVersion]bool{}
+	for _, container := range configStatus.Containers {
+		name, version := imageNameAndVersion(container.Image)
+		operandVersion := osconfv1.OperandVersion{Name: name, Version: version}
+		if !found[operandVersion] {
+			found[operandVersion] = true
+			operandVersions = append(operandVersions, operandVersion)
+		}
+	}
+	sort.Slice(operandVersions, func(i, j int) bool {
+		if operandVersions[i].Name != operandVersions[j].Name {
+			return operandVersions[i].Name < operandVersions[j].Name
+		}
+		return operandVersions[i].Version < operandVersions[j].Version
+	})
+
+	return append(versions, operandVersions...)
+}
+
+// imageNameAndVersion splits an image reference to the name of the image repository and its
+// digest or tag
+func imageNameAndVersion(image string) (string, string) {
+	repository, version := image, "latest"
+	if i := strings.Index(image, "@"); i >= 0 {
+		repository, version = image[:i], image[i+1:]
+	} else if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		repository, version = image[:i], image[i+1:]
+	}
+	return repository[strings.LastIndex(repository, "/")+1:], version
+}
+
+// relatedObjects lists the NetworkAddonsConfig, the operator namespace, all the namespaces holding objects
+// deployed by the operator and the kinds of these objects. Namespaced kinds are listed per namespace,
+// cluster-scoped objects are listed by name so that unrelated objects of their kind are not collected.
+func relatedObjects(configName, operatorNamespace string, objs []*unstructured.Unstructured) []osconfv1.ObjectReference {
+	references := []osconfv1.ObjectReference{
+		{Group: cnaov1.GroupVersion.Group, Resource: "networkaddonsconfigs", Name: configName},
+	}
+
+	found := map[string]bool{}
+	addNamespace := func(namespace string) {
+		if namespace != "" && !found[namespace] {
+			found[namespace] = true
+			references = append(references, osconfv1.ObjectReference{Resource: "namespaces", Name: namespace})
+		}
+	}
+	addNamespace(operatorNamespace)
+	for _, obj := range objs {
+		if obj.GetAPIVersion() == "v1" && obj.GetKind() == "Namespace" {
+			addNamespace(obj.GetName())
+		} else {
+			addNamespace(obj.GetNamespace())
+		}
+	}
+
+	kindReferences := []osconfv1.ObjectReference{}
+	foundKinds := map[osconfv1.ObjectReference]bool{}
+	for _, obj := range objs {
+		if obj.GetAPIVersion() == "v1" && obj.GetKind() == "Namespace" {
+			continue
+		}
+		resource := components.ResourceForKind(obj.GroupVersionKind())
+		reference := osconfv1.ObjectReference{Group: resource.Group, Resource: resource.Resource, Namespace: obj.GetNamespace()}
+		if reference.Namespace == "" {
+			reference.Name = obj.GetName()
+		}
+		if !foundKinds[reference] {
+			foundKinds[reference] = true
+			kindReferences = append(kindReferences, reference)
+		}
+	}
+	sort.Slice(kindReferences, func(i, j int) bool {
+		a, b := kindReferences[i], kindReferences[j]
+		if a.Group != b.Group {
+			return a.Group < b.Group
+		}
+		if a.Resource != b.Resource {
+			return a.Resource < b.Resource
+		}
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		return a.Name < b.Name
+	})
+
+	return append(references, kindReferences...)
+}
@@ -0,0 +1,135 @@
+package statusmanager
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	osconfv1 "github.com/openshift/api/config/v1"
+	conditionsv1 "github.com/openshift/custom-resource-status/conditions/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	cnao "github.com/kubevirt/cluster-network-addons-operator/pkg/apis/networkaddonsoperator/shared"
+	cnaov1 "github.com/kubevirt/cluster-network-addons-operator/pkg/apis/networkaddonsoperator/v1"
+	"github.com/kubevirt/cluster-network-addons-operator/pkg/names"
+	"github.com/kubevirt/cluster-network-addons-operator/pkg/util/k8s"
+)
+
+var _ = Describe("ClusterOperator", func() {
+	configStatus := &cnao.NetworkAddonsConfigStatus{
+		ObservedVersion: "99.0.0",
+		Conditions: []conditionsv1.Condition{
+			{Type: conditionsv1.ConditionAvailable, Status: corev1.ConditionTrue},
+			{Type: conditionsv1.ConditionDegraded, Status: corev1.ConditionTrue, Reason: "FailedToValidate", Message: "invalid configuration"},
+		},
+		Containers: []cnao.Container{
+			{ParentKind: "Deployment", ParentName: "kubemacpool-mac-controller-manager", Name: "manager", Image: "quay.io/kubevirt/kubemacpool@sha256:abc"},
+			{ParentKind: "Deployment", ParentName: "kubemacpool-cert-manager", Name: "manager", Image: "quay.io/kubevirt/kubemacpool@sha256:abc"},
+			{ParentKind: "DaemonSet", ParentName: "bridge-marker", Name: "bridge-marker", Image: "quay.io/kubevirt/bridge-marker:0.10.0"},
+			{ParentKind: "DaemonSet", ParentName: "kube-multus-ds", Name: "kube-multus", Image: "localhost:5000/multus"},
+		},
+	}
+	config := &cnaov1.NetworkAddonsConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: names.OPERATOR_CONFIG, UID: "config-uid"},
+		Status:     *configStatus,
+	}
+	now := metav1.NewTime(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	It("should mirror NetworkAddonsConfig conditions", func() {
+		status := clusterOperatorStatus(configStatus, nil, nil, now)
+		Expect(status.Conditions).To(Equal([]osconfv1.ClusterOperatorStatusCondition{
+			{Type: osconfv1.OperatorAvailable, Status: osconfv1.ConditionTrue, LastTransitionTime: now},
+			{Type: osconfv1.OperatorProgressing, Status: osconfv1.ConditionUnknown, LastTransitionTime: now, Reason: "NotReported", Message: "NetworkAddonsConfig does not report this condition yet"},
+			{Type: osconfv1.OperatorDegraded, Status: osconfv1.ConditionTrue, LastTransitionTime: now, Reason: "FailedToValidate", Message: "invalid configuration"},
+		}))
+	})
+
+	It("should keep transition time of conditions which did not change", func() {
+		before := metav1.NewTime(now.Add(-time.Hour))
+		current := []osconfv1.ClusterOperatorStatusCondition{
+			{Type: osconfv1.OperatorAvailable, Status: osconfv1.ConditionTrue, LastTransitionTime: before},
+			{Type: osconfv1.OperatorDegraded, Status: osconfv1.ConditionFalse, LastTransitionTime: before},
+		}
+		status := clusterOperatorStatus(configStatus, current, nil, now)
+		Expect(status.Conditions[0].LastTransitionTime).To(Equal(before))
+		Expect(status.Conditions[2].LastTransitionTime).To(Equal(now))
+	})
+
+	It("should report versions of the operator and of operand images", func() {
+		status := clusterOperatorStatus(configStatus, nil, nil, now)
+		Expect(status.Versions).To(Equal([]osconfv1.OperandVersion{
+			{Name: "operator", Version: "99.0.0"},
+			{Name: "bridge-marker", Version: "0.10.0"},
+			{Name: "kubemacpool", Version: "sha256:abc"},
+			{Name: "multus", Version: "latest"},
+		}))
+	})
+
+	It("should not report the operator version before it is rolled out", func() {
+		status := clusterOperatorStatus(&cnao.NetworkAddonsConfigStatus{}, nil, nil, now)
+		Expect(status.Versions).To(BeEmpty())
+	})
+
+	It("should list the config, namespaces and kinds managed by the operator as related objects", func() {
+		objs := []*unstructured.Unstructured{
+			k8s.UnstructuredFromYaml("apiVersion: v1\nkind: Namespace\nmetadata:\n  name: kubemacpool"),
+			k8s.UnstructuredFromYaml("apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: kubemacpool-mac-controller-manager\n  namespace: kubemacpool"),
+			k8s.UnstructuredFromYaml("apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: kubemacpool-cert-manager\n  namespace: kubemacpool"),
+			k8s.UnstructuredFromYaml("apiVersion: rbac.authorization.k8s.io/v1\nkind: ClusterRole\nmetadata:\n  name: kubemacpool"),
+			k8s.UnstructuredFromYaml("apiVersion: v1\nkind: Namespace\nmetadata:\n  name: cluster-network-addons"),
+			k8s.UnstructuredFromYaml("apiVersion: apps/v1\nkind: DaemonSet\nmetadata:\n  name: bridge-marker\n  namespace: cluster-network-addons"),
+			k8s.UnstructuredFromYaml("apiVersion: v1\nkind: ServiceAccount\nmetadata:\n  name: bridge-marker\n  namespace: cluster-network-addons"),
+		}
+		Expect(relatedObjects(names.OPERATOR_CONFIG, "cluster-network-addons", objs)).To(Equal([]osconfv1.ObjectReference{
+			{Group: "networkaddonsoperator.network.kubevirt.io", Resource: "networkaddonsconfigs", Name: names.OPERATOR_CONFIG},
+			{Resource: "namespaces", Name: "cluster-network-addons"},
+			{Resource: "namespaces", Name: "kubemacpool"},
+			{Resource: "serviceaccounts", Namespace: "cluster-network-addons"},
+			{Group: "apps", Resource: "daemonsets", Namespace: "cluster-network-addons"},
+			{Group: "apps", Resource: "deployments", Namespace: "kubemacpool"},
+			{Group: "rbac.authorization.k8s.io", Resource: "clusterroles", Name: "kubemacpool"},
+		}))
+	})
+
+	It("should create the ClusterOperator and update it only on change", func() {
+		scheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		Expect(osconfv1.Install(scheme)).To(Succeed())
+		client := fake.NewClientBuilder().WithScheme(scheme).Build()
+		status := &StatusManager{client: client, name: names.OPERATOR_CONFIG, clusterOperator: true}
+
+		Expect(status.syncClusterOperator(config)).To(Succeed())
+		clusterOperator := &osconfv1.ClusterOperator{}
+		Expect(client.Get(context.TODO(), types.NamespacedName{Name: names.CLUSTER_OPERATOR}, clusterOperator)).To(Succeed())
+		Expect(clusterOperator.Status.Conditions).To(HaveLen(3))
+		Expect(clusterOperator.Status.Versions).To(ContainElement(osconfv1.OperandVersion{Name: "operator", Version: "99.0.0"}))
+		Expect(metav1.IsControlledBy(clusterOperator, config)).To(BeTrue())
+
+		resourceVersion := clusterOperator.ResourceVersion
+		Expect(status.syncClusterOperator(config)).To(Succeed())
+		Expect(client.Get(context.TODO(), types.NamespacedName{Name: names.CLUSTER_OPERATOR}, clusterOperator)).To(Succeed())
+		Expect(clusterOperator.ResourceVersion).To(Equal(resourceVersion))
+	})
+
+	It("should be removed together with the NetworkAddonsConfig", func() {
+		scheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		Expect(osconfv1.Install(scheme)).To(Succeed())
+		existing := &osconfv1.ClusterOperator{ObjectMeta: metav1.ObjectMeta{Name: names.CLUSTER_OPERATOR}}
+		client := fake.NewClientBuilder().WithScheme(scheme).WithObjects(existing).Build()
+		status := &StatusManager{client: client, name: names.OPERATOR_CONFIG, clusterOperator: true}
+
+		Expect(status.syncClusterOperator(config)).To(Succeed())
+		clusterOperator := &osconfv1.ClusterOperator{}
+		Expect(client.Get(context.TODO(), types.NamespacedName{Name: names.CLUSTER_OPERATOR}, clusterOperator)).To(Succeed())
+		Expect(metav1.IsControlledBy(clusterOperator, config)).To(BeTrue())
+	})
+})
@@ -0,0 +1,61 @@
+package statusmanager
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kubevirt/cluster-network-addons-operator/pkg/cnihealth"
+	"github.com/kubevirt/cluster-network-addons-operator/pkg/names"
+)
+
+// unhealthyCNINodes describes nodes where the CNI health container of the DaemonSet reports plugin
+// binaries or configuration missing or differing from those shipped by the image
+func (status *StatusManager) unhealthyCNINodes(ds *appsv1.DaemonSet) ([]string, error) {
+	if !hasCNIHealthContainer(ds) {
+		return nil, nil
+	}
+
+	pods, err := status.listDaemonSetPods(ds)
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := []string{}
+	for _, pod := range pods {
+		// Pods which did not report yet are left to DaemonSet availability
+		condition := cnihealth.FindCondition(&pod)
+		if condition != nil && condition.Status == corev1.ConditionFalse {
+			nodes = append(nodes, fmt.Sprintf("%s (%s: %s)", pod.Spec.NodeName, condition.Reason, condition.Message))
+		}
+	}
+	sort.Strings(nodes)
+	return nodes, nil
+}
+
+// listDaemonSetPods lists pods selected by the DaemonSet
+func (status *StatusManager) listDaemonSetPods(ds *appsv1.DaemonSet) ([]corev1.Pod, error) {
+	selector, err := metav1.LabelSelectorAsSelector(ds.Spec.Selector)
+	if err != nil {
+		return nil, err
+	}
+	pods := &corev1.PodList{}
+	if err := status.client.List(context.TODO(), pods, client.InNamespace(ds.Namespace), client.MatchingLabelsSelector{Selector: selector}); err != nil {
+		return nil, err
+	}
+	return pods.Items, nil
+}
+
+func hasCNIHealthContainer(ds *appsv1.DaemonSet) bool {
+	for _, container := range ds.Spec.Template.Spec.Containers {
+		if container.Name == names.CNI_HEALTH_CONTAINER {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,89 @@
+package statusmanager
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kubevirt/cluster-network-addons-operator/pkg/cnihealth"
+	"github.com/kubevirt/cluster-network-addons-operator/pkg/names"
+)
+
+var _ = Describe("CNI health", func() {
+	const namespace = "cluster-network-addons"
+
+	daemonSet := func(containers ...string) *appsv1.DaemonSet {
+		ds := &appsv1.DaemonSet{
+			ObjectMeta: metav1.ObjectMeta{Name: "multus", Namespace: namespace},
+			Spec: appsv1.DaemonSetSpec{
+				Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"name": "multus"}},
+			},
+		}
+		for _, container := range containers {
+			ds.Spec.Template.Spec.Containers = append(ds.Spec.Template.Spec.Containers, corev1.Container{Name: container})
+		}
+		return ds
+	}
+
+	pod := func(name, node string, labels map[string]string, conditions ...corev1.PodCondition) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, Labels: labels},
+			Spec:       corev1.PodSpec{NodeName: node},
+			Status:     corev1.PodStatus{Conditions: conditions},
+		}
+	}
+
+	statusManager := func(objs ...client.Object) *StatusManager {
+		scheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		return &StatusManager{client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build(), name: names.OPERATOR_CONFIG}
+	}
+
+	multusLabels := map[string]string{"name": "multus"}
+	healthy := corev1.PodCondition{Type: names.CNI_HEALTH_CONDITION, Status: corev1.ConditionTrue, Reason: cnihealth.ReasonHealthy}
+	unhealthy := corev1.PodCondition{Type: names.CNI_HEALTH_CONDITION, Status: corev1.ConditionFalse, Reason: cnihealth.ReasonBinaryMissing, Message: "/host/opt/cni/bin/multus is not installed"}
+	// Readiness of the pod does not matter, the health is reported by its own condition
+	notReady := corev1.PodCondition{Type: corev1.PodReady, Status: corev1.ConditionFalse}
+
+	It("should report nodes where the health container reports a problem", func() {
+		status := statusManager(
+			pod("multus-a", "node02", multusLabels, unhealthy),
+			pod("multus-b", "node01", multusLabels, unhealthy),
+			pod("multus-c", "node03", multusLabels, healthy, notReady),
+		)
+		nodes, err := status.unhealthyCNINodes(daemonSet("kube-multus", names.CNI_HEALTH_CONTAINER))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(nodes).To(Equal([]string{
+			"node01 (BinaryMissing: /host/opt/cni/bin/multus is not installed)",
+			"node02 (BinaryMissing: /host/opt/cni/bin/multus is not installed)",
+		}))
+	})
+
+	It("should leave pods which did not report yet to DaemonSet availability", func() {
+		status := statusManager(pod("multus-a", "node01", multusLabels, notReady))
+		nodes, err := status.unhealthyCNINodes(daemonSet("kube-multus", names.CNI_HEALTH_CONTAINER))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(nodes).To(BeEmpty())
+	})
+
+	It("should ignore pods of other DaemonSets", func() {
+		status := statusManager(pod("other-a", "node01", map[string]string{"name": "other"}, unhealthy))
+		nodes, err := status.unhealthyCNINodes(daemonSet("kube-multus", names.CNI_HEALTH_CONTAINER))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(nodes).To(BeEmpty())
+	})
+
+	It("should skip DaemonSets without the health container", func() {
+		status := statusManager(pod("multus-a", "node01", multusLabels, unhealthy))
+		nodes, err := status.unhealthyCNINodes(daemonSet("kube-multus"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(nodes).To(BeEmpty())
+	})
+})
@@ -0,0 +1,67 @@
+package statusmanager
+
+import (
+	"sort"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// hostCleanupContainer is the init container of host cleanup DaemonSets removing host artifacts
+const hostCleanupContainer = "cleanup"
+
+// SetHostCleanups sets DaemonSets removing host artifacts of removed components. The operator is
+// progressing until all of them finish.
+func (status *StatusManager) SetHostCleanups(daemonSets []types.NamespacedName) {
+	status.mux.Lock()
+	defer status.mux.Unlock()
+	status.hostCleanups = daemonSets
+}
+
+func (status *StatusManager) getHostCleanups() []types.NamespacedName {
+	status.mux.Lock()
+	defer status.mux.Unlock()
+	return status.hostCleanups
+}
+
+// HostCleanupComplete checks whether the host cleanup DaemonSet finished on all its nodes
+func HostCleanupComplete(ds *appsv1.DaemonSet) bool {
+	return ds.Status.ObservedGeneration >= ds.Generation &&
+		ds.Status.UpdatedNumberScheduled == ds.Status.DesiredNumberScheduled &&
+		ds.Status.NumberReady == ds.Status.DesiredNumberScheduled
+}
+
+// failedHostCleanupNodes returns names of nodes where the host cleanup DaemonSet failed to remove
+// host artifacts
+func (status *StatusManager) failedHostCleanupNodes(ds *appsv1.DaemonSet) ([]string, error) {
+	pods, err := status.listDaemonSetPods(ds)
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := []string{}
+	for _, pod := range pods {
+		if hostCleanupFailed(&pod) {
+			nodes = append(nodes, pod.Spec.NodeName)
+		}
+	}
+	sort.Strings(nodes)
+	return nodes, nil
+}
+
+// hostCleanupFailed checks whether the cleanup container of the pod exited with an error, it is
+// restarted by kubelet until it succeeds
+func hostCleanupFailed(pod *corev1.Pod) bool {
+	for _, containerStatus := range pod.Status.InitContainerStatuses {
+		if containerStatus.Name != hostCleanupContainer || containerStatus.Ready {
+			continue
+		}
+		for _, terminated := range []*corev1.ContainerStateTerminated{containerStatus.State.Terminated, containerStatus.LastTerminationState.Terminated} {
+			if terminated != nil && terminated.ExitCode != 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,73 @@
+package statusmanager
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kubevirt/cluster-network-addons-operator/pkg/names"
+)
+
+var _ = Describe("Host cleanup", func() {
+	const namespace = "cluster-network-addons"
+	labels := map[string]string{"name": "cnao-host-cleanup-ovs"}
+
+	daemonSet := func(generation int64, status appsv1.DaemonSetStatus) *appsv1.DaemonSet {
+		return &appsv1.DaemonSet{
+			ObjectMeta: metav1.ObjectMeta{Name: "cnao-host-cleanup-ovs", Namespace: namespace, Generation: generation, Labels: map[string]string{names.HOST_CLEANUP_LABEL_KEY: "ovs"}},
+			Spec:       appsv1.DaemonSetSpec{Selector: &metav1.LabelSelector{MatchLabels: labels}},
+			Status:     status,
+		}
+	}
+
+	pod := func(name, node string, initContainerStatus corev1.ContainerStatus) *corev1.Pod {
+		initContainerStatus.Name = hostCleanupContainer
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, Labels: labels},
+			Spec:       corev1.PodSpec{NodeName: node},
+			Status:     corev1.PodStatus{InitContainerStatuses: []corev1.ContainerStatus{initContainerStatus}},
+		}
+	}
+
+	DescribeTable("should detect completion",
+		func(ds *appsv1.DaemonSet, complete bool) {
+			Expect(HostCleanupComplete(ds)).To(Equal(complete))
+		},
+		Entry("when not observed yet", daemonSet(1, appsv1.DaemonSetStatus{}), false),
+		Entry("when running on some nodes", daemonSet(1, appsv1.DaemonSetStatus{ObservedGeneration: 1, DesiredNumberScheduled: 3, UpdatedNumberScheduled: 3, NumberReady: 2}), false),
+		Entry("when done on all nodes", daemonSet(1, appsv1.DaemonSetStatus{ObservedGeneration: 1, DesiredNumberScheduled: 3, UpdatedNumberScheduled: 3, NumberReady: 3}), true),
+		Entry("when there are no nodes", daemonSet(1, appsv1.DaemonSetStatus{ObservedGeneration: 1}), true),
+	)
+
+	It("should report nodes where the cleanup failed", func() {
+		scheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		client := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+			pod("cleanup-a", "node03", corev1.ContainerStatus{
+				State:                corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+				LastTerminationState: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 1}},
+			}),
+			pod("cleanup-b", "node01", corev1.ContainerStatus{
+				State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 2}},
+			}),
+			pod("cleanup-c", "node02", corev1.ContainerStatus{
+				Ready: true,
+				State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 0}},
+			}),
+			pod("cleanup-d", "node04", corev1.ContainerStatus{
+				State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}},
+			}),
+		).Build()
+		status := &StatusManager{client: client, name: names.OPERATOR_CONFIG}
+
+		nodes, err := status.failedHostCleanupNodes(daemonSet(1, appsv1.DaemonSetStatus{}))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(nodes).To(Equal([]string{"node01", "node03"}))
+	})
+})
@@ -9,6 +9,7 @@ import (
 	"sync"
 	"time"
 
+	osconfv1 "github.com/openshift/api/config/v1"
 	conditionsv1 "github.com/openshift/custom-resource-status/conditions/v1"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -21,6 +22,7 @@ import (
 	cnao "github.com/kubevirt/cluster-network-addons-operator/pkg/apis/networkaddonsoperator/shared"
 	cnaov1 "github.com/kubevirt/cluster-network-addons-operator/pkg/apis/networkaddonsoperator/v1"
 	eventemitter "github.com/kubevirt/cluster-network-addons-operator/pkg/eventemitter"
+	"github.com/kubevirt/cluster-network-addons-operator/pkg/names"
 )
 
 const (
@@ -59,16 +61,28 @@ type StatusManager struct {
 	daemonSets  []types.NamespacedName
 	deployments []types.NamespacedName
 
-	containers   []cnao.Container
-	mux          sync.Mutex
-	eventEmitter eventemitter.EventEmitter
+	hostCleanups []types.NamespacedName
+
+	containers      []cnao.Container
+	cniDirectories  *cnao.CNIDirectories
+	appliedRevision int64
+	certificates    []cnao.CertificateStatus
+	mux             sync.Mutex
+	eventEmitter    eventemitter.EventEmitter
+
+	// clusterOperator enables mirroring of the status to the OpenShift ClusterOperator
+	clusterOperator bool
+	relatedObjects  []osconfv1.ObjectReference
 }
 
-func New(mgr manager.Manager, name string) *StatusManager {
+// New creates a StatusManager of the given NetworkAddonsConfig. With clusterOperator set, the status
+// is mirrored to the OpenShift ClusterOperator too.
+func New(mgr manager.Manager, name string, clusterOperator bool) *StatusManager {
 	return &StatusManager{
-		client:       mgr.GetClient(),
-		name:         name,
-		eventEmitter: eventemitter.New(mgr),
+		client:          mgr.GetClient(),
+		name:            name,
+		eventEmitter:    eventemitter.New(mgr),
+		clusterOperator: clusterOperator,
 	}
 }
 
@@ -93,8 +107,12 @@ func (status *StatusManager) Set(reachedAvailableLevel bool, conditions ...condi
 func (status *StatusManager) set(reachedAvailableLevel bool, conditions ...conditionsv1.Condition) error {
 	config, err := status.getCurrentNetworkAddonsConfig()
 	if err != nil {
-		log.Printf("Failed to get NetworkAddonsOperator %q in order to update its State: %v", status.name, err)
-		return nil
+		// There is nothing to update once the config is removed, other failures are retried
+		if errors.IsNotFound(err) {
+			log.Printf("NetworkAddonsOperator %q was removed, not updating its State", status.name)
+			return nil
+		}
+		return fmt.Errorf("Failed to get NetworkAddonsOperator %q in order to update its State: %v", status.name, err)
 	}
 
 	patch := client.MergeFrom(config.DeepCopy())
@@ -172,6 +190,17 @@ func (status *StatusManager) set(reachedAvailableLevel bool, conditions ...condi
 	// Make sure to expose deployed containers
 	_, _, config.Status.Containers, status.generation = status.GetAttributes()
 
+	// Expose CNI directories used by deployed components
+	config.Status.CNIDirectories = status.getCNIDirectories()
+
+	// Expose the revision of the applied configuration
+	if appliedRevision := status.getAppliedRevision(); appliedRevision != 0 {
+		config.Status.AppliedRevision = appliedRevision
+	}
+
+	// Expose expiry of certificates deployed by the operator
+	config.Status.Certificates = status.getCertificates()
+
 	// Expose currently handled version
 	config.Status.OperatorVersion = operatorVersion
 	config.Status.TargetVersion = operatorVersion
@@ -179,14 +208,20 @@ func (status *StatusManager) set(reachedAvailableLevel bool, conditions ...condi
 	// Failing condition had been replaced by Degraded in 0.12.0, drop it from CR if needed
 	conditionsv1.RemoveStatusCondition(&config.Status.Conditions, conditionsv1.ConditionType("Failing"))
 
-	if (*oldStatus).DeepEqual(config.Status) {
-		return nil
+	if !(*oldStatus).DeepEqual(config.Status) {
+		// Patch NetworkAddonsConfig's status
+		err = status.client.Status().Patch(context.TODO(), config, patch)
+		if err != nil {
+			return fmt.Errorf("Failed to patch NetworkAddonsConfig %q Status: %v", config.Name, err)
+		}
 	}
 
-	// Patch NetworkAddonsConfig's status
-	err = status.client.Status().Patch(context.TODO(), config, patch)
-	if err != nil {
-		return fmt.Errorf("Failed to patch NetworkAddonsConfig %q Status: %v", config.Name, err)
+	// Expose the status to OpenShift cluster administrators. NetworkAddonsConfig is the source of
+	// truth, failing to mirror it must not fail its update, it is mirrored again on the next one.
+	if status.clusterOperator {
+		if err := status.syncClusterOperator(config); err != nil {
+			log.Printf("Failed to sync ClusterOperator %q: %v", names.CLUSTER_OPERATOR, err)
+		}
 	}
 	return nil
 }
@@ -202,6 +237,22 @@ func (status *StatusManager) getCurrentNetworkAddonsConfig() (*cnaov1.NetworkAdd
 	return config, nil
 }
 
+// getCurrentNetworkAddonsConfigWithRetries reads the NetworkAddonsConfig, transient failures are retried
+// the same way updates of its status are
+func (status *StatusManager) getCurrentNetworkAddonsConfigWithRetries() (*cnaov1.NetworkAddonsConfig, error) {
+	var err error
+	for i := 0; i < conditionsUpdateRetries; i++ {
+		var config *cnaov1.NetworkAddonsConfig
+		config, err = status.getCurrentNetworkAddonsConfig()
+		if err == nil || errors.IsNotFound(err) {
+			return config, err
+		}
+		log.Printf("Failed getting NetworkAddonsOperator %q %d/%d: %v", status.name, i+1, conditionsUpdateRetries, err)
+		time.Sleep(conditionsUpdateCoolDown)
+	}
+	return nil, err
+}
+
 // IsStatusAvailable returns true if NetworkAddonsConfig intance is in Available True state
 func (status *StatusManager) IsStatusAvailable() bool {
 	config, err := status.getCurrentNetworkAddonsConfig()
@@ -270,6 +321,45 @@ func (status *StatusManager) GetAttributes() ([]types.NamespacedName, []types.Na
 	return status.daemonSets, status.deployments, status.containers, status.generation
 }
 
+// SetCNIDirectories sets CNI directories deployed components were installed to
+func (status *StatusManager) SetCNIDirectories(cniDirectories *cnao.CNIDirectories) {
+	status.mux.Lock()
+	defer status.mux.Unlock()
+	status.cniDirectories = cniDirectories
+}
+
+func (status *StatusManager) getCNIDirectories() *cnao.CNIDirectories {
+	status.mux.Lock()
+	defer status.mux.Unlock()
+	return status.cniDirectories
+}
+
+// SetAppliedRevision sets the revision of the configuration applied by the operator
+func (status *StatusManager) SetAppliedRevision(revision int64) {
+	status.mux.Lock()
+	defer status.mux.Unlock()
+	status.appliedRevision = revision
+}
+
+func (status *StatusManager) getAppliedRevision() int64 {
+	status.mux.Lock()
+	defer status.mux.Unlock()
+	return status.appliedRevision
+}
+
+// SetCertificates sets the expiry of webhook certificates deployed by the operator
+func (status *StatusManager) SetCertificates(certificates []cnao.CertificateStatus) {
+	status.mux.Lock()
+	defer status.mux.Unlock()
+	status.certificates = certificates
+}
+
+func (status *StatusManager) getCertificates() []cnao.CertificateStatus {
+	status.mux.Lock()
+	defer status.mux.Unlock()
+	return status.certificates
+}
+
 // SetFromOperator sets the operator status
 func (status *StatusManager) SetFromOperator() {
 	conditions := []conditionsv1.Condition{}
@@ -319,6 +409,19 @@ func (status *StatusManager) SetFromPods() {
 			return
 		}
 
+		// Check whether CNI plugins installed by the DaemonSet are in place on all nodes
+		unhealthyNodes, err := status.unhealthyCNINodes(ds)
+		if err != nil {
+			status.SetFailing(PodDeployment, "InternalError",
+				fmt.Sprintf("Internal error checking CNI plugins health: %v", err))
+			return
+		}
+		if len(unhealthyNodes) > 0 {
+			status.SetFailing(PodDeployment, "CNIPluginUnhealthy",
+				fmt.Sprintf("DaemonSet %q CNI plugin binaries or configuration are missing or modified on nodes: %s", dsName.String(), strings.Join(unhealthyNodes, "; ")))
+			return
+		}
+
 		// Finally check whether Pods belonging to this DaemonSets are being started or they
 		// are being scheduled.
 		if ds.Status.NumberUnavailable > 0 {
@@ -332,6 +435,38 @@ func (status *StatusManager) SetFromPods() {
 		}
 	}
 
+	// Host artifacts of removed components have to be removed from all nodes before the removal
+	// is done
+	for _, dsName := range status.getHostCleanups() {
+		ds := &appsv1.DaemonSet{}
+		if err := status.client.Get(context.TODO(), dsName, ds); err != nil {
+			if errors.IsNotFound(err) {
+				// The cleanup DaemonSet is removed once it finishes
+				continue
+			}
+			status.SetFailing(PodDeployment, "InternalError",
+				fmt.Sprintf("Internal error removing host artifacts: %v", err))
+			return
+		}
+
+		component := ds.Labels[names.HOST_CLEANUP_LABEL_KEY]
+		failedNodes, err := status.failedHostCleanupNodes(ds)
+		if err != nil {
+			status.SetFailing(PodDeployment, "InternalError",
+				fmt.Sprintf("Internal error removing host artifacts: %v", err))
+			return
+		}
+		if len(failedNodes) > 0 {
+			status.SetFailing(PodDeployment, "HostCleanupFailed",
+				fmt.Sprintf("Failed to remove host artifacts of %s on nodes: %s", component, strings.Join(failedNodes, ", ")))
+			return
+		}
+
+		if !HostCleanupComplete(ds) {
+			progressing = append(progressing, fmt.Sprintf("Host artifacts of %s are being removed (%d out of %d nodes done)", component, ds.Status.NumberReady, ds.Status.DesiredNumberScheduled))
+		}
+	}
+
 	// Do the same for Deployments. Iterate all owned Deployments and check whether they are
 	// progressing smoothly or have been already deployed.
 	for _, depName := range deployments {
@@ -398,8 +533,9 @@ func (status *StatusManager) SetFromPods() {
 	status.MarkStatusLevelNotFailing(PodDeployment)
 	conditions = append(conditions, status.getFailureStateCondition())
 
-	config, err := status.getCurrentNetworkAddonsConfig()
+	config, err := status.getCurrentNetworkAddonsConfigWithRetries()
 	if err != nil {
+		log.Printf("Failed to get NetworkAddonsOperator %q in order to assess availability: %v", status.name, err)
 		return
 	}
 
@@ -411,3 +547,18 @@ func (status *StatusManager) SetFromPods() {
 	// set the aggregated conditions to status-manager
 	status.Set(availableStatusReached, conditions...)
 }
+
+// DaemonSetAvailable checks whether the DaemonSet finished its rollout and its pods are available
+func DaemonSetAvailable(ds *appsv1.DaemonSet) bool {
+	return ds.Status.NumberUnavailable == 0 &&
+		(ds.Status.NumberAvailable > 0 || ds.Status.DesiredNumberScheduled == 0) &&
+		ds.Status.UpdatedNumberScheduled >= ds.Status.DesiredNumberScheduled &&
+		ds.Generation <= ds.Status.ObservedGeneration
+}
+
+// DeploymentAvailable checks whether the Deployment finished its rollout and its pods are available
+func DeploymentAvailable(dep *appsv1.Deployment) bool {
+	return dep.Status.UnavailableReplicas == 0 &&
+		dep.Status.AvailableReplicas > 0 &&
+		dep.Status.ObservedGeneration >= dep.Generation
+}
@@ -0,0 +1,140 @@
+package statusmanager
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	conditionsv1 "github.com/openshift/custom-resource-status/conditions/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	cnaov1 "github.com/kubevirt/cluster-network-addons-operator/pkg/apis/networkaddonsoperator/v1"
+	"github.com/kubevirt/cluster-network-addons-operator/pkg/faultinjection"
+	"github.com/kubevirt/cluster-network-addons-operator/pkg/names"
+)
+
+// recordingEmitter keeps reasons of emitted events instead of sending them
+type recordingEmitter struct {
+	reasons []string
+}
+
+func (e *recordingEmitter) Init(manager.Manager) {}
+func (e *recordingEmitter) EmitEventForConfig(_ *cnaov1.NetworkAddonsConfig, _, reason, _ string) {
+	e.reasons = append(e.reasons, reason)
+}
+func (e *recordingEmitter) EmitModifiedForConfig()    { e.reasons = append(e.reasons, "Modified") }
+func (e *recordingEmitter) EmitProgressingForConfig() { e.reasons = append(e.reasons, "Progressing") }
+func (e *recordingEmitter) EmitFailingForConfig(reason, _ string) {
+	e.reasons = append(e.reasons, "Failed: "+reason)
+}
+func (e *recordingEmitter) EmitAvailableForConfig() { e.reasons = append(e.reasons, "Available") }
+
+var _ = Describe("Status manager with injected faults", func() {
+	const namespace = "cluster-network-addons"
+	daemonSetKey := types.NamespacedName{Namespace: namespace, Name: "bridge-marker"}
+
+	var (
+		base    client.Client
+		emitter *recordingEmitter
+	)
+
+	readyDaemonSet := func() *appsv1.DaemonSet {
+		return &appsv1.DaemonSet{
+			ObjectMeta: metav1.ObjectMeta{Name: daemonSetKey.Name, Namespace: namespace, Generation: 1},
+			Status:     appsv1.DaemonSetStatus{ObservedGeneration: 1, DesiredNumberScheduled: 1, NumberAvailable: 1, UpdatedNumberScheduled: 1},
+		}
+	}
+
+	statusManager := func(faults ...faultinjection.Fault) (*StatusManager, *faultinjection.Client) {
+		c := faultinjection.NewClient(base, 0, faults...)
+		status := &StatusManager{client: c, name: names.OPERATOR_CONFIG, eventEmitter: emitter}
+		status.SetAttributes([]types.NamespacedName{daemonSetKey}, nil, nil, 1)
+		return status, c
+	}
+
+	condition := func(conditionType conditionsv1.ConditionType) *conditionsv1.Condition {
+		config := &cnaov1.NetworkAddonsConfig{}
+		Expect(base.Get(context.TODO(), types.NamespacedName{Name: names.OPERATOR_CONFIG}, config)).To(Succeed())
+		return conditionsv1.FindStatusCondition(config.Status.Conditions, conditionType)
+	}
+
+	conditionStatus := func(conditionType conditionsv1.ConditionType) corev1.ConditionStatus {
+		if c := condition(conditionType); c != nil {
+			return c.Status
+		}
+		return ""
+	}
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		Expect(cnaov1.AddToScheme(scheme)).To(Succeed())
+		config := &cnaov1.NetworkAddonsConfig{ObjectMeta: metav1.ObjectMeta{Name: names.OPERATOR_CONFIG, Generation: 1}}
+		base = fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+			config,
+			&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: namespace}},
+			readyDaemonSet(),
+		).Build()
+		emitter = &recordingEmitter{}
+	})
+
+	DescribeTable("should report available components",
+		func(faults ...faultinjection.Fault) {
+			status, c := statusManager(faults...)
+			status.SetFromPods()
+
+			Expect(c.Injected()).To(BeNumerically(">", 0))
+			Expect(conditionStatus(conditionsv1.ConditionAvailable)).To(Equal(corev1.ConditionTrue))
+			Expect(conditionStatus(conditionsv1.ConditionProgressing)).To(Equal(corev1.ConditionFalse))
+			Expect(conditionStatus(conditionsv1.ConditionDegraded)).To(Equal(corev1.ConditionFalse))
+			Expect(emitter.reasons).To(ContainElement("Available"))
+		},
+		Entry("despite conflicting status patches",
+			faultinjection.Fault{Type: faultinjection.Conflict, Probability: 1, Count: 3}),
+		Entry("despite timeouts reading the config",
+			faultinjection.Fault{Type: faultinjection.Timeout, Verbs: []faultinjection.Verb{faultinjection.Get}, Kinds: []string{"NetworkAddonsConfig"}, Probability: 1, Count: 3}),
+		Entry("despite a lost response to a status patch",
+			faultinjection.Fault{Type: faultinjection.Partial, Probability: 1, Count: 1}),
+		Entry("despite random faults of all types",
+			faultinjection.Fault{Type: faultinjection.Conflict, Probability: 0.3, Count: 3},
+			faultinjection.Fault{Type: faultinjection.Timeout, Kinds: []string{"NetworkAddonsConfig"}, Probability: 0.3, Count: 3},
+			faultinjection.Fault{Type: faultinjection.Partial, Probability: 0.3, Count: 3}),
+	)
+
+	It("should converge on the next update once retries are exhausted", func() {
+		status, _ := statusManager(faultinjection.Fault{Type: faultinjection.Conflict, Probability: 1, Count: conditionsUpdateRetries})
+		status.SetFromPods()
+		Expect(condition(conditionsv1.ConditionAvailable)).To(BeNil())
+
+		status.SetFromPods()
+		Expect(conditionStatus(conditionsv1.ConditionAvailable)).To(Equal(corev1.ConditionTrue))
+	})
+
+	It("should report a DaemonSet missing due to a race and recover once it is found", func() {
+		status, _ := statusManager(faultinjection.Fault{Type: faultinjection.NotFound, Verbs: []faultinjection.Verb{faultinjection.Get}, Kinds: []string{"DaemonSet"}, Probability: 1, Count: 1})
+		status.SetFromPods()
+		Expect(conditionStatus(conditionsv1.ConditionDegraded)).To(Equal(corev1.ConditionTrue))
+		Expect(condition(conditionsv1.ConditionDegraded).Reason).To(Equal("NoDaemonSet"))
+		Expect(conditionStatus(conditionsv1.ConditionAvailable)).To(Equal(corev1.ConditionFalse))
+
+		status.SetFromPods()
+		Expect(conditionStatus(conditionsv1.ConditionDegraded)).To(Equal(corev1.ConditionFalse))
+		Expect(conditionStatus(conditionsv1.ConditionAvailable)).To(Equal(corev1.ConditionTrue))
+	})
+
+	It("should stop updating the status once the config is removed", func() {
+		status, c := statusManager(faultinjection.Fault{Type: faultinjection.NotFound, Verbs: []faultinjection.Verb{faultinjection.Get}, Kinds: []string{"NetworkAddonsConfig"}, Probability: 1})
+		status.Set(true)
+		Expect(c.Injected()).To(Equal(1))
+		Expect(condition(conditionsv1.ConditionAvailable)).To(BeNil())
+	})
+})
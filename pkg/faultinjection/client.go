@@ -0,0 +1,157 @@
+package faultinjection
+
+import (
+	"context"
+	"log"
+	"math/rand"
+	"strings"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/cluster"
+)
+
+// Client injects faults into calls of the wrapped client
+type Client struct {
+	client.Client
+	lock     sync.Mutex
+	faults   []Fault
+	injected []int
+	random   *rand.Rand
+}
+
+var _ client.Client = &Client{}
+
+// NewClient wraps the client, faults are picked at random from the seed, so a failing sequence of calls
+// can be reproduced
+func NewClient(c client.Client, seed int64, faults ...Fault) *Client {
+	return &Client{
+		Client:   c,
+		faults:   faults,
+		injected: make([]int, len(faults)),
+		random:   rand.New(rand.NewSource(seed)),
+	}
+}
+
+// NewClientFunc returns a function creating clients of a manager, with faults injected into the default ones
+func NewClientFunc(seed int64, faults ...Fault) cluster.NewClientFunc {
+	return func(cache cache.Cache, config *rest.Config, options client.Options, uncachedObjects ...client.Object) (client.Client, error) {
+		c, err := cluster.DefaultNewClient(cache, config, options, uncachedObjects...)
+		if err != nil {
+			return nil, err
+		}
+		return NewClient(c, seed, faults...), nil
+	}
+}
+
+// Injected returns the number of faults injected so far
+func (c *Client) Injected() int {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	total := 0
+	for _, count := range c.injected {
+		total += count
+	}
+	return total
+}
+
+// pick selects the fault injected into the call, if any
+func (c *Client) pick(verb Verb, kind string) *Fault {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	for i := range c.faults {
+		fault := &c.faults[i]
+		if !fault.matches(verb, kind) {
+			continue
+		}
+		if fault.Count > 0 && c.injected[i] >= fault.Count {
+			continue
+		}
+		if fault.Probability < 1 && c.random.Float64() >= fault.Probability {
+			continue
+		}
+		c.injected[i]++
+		return fault
+	}
+	return nil
+}
+
+// call runs the call unless a fault is injected in its place, partial failures are reported after the call
+func (c *Client) call(verb Verb, obj runtime.Object, name string, do func() error) error {
+	gvk, err := apiutil.GVKForObject(obj, c.Scheme())
+	if err != nil {
+		return do()
+	}
+	gvk = gvk.GroupVersion().WithKind(strings.TrimSuffix(gvk.Kind, "List"))
+	fault := c.pick(verb, gvk.Kind)
+	if fault == nil {
+		return do()
+	}
+
+	resource := c.resourceFor(gvk)
+	log.Printf("injecting %s fault into %s of %s %q", fault.Type, verb, resource, name)
+	if fault.injectsBeforeCall() {
+		return fault.err(verb, resource, name)
+	}
+	if err := do(); err != nil {
+		return err
+	}
+	return fault.err(verb, resource, name)
+}
+
+// resourceFor returns the resource of the kind reported in injected errors, its plural is guessed if the
+// RESTMapper does not know the kind
+func (c *Client) resourceFor(gvk schema.GroupVersionKind) schema.GroupResource {
+	if mapping, err := c.RESTMapper().RESTMapping(gvk.GroupKind(), gvk.Version); err == nil {
+		return mapping.Resource.GroupResource()
+	}
+	plural, _ := meta.UnsafeGuessKindToResource(gvk)
+	return plural.GroupResource()
+}
+
+func (c *Client) Get(ctx context.Context, key client.ObjectKey, obj client.Object) error {
+	return c.call(Get, obj, key.Name, func() error { return c.Client.Get(ctx, key, obj) })
+}
+
+func (c *Client) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	return c.call(List, list, "", func() error { return c.Client.List(ctx, list, opts...) })
+}
+
+func (c *Client) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	return c.call(Create, obj, obj.GetName(), func() error { return c.Client.Create(ctx, obj, opts...) })
+}
+
+func (c *Client) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	return c.call(Update, obj, obj.GetName(), func() error { return c.Client.Update(ctx, obj, opts...) })
+}
+
+func (c *Client) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	return c.call(Patch, obj, obj.GetName(), func() error { return c.Client.Patch(ctx, obj, patch, opts...) })
+}
+
+func (c *Client) Delete(ctx context.Context, obj client.Object, opts ...client.DeleteOption) error {
+	return c.call(Delete, obj, obj.GetName(), func() error { return c.Client.Delete(ctx, obj, opts...) })
+}
+
+func (c *Client) Status() client.StatusWriter {
+	return &statusWriter{StatusWriter: c.Client.Status(), client: c}
+}
+
+type statusWriter struct {
+	client.StatusWriter
+	client *Client
+}
+
+func (w *statusWriter) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	return w.client.call(StatusUpdate, obj, obj.GetName(), func() error { return w.StatusWriter.Update(ctx, obj, opts...) })
+}
+
+func (w *statusWriter) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	return w.client.call(StatusPatch, obj, obj.GetName(), func() error { return w.StatusWriter.Patch(ctx, obj, patch, opts...) })
+}
@@ -0,0 +1,139 @@
+package faultinjection_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kubevirt/cluster-network-addons-operator/pkg/faultinjection"
+)
+
+var _ = Describe("Fault injecting client", func() {
+	var base client.Client
+	key := types.NamespacedName{Namespace: "default", Name: "config"}
+
+	configMap := func(value string) *corev1.ConfigMap {
+		return &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Namespace: key.Namespace, Name: key.Name},
+			Data:       map[string]string{"key": value},
+		}
+	}
+
+	storedValue := func() string {
+		stored := &corev1.ConfigMap{}
+		Expect(base.Get(context.TODO(), key, stored)).To(Succeed())
+		return stored.Data["key"]
+	}
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		base = fake.NewClientBuilder().WithScheme(scheme).WithObjects(configMap("stored")).Build()
+	})
+
+	It("should fail updates with a conflict without changing the object", func() {
+		c := faultinjection.NewClient(base, 0, faultinjection.Fault{Type: faultinjection.Conflict, Probability: 1})
+		err := c.Update(context.TODO(), configMap("updated"))
+		Expect(apierrors.IsConflict(err)).To(BeTrue(), "unexpected error: %v", err)
+		Expect(storedValue()).To(Equal("stored"))
+	})
+
+	It("should report the resource of the object in injected errors", func() {
+		c := faultinjection.NewClient(base, 0, faultinjection.Fault{Type: faultinjection.NotFound, Probability: 1})
+		err := c.Get(context.TODO(), types.NamespacedName{Namespace: "default", Name: "policy"}, &networkingv1.NetworkPolicy{})
+		Expect(apierrors.IsNotFound(err)).To(BeTrue(), "unexpected error: %v", err)
+		details := err.(apierrors.APIStatus).Status().Details
+		Expect(details.Group).To(Equal("networking.k8s.io"))
+		Expect(details.Kind).To(Equal("networkpolicies"))
+	})
+
+	It("should perform a partially failing update", func() {
+		c := faultinjection.NewClient(base, 0, faultinjection.Fault{Type: faultinjection.Partial, Probability: 1})
+		existing := &corev1.ConfigMap{}
+		Expect(c.Get(context.TODO(), key, existing)).To(Succeed())
+		existing.Data["key"] = "updated"
+		err := c.Update(context.TODO(), existing)
+		Expect(apierrors.IsTimeout(err)).To(BeTrue(), "unexpected error: %v", err)
+		Expect(storedValue()).To(Equal("updated"))
+	})
+
+	It("should report an existing object as missing and a new one as existing", func() {
+		c := faultinjection.NewClient(base, 0, faultinjection.Fault{Type: faultinjection.NotFound, Probability: 1})
+		err := c.Get(context.TODO(), key, &corev1.ConfigMap{})
+		Expect(apierrors.IsNotFound(err)).To(BeTrue(), "unexpected error: %v", err)
+
+		created := configMap("created")
+		created.Name = "created"
+		err = c.Create(context.TODO(), created)
+		Expect(apierrors.IsAlreadyExists(err)).To(BeTrue(), "unexpected error: %v", err)
+	})
+
+	It("should inject faults into status writes", func() {
+		c := faultinjection.NewClient(base, 0, faultinjection.Fault{Type: faultinjection.Timeout, Verbs: []faultinjection.Verb{faultinjection.StatusPatch}, Probability: 1})
+		Expect(c.Get(context.TODO(), key, &corev1.ConfigMap{})).To(Succeed())
+		err := c.Status().Patch(context.TODO(), configMap("patched"), client.MergeFrom(configMap("stored")))
+		Expect(apierrors.IsTimeout(err)).To(BeTrue(), "unexpected error: %v", err)
+	})
+
+	It("should inject faults only into calls of the requested kinds", func() {
+		c := faultinjection.NewClient(base, 0, faultinjection.Fault{Type: faultinjection.Timeout, Kinds: []string{"Secret"}, Probability: 1})
+		Expect(c.Get(context.TODO(), key, &corev1.ConfigMap{})).To(Succeed())
+		Expect(c.List(context.TODO(), &corev1.ConfigMapList{})).To(Succeed())
+		Expect(apierrors.IsTimeout(c.List(context.TODO(), &corev1.SecretList{}))).To(BeTrue())
+	})
+
+	It("should stop injecting a fault once its count is reached", func() {
+		c := faultinjection.NewClient(base, 0, faultinjection.Fault{Type: faultinjection.Timeout, Probability: 1, Count: 2})
+		Expect(c.Get(context.TODO(), key, &corev1.ConfigMap{})).ToNot(Succeed())
+		Expect(c.Get(context.TODO(), key, &corev1.ConfigMap{})).ToNot(Succeed())
+		Expect(c.Get(context.TODO(), key, &corev1.ConfigMap{})).To(Succeed())
+		Expect(c.Injected()).To(Equal(2))
+	})
+
+	It("should inject the same faults with the same seed", func() {
+		failures := func(seed int64) []bool {
+			c := faultinjection.NewClient(base, seed, faultinjection.Fault{Type: faultinjection.Timeout, Probability: 0.5})
+			failed := []bool{}
+			for i := 0; i < 20; i++ {
+				failed = append(failed, c.Get(context.TODO(), key, &corev1.ConfigMap{}) != nil)
+			}
+			return failed
+		}
+		Expect(failures(42)).To(Equal(failures(42)))
+		Expect(failures(42)).To(ContainElements(true, false))
+	})
+
+	Describe("ParseFaults", func() {
+		It("should read probabilities of fault types", func() {
+			faults, err := faultinjection.ParseFaults("conflict=0.1, timeout=0.05,,partial=1")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(faults).To(Equal([]faultinjection.Fault{
+				{Type: faultinjection.Conflict, Probability: 0.1},
+				{Type: faultinjection.Timeout, Probability: 0.05},
+				{Type: faultinjection.Partial, Probability: 1},
+			}))
+		})
+
+		DescribeTable("should reject invalid faults",
+			func(spec, expectedError string) {
+				_, err := faultinjection.ParseFaults(spec)
+				Expect(err).To(MatchError(ContainSubstring(expectedError)))
+			},
+			Entry("without probability", "conflict", "type=probability"),
+			Entry("of an unknown type", "crash=0.1", `unknown fault type "crash"`),
+			Entry("with an invalid probability", "timeout=often", "between 0 and 1"),
+			Entry("with a probability above one", "timeout=2", "between 0 and 1"),
+		)
+	})
+})
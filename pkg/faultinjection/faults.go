@@ -0,0 +1,137 @@
+// Package faultinjection wraps clients of the API server to inject conflicts, timeouts, races and partial
+// failures into their calls, so the operator can be checked to converge despite them
+package faultinjection
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// Verb is a call of the client faults are injected into
+type Verb string
+
+const (
+	Get          Verb = "get"
+	List         Verb = "list"
+	Create       Verb = "create"
+	Update       Verb = "update"
+	Patch        Verb = "patch"
+	Delete       Verb = "delete"
+	StatusUpdate Verb = "status-update"
+	StatusPatch  Verb = "status-patch"
+)
+
+// FaultType is the kind of failure injected into a call
+type FaultType string
+
+const (
+	// Conflict fails a write as if the object was modified concurrently
+	Conflict FaultType = "conflict"
+	// Timeout fails a call before it reaches the API server
+	Timeout FaultType = "timeout"
+	// NotFound fails a call as if the object was removed concurrently, a creation fails as if the
+	// object was created concurrently
+	NotFound FaultType = "notfound"
+	// Partial performs a write, but fails it as if the response got lost
+	Partial FaultType = "partial"
+)
+
+// defaultVerbs lists calls each type of fault is injected into unless the fault says otherwise
+var defaultVerbs = map[FaultType][]Verb{
+	Conflict: {Update, Patch, StatusUpdate, StatusPatch},
+	Timeout:  {Get, List, Create, Update, Patch, Delete, StatusUpdate, StatusPatch},
+	NotFound: {Get, Create, Update, Patch, Delete, StatusUpdate, StatusPatch},
+	Partial:  {Create, Update, Patch, Delete, StatusUpdate, StatusPatch},
+}
+
+// Fault describes failures injected into calls of the client
+type Fault struct {
+	Type FaultType
+	// Verbs are the calls the fault is injected into, calls of the type are used if empty
+	Verbs []Verb
+	// Kinds are the kinds of objects the fault is injected into, all kinds are used if empty
+	Kinds []string
+	// Probability is the chance of injecting the fault into a matching call, between 0 and 1
+	Probability float64
+	// Count limits the number of injected faults, unlimited if zero
+	Count int
+}
+
+func (f *Fault) matches(verb Verb, kind string) bool {
+	verbs := f.Verbs
+	if len(verbs) == 0 {
+		verbs = defaultVerbs[f.Type]
+	}
+	if !containsVerb(verbs, verb) {
+		return false
+	}
+	if len(f.Kinds) == 0 {
+		return true
+	}
+	for _, faultKind := range f.Kinds {
+		if faultKind == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// injectsBeforeCall says whether the call is skipped, partial failures are reported once the call is done
+func (f *Fault) injectsBeforeCall() bool {
+	return f.Type != Partial
+}
+
+func (f *Fault) err(verb Verb, resource schema.GroupResource, name string) error {
+	switch f.Type {
+	case Conflict:
+		return apierrors.NewConflict(resource, name, errors.New("injected conflict"))
+	case NotFound:
+		if verb == Create {
+			return apierrors.NewAlreadyExists(resource, name)
+		}
+		return apierrors.NewNotFound(resource, name)
+	case Partial:
+		return apierrors.NewTimeoutError(fmt.Sprintf("injected lost response to %s of %s %q", verb, resource, name), 1)
+	default:
+		return apierrors.NewTimeoutError(fmt.Sprintf("injected timeout of %s of %s %q", verb, resource, name), 1)
+	}
+}
+
+func containsVerb(verbs []Verb, verb Verb) bool {
+	for _, v := range verbs {
+		if v == verb {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseFaults reads faults from a comma separated list of type=probability pairs, e.g. "conflict=0.1,timeout=0.05"
+func ParseFaults(spec string) ([]Fault, error) {
+	faults := []Fault{}
+	for _, item := range strings.Split(spec, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		parts := strings.SplitN(item, "=", 2)
+		if len(parts) != 2 {
+			return nil, errors.Errorf("fault %q has to be in the type=probability format", item)
+		}
+		faultType := FaultType(strings.TrimSpace(parts[0]))
+		if _, known := defaultVerbs[faultType]; !known {
+			return nil, errors.Errorf("unknown fault type %q", faultType)
+		}
+		probability, err := strconv.ParseFloat(strings.TrimSpace(parts[1]), 64)
+		if err != nil || probability < 0 || probability > 1 {
+			return nil, errors.Errorf("probability of fault %q has to be a number between 0 and 1", faultType)
+		}
+		faults = append(faults, Fault{Type: faultType, Probability: probability})
+	}
+	return faults, nil
+}
@@ -5,6 +5,7 @@ import (
 	"path/filepath"
 	"regexp"
 	"strconv"
+	"strings"
 
 	"github.com/pkg/errors"
 	"github.com/prometheus/client_golang/prometheus"
@@ -12,6 +13,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/metrics"
 
 	cnao "github.com/kubevirt/cluster-network-addons-operator/pkg/apis/networkaddonsoperator/shared"
+	"github.com/kubevirt/cluster-network-addons-operator/pkg/certificates"
 	"github.com/kubevirt/cluster-network-addons-operator/pkg/controller/statusmanager"
 	"github.com/kubevirt/cluster-network-addons-operator/pkg/render"
 )
@@ -31,8 +33,10 @@ type MetricsOpts struct {
 type MetricsKey string
 
 const (
-	ReadyGauge     MetricsKey = "readyGauge"
-	KMPDeployGauge MetricsKey = "kmpDeployedGauge"
+	ReadyGauge                 MetricsKey = "readyGauge"
+	KMPDeployGauge             MetricsKey = "kmpDeployedGauge"
+	CertificateExpirationGauge MetricsKey = "certificateExpirationGauge"
+	CertificateRotationGauge   MetricsKey = "certificateRotationGauge"
 )
 
 var MetricsOptsList = map[MetricsKey]MetricsOpts{
@@ -46,6 +50,16 @@ var MetricsOptsList = map[MetricsKey]MetricsOpts{
 		Help: "KubeMacpool is deployed by CNAO CR",
 		Type: "Gauge",
 	},
+	CertificateExpirationGauge: {
+		Name: "kubevirt_cnao_certificate_expiration_timestamp_seconds",
+		Help: "Expiration time of webhook serving certificates and CA bundles deployed by CNAO, in seconds since the epoch",
+		Type: "Gauge",
+	},
+	CertificateRotationGauge: {
+		Name: "kubevirt_cnao_certificate_rotation_timestamp_seconds",
+		Help: "Time webhook serving certificates and CA bundles deployed by CNAO are due to be rotated by their issuer, in seconds since the epoch",
+		Type: "Gauge",
+	},
 }
 
 var (
@@ -59,10 +73,20 @@ var (
 			Name: MetricsOptsList[KMPDeployGauge].Name,
 			Help: MetricsOptsList[KMPDeployGauge].Help,
 		})
+	certificateExpirationGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: MetricsOptsList[CertificateExpirationGauge].Name,
+			Help: MetricsOptsList[CertificateExpirationGauge].Help,
+		}, []string{"kind", "object_namespace", "object_name"})
+	certificateRotationGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: MetricsOptsList[CertificateRotationGauge].Name,
+			Help: MetricsOptsList[CertificateRotationGauge].Help,
+		}, []string{"kind", "object_namespace", "object_name"})
 )
 
 func init() {
-	metrics.Registry.MustRegister(readyGauge, kmpDeployedGauge)
+	metrics.Registry.MustRegister(readyGauge, kmpDeployedGauge, certificateExpirationGauge, certificateRotationGauge)
 }
 
 func setGaugeParam(setTrueFlag bool, gaugeParam *prometheus.Gauge) {
@@ -76,6 +100,8 @@ func setGaugeParam(setTrueFlag bool, gaugeParam *prometheus.Gauge) {
 func ResetMonitoredComponents() {
 	setGaugeParam(false, &readyGauge)
 	setGaugeParam(false, &kmpDeployedGauge)
+	certificateExpirationGauge.Reset()
+	certificateRotationGauge.Reset()
 }
 
 func TrackMonitoredComponents(conf *cnao.NetworkAddonsConfigSpec, statusManager *statusmanager.StatusManager) {
@@ -84,14 +110,36 @@ func TrackMonitoredComponents(conf *cnao.NetworkAddonsConfigSpec, statusManager
 	setGaugeParam(statusManager.IsStatusAvailable(), &readyGauge)
 }
 
-func RenderMonitoring(manifestDir string, monitoringAvailable bool) ([]*unstructured.Unstructured, error) {
+// TrackCertificates exposes expiration and due rotation of certificates deployed by the operator
+func TrackCertificates(inspected []certificates.Certificate) {
+	certificateExpirationGauge.Reset()
+	certificateRotationGauge.Reset()
+	for _, certificate := range inspected {
+		source := certificate.Source
+		certificateExpirationGauge.WithLabelValues(source.Kind, source.Namespace, source.Name).Set(float64(certificate.NotAfter.Unix()))
+		certificateRotationGauge.WithLabelValues(source.Kind, source.Namespace, source.Name).Set(float64(certificate.RotationTime.Unix()))
+	}
+}
+
+// RenderMonitoring generates monitoring manifests, components are scraped in the operand namespace
+// and in the given namespaces
+func RenderMonitoring(manifestDir string, monitoringAvailable bool, namespaces []string) ([]*unstructured.Unstructured, error) {
 	if !monitoringAvailable {
 		return nil, nil
 	}
 
+	scrapedNamespaces := []string{os.Getenv("OPERAND_NAMESPACE")}
+	for _, namespace := range namespaces {
+		if namespace != scrapedNamespaces[0] {
+			scrapedNamespaces = append(scrapedNamespaces, namespace)
+		}
+	}
+
 	// render the manifests on disk
 	data := render.MakeRenderData()
-	data.Data["Namespace"] = os.Getenv("OPERAND_NAMESPACE")
+	data.Data["Namespace"] = scrapedNamespaces[0]
+	data.Data["ScrapedNamespaces"] = scrapedNamespaces
+	data.Data["ScrapedNamespacesRegex"] = strings.Join(scrapedNamespaces, "|")
 	data.Data["MonitoringNamespace"] = getNamespace()
 	data.Data["MonitoringServiceAccount"] = getServiceAccount()
 
@@ -13,6 +13,15 @@ const APPLIED_PREFIX = "cluster-networks-addons-operator-applied-"
 // garbage collection deletion upon NetworkAddonsConfig removal.
 const REJECT_OWNER_ANNOTATION = "networkaddonsoperator.network.kubevirt.io/rejectOwner"
 
+// ROLLBACK_ANNOTATION can be set on NetworkAddonsConfig to the number of an applied
+// revision. The operator then replaces the spec with the one of the revision.
+const ROLLBACK_ANNOTATION = "networkaddonsoperator.network.kubevirt.io/rollbackToRevision"
+
+// ROTATE_CERTIFICATES_ANNOTATION can be set on NetworkAddonsConfig to force immediate rotation of
+// webhook serving certificates deployed by the operator. The operator sets it on its own webhook
+// certificates Secret too, to request rotation from replicas serving the webhooks.
+const ROTATE_CERTIFICATES_ANNOTATION = "networkaddonsoperator.network.kubevirt.io/rotateCertificates"
+
 const PROMETHEUS_LABEL_KEY = "prometheus.cnao.io"
 const PROMETHEUS_LABEL_VALUE = "true"
 
@@ -25,3 +34,56 @@ const COMPONENT_LABEL_DEFAULT_VALUE = "network"
 const MANAGED_BY_LABEL_DEFAULT_VALUE = "cnao-operator"
 
 const KUBEMACPOOL_CONTROL_PLANE_KEY = "control-plane"
+
+// OVS_NODE_LABEL_KEY marks nodes detected to run Open vSwitch, ovs-cni can be
+// restricted to these nodes
+const OVS_NODE_LABEL_KEY = "network.kubevirt.io/ovs"
+const OVS_NODE_LABEL_VALUE = "true"
+
+// OVS_NODE_LABELED_ANNOTATION marks nodes labeled by the operator, only these
+// labels are removed by the operator once they are not needed
+const OVS_NODE_LABELED_ANNOTATION = "networkaddonsoperator.network.kubevirt.io/ovsNodeLabeled"
+
+// TRUSTED_CA_BUNDLE_CONFIGMAP is the ConfigMap OpenShift injects the cluster
+// trusted CA bundle into, it is mounted to components reaching external services
+const TRUSTED_CA_BUNDLE_CONFIGMAP = "cnao-trusted-ca-bundle"
+
+// TRUSTED_CA_BUNDLE_INJECT_LABEL_KEY requests OpenShift to inject the trusted
+// CA bundle into the labeled ConfigMap
+const TRUSTED_CA_BUNDLE_INJECT_LABEL_KEY = "config.openshift.io/inject-trusted-cabundle"
+
+// WEBHOOK_SERVICE is the Service exposing admission webhooks served by the operator
+const WEBHOOK_SERVICE = "cluster-network-addons-operator-webhook"
+
+// WEBHOOK_CERT_SECRET keeps the CA and the serving certificate of the operator
+// admission webhooks, both are issued and rotated by the operator
+const WEBHOOK_CERT_SECRET = "cluster-network-addons-operator-webhook-cert"
+
+// VALIDATING_WEBHOOK_CONFIGURATION registers the operator validating webhooks
+const VALIDATING_WEBHOOK_CONFIGURATION = "cluster-network-addons-operator-validator"
+
+// NETWORK_ADDONS_CONFIG_CRD is the CustomResourceDefinition of NetworkAddonsConfig,
+// its versions are converted by the operator conversion webhook
+const NETWORK_ADDONS_CONFIG_CRD = "networkaddonsconfigs.networkaddonsoperator.network.kubevirt.io"
+
+// WEBHOOK_PORT is the port operator admission webhooks are served at
+const WEBHOOK_PORT = 9443
+
+// CONVERSION_WEBHOOK_PATH is where the operator serves conversion of NetworkAddonsConfig versions
+const CONVERSION_WEBHOOK_PATH = "/convert"
+
+// CLUSTER_OPERATOR is the OpenShift ClusterOperator reporting the state of the operator
+// and its components to cluster administrators
+const CLUSTER_OPERATOR = "cluster-network-addons"
+
+// CNI_HEALTH_CONTAINER is the container of CNI plugin DaemonSets that verifies on each
+// node that plugin binaries and configuration are installed as shipped by the image
+const CNI_HEALTH_CONTAINER = "cni-health"
+
+// CNI_HEALTH_CONDITION is the condition the CNI health container sets on its pod, reporting
+// whether plugin binaries and configuration are installed on the node as shipped by the image
+const CNI_HEALTH_CONDITION = "networkaddonsoperator.network.kubevirt.io/CNIPluginsHealthy"
+
+// HOST_CLEANUP_LABEL_KEY marks objects removing CNI binaries and configuration left on nodes
+// by a removed component, its value is the name of the component
+const HOST_CLEANUP_LABEL_KEY = "networkaddonsoperator.network.kubevirt.io/host-cleanup"
@@ -1,8 +1,18 @@
 package network
 
+import (
+	cnao "github.com/kubevirt/cluster-network-addons-operator/pkg/apis/networkaddonsoperator/shared"
+)
+
 type ClusterInfo struct {
 	SCCAvailable        bool
 	OpenShift4          bool
 	MonitoringAvailable bool
 	IsSingleReplica     bool
+	// CNIDirectories are the CNI directories detected on cluster nodes, nil if unknown
+	CNIDirectories *cnao.CNIDirectories
+	// ProxyConfigAvailable is set when the cluster-wide OpenShift Proxy configuration is available
+	ProxyConfigAvailable bool
+	// Proxy is the cluster-wide proxy configuration, nil if not available
+	Proxy *cnao.Proxy
 }
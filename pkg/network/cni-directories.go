@@ -0,0 +1,130 @@
+package network
+
+import (
+	"path"
+	"strings"
+
+	"github.com/pkg/errors"
+	v1 "k8s.io/api/core/v1"
+
+	cnao "github.com/kubevirt/cluster-network-addons-operator/pkg/apis/networkaddonsoperator/shared"
+	"github.com/kubevirt/cluster-network-addons-operator/pkg/network/cni"
+)
+
+// cniDistribution describes where a Kubernetes distribution configures its container runtime to look for CNI
+type cniDistribution struct {
+	name      string
+	matches   func(node *v1.Node) bool
+	configDir string
+	binDir    string
+}
+
+// cniDistributions lists distributions with CNI directories that can be recognized from their nodes
+var cniDistributions = []cniDistribution{
+	{
+		name: "k3s",
+		matches: func(node *v1.Node) bool {
+			return strings.Contains(node.Status.NodeInfo.KubeletVersion, "+k3s")
+		},
+		configDir: cni.ConfigDirK3s,
+		binDir:    cni.BinDirK3s,
+	},
+	{
+		name: "RKE2",
+		matches: func(node *v1.Node) bool {
+			return strings.Contains(node.Status.NodeInfo.KubeletVersion, "+rke2")
+		},
+		configDir: cni.ConfigDir,
+		binDir:    cni.BinDir,
+	},
+	{
+		name: "MicroK8s",
+		matches: func(node *v1.Node) bool {
+			_, exists := node.GetLabels()["microk8s.io/cluster"]
+			return exists
+		},
+		configDir: cni.ConfigDirMicroK8s,
+		binDir:    cni.BinDirMicroK8s,
+	},
+	{
+		name: "Talos",
+		matches: func(node *v1.Node) bool {
+			return strings.HasPrefix(node.Status.NodeInfo.OSImage, "Talos")
+		},
+		configDir: cni.ConfigDir,
+		binDir:    cni.BinDir,
+	},
+}
+
+// DetectCNIDirectories recognizes the Kubernetes distribution from versions and labels reported by its nodes
+// and returns CNI directories configured in its container runtime. Nil is returned if the distribution is unknown.
+// The detection is a best-effort default, configuration of the container runtime is not read, so the spec can
+// override the detected directories. An error is returned if nodes of different distributions are found.
+func DetectCNIDirectories(nodes []v1.Node) (*cnao.CNIDirectories, string, error) {
+	var detected *cniDistribution
+	for i := range nodes {
+		distribution := nodeCNIDistribution(&nodes[i])
+		if i > 0 && distribution != detected {
+			return nil, "", errors.Errorf("nodes %s and %s run different distributions", nodes[0].GetName(), nodes[i].GetName())
+		}
+		detected = distribution
+	}
+
+	if detected == nil {
+		return nil, "", nil
+	}
+	return &cnao.CNIDirectories{ConfigDir: detected.configDir, BinDir: detected.binDir}, detected.name, nil
+}
+
+func nodeCNIDistribution(node *v1.Node) *cniDistribution {
+	for i := range cniDistributions {
+		if cniDistributions[i].matches(node) {
+			return &cniDistributions[i]
+		}
+	}
+	return nil
+}
+
+// validateCNIDirectories validates CNI directories requested in the spec
+func validateCNIDirectories(conf *cnao.NetworkAddonsConfigSpec) []error {
+	errs := []error{}
+
+	errs = appendOnError(errs, validateCNIDirectory("cniConfigDir", conf.CNIConfigDir))
+	errs = appendOnError(errs, validateCNIDirectory("cniBinDir", conf.CNIBinDir))
+
+	if conf.CNIConfigDir != "" && conf.CNIConfigDir == conf.CNIBinDir {
+		errs = append(errs, errors.Errorf("failed to validate cni directories: cniConfigDir and cniBinDir have to differ"))
+	}
+
+	return errs
+}
+
+func validateCNIDirectory(name, dir string) error {
+	if dir == "" {
+		return nil
+	}
+	if !path.IsAbs(dir) || path.Clean(dir) != dir || dir == "/" {
+		return errors.Errorf("failed to validate cni directories: %s %q has to be a clean absolute path other than the root directory", name, dir)
+	}
+	return nil
+}
+
+// CNIDirectories returns CNI directories components are installed to. Directories requested in the spec take
+// precedence over the ones detected on the cluster, upstream defaults are used if neither is available.
+func CNIDirectories(conf *cnao.NetworkAddonsConfigSpec, clusterInfo *ClusterInfo) *cnao.CNIDirectories {
+	directories := &cnao.CNIDirectories{ConfigDir: cni.ConfigDir, BinDir: cni.BinDir}
+	if clusterInfo.OpenShift4 {
+		directories = &cnao.CNIDirectories{ConfigDir: cni.ConfigDirOpenShift4, BinDir: cni.BinDirOpenShift4}
+	} else if clusterInfo.CNIDirectories != nil {
+		directories = clusterInfo.CNIDirectories.DeepCopy()
+	}
+
+	if conf.CNIConfigDir != "" {
+		directories.ConfigDir = conf.CNIConfigDir
+	}
+	if conf.CNIBinDir != "" {
+		directories.BinDir = conf.CNIBinDir
+	}
+
+	return directories
+}
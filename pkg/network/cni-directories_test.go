@@ -0,0 +1,110 @@
+package network
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	cnao "github.com/kubevirt/cluster-network-addons-operator/pkg/apis/networkaddonsoperator/shared"
+	"github.com/kubevirt/cluster-network-addons-operator/pkg/network/cni"
+)
+
+var _ = Describe("Testing CNI directories", func() {
+	type validateCase struct {
+		configDir     string
+		binDir        string
+		expectedError string
+	}
+	DescribeTable("validation function",
+		func(c validateCase) {
+			conf := &cnao.NetworkAddonsConfigSpec{CNIConfigDir: c.configDir, CNIBinDir: c.binDir}
+			errorList := validateCNIDirectories(conf)
+			if len(c.expectedError) > 0 {
+				Expect(errorList).To(HaveLen(1), "validation failed due to an unexpected error: %v", errorList)
+				Expect(errorList[0].Error()).To(MatchRegexp(c.expectedError))
+			} else {
+				Expect(errorList).To(BeEmpty())
+			}
+		},
+		Entry("When directories are not configured should pass", validateCase{}),
+		Entry("When directories are valid should pass", validateCase{
+			configDir: "/var/lib/rancher/k3s/agent/etc/cni/net.d",
+			binDir:    "/var/lib/rancher/k3s/data/current/bin",
+		}),
+		Entry("When config directory is relative should return an error", validateCase{
+			configDir:     "etc/cni/net.d",
+			expectedError: `failed to validate cni directories: cniConfigDir "etc/cni/net.d" has to be a clean absolute path`,
+		}),
+		Entry("When bin directory is not clean should return an error", validateCase{
+			binDir:        "/opt/cni/bin/",
+			expectedError: `failed to validate cni directories: cniBinDir "/opt/cni/bin/" has to be a clean absolute path`,
+		}),
+		Entry("When bin directory is the root directory should return an error", validateCase{
+			binDir:        "/",
+			expectedError: `failed to validate cni directories: cniBinDir "/" has to be a clean absolute path other than the root directory`,
+		}),
+		Entry("When directories are the same should return an error", validateCase{
+			configDir:     "/opt/cni",
+			binDir:        "/opt/cni",
+			expectedError: "failed to validate cni directories: cniConfigDir and cniBinDir have to differ",
+		}),
+	)
+
+	Describe("DetectCNIDirectories", func() {
+		It("should not detect directories of unknown distributions", func() {
+			nodes := []v1.Node{{Status: v1.NodeStatus{NodeInfo: v1.NodeSystemInfo{KubeletVersion: "v1.23.0"}}}}
+			cniDirectories, _, err := DetectCNIDirectories(nodes)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(cniDirectories).To(BeNil())
+		})
+
+		It("should detect k3s from the kubelet version", func() {
+			nodes := []v1.Node{{Status: v1.NodeStatus{NodeInfo: v1.NodeSystemInfo{KubeletVersion: "v1.23.6+k3s1"}}}}
+			cniDirectories, distribution, err := DetectCNIDirectories(nodes)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(distribution).To(Equal("k3s"))
+			Expect(cniDirectories).To(Equal(&cnao.CNIDirectories{ConfigDir: cni.ConfigDirK3s, BinDir: cni.BinDirK3s}))
+		})
+
+		It("should detect MicroK8s from node labels", func() {
+			nodes := []v1.Node{{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"microk8s.io/cluster": "true"}}}}
+			cniDirectories, distribution, err := DetectCNIDirectories(nodes)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(distribution).To(Equal("MicroK8s"))
+			Expect(cniDirectories).To(Equal(&cnao.CNIDirectories{ConfigDir: cni.ConfigDirMicroK8s, BinDir: cni.BinDirMicroK8s}))
+		})
+
+		It("should fail when nodes run different distributions", func() {
+			nodes := []v1.Node{
+				{ObjectMeta: metav1.ObjectMeta{Name: "k3s"}, Status: v1.NodeStatus{NodeInfo: v1.NodeSystemInfo{KubeletVersion: "v1.23.6+k3s1"}}},
+				{ObjectMeta: metav1.ObjectMeta{Name: "upstream"}, Status: v1.NodeStatus{NodeInfo: v1.NodeSystemInfo{KubeletVersion: "v1.23.0"}}},
+			}
+			_, _, err := DetectCNIDirectories(nodes)
+			Expect(err).To(MatchError("nodes k3s and upstream run different distributions"))
+		})
+	})
+
+	Describe("CNIDirectories", func() {
+		detected := &cnao.CNIDirectories{ConfigDir: cni.ConfigDirK3s, BinDir: cni.BinDirK3s}
+
+		It("should use upstream defaults when nothing is known", func() {
+			Expect(CNIDirectories(&cnao.NetworkAddonsConfigSpec{}, &ClusterInfo{})).To(Equal(&cnao.CNIDirectories{ConfigDir: cni.ConfigDir, BinDir: cni.BinDir}))
+		})
+
+		It("should use OpenShift 4 directories on OpenShift 4", func() {
+			Expect(CNIDirectories(&cnao.NetworkAddonsConfigSpec{}, &ClusterInfo{OpenShift4: true})).To(Equal(&cnao.CNIDirectories{ConfigDir: cni.ConfigDirOpenShift4, BinDir: cni.BinDirOpenShift4}))
+		})
+
+		It("should use detected directories", func() {
+			Expect(CNIDirectories(&cnao.NetworkAddonsConfigSpec{}, &ClusterInfo{CNIDirectories: detected})).To(Equal(detected))
+		})
+
+		It("should prefer directories requested in the spec", func() {
+			conf := &cnao.NetworkAddonsConfigSpec{CNIBinDir: "/usr/libexec/cni"}
+			Expect(CNIDirectories(conf, &ClusterInfo{CNIDirectories: detected})).To(Equal(&cnao.CNIDirectories{ConfigDir: cni.ConfigDirK3s, BinDir: "/usr/libexec/cni"}))
+			Expect(detected.BinDir).To(Equal(cni.BinDirK3s))
+		})
+	})
+})
@@ -0,0 +1,115 @@
+package network
+
+import (
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	cnao "github.com/kubevirt/cluster-network-addons-operator/pkg/apis/networkaddonsoperator/shared"
+	"github.com/kubevirt/cluster-network-addons-operator/pkg/names"
+)
+
+var _ = Describe("Testing CNI health", func() {
+	renderDaemonSets := func(conf *cnao.NetworkAddonsConfigSpec) map[string]*appsv1.DaemonSet {
+		Expect(FillDefaults(conf, nil)).To(Succeed())
+		objs, err := Render(conf, "../../data", nil, &ClusterInfo{})
+		Expect(err).NotTo(HaveOccurred())
+
+		daemonSets := map[string]*appsv1.DaemonSet{}
+		for _, obj := range objs {
+			if obj.GetKind() != "DaemonSet" {
+				continue
+			}
+			ds := &appsv1.DaemonSet{}
+			Expect(runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, ds)).To(Succeed())
+			daemonSets[ds.Name] = ds
+		}
+		return daemonSets
+	}
+
+	healthContainer := func(ds *appsv1.DaemonSet) *corev1.Container {
+		for i := range ds.Spec.Template.Spec.Containers {
+			if ds.Spec.Template.Spec.Containers[i].Name == names.CNI_HEALTH_CONTAINER {
+				return &ds.Spec.Template.Spec.Containers[i]
+			}
+		}
+		return nil
+	}
+
+	command := func(container *corev1.Container) string {
+		return strings.Join(container.Command, " ")
+	}
+
+	Context("when CNI plugins are deployed", func() {
+		conf := &cnao.NetworkAddonsConfigSpec{
+			Multus:      &cnao.Multus{},
+			LinuxBridge: &cnao.LinuxBridge{},
+			Ovs:         &cnao.Ovs{},
+			MacvtapCni:  &cnao.MacvtapCni{},
+		}
+
+		It("should verify installed binaries of each plugin", func() {
+			daemonSets := renderDaemonSets(conf)
+			for dsName, binary := range map[string]string{
+				"multus":                       "/usr/src/multus-cni/bin/multus=/host/opt/cni/bin/multus",
+				"kube-cni-linux-bridge-plugin": "/usr/src/github.com/containernetworking/plugins/bin/bridge=/opt/cni/bin/cnv-bridge",
+				"ovs-cni-amd64":                "/ovs=/host/opt/cni/bin/ovs",
+				"macvtap-cni":                  "/macvtap-cni=/host/opt/cni/bin/macvtap",
+			} {
+				Expect(daemonSets).To(HaveKey(dsName))
+				container := healthContainer(daemonSets[dsName])
+				Expect(container).NotTo(BeNil(), "DaemonSet %s has no CNI health container", dsName)
+				Expect(command(container)).To(ContainSubstring("--binary=" + binary))
+			}
+		})
+
+		It("should run the health container unprivileged with read-only mounts", func() {
+			for dsName, ds := range renderDaemonSets(conf) {
+				container := healthContainer(ds)
+				if container == nil {
+					continue
+				}
+				Expect(container.SecurityContext.Privileged).To(BeNil(), "DaemonSet %s runs a privileged health container", dsName)
+				Expect(*container.SecurityContext.AllowPrivilegeEscalation).To(BeFalse())
+				for _, mount := range container.VolumeMounts {
+					Expect(mount.ReadOnly).To(BeTrue(), "DaemonSet %s mounts %s writable", dsName, mount.MountPath)
+				}
+			}
+		})
+
+		It("should report health as a pod condition rather than readiness", func() {
+			for dsName, ds := range renderDaemonSets(conf) {
+				container := healthContainer(ds)
+				if container == nil {
+					continue
+				}
+				Expect(container.ReadinessProbe).To(BeNil(), "DaemonSet %s probes CNI health through readiness", dsName)
+				Expect(container.Env).To(ContainElement(corev1.EnvVar{Name: "POD_NAME", ValueFrom: &corev1.EnvVarSource{FieldRef: &corev1.ObjectFieldSelector{FieldPath: "metadata.name"}}}))
+			}
+		})
+
+		It("should verify multus configuration", func() {
+			container := healthContainer(renderDaemonSets(conf)["multus"])
+			Expect(command(container)).To(ContainSubstring("--config=/host/etc/cni/net.d/00-multus.conf"))
+		})
+
+		It("should not add the health container to DaemonSets not installing plugins", func() {
+			Expect(healthContainer(renderDaemonSets(conf)["bridge-marker"])).To(BeNil())
+		})
+	})
+
+	Context("when linux-bridge installs auxiliary plugins", func() {
+		It("should verify them too", func() {
+			installAuxiliaryPlugins := true
+			conf := &cnao.NetworkAddonsConfigSpec{LinuxBridge: &cnao.LinuxBridge{InstallAuxiliaryPlugins: &installAuxiliaryPlugins}}
+			container := healthContainer(renderDaemonSets(conf)["kube-cni-linux-bridge-plugin"])
+			Expect(command(container)).To(ContainSubstring("/usr/src/github.com/containernetworking/plugins/bin/tuning=/opt/cni/bin/cnv-tuning"))
+			Expect(command(container)).To(ContainSubstring("/usr/src/github.com/containernetworking/plugins/bin/host-local=/opt/cni/bin/cnv-host-local"))
+		})
+	})
+})
@@ -5,4 +5,8 @@ const (
 	BinDir              = "/opt/cni/bin"
 	ConfigDirOpenShift4 = "/etc/kubernetes/cni/net.d"
 	BinDirOpenShift4    = "/var/lib/cni/bin"
+	ConfigDirK3s        = "/var/lib/rancher/k3s/agent/etc/cni/net.d"
+	BinDirK3s           = "/var/lib/rancher/k3s/data/current/bin"
+	ConfigDirMicroK8s   = "/var/snap/microk8s/current/args/cni-network"
+	BinDirMicroK8s      = "/var/snap/microk8s/current/opt/cni/bin"
 )
@@ -0,0 +1,54 @@
+package network
+
+import (
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	cnao "github.com/kubevirt/cluster-network-addons-operator/pkg/apis/networkaddonsoperator/shared"
+)
+
+// ObjectContainers lists init containers and containers, with their images, of a rendered DaemonSet
+// or Deployment. Other objects have no containers.
+func ObjectContainers(obj *unstructured.Unstructured) ([]cnao.Container, error) {
+	if obj.GetAPIVersion() != "apps/v1" {
+		return nil, nil
+	}
+
+	var podSpec v1.PodSpec
+	switch obj.GetKind() {
+	case "DaemonSet":
+		daemonSet := &appsv1.DaemonSet{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, daemonSet); err != nil {
+			return nil, err
+		}
+		podSpec = daemonSet.Spec.Template.Spec
+	case "Deployment":
+		deployment := &appsv1.Deployment{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, deployment); err != nil {
+			return nil, err
+		}
+		podSpec = deployment.Spec.Template.Spec
+	default:
+		return nil, nil
+	}
+
+	containers := collectContainersInfo(obj.GetKind(), obj.GetName(), podSpec.InitContainers)
+	return append(containers, collectContainersInfo(obj.GetKind(), obj.GetName(), podSpec.Containers)...), nil
+}
+
+func collectContainersInfo(parentKind string, parentName string, containers []v1.Container) []cnao.Container {
+	containersInfo := []cnao.Container{}
+
+	for _, container := range containers {
+		containersInfo = append(containersInfo, cnao.Container{
+			ParentKind: parentKind,
+			ParentName: parentName,
+			Image:      container.Image,
+			Name:       container.Name,
+		})
+	}
+
+	return containersInfo
+}
@@ -0,0 +1,51 @@
+package network
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	cnao "github.com/kubevirt/cluster-network-addons-operator/pkg/apis/networkaddonsoperator/shared"
+)
+
+var _ = Describe("Testing containers of objects", func() {
+	podTemplate := map[string]interface{}{
+		"spec": map[string]interface{}{
+			"initContainers": []interface{}{
+				map[string]interface{}{"name": "install", "image": "quay.io/kubevirt/test:init"},
+			},
+			"containers": []interface{}{
+				map[string]interface{}{"name": "main", "image": "quay.io/kubevirt/test:main"},
+			},
+		},
+	}
+	newObject := func(apiVersion, kind string) *unstructured.Unstructured {
+		obj := &unstructured.Unstructured{Object: map[string]interface{}{
+			"spec": map[string]interface{}{"template": podTemplate},
+		}}
+		obj.SetAPIVersion(apiVersion)
+		obj.SetKind(kind)
+		obj.SetName("test")
+		return obj
+	}
+
+	DescribeTable("listing init containers and containers of workloads",
+		func(kind string) {
+			containers, err := ObjectContainers(newObject("apps/v1", kind))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(containers).To(Equal([]cnao.Container{
+				{ParentKind: kind, ParentName: "test", Name: "install", Image: "quay.io/kubevirt/test:init"},
+				{ParentKind: kind, ParentName: "test", Name: "main", Image: "quay.io/kubevirt/test:main"},
+			}))
+		},
+		Entry("of a DaemonSet", "DaemonSet"),
+		Entry("of a Deployment", "Deployment"),
+	)
+
+	It("should not list containers of other objects", func() {
+		containers, err := ObjectContainers(newObject("batch/v1", "Job"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(containers).To(BeEmpty())
+	})
+})
@@ -0,0 +1,136 @@
+package network
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	ocpv1 "github.com/openshift/api/config/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+
+	cnao "github.com/kubevirt/cluster-network-addons-operator/pkg/apis/networkaddonsoperator/shared"
+	"github.com/kubevirt/cluster-network-addons-operator/pkg/components"
+)
+
+var updateGolden = flag.Bool("update", false, "update golden files of rendered components instead of comparing with them")
+
+const goldenDir = "testdata/golden"
+
+type goldenComponent struct {
+	name   string
+	enable func(*cnao.NetworkAddonsConfigSpec)
+}
+
+// goldenComponents enable a single component in the spec. The MAC range of KubeMacPool is set,
+// so the random default does not change snapshots.
+var goldenComponents = []goldenComponent{
+	{name: "kubemacpool", enable: func(conf *cnao.NetworkAddonsConfigSpec) {
+		conf.KubeMacPool = &cnao.KubeMacPool{RangeStart: "02:00:00:00:00:00", RangeEnd: "02:00:00:FF:FF:FF"}
+	}},
+	{name: "linux-bridge", enable: func(conf *cnao.NetworkAddonsConfigSpec) { conf.LinuxBridge = &cnao.LinuxBridge{} }},
+	{name: "macvtap", enable: func(conf *cnao.NetworkAddonsConfigSpec) { conf.MacvtapCni = &cnao.MacvtapCni{} }},
+	{name: "multus", enable: func(conf *cnao.NetworkAddonsConfigSpec) { conf.Multus = &cnao.Multus{} }},
+	{name: "ovs", enable: func(conf *cnao.NetworkAddonsConfigSpec) { conf.Ovs = &cnao.Ovs{} }},
+}
+
+type goldenConfiguration struct {
+	name        string
+	clusterInfo ClusterInfo
+	spec        cnao.NetworkAddonsConfigSpec
+}
+
+var goldenConfigurations = []goldenConfiguration{
+	{name: "kubernetes"},
+	{name: "kubernetes-scc", clusterInfo: ClusterInfo{SCCAvailable: true}},
+	{name: "kubernetes-monitoring", clusterInfo: ClusterInfo{MonitoringAvailable: true}},
+	{name: "openshift", clusterInfo: ClusterInfo{OpenShift4: true, SCCAvailable: true, MonitoringAvailable: true}},
+	{name: "placement", spec: cnao.NetworkAddonsConfigSpec{
+		PlacementConfiguration: &cnao.PlacementConfiguration{
+			Infra: &cnao.Placement{
+				NodeSelector: map[string]string{"node-role.kubernetes.io/infra": ""},
+				Tolerations:  []corev1.Toleration{{Key: "node-role.kubernetes.io/infra", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule}},
+			},
+			Workloads: &cnao.Placement{
+				NodeSelector: map[string]string{"node-role.kubernetes.io/worker": ""},
+				Tolerations:  []corev1.Toleration{{Key: "dedicated", Operator: corev1.TolerationOpEqual, Value: "network", Effect: corev1.TaintEffectNoExecute}},
+			},
+		},
+	}},
+	{name: "tls-old", spec: cnao.NetworkAddonsConfigSpec{
+		TLSSecurityProfile: &ocpv1.TLSSecurityProfile{Type: ocpv1.TLSProfileOldType, Old: &ocpv1.OldTLSProfile{}},
+	}},
+}
+
+// setGoldenEnv sets the environment the operator deployment passes to the operator, so snapshots
+// show default images of components
+func setGoldenEnv() {
+	deployment := components.GetDeployment("99.0.0", "99.0.0", components.Namespace, "quay.io/kubevirt", "cluster-network-addons-operator", "99.0.0", "", (&components.AddonsImages{}).FillDefaults(), nil)
+	env := map[string]string{"OPERAND_NAMESPACE": components.Namespace}
+	for _, envVar := range deployment.Spec.Template.Spec.Containers[0].Env {
+		if envVar.ValueFrom == nil {
+			env[envVar.Name] = envVar.Value
+		}
+	}
+	for name, value := range env {
+		previousValue, found := os.LookupEnv(name)
+		Expect(os.Setenv(name, value)).To(Succeed())
+		name := name
+		DeferCleanup(func() {
+			if found {
+				os.Setenv(name, previousValue)
+			} else {
+				os.Unsetenv(name)
+			}
+		})
+	}
+}
+
+func objectsToYAML(objs []*unstructured.Unstructured) []byte {
+	buffer := bytes.Buffer{}
+	for _, obj := range objs {
+		manifest, err := yaml.Marshal(obj.Object)
+		ExpectWithOffset(1, err).ToNot(HaveOccurred())
+		buffer.WriteString("---\n")
+		buffer.Write(manifest)
+	}
+	return buffer.Bytes()
+}
+
+var _ = Describe("Testing rendered components against golden files", func() {
+	BeforeEach(func() {
+		setGoldenEnv()
+	})
+
+	for _, component := range goldenComponents {
+		for _, configuration := range goldenConfigurations {
+			component, configuration := component, configuration
+			goldenFile := filepath.Join(goldenDir, component.name, configuration.name+".yaml")
+
+			It("should render "+component.name+" in the "+configuration.name+" configuration as in "+goldenFile, func() {
+				conf := configuration.spec.DeepCopy()
+				component.enable(conf)
+				Expect(FillDefaults(conf, nil)).To(Succeed())
+				clusterInfo := configuration.clusterInfo
+				objs, err := Render(conf, "../../data", nil, &clusterInfo)
+				Expect(err).ToNot(HaveOccurred())
+				rendered := objectsToYAML(objs)
+
+				if *updateGolden {
+					Expect(os.MkdirAll(filepath.Dir(goldenFile), 0755)).To(Succeed())
+					Expect(os.WriteFile(goldenFile, rendered, 0644)).To(Succeed())
+					return
+				}
+
+				golden, err := os.ReadFile(goldenFile)
+				Expect(err).ToNot(HaveOccurred(), "golden file is missing, run make gen-golden")
+				Expect(string(rendered)).To(Equal(string(golden)), "rendered objects differ from the golden file, run make gen-golden if the change is expected")
+			})
+		}
+	}
+})
@@ -0,0 +1,155 @@
+package network
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+
+	osv1 "github.com/openshift/api/operator/v1"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	cnao "github.com/kubevirt/cluster-network-addons-operator/pkg/apis/networkaddonsoperator/shared"
+	"github.com/kubevirt/cluster-network-addons-operator/pkg/render"
+)
+
+const hostCleanupPrefix = "cnao-host-cleanup-"
+
+// hostArtifacts are files a component installs on cluster nodes
+type hostArtifacts struct {
+	// imageEnv names the variable with the image of the component, its shell removes the artifacts
+	imageEnv string
+	// binaries are installed to the CNI bin directory
+	binaries []string
+	// links are created in the CNI bin directory, they are removed only if they still point to
+	// binaries of the component, either as the installer sees them or as installed on the node
+	links []hostArtifactLink
+	// configs are installed to the CNI config directory
+	configs []string
+}
+
+type hostArtifactLink struct {
+	Name   string
+	Binary string
+}
+
+var componentHostArtifacts = map[string]hostArtifacts{
+	"multus": {
+		imageEnv: "MULTUS_IMAGE",
+		binaries: []string{"multus"},
+		configs:  []string{"00-multus.conf", "multus.d"},
+	},
+	"linux-bridge": {
+		imageEnv: "LINUX_BRIDGE_IMAGE",
+		binaries: []string{"cnv-bridge", "cnv-tuning", "cnv-host-local"},
+		links: []hostArtifactLink{
+			{Name: "bridge", Binary: "cnv-bridge"},
+			{Name: "tuning", Binary: "cnv-tuning"},
+			{Name: "host-local", Binary: "cnv-host-local"},
+		},
+	},
+	"ovs": {
+		imageEnv: "OVS_CNI_IMAGE",
+		binaries: []string{"ovs", "ovs-mirror-producer", "ovs-mirror-consumer"},
+	},
+	"macvtap": {
+		imageEnv: "MACVTAP_CNI_IMAGE",
+		binaries: []string{"macvtap"},
+	},
+}
+
+// hostComponents lists deployed components which install artifacts on cluster nodes
+func hostComponents(conf *cnao.NetworkAddonsConfigSpec, openshiftNetworkConfig *osv1.Network) map[string]bool {
+	components := map[string]bool{}
+	if conf == nil {
+		return components
+	}
+	// Multus is deployed by OpenShift network operator, its artifacts are not ours
+	if conf.Multus != nil && openshiftNetworkConfig == nil {
+		components["multus"] = true
+	}
+	if conf.LinuxBridge != nil {
+		components["linux-bridge"] = true
+	}
+	if conf.Ovs != nil {
+		components["ovs"] = true
+	}
+	if conf.MacvtapCni != nil {
+		components["macvtap"] = true
+	}
+	return components
+}
+
+// IsHostComponentDeployed checks whether the component, whose host artifacts may be being removed,
+// is requested by the spec
+func IsHostComponentDeployed(component string, conf *cnao.NetworkAddonsConfigSpec, openshiftNetworkConfig *osv1.Network) bool {
+	return hostComponents(conf, openshiftNetworkConfig)[component]
+}
+
+// RenderHostCleanup renders DaemonSets removing CNI binaries and configuration installed on
+// cluster nodes by components removed from the spec
+func RenderHostCleanup(prev, conf *cnao.NetworkAddonsConfigSpec, manifestDir string, openshiftNetworkConfig *osv1.Network, clusterInfo *ClusterInfo) ([]*unstructured.Unstructured, error) {
+	removed := []string{}
+	deployed := hostComponents(conf, openshiftNetworkConfig)
+	for component := range hostComponents(prev, openshiftNetworkConfig) {
+		if !deployed[component] {
+			removed = append(removed, component)
+		}
+	}
+	sort.Strings(removed)
+
+	objs := []*unstructured.Unstructured{}
+	for _, component := range removed {
+		artifacts := componentHostArtifacts[component]
+		cniDirectories := CNIDirectories(prev, clusterInfo)
+
+		data := render.MakeRenderData()
+		data.Data["Component"] = component
+		data.Data["Name"] = hostCleanupPrefix + component
+		data.Data["Namespace"] = os.Getenv("OPERATOR_NAMESPACE")
+		data.Data["Image"] = os.Getenv(artifacts.imageEnv)
+		data.Data["ImagePullPolicy"] = prev.ImagePullPolicy
+		data.Data["Placement"] = prev.PlacementConfiguration.Workloads
+		data.Data["CNIBinDir"] = cniDirectories.BinDir
+		data.Data["CNIConfigDir"] = cniDirectories.ConfigDir
+		data.Data["Binaries"] = artifacts.binaries
+		data.Data["Links"] = artifacts.links
+		data.Data["Configs"] = artifacts.configs
+		data.Data["EnableSCC"] = clusterInfo.SCCAvailable
+
+		componentObjs, err := render.RenderDir(filepath.Join(manifestDir, "host-cleanup"), &data)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to render %s host cleanup manifests", component)
+		}
+		objs = append(objs, componentObjs...)
+	}
+	return objs, nil
+}
+
+// HostCleanupObjects returns objects rendered for the host cleanup of the component, they are
+// removed once the cleanup is done
+func HostCleanupObjects(component string, clusterInfo *ClusterInfo) []*unstructured.Unstructured {
+	name := hostCleanupPrefix + component
+	namespace := os.Getenv("OPERATOR_NAMESPACE")
+
+	kinds := []schema.GroupVersionKind{
+		{Group: "apps", Version: "v1", Kind: "DaemonSet"},
+		{Group: "", Version: "v1", Kind: "ServiceAccount"},
+	}
+	if clusterInfo.SCCAvailable {
+		kinds = append(kinds, schema.GroupVersionKind{Group: "security.openshift.io", Version: "v1", Kind: "SecurityContextConstraints"})
+	}
+
+	objs := []*unstructured.Unstructured{}
+	for _, kind := range kinds {
+		obj := &unstructured.Unstructured{}
+		obj.SetGroupVersionKind(kind)
+		obj.SetName(name)
+		if kind.Kind != "SecurityContextConstraints" {
+			obj.SetNamespace(namespace)
+		}
+		objs = append(objs, obj)
+	}
+	return objs
+}
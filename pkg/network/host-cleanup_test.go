@@ -0,0 +1,146 @@
+package network
+
+import (
+	"os"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	osv1 "github.com/openshift/api/operator/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	cnao "github.com/kubevirt/cluster-network-addons-operator/pkg/apis/networkaddonsoperator/shared"
+	"github.com/kubevirt/cluster-network-addons-operator/pkg/names"
+)
+
+var _ = Describe("Testing host cleanup", func() {
+	const operatorNamespace = "cluster-network-addons"
+
+	BeforeEach(func() {
+		previousOperatorNamespace, found := os.LookupEnv("OPERATOR_NAMESPACE")
+		Expect(os.Setenv("OPERATOR_NAMESPACE", operatorNamespace)).To(Succeed())
+		DeferCleanup(func() {
+			if found {
+				os.Setenv("OPERATOR_NAMESPACE", previousOperatorNamespace)
+			} else {
+				os.Unsetenv("OPERATOR_NAMESPACE")
+			}
+		})
+	})
+
+	allComponents := func() *cnao.NetworkAddonsConfigSpec {
+		conf := &cnao.NetworkAddonsConfigSpec{
+			Multus:      &cnao.Multus{},
+			LinuxBridge: &cnao.LinuxBridge{},
+			Ovs:         &cnao.Ovs{},
+			MacvtapCni:  &cnao.MacvtapCni{},
+			KubeMacPool: &cnao.KubeMacPool{},
+		}
+		Expect(FillDefaults(conf, nil)).To(Succeed())
+		return conf
+	}
+
+	cleanupDaemonSets := func(objs []*unstructured.Unstructured) map[string]*appsv1.DaemonSet {
+		daemonSets := map[string]*appsv1.DaemonSet{}
+		for _, obj := range objs {
+			if obj.GetKind() != "DaemonSet" {
+				continue
+			}
+			Expect(obj.GetNamespace()).To(Equal(operatorNamespace))
+			ds := &appsv1.DaemonSet{}
+			Expect(runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, ds)).To(Succeed())
+			daemonSets[ds.Labels[names.HOST_CLEANUP_LABEL_KEY]] = ds
+		}
+		return daemonSets
+	}
+
+	cleanupScript := func(ds *appsv1.DaemonSet) string {
+		Expect(ds.Spec.Template.Spec.InitContainers).To(HaveLen(1))
+		return strings.Join(ds.Spec.Template.Spec.InitContainers[0].Command, " ")
+	}
+
+	Context("when components are removed", func() {
+		It("should render a cleanup of each component installing host artifacts", func() {
+			objs, err := RenderHostCleanup(allComponents(), &cnao.NetworkAddonsConfigSpec{}, "../../data", nil, &ClusterInfo{})
+			Expect(err).NotTo(HaveOccurred())
+
+			daemonSets := cleanupDaemonSets(objs)
+			Expect(daemonSets).To(HaveLen(4))
+			Expect(cleanupScript(daemonSets["multus"])).To(And(
+				ContainSubstring("rm -f multus"),
+				ContainSubstring("rm -rf 00-multus.conf"),
+				ContainSubstring("rm -rf multus.d"),
+			))
+			Expect(cleanupScript(daemonSets["linux-bridge"])).To(And(
+				ContainSubstring("rm -f cnv-bridge"),
+				ContainSubstring(`case "$(readlink bridge)" in /opt/cni/bin/cnv-bridge|/opt/cni/bin/cnv-bridge) rm -f bridge;; esac`),
+			))
+			Expect(cleanupScript(daemonSets["ovs"])).To(ContainSubstring("rm -f ovs-mirror-consumer"))
+			Expect(cleanupScript(daemonSets["macvtap"])).To(ContainSubstring("rm -f macvtap"))
+		})
+
+		It("should clean up directories the components were installed to", func() {
+			prev := allComponents()
+			prev.CNIBinDir = "/var/lib/cni/bin"
+			objs, err := RenderHostCleanup(prev, &cnao.NetworkAddonsConfigSpec{}, "../../data", nil, &ClusterInfo{})
+			Expect(err).NotTo(HaveOccurred())
+
+			daemonSets := cleanupDaemonSets(objs)
+			volumes := daemonSets["ovs"].Spec.Template.Spec.Volumes
+			Expect(volumes).To(ContainElement(HaveField("HostPath.Path", "/var/lib/cni/bin")))
+			Expect(cleanupScript(daemonSets["linux-bridge"])).To(ContainSubstring(`case "$(readlink bridge)" in /opt/cni/bin/cnv-bridge|/var/lib/cni/bin/cnv-bridge) rm -f bridge;; esac`))
+		})
+
+		It("should not remove Multus deployed by OpenShift", func() {
+			objs, err := RenderHostCleanup(allComponents(), &cnao.NetworkAddonsConfigSpec{}, "../../data", &osv1.Network{}, &ClusterInfo{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cleanupDaemonSets(objs)).NotTo(HaveKey("multus"))
+		})
+
+		It("should allow the cleanup to run privileged when SCC is available", func() {
+			objs, err := RenderHostCleanup(allComponents(), &cnao.NetworkAddonsConfigSpec{}, "../../data", nil, &ClusterInfo{SCCAvailable: true})
+			Expect(err).NotTo(HaveOccurred())
+
+			sccs := []string{}
+			for _, obj := range objs {
+				if obj.GetKind() == "SecurityContextConstraints" {
+					sccs = append(sccs, obj.GetName())
+					Expect(obj.Object["volumes"]).To(ConsistOf("hostPath", "projected"), "only host paths and the service account token should be allowed")
+				}
+			}
+			Expect(sccs).To(ConsistOf("cnao-host-cleanup-linux-bridge", "cnao-host-cleanup-macvtap", "cnao-host-cleanup-multus", "cnao-host-cleanup-ovs"))
+			Expect(HostCleanupObjects("ovs", &ClusterInfo{SCCAvailable: true})).To(HaveLen(3))
+		})
+	})
+
+	Context("when components stay deployed", func() {
+		It("should not render any cleanup", func() {
+			objs, err := RenderHostCleanup(allComponents(), allComponents(), "../../data", nil, &ClusterInfo{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(objs).To(BeEmpty())
+
+			objs, err = RenderHostCleanup(nil, allComponents(), "../../data", nil, &ClusterInfo{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(objs).To(BeEmpty())
+		})
+	})
+
+	Context("when a cleaned up component is requested again", func() {
+		It("should be reported as deployed", func() {
+			Expect(IsHostComponentDeployed("ovs", allComponents(), nil)).To(BeTrue())
+			Expect(IsHostComponentDeployed("ovs", &cnao.NetworkAddonsConfigSpec{}, nil)).To(BeFalse())
+		})
+	})
+
+	It("should list objects of a cleanup for its removal", func() {
+		objs := HostCleanupObjects("multus", &ClusterInfo{})
+		Expect(objs).To(HaveLen(2))
+		for _, obj := range objs {
+			Expect(obj.GetName()).To(Equal("cnao-host-cleanup-multus"))
+			Expect(obj.GetNamespace()).To(Equal(operatorNamespace))
+		}
+	})
+})
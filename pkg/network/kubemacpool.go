@@ -6,14 +6,22 @@ import (
 	"net"
 	"os"
 	"path/filepath"
-	"reflect"
 	"strings"
+	"time"
 
 	"github.com/kubevirt/cluster-network-addons-operator/pkg/render"
 	"github.com/pkg/errors"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 
 	cnao "github.com/kubevirt/cluster-network-addons-operator/pkg/apis/networkaddonsoperator/shared"
+	"github.com/kubevirt/cluster-network-addons-operator/pkg/certificates"
+)
+
+const (
+	// kubeMacPoolCertSecret keeps the serving certificate KubeMacPool issues for its webhook
+	kubeMacPoolCertSecret = "kubemacpool-service"
+	// kubeMacPoolMutatingWebhookConfiguration keeps the CA bundle of the KubeMacPool webhook
+	kubeMacPoolMutatingWebhookConfiguration = "kubemacpool-mutator"
 )
 
 // ValidateMultus validates the combination of DisableMultiNetwork and AddtionalNetworks
@@ -67,7 +75,8 @@ func fillDefaultsKubeMacPool(conf, previous *cnao.NetworkAddonsConfigSpec) []err
 	// If user hasn't explicitly requested a range, we try to reuse previously applied range
 	if conf.KubeMacPool.RangeStart == "" || conf.KubeMacPool.RangeEnd == "" {
 		if previous != nil && previous.KubeMacPool != nil {
-			conf.KubeMacPool = previous.KubeMacPool
+			conf.KubeMacPool.RangeStart = previous.KubeMacPool.RangeStart
+			conf.KubeMacPool.RangeEnd = previous.KubeMacPool.RangeEnd
 			return []error{}
 		}
 
@@ -88,7 +97,9 @@ func fillDefaultsKubeMacPool(conf, previous *cnao.NetworkAddonsConfigSpec) []err
 }
 
 func changeSafeKubeMacPool(prev, next *cnao.NetworkAddonsConfigSpec) []error {
-	if prev.KubeMacPool != nil && next.KubeMacPool != nil && !reflect.DeepEqual(prev.KubeMacPool, next.KubeMacPool) {
+	// KubeMacPool can be moved to another namespace, but its range has to stay the same
+	if prev.KubeMacPool != nil && next.KubeMacPool != nil &&
+		(prev.KubeMacPool.RangeStart != next.KubeMacPool.RangeStart || prev.KubeMacPool.RangeEnd != next.KubeMacPool.RangeEnd) {
 		return []error{errors.Errorf("cannot modify KubeMacPool configuration once it is deployed")}
 	}
 
@@ -103,7 +114,7 @@ func renderKubeMacPool(conf *cnao.NetworkAddonsConfigSpec, manifestDir string) (
 
 	// render the manifests on disk
 	data := render.MakeRenderData()
-	data.Data["Namespace"] = os.Getenv("OPERAND_NAMESPACE")
+	data.Data["Namespace"] = componentNamespace(conf.KubeMacPool.Namespace)
 	data.Data["KubeMacPoolImage"] = os.Getenv("KUBEMACPOOL_IMAGE")
 	data.Data["KubeRbacProxyImage"] = os.Getenv("KUBE_RBAC_PROXY_IMAGE")
 	data.Data["ImagePullPolicy"] = conf.ImagePullPolicy
@@ -127,6 +138,28 @@ func renderKubeMacPool(conf *cnao.NetworkAddonsConfigSpec, manifestDir string) (
 	return objs, nil
 }
 
+// KubeMacPoolCertificateSources lists objects keeping certificates KubeMacPool issues for its
+// webhook. KubeMacPool replaces them the configured overlap interval before they expire.
+func KubeMacPoolCertificateSources(conf *cnao.NetworkAddonsConfigSpec) ([]certificates.Source, error) {
+	selfSignConfiguration := conf.SelfSignConfiguration
+	if selfSignConfiguration == nil {
+		selfSignConfiguration = DefaultSelfSignConfiguration()
+	}
+	caOverlapInterval, err := time.ParseDuration(selfSignConfiguration.CAOverlapInterval)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse caOverlapInterval")
+	}
+	certOverlapInterval, err := time.ParseDuration(selfSignConfiguration.CertOverlapInterval)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse certOverlapInterval")
+	}
+
+	return []certificates.Source{
+		certificates.SecretSource("", kubeMacPoolCertSecret, certificates.Rotation{Overlap: certOverlapInterval}),
+		certificates.MutatingWebhookConfigurationSource(kubeMacPoolMutatingWebhookConfiguration, certificates.Rotation{Overlap: caOverlapInterval}),
+	}, nil
+}
+
 func generateRandomMacPrefix() ([]byte, error) {
 	suffix := make([]byte, 2)
 	_, err := rand.Read(suffix)
@@ -158,6 +158,17 @@ var _ = Describe("Testing kubeMacPool", func() {
 					Expect(currentClusterConfig.KubeMacPool.RangeEnd).To(Equal(previousClusterConfig.KubeMacPool.RangeEnd))
 
 				})
+
+				It("should keep the requested namespace", func() {
+					previousClusterConfig := &cnao.NetworkAddonsConfigSpec{
+						KubeMacPool: &cnao.KubeMacPool{RangeStart: "02:00:00:00:00:00", RangeEnd: "0A:FF:FF:FF:FF:FF"}}
+					currentClusterConfig := &cnao.NetworkAddonsConfigSpec{
+						KubeMacPool: &cnao.KubeMacPool{Namespace: "kubemacpool"}}
+					errorList := fillDefaultsKubeMacPool(currentClusterConfig, previousClusterConfig)
+					Expect(errorList).To(BeEmpty())
+					Expect(currentClusterConfig.KubeMacPool.Namespace).To(Equal(cnao.ComponentNamespace("kubemacpool")))
+					Expect(currentClusterConfig.KubeMacPool.RangeStart).To(Equal(previousClusterConfig.KubeMacPool.RangeStart))
+				})
 			})
 
 			Context("When a previous kubeMacPool doesn't exits", func() {
@@ -220,6 +231,18 @@ var _ = Describe("Testing kubeMacPool", func() {
 			})
 		})
 
+		Context("When only the namespace differs", func() {
+			It("should NOT return an error", func() {
+				previousClusterConfig := &cnao.NetworkAddonsConfigSpec{
+					KubeMacPool: &cnao.KubeMacPool{RangeStart: "02:00:00:00:00:00", RangeEnd: "0A:FF:FF:FF:FF:FF"}}
+				currentClusterConfig := &cnao.NetworkAddonsConfigSpec{
+					KubeMacPool: &cnao.KubeMacPool{Namespace: "kubemacpool", RangeStart: "02:00:00:00:00:00", RangeEnd: "0A:FF:FF:FF:FF:FF"}}
+
+				errorList := changeSafeKubeMacPool(previousClusterConfig, currentClusterConfig)
+				Expect(errorList).To(BeEmpty())
+			})
+		})
+
 		Context("When trying to remove kubeMacPool", func() {
 			It("should NOT return an error", func() {
 				previousClusterConfig := &cnao.NetworkAddonsConfigSpec{
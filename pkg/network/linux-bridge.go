@@ -1,36 +1,183 @@
 package network
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
+	"time"
 
 	"github.com/kubevirt/cluster-network-addons-operator/pkg/render"
 	"github.com/pkg/errors"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 
 	cnao "github.com/kubevirt/cluster-network-addons-operator/pkg/apis/networkaddonsoperator/shared"
-	"github.com/kubevirt/cluster-network-addons-operator/pkg/network/cni"
 )
 
+const (
+	bridgeMarkerUpdateIntervalDefault = time.Minute
+	installAuxiliaryPluginsDefault    = true
+
+	// filteredBridgeMarkerCommand is the path of filtered-bridge-marker in the operator image
+	filteredBridgeMarkerCommand = "/usr/bin/filtered-bridge-marker"
+)
+
+// validateLinuxBridge validates the bridge-marker configuration
+func validateLinuxBridge(conf *cnao.NetworkAddonsConfigSpec) []error {
+	if conf.LinuxBridge == nil || conf.LinuxBridge.BridgeMarker == nil {
+		return []error{}
+	}
+
+	bridgeMarker := conf.LinuxBridge.BridgeMarker
+	errs := []error{}
+
+	if bridgeMarker.UpdateInterval != "" {
+		updateInterval, err := time.ParseDuration(bridgeMarker.UpdateInterval)
+		if err != nil {
+			errs = append(errs, errors.Wrap(err, "failed to validate linuxBridge: error parsing bridgeMarker.updateInterval"))
+		} else if updateInterval < time.Second {
+			errs = append(errs, errors.Errorf("failed to validate linuxBridge: bridgeMarker.updateInterval(%s) has to be >= 1s", updateInterval))
+		}
+	}
+
+	for _, bridge := range append(append([]string{}, bridgeMarker.AllowedBridges...), bridgeMarker.DeniedBridges...) {
+		if !isValidInterfaceName(bridge) {
+			errs = append(errs, errors.Errorf("failed to validate linuxBridge: %q is not a valid bridge name", bridge))
+		}
+	}
+
+	denied := map[string]bool{}
+	for _, bridge := range bridgeMarker.DeniedBridges {
+		denied[bridge] = true
+	}
+	for _, bridge := range bridgeMarker.AllowedBridges {
+		if denied[bridge] {
+			errs = append(errs, errors.Errorf("failed to validate linuxBridge: bridge %q cannot be both allowed and denied", bridge))
+		}
+	}
+
+	if bridgeMarker.BridgeNameRegex != "" {
+		if len(bridgeMarker.AllowedBridges) > 0 {
+			errs = append(errs, errors.Errorf("failed to validate linuxBridge: bridgeMarker.allowedBridges and bridgeMarker.bridgeNameRegex are mutually exclusive"))
+		}
+		if _, err := regexp.Compile(bridgeMarker.BridgeNameRegex); err != nil {
+			errs = append(errs, errors.Wrap(err, "failed to validate linuxBridge: error parsing bridgeMarker.bridgeNameRegex"))
+		}
+	}
+
+	return errs
+}
+
+func fillDefaultsLinuxBridge(conf, previous *cnao.NetworkAddonsConfigSpec) []error {
+	if conf.LinuxBridge == nil {
+		return []error{}
+	}
+
+	var previousLinuxBridge *cnao.LinuxBridge
+	if previous != nil && previous.LinuxBridge != nil {
+		previousLinuxBridge = previous.LinuxBridge
+	}
+
+	if conf.LinuxBridge.BridgeMarker == nil {
+		conf.LinuxBridge.BridgeMarker = &cnao.BridgeMarker{}
+	}
+	if conf.LinuxBridge.BridgeMarker.UpdateInterval == "" {
+		if previousLinuxBridge != nil && previousLinuxBridge.BridgeMarker != nil && previousLinuxBridge.BridgeMarker.UpdateInterval != "" {
+			conf.LinuxBridge.BridgeMarker.UpdateInterval = previousLinuxBridge.BridgeMarker.UpdateInterval
+		} else {
+			conf.LinuxBridge.BridgeMarker.UpdateInterval = bridgeMarkerUpdateIntervalDefault.String()
+		}
+	}
+
+	if conf.LinuxBridge.InstallAuxiliaryPlugins == nil {
+		installAuxiliaryPlugins := installAuxiliaryPluginsDefault
+		if previousLinuxBridge != nil && previousLinuxBridge.InstallAuxiliaryPlugins != nil {
+			installAuxiliaryPlugins = *previousLinuxBridge.InstallAuxiliaryPlugins
+		}
+		conf.LinuxBridge.InstallAuxiliaryPlugins = &installAuxiliaryPlugins
+	}
+
+	return []error{}
+}
+
+// bridgeMarkerFiltered reports whether bridges exposed as node resources are filtered. bridge-marker
+// cannot filter them, filtered-bridge-marker shipped in the operator image runs in its place then.
+func bridgeMarkerFiltered(bridgeMarker *cnao.BridgeMarker) bool {
+	return bridgeMarker != nil &&
+		(len(bridgeMarker.AllowedBridges) > 0 || len(bridgeMarker.DeniedBridges) > 0 || bridgeMarker.BridgeNameRegex != "")
+}
+
+// bridgeMarkerArgs composes the command line arguments of the bridge-marker container
+func bridgeMarkerArgs(bridgeMarker *cnao.BridgeMarker) ([]string, error) {
+	updateInterval := bridgeMarkerUpdateIntervalDefault
+	if bridgeMarker != nil && bridgeMarker.UpdateInterval != "" {
+		var err error
+		updateInterval, err = time.ParseDuration(bridgeMarker.UpdateInterval)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to parse bridge-marker update interval")
+		}
+	}
+
+	if !bridgeMarkerFiltered(bridgeMarker) {
+		args := []string{
+			"-node-name", "$(NODE_NAME)",
+			"-update-interval", fmt.Sprintf("%d", int64(updateInterval.Seconds())),
+		}
+		return args, nil
+	}
+
+	args := []string{
+		"--node-name", "$(NODE_NAME)",
+		"--update-interval", updateInterval.String(),
+	}
+	for _, bridge := range bridgeMarker.AllowedBridges {
+		args = append(args, "--allowed-bridge", bridge)
+	}
+	for _, bridge := range bridgeMarker.DeniedBridges {
+		args = append(args, "--denied-bridge", bridge)
+	}
+	if bridgeMarker.BridgeNameRegex != "" {
+		args = append(args, "--bridge-name-regex", bridgeMarker.BridgeNameRegex)
+	}
+	return args, nil
+}
+
 // renderLinuxBridge generates the manifests of Linux Bridge
 func renderLinuxBridge(conf *cnao.NetworkAddonsConfigSpec, manifestDir string, clusterInfo *ClusterInfo) ([]*unstructured.Unstructured, error) {
 	if conf.LinuxBridge == nil {
 		return nil, nil
 	}
 
+	markerArgs, err := bridgeMarkerArgs(conf.LinuxBridge.BridgeMarker)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to render linux-bridge manifests")
+	}
+
+	markerImage := os.Getenv("LINUX_BRIDGE_MARKER_IMAGE")
+	var markerCommand []string
+	if bridgeMarkerFiltered(conf.LinuxBridge.BridgeMarker) {
+		markerImage = os.Getenv("OPERATOR_IMAGE")
+		markerCommand = []string{filteredBridgeMarkerCommand}
+	}
+
+	installAuxiliaryPlugins := installAuxiliaryPluginsDefault
+	if conf.LinuxBridge.InstallAuxiliaryPlugins != nil {
+		installAuxiliaryPlugins = *conf.LinuxBridge.InstallAuxiliaryPlugins
+	}
+
 	// render the manifests on disk
 	data := render.MakeRenderData()
-	data.Data["Namespace"] = os.Getenv("OPERAND_NAMESPACE")
-	data.Data["LinuxBridgeMarkerImage"] = os.Getenv("LINUX_BRIDGE_MARKER_IMAGE")
+	data.Data["Namespace"] = componentNamespace(conf.LinuxBridge.Namespace)
+	data.Data["LinuxBridgeMarkerImage"] = markerImage
 	data.Data["LinuxBridgeImage"] = os.Getenv("LINUX_BRIDGE_IMAGE")
+	data.Data["CNIHealthImage"] = os.Getenv("OPERATOR_IMAGE")
 	data.Data["ImagePullPolicy"] = conf.ImagePullPolicy
-	if clusterInfo.OpenShift4 {
-		data.Data["CNIBinDir"] = cni.BinDirOpenShift4
-	} else {
-		data.Data["CNIBinDir"] = cni.BinDir
-	}
+	data.Data["CNIBinDir"] = CNIDirectories(conf, clusterInfo).BinDir
 	data.Data["EnableSCC"] = clusterInfo.SCCAvailable
 	data.Data["Placement"] = conf.PlacementConfiguration.Workloads
+	data.Data["BridgeMarkerCommand"] = markerCommand
+	data.Data["BridgeMarkerArgs"] = markerArgs
+	data.Data["InstallAuxiliaryPlugins"] = installAuxiliaryPlugins
 
 	objs, err := render.RenderDir(filepath.Join(manifestDir, "linux-bridge"), &data)
 	if err != nil {
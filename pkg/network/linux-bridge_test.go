@@ -0,0 +1,177 @@
+package network
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/yaml"
+
+	cnao "github.com/kubevirt/cluster-network-addons-operator/pkg/apis/networkaddonsoperator/shared"
+)
+
+var _ = Describe("Testing linux-bridge", func() {
+	type validateCase struct {
+		bridgeMarker  *cnao.BridgeMarker
+		expectedError string
+	}
+	DescribeTable("validation function",
+		func(c validateCase) {
+			conf := &cnao.NetworkAddonsConfigSpec{LinuxBridge: &cnao.LinuxBridge{BridgeMarker: c.bridgeMarker}}
+			errorList := validateLinuxBridge(conf)
+			if len(c.expectedError) > 0 {
+				Expect(errorList).To(HaveLen(1), "validation failed due to an unexpected error: %v", errorList)
+				Expect(errorList[0].Error()).To(MatchRegexp(c.expectedError))
+			} else {
+				Expect(errorList).To(BeEmpty())
+			}
+		},
+		Entry("When bridgeMarker is not configured should pass", validateCase{}),
+		Entry("When bridgeMarker is fully configured should pass", validateCase{
+			bridgeMarker: &cnao.BridgeMarker{
+				UpdateInterval:  "30s",
+				DeniedBridges:   []string{"br-ex"},
+				BridgeNameRegex: "^br[0-9]+$",
+			},
+		}),
+		Entry("When updateInterval cannot be parsed should return an error", validateCase{
+			bridgeMarker:  &cnao.BridgeMarker{UpdateInterval: "foo"},
+			expectedError: "failed to validate linuxBridge: error parsing bridgeMarker.updateInterval",
+		}),
+		Entry("When updateInterval is shorter than a second should return an error", validateCase{
+			bridgeMarker:  &cnao.BridgeMarker{UpdateInterval: "10ms"},
+			expectedError: `failed to validate linuxBridge: bridgeMarker.updateInterval\(10ms\) has to be >= 1s`,
+		}),
+		Entry("When a bridge name is too long should return an error", validateCase{
+			bridgeMarker:  &cnao.BridgeMarker{AllowedBridges: []string{"bridge-name-too-long"}},
+			expectedError: `failed to validate linuxBridge: "bridge-name-too-long" is not a valid bridge name`,
+		}),
+		Entry("When a bridge is both allowed and denied should return an error", validateCase{
+			bridgeMarker:  &cnao.BridgeMarker{AllowedBridges: []string{"br1"}, DeniedBridges: []string{"br1"}},
+			expectedError: `failed to validate linuxBridge: bridge "br1" cannot be both allowed and denied`,
+		}),
+		Entry("When both allowedBridges and bridgeNameRegex are set should return an error", validateCase{
+			bridgeMarker:  &cnao.BridgeMarker{AllowedBridges: []string{"br1"}, BridgeNameRegex: "^br"},
+			expectedError: "failed to validate linuxBridge: bridgeMarker.allowedBridges and bridgeMarker.bridgeNameRegex are mutually exclusive",
+		}),
+		Entry("When bridgeNameRegex cannot be compiled should return an error", validateCase{
+			bridgeMarker:  &cnao.BridgeMarker{BridgeNameRegex: "br(["},
+			expectedError: "failed to validate linuxBridge: error parsing bridgeMarker.bridgeNameRegex",
+		}),
+	)
+
+	Describe("fillDefaultsLinuxBridge", func() {
+		Context("when there is no previous configuration", func() {
+			It("should fill the defaults", func() {
+				conf := &cnao.NetworkAddonsConfigSpec{LinuxBridge: &cnao.LinuxBridge{}}
+				Expect(fillDefaultsLinuxBridge(conf, nil)).To(BeEmpty())
+				Expect(conf.LinuxBridge.BridgeMarker.UpdateInterval).To(Equal("1m0s"))
+				Expect(*conf.LinuxBridge.InstallAuxiliaryPlugins).To(BeTrue())
+			})
+		})
+
+		Context("when previous configuration is set", func() {
+			It("should carry forward the previous values", func() {
+				installAuxiliaryPlugins := false
+				previous := &cnao.NetworkAddonsConfigSpec{LinuxBridge: &cnao.LinuxBridge{
+					BridgeMarker:            &cnao.BridgeMarker{UpdateInterval: "2m"},
+					InstallAuxiliaryPlugins: &installAuxiliaryPlugins,
+				}}
+				conf := &cnao.NetworkAddonsConfigSpec{LinuxBridge: &cnao.LinuxBridge{}}
+				Expect(fillDefaultsLinuxBridge(conf, previous)).To(BeEmpty())
+				Expect(conf.LinuxBridge.BridgeMarker.UpdateInterval).To(Equal("2m"))
+				Expect(*conf.LinuxBridge.InstallAuxiliaryPlugins).To(BeFalse())
+			})
+		})
+	})
+
+	Describe("renderLinuxBridge", func() {
+		renderBridgeMarker := func(bridgeMarkerConfig *cnao.BridgeMarker) *appsv1.DaemonSet {
+			conf := &cnao.NetworkAddonsConfigSpec{
+				LinuxBridge:            &cnao.LinuxBridge{BridgeMarker: bridgeMarkerConfig},
+				PlacementConfiguration: &cnao.PlacementConfiguration{Workloads: &cnao.Placement{}},
+			}
+			objs, err := renderLinuxBridge(conf, "../../data", &ClusterInfo{})
+			Expect(err).NotTo(HaveOccurred())
+
+			var bridgeMarker *appsv1.DaemonSet
+			for _, obj := range objs {
+				if obj.GetKind() == "DaemonSet" && obj.GetName() == "bridge-marker" {
+					bridgeMarker = &appsv1.DaemonSet{}
+					Expect(runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, bridgeMarker)).To(Succeed())
+				}
+			}
+			Expect(bridgeMarker).NotTo(BeNil())
+			return bridgeMarker
+		}
+
+		BeforeEach(func() {
+			os.Setenv("LINUX_BRIDGE_MARKER_IMAGE", "bridge-marker-image")
+			os.Setenv("OPERATOR_IMAGE", "operator-image")
+			DeferCleanup(func() {
+				os.Unsetenv("LINUX_BRIDGE_MARKER_IMAGE")
+				os.Unsetenv("OPERATOR_IMAGE")
+			})
+		})
+
+		It("should pass the bridge-marker configuration as container arguments", func() {
+			container := renderBridgeMarker(&cnao.BridgeMarker{UpdateInterval: "2m"}).Spec.Template.Spec.Containers[0]
+			Expect(container.Image).To(Equal("bridge-marker-image"))
+			Expect(container.Command).To(BeEmpty())
+			Expect(container.Args).To(Equal([]string{
+				"-node-name", "$(NODE_NAME)",
+				"-update-interval", "120",
+			}))
+		})
+
+		It("should run filtered-bridge-marker of the operator image when bridges are filtered", func() {
+			container := renderBridgeMarker(&cnao.BridgeMarker{
+				UpdateInterval:  "2m",
+				DeniedBridges:   []string{"br-ex", "br-int"},
+				BridgeNameRegex: "^br",
+			}).Spec.Template.Spec.Containers[0]
+			Expect(container.Image).To(Equal("operator-image"))
+			Expect(container.Command).To(Equal([]string{"/usr/bin/filtered-bridge-marker"}))
+			Expect(container.Args).To(Equal([]string{
+				"--node-name", "$(NODE_NAME)",
+				"--update-interval", "2m0s",
+				"--denied-bridge", "br-ex",
+				"--denied-bridge", "br-int",
+				"--bridge-name-regex", "^br",
+			}))
+		})
+	})
+
+	Describe("bridgeMarkerArgs", func() {
+		// Flags defined by cmd/marker/main.go of bridge-marker at the commit below, the container
+		// crash-loops on any other flag
+		const bridgeMarkerFlagsCommit = "965fe62eb9c1c3f076f04c0e9a694c49e2066925"
+		bridgeMarkerFlags := []string{"node-name", "update-interval"}
+
+		It("should be checked against the pinned bridge-marker release", func() {
+			componentsConfig := struct {
+				Components map[string]struct {
+					Commit string `json:"commit"`
+				} `json:"components"`
+			}{}
+			content, err := ioutil.ReadFile("../../components.yaml")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(yaml.Unmarshal(content, &componentsConfig)).To(Succeed())
+			Expect(componentsConfig.Components["bridge-marker"].Commit).To(Equal(bridgeMarkerFlagsCommit),
+				"bridge-marker was bumped, check its flags and update the flags of this test")
+		})
+
+		It("should use only flags supported by bridge-marker", func() {
+			args, err := bridgeMarkerArgs(&cnao.BridgeMarker{UpdateInterval: "2m"})
+			Expect(err).NotTo(HaveOccurred())
+			for i := 0; i < len(args); i += 2 {
+				Expect(bridgeMarkerFlags).To(ContainElement(strings.TrimPrefix(args[i], "-")))
+			}
+		})
+	})
+})
@@ -1,35 +1,107 @@
 package network
 
 import (
+	"encoding/json"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/pkg/errors"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/validation"
 
 	cnao "github.com/kubevirt/cluster-network-addons-operator/pkg/apis/networkaddonsoperator/shared"
-	"github.com/kubevirt/cluster-network-addons-operator/pkg/network/cni"
 	"github.com/kubevirt/cluster-network-addons-operator/pkg/render"
 )
 
+const (
+	macvtapResourcePrefix = "macvtap.network.kubevirt.io/"
+
+	// interface names are limited by IFNAMSIZ, including the terminating null byte
+	maxInterfaceNameLength = 15
+)
+
+var macvtapModes = map[string]bool{
+	"bridge":   true,
+	"vepa":     true,
+	"private":  true,
+	"passthru": true,
+}
+
+// isValidInterfaceName checks the name against the restrictions kernel puts on network interface names
+func isValidInterfaceName(name string) bool {
+	return name != "" && len(name) <= maxInterfaceNameLength && !strings.ContainsAny(name, "/: \t\n")
+}
+
+// validateMacvtapCni validates the macvtap device plugin resources
+func validateMacvtapCni(conf *cnao.NetworkAddonsConfigSpec) []error {
+	if conf.MacvtapCni == nil {
+		return []error{}
+	}
+
+	errs := []error{}
+	names := map[string]bool{}
+	for _, resource := range conf.MacvtapCni.DevicePluginResources {
+		if resource.Name == "" {
+			errs = append(errs, errors.Errorf("failed to validate macvtap: resource name is missing"))
+		} else if msgs := validation.IsQualifiedName(macvtapResourcePrefix + resource.Name); len(msgs) > 0 {
+			errs = append(errs, errors.Errorf("failed to validate macvtap: resource name %q is invalid: %v", resource.Name, msgs))
+		} else if names[resource.Name] {
+			errs = append(errs, errors.Errorf("failed to validate macvtap: resource name %q is duplicated", resource.Name))
+		}
+		names[resource.Name] = true
+
+		if !isValidInterfaceName(resource.LowerDevice) {
+			errs = append(errs, errors.Errorf("failed to validate macvtap: lowerDevice %q of resource %q is not a valid interface name", resource.LowerDevice, resource.Name))
+		}
+
+		if resource.Mode != "" && !macvtapModes[resource.Mode] {
+			errs = append(errs, errors.Errorf("failed to validate macvtap: mode %q of resource %q is not valid, it has to be one of bridge, vepa, private or passthru", resource.Mode, resource.Name))
+		}
+
+		if resource.Capacity < 0 {
+			errs = append(errs, errors.Errorf("failed to validate macvtap: capacity of resource %q has to be >= 0", resource.Name))
+		}
+	}
+
+	return errs
+}
+
+// macvtapDevicePluginConfig serializes the resources into the format consumed by the macvtap device plugin
+func macvtapDevicePluginConfig(macvtapCni *cnao.MacvtapCni) (string, error) {
+	resources := macvtapCni.DevicePluginResources
+	if resources == nil {
+		resources = []cnao.MacvtapResource{}
+	}
+
+	config, err := json.Marshal(resources)
+	if err != nil {
+		return "", err
+	}
+	return string(config), nil
+}
+
 // renderMacvtapCni generates the manifests of macvtap-cni handler
 func renderMacvtapCni(conf *cnao.NetworkAddonsConfigSpec, manifestDir string, clusterInfo *ClusterInfo) ([]*unstructured.Unstructured, error) {
 	if conf.MacvtapCni == nil {
 		return nil, nil
 	}
 
+	devicePluginConfig, err := macvtapDevicePluginConfig(conf.MacvtapCni)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to render macvtap device plugin configuration")
+	}
+
 	// render the manifests on disk
 	data := render.MakeRenderData()
-	data.Data["Namespace"] = os.Getenv("OPERAND_NAMESPACE")
+	data.Data["Namespace"] = componentNamespace(conf.MacvtapCni.Namespace)
 	data.Data["ImagePullPolicy"] = conf.ImagePullPolicy
 	data.Data["EnableSCC"] = clusterInfo.SCCAvailable
 	data.Data["MacvtapImage"] = os.Getenv("MACVTAP_CNI_IMAGE")
-	if clusterInfo.OpenShift4 {
-		data.Data["CniMountPath"] = cni.BinDirOpenShift4
-	} else {
-		data.Data["CniMountPath"] = cni.BinDir
-	}
+	data.Data["CNIHealthImage"] = os.Getenv("OPERATOR_IMAGE")
+	data.Data["CniMountPath"] = CNIDirectories(conf, clusterInfo).BinDir
 	data.Data["Placement"] = conf.PlacementConfiguration.Workloads
+	data.Data["DevicePluginConfig"] = devicePluginConfig
 	objs, err := render.RenderDir(filepath.Join(manifestDir, "macvtap"), &data)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to render macvtap-cni state handler manifests")
@@ -0,0 +1,102 @@
+package network
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	cnao "github.com/kubevirt/cluster-network-addons-operator/pkg/apis/networkaddonsoperator/shared"
+)
+
+var _ = Describe("Testing macvtap", func() {
+	type validateCase struct {
+		resources     []cnao.MacvtapResource
+		expectedError string
+	}
+	DescribeTable("validation function",
+		func(c validateCase) {
+			conf := &cnao.NetworkAddonsConfigSpec{MacvtapCni: &cnao.MacvtapCni{DevicePluginResources: c.resources}}
+			errorList := validateMacvtapCni(conf)
+			if len(c.expectedError) > 0 {
+				Expect(errorList).To(HaveLen(1), "validation failed due to an unexpected error: %v", errorList)
+				Expect(errorList[0].Error()).To(MatchRegexp(c.expectedError))
+			} else {
+				Expect(errorList).To(BeEmpty())
+			}
+		},
+		Entry("When no resources are configured should pass", validateCase{}),
+		Entry("When resources are valid should pass", validateCase{
+			resources: []cnao.MacvtapResource{
+				{Name: "dataplane", LowerDevice: "eth0", Mode: "bridge", Capacity: 50},
+				{Name: "storage", LowerDevice: "eth1"},
+			},
+		}),
+		Entry("When resource name is missing should return an error", validateCase{
+			resources:     []cnao.MacvtapResource{{LowerDevice: "eth0"}},
+			expectedError: "failed to validate macvtap: resource name is missing",
+		}),
+		Entry("When resource name is invalid should return an error", validateCase{
+			resources:     []cnao.MacvtapResource{{Name: "data plane", LowerDevice: "eth0"}},
+			expectedError: `failed to validate macvtap: resource name "data plane" is invalid`,
+		}),
+		Entry("When resource name is duplicated should return an error", validateCase{
+			resources: []cnao.MacvtapResource{
+				{Name: "dataplane", LowerDevice: "eth0"},
+				{Name: "dataplane", LowerDevice: "eth1"},
+			},
+			expectedError: `failed to validate macvtap: resource name "dataplane" is duplicated`,
+		}),
+		Entry("When lower device is missing should return an error", validateCase{
+			resources:     []cnao.MacvtapResource{{Name: "dataplane"}},
+			expectedError: `failed to validate macvtap: lowerDevice "" of resource "dataplane" is not a valid interface name`,
+		}),
+		Entry("When mode is unknown should return an error", validateCase{
+			resources:     []cnao.MacvtapResource{{Name: "dataplane", LowerDevice: "eth0", Mode: "foo"}},
+			expectedError: `failed to validate macvtap: mode "foo" of resource "dataplane" is not valid`,
+		}),
+		Entry("When capacity is negative should return an error", validateCase{
+			resources:     []cnao.MacvtapResource{{Name: "dataplane", LowerDevice: "eth0", Capacity: -1}},
+			expectedError: `failed to validate macvtap: capacity of resource "dataplane" has to be >= 0`,
+		}),
+	)
+
+	Describe("renderMacvtapCni", func() {
+		render := func(macvtapCni *cnao.MacvtapCni) string {
+			conf := &cnao.NetworkAddonsConfigSpec{
+				MacvtapCni:             macvtapCni,
+				PlacementConfiguration: &cnao.PlacementConfiguration{Workloads: &cnao.Placement{}},
+			}
+			objs, err := renderMacvtapCni(conf, "../../data", &ClusterInfo{})
+			Expect(err).NotTo(HaveOccurred())
+			for _, obj := range objs {
+				if obj.GetKind() == "ConfigMap" && obj.GetName() == "macvtap-deviceplugin-config" {
+					data := obj.Object["data"].(map[string]interface{})
+					return data["DP_MACVTAP_CONF"].(string)
+				}
+			}
+			Fail("macvtap-deviceplugin-config ConfigMap was not rendered")
+			return ""
+		}
+
+		Context("when no resources are configured", func() {
+			It("should render an empty device plugin configuration", func() {
+				Expect(render(&cnao.MacvtapCni{})).To(Equal("[]"))
+			})
+		})
+
+		Context("when resources are configured", func() {
+			It("should render them into the device plugin configuration", func() {
+				macvtapCni := &cnao.MacvtapCni{DevicePluginResources: []cnao.MacvtapResource{
+					{Name: "dataplane", LowerDevice: "eth0", Mode: "bridge", Capacity: 50},
+				}}
+				Expect(render(macvtapCni)).To(MatchJSON(`[{"name":"dataplane","lowerDevice":"eth0","mode":"bridge","capacity":50}]`))
+			})
+
+			It("should keep quotes and backslashes of interface names", func() {
+				macvtapCni := &cnao.MacvtapCni{DevicePluginResources: []cnao.MacvtapResource{
+					{Name: "dataplane", LowerDevice: `it's"\`},
+				}}
+				Expect(render(macvtapCni)).To(MatchJSON(`[{"name":"dataplane","lowerDevice":"it's\"\\"}]`))
+			})
+		})
+	})
+})
@@ -0,0 +1,35 @@
+package network
+
+import (
+	osv1 "github.com/openshift/api/operator/v1"
+	"github.com/pkg/errors"
+
+	cnao "github.com/kubevirt/cluster-network-addons-operator/pkg/apis/networkaddonsoperator/shared"
+)
+
+func validateManagementState(conf *cnao.NetworkAddonsConfigSpec) []error {
+	switch conf.ManagementState {
+	case "", osv1.Managed, osv1.Unmanaged, osv1.Removed:
+		return []error{}
+	default:
+		return []error{errors.Errorf("requested managementState '%s' is not valid", conf.ManagementState)}
+	}
+}
+
+// IsUnmanaged reports whether the operator has to leave components of the configuration as they are
+func IsUnmanaged(conf *cnao.NetworkAddonsConfigSpec) bool {
+	return conf.ManagementState == osv1.Unmanaged
+}
+
+// DropRemovedComponents drops all components of a configuration in the Removed management state. They
+// are then removed from the cluster the same way as components dropped from the configuration.
+func DropRemovedComponents(conf *cnao.NetworkAddonsConfigSpec) {
+	if conf.ManagementState != osv1.Removed {
+		return
+	}
+	conf.Multus = nil
+	conf.LinuxBridge = nil
+	conf.Ovs = nil
+	conf.KubeMacPool = nil
+	conf.MacvtapCni = nil
+}
@@ -0,0 +1,54 @@
+package network
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	osv1 "github.com/openshift/api/operator/v1"
+
+	cnao "github.com/kubevirt/cluster-network-addons-operator/pkg/apis/networkaddonsoperator/shared"
+)
+
+var _ = Describe("Testing management-state", func() {
+	Describe("validateManagementState", func() {
+		It("should accept the states the operator supports", func() {
+			for _, state := range []osv1.ManagementState{"", osv1.Managed, osv1.Unmanaged, osv1.Removed} {
+				Expect(validateManagementState(&cnao.NetworkAddonsConfigSpec{ManagementState: state})).To(BeEmpty())
+			}
+		})
+
+		It("should reject other states", func() {
+			errorList := validateManagementState(&cnao.NetworkAddonsConfigSpec{ManagementState: osv1.Force})
+			Expect(errorList).To(HaveLen(1), "validation failed due to an unexpected error: %v", errorList)
+			Expect(errorList[0]).To(MatchError("requested managementState 'Force' is not valid"))
+		})
+	})
+
+	Describe("DropRemovedComponents", func() {
+		newSpec := func(state osv1.ManagementState) *cnao.NetworkAddonsConfigSpec {
+			return &cnao.NetworkAddonsConfigSpec{
+				ManagementState: state,
+				Multus:          &cnao.Multus{},
+				LinuxBridge:     &cnao.LinuxBridge{},
+				Ovs:             &cnao.Ovs{},
+				KubeMacPool:     &cnao.KubeMacPool{},
+				MacvtapCni:      &cnao.MacvtapCni{},
+				ImagePullPolicy: "Always",
+			}
+		}
+
+		It("should drop all components when Removed", func() {
+			spec := newSpec(osv1.Removed)
+			DropRemovedComponents(spec)
+			Expect(spec).To(Equal(&cnao.NetworkAddonsConfigSpec{ManagementState: osv1.Removed, ImagePullPolicy: "Always"}))
+		})
+
+		It("should keep components otherwise", func() {
+			for _, state := range []osv1.ManagementState{"", osv1.Managed, osv1.Unmanaged} {
+				spec := newSpec(state)
+				DropRemovedComponents(spec)
+				Expect(spec).To(Equal(newSpec(state)))
+			}
+		})
+	})
+})
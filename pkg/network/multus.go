@@ -16,7 +16,6 @@ import (
 	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
 
 	cnao "github.com/kubevirt/cluster-network-addons-operator/pkg/apis/networkaddonsoperator/shared"
-	"github.com/kubevirt/cluster-network-addons-operator/pkg/network/cni"
 	"github.com/kubevirt/cluster-network-addons-operator/pkg/render"
 )
 
@@ -51,10 +50,11 @@ func cleanUpMultus(conf *cnao.NetworkAddonsConfigSpec, ctx context.Context, clie
 func cleanUpMultusOldName(ctx context.Context, client k8sclient.Client) []error {
 	// Get existing
 	existing := &unstructured.Unstructured{}
-	gvk := schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "DaemonSet"}
+	oldDaemonSet := multusOldNameDaemonSet()
+	gvk := oldDaemonSet.GroupVersionKind()
 	existing.SetGroupVersionKind(gvk)
-	namespace := os.Getenv("OPERAND_NAMESPACE")
-	name := "kube-multus-ds-amd64"
+	namespace := oldDaemonSet.GetNamespace()
+	name := oldDaemonSet.GetName()
 
 	err := client.Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, existing)
 	if err != nil {
@@ -79,6 +79,15 @@ func cleanUpMultusOldName(ctx context.Context, client k8sclient.Client) []error
 	return []error{}
 }
 
+// multusOldNameDaemonSet returns the multus DaemonSet with the name used before 0.25.0
+func multusOldNameDaemonSet() *unstructured.Unstructured {
+	daemonSet := &unstructured.Unstructured{}
+	daemonSet.SetGroupVersionKind(schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "DaemonSet"})
+	daemonSet.SetNamespace(os.Getenv("OPERAND_NAMESPACE"))
+	daemonSet.SetName("kube-multus-ds-amd64")
+	return daemonSet
+}
+
 // RenderMultus generates the manifests of Multus
 func renderMultus(conf *cnao.NetworkAddonsConfigSpec, manifestDir string, openshiftNetworkConfig *osv1.Network, clusterInfo *ClusterInfo) ([]*unstructured.Unstructured, error) {
 	if conf.Multus == nil || openshiftNetworkConfig != nil {
@@ -87,17 +96,14 @@ func renderMultus(conf *cnao.NetworkAddonsConfigSpec, manifestDir string, opensh
 
 	// render manifests from disk
 	data := render.MakeRenderData()
-	data.Data["Namespace"] = os.Getenv("OPERAND_NAMESPACE")
+	data.Data["Namespace"] = componentNamespace(conf.Multus.Namespace)
 	data.Data["MultusImage"] = os.Getenv("MULTUS_IMAGE")
+	data.Data["CNIHealthImage"] = os.Getenv("OPERATOR_IMAGE")
 	data.Data["ImagePullPolicy"] = conf.ImagePullPolicy
 	data.Data["Placement"] = conf.PlacementConfiguration.Workloads
-	if clusterInfo.OpenShift4 {
-		data.Data["CNIConfigDir"] = cni.ConfigDirOpenShift4
-		data.Data["CNIBinDir"] = cni.BinDirOpenShift4
-	} else {
-		data.Data["CNIConfigDir"] = cni.ConfigDir
-		data.Data["CNIBinDir"] = cni.BinDir
-	}
+	cniDirectories := CNIDirectories(conf, clusterInfo)
+	data.Data["CNIConfigDir"] = cniDirectories.ConfigDir
+	data.Data["CNIBinDir"] = cniDirectories.BinDir
 	data.Data["EnableSCC"] = clusterInfo.SCCAvailable
 
 	objs, err := render.RenderDir(filepath.Join(manifestDir, "multus"), &data)
@@ -0,0 +1,154 @@
+package network
+
+import (
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	cnao "github.com/kubevirt/cluster-network-addons-operator/pkg/apis/networkaddonsoperator/shared"
+)
+
+// componentNamespace returns the namespace a component is deployed to. Components are deployed
+// to the operand namespace unless the spec requests otherwise.
+func componentNamespace(namespace cnao.ComponentNamespace) string {
+	if namespace != "" {
+		return string(namespace)
+	}
+	return os.Getenv("OPERAND_NAMESPACE")
+}
+
+// componentMoved checks whether a component was requested to move to another namespace
+func componentMoved(prevNamespace, nextNamespace cnao.ComponentNamespace) bool {
+	return componentNamespace(prevNamespace) != componentNamespace(nextNamespace)
+}
+
+// allowedComponentNamespaces returns namespaces the operator RBAC allows components to be deployed
+// to, the operand namespace and namespaces listed when the operator manifests were generated
+func allowedComponentNamespaces() map[string]bool {
+	allowed := map[string]bool{os.Getenv("OPERAND_NAMESPACE"): true}
+	for _, namespace := range strings.Split(os.Getenv("COMPONENT_NAMESPACES"), ",") {
+		if namespace != "" {
+			allowed[namespace] = true
+		}
+	}
+	return allowed
+}
+
+func validateComponentNamespaces(conf *cnao.NetworkAddonsConfigSpec) []error {
+	allowed := allowedComponentNamespaces()
+	namespaces := componentNamespaces(conf)
+	components := []string{}
+	for component := range namespaces {
+		components = append(components, component)
+	}
+	sort.Strings(components)
+
+	errs := []error{}
+	for _, component := range components {
+		namespace := namespaces[component]
+		if namespace == "" {
+			continue
+		}
+		if msgs := validation.IsDNS1123Label(string(namespace)); len(msgs) > 0 {
+			errs = append(errs, errors.Errorf("invalid %s namespace %q: %s", component, namespace, strings.Join(msgs, ", ")))
+		} else if !allowed[string(namespace)] {
+			errs = append(errs, errors.Errorf("invalid %s namespace %q: the operator is allowed to deploy components only to namespaces listed in its COMPONENT_NAMESPACES", component, namespace))
+		}
+	}
+	return errs
+}
+
+// componentNamespaces returns namespaces requested in the spec by enabled components
+func componentNamespaces(conf *cnao.NetworkAddonsConfigSpec) map[string]cnao.ComponentNamespace {
+	namespaces := map[string]cnao.ComponentNamespace{}
+	if conf.Multus != nil {
+		namespaces["Multus"] = conf.Multus.Namespace
+	}
+	if conf.LinuxBridge != nil {
+		namespaces["LinuxBridge"] = conf.LinuxBridge.Namespace
+	}
+	if conf.KubeMacPool != nil {
+		namespaces["KubeMacPool"] = conf.KubeMacPool.Namespace
+	}
+	if conf.Ovs != nil {
+		namespaces["Ovs"] = conf.Ovs.Namespace
+	}
+	if conf.MacvtapCni != nil {
+		namespaces["MacvtapCni"] = conf.MacvtapCni.Namespace
+	}
+	return namespaces
+}
+
+// MovedNamespaces returns namespaces components enabled in conf were moved to from any of the
+// previous specs
+func MovedNamespaces(prevs []*cnao.NetworkAddonsConfigSpec, conf *cnao.NetworkAddonsConfigSpec) []string {
+	next := componentNamespaces(conf)
+	found := map[string]bool{}
+	namespaces := []string{}
+	for _, prev := range prevs {
+		for component, prevNamespace := range componentNamespaces(prev) {
+			nextNamespace, enabled := next[component]
+			if !enabled || !componentMoved(prevNamespace, nextNamespace) {
+				continue
+			}
+			namespace := componentNamespace(nextNamespace)
+			if !found[namespace] {
+				found[namespace] = true
+				namespaces = append(namespaces, namespace)
+			}
+		}
+	}
+	sort.Strings(namespaces)
+	return namespaces
+}
+
+// workloadNamespaces returns the operand namespace followed by other namespaces holding rendered
+// workloads of the given kinds
+func workloadNamespaces(objs []*unstructured.Unstructured, kinds ...string) []string {
+	namespaces := []string{os.Getenv("OPERAND_NAMESPACE")}
+	found := map[string]bool{namespaces[0]: true}
+	for _, obj := range objs {
+		for _, kind := range kinds {
+			if obj.GetAPIVersion() == "apps/v1" && obj.GetKind() == kind && !found[obj.GetNamespace()] {
+				found[obj.GetNamespace()] = true
+				namespaces = append(namespaces, obj.GetNamespace())
+			}
+		}
+	}
+	return namespaces
+}
+
+// withoutRenderedObjects filters out objects to remove which are still rendered, such as
+// cluster-scoped objects of a component moved to another namespace, and namespaces which still
+// hold rendered objects. Whether a remaining namespace gets removed is decided by its ownership.
+func withoutRenderedObjects(objsToRemove, objs []*unstructured.Unstructured) []*unstructured.Unstructured {
+	rendered := map[string]bool{}
+	usedNamespaces := map[string]bool{}
+	for _, obj := range objs {
+		rendered[objectKey(obj)] = true
+		if obj.GetNamespace() != "" {
+			usedNamespaces[obj.GetNamespace()] = true
+		}
+	}
+
+	remaining := []*unstructured.Unstructured{}
+	for _, obj := range objsToRemove {
+		if rendered[objectKey(obj)] {
+			continue
+		}
+		if obj.GetKind() == "Namespace" && usedNamespaces[obj.GetName()] {
+			continue
+		}
+		remaining = append(remaining, obj)
+	}
+	return remaining
+}
+
+// objectKey identifies an object regardless of its API version
+func objectKey(obj *unstructured.Unstructured) string {
+	return obj.GroupVersionKind().GroupKind().String() + "/" + obj.GetNamespace() + "/" + obj.GetName()
+}
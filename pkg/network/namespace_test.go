@@ -0,0 +1,183 @@
+package network
+
+import (
+	"os"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	osv1 "github.com/openshift/api/operator/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	cnao "github.com/kubevirt/cluster-network-addons-operator/pkg/apis/networkaddonsoperator/shared"
+	"github.com/kubevirt/cluster-network-addons-operator/pkg/names"
+)
+
+var _ = Describe("Testing component namespaces", func() {
+	const operandNamespace = "cluster-network-addons"
+	manifestDir := "../../data"
+
+	BeforeEach(func() {
+		previousOperandNamespace, found := os.LookupEnv("OPERAND_NAMESPACE")
+		Expect(os.Setenv("OPERAND_NAMESPACE", operandNamespace)).To(Succeed())
+		DeferCleanup(func() {
+			if found {
+				os.Setenv("OPERAND_NAMESPACE", previousOperandNamespace)
+			} else {
+				os.Unsetenv("OPERAND_NAMESPACE")
+			}
+		})
+	})
+
+	findObject := func(objs []*unstructured.Unstructured, kind, namespace, name string) *unstructured.Unstructured {
+		for _, obj := range objs {
+			if obj.GetKind() == kind && obj.GetNamespace() == namespace && obj.GetName() == name {
+				return obj
+			}
+		}
+		return nil
+	}
+
+	renderObjsToRemove := func(prev, conf *cnao.NetworkAddonsConfigSpec) ([]*unstructured.Unstructured, []*unstructured.Unstructured) {
+		Expect(FillDefaults(prev, nil)).To(Succeed())
+		Expect(FillDefaults(conf, prev)).To(Succeed())
+		objs, err := Render(conf, manifestDir, nil, &ClusterInfo{})
+		Expect(err).NotTo(HaveOccurred())
+		objsToRemove, err := RenderObjsToRemove(prev, conf, objs, manifestDir, nil, &ClusterInfo{})
+		Expect(err).NotTo(HaveOccurred())
+		return objs, objsToRemove
+	}
+
+	kubeMacPool := func(namespace cnao.ComponentNamespace) *cnao.KubeMacPool {
+		return &cnao.KubeMacPool{Namespace: namespace, RangeStart: "02:00:00:00:00:00", RangeEnd: "02:00:00:FF:FF:FF"}
+	}
+
+	Context("when a namespace is requested for a component", func() {
+		It("should render the component into the namespace", func() {
+			conf := &cnao.NetworkAddonsConfigSpec{LinuxBridge: &cnao.LinuxBridge{}, KubeMacPool: kubeMacPool("kubemacpool")}
+			objs, _ := renderObjsToRemove(&cnao.NetworkAddonsConfigSpec{}, conf)
+
+			Expect(findObject(objs, "Namespace", "", "kubemacpool")).NotTo(BeNil())
+			Expect(findObject(objs, "Deployment", "kubemacpool", "kubemacpool-mac-controller-manager")).NotTo(BeNil())
+			Expect(findObject(objs, "Namespace", "", operandNamespace)).NotTo(BeNil())
+			Expect(findObject(objs, "DaemonSet", operandNamespace, "bridge-marker")).NotTo(BeNil())
+		})
+
+		It("should monitor components in the namespace", func() {
+			conf := &cnao.NetworkAddonsConfigSpec{KubeMacPool: kubeMacPool("kubemacpool")}
+			Expect(FillDefaults(conf, nil)).To(Succeed())
+			objs, err := Render(conf, manifestDir, nil, &ClusterInfo{MonitoringAvailable: true, ProxyConfigAvailable: true, Proxy: &cnao.Proxy{TrustedCA: names.TRUSTED_CA_BUNDLE_CONFIGMAP}})
+			Expect(err).NotTo(HaveOccurred())
+
+			for _, namespace := range []string{operandNamespace, "kubemacpool"} {
+				Expect(findObject(objs, "Service", namespace, "cluster-network-addons-operator-prometheus-metrics")).NotTo(BeNil())
+				Expect(findObject(objs, "RoleBinding", namespace, "cluster-network-addons-operator-monitoring")).NotTo(BeNil())
+				Expect(findObject(objs, "ConfigMap", namespace, names.TRUSTED_CA_BUNDLE_CONFIGMAP)).NotTo(BeNil())
+			}
+			serviceMonitor := findObject(objs, "ServiceMonitor", operandNamespace, "service-monitor-cluster-network-addons-operator")
+			Expect(serviceMonitor).NotTo(BeNil())
+			matchNames, _, err := unstructured.NestedStringSlice(serviceMonitor.Object, "spec", "namespaceSelector", "matchNames")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(matchNames).To(Equal([]string{operandNamespace, "kubemacpool"}))
+		})
+
+		It("should reject an invalid namespace name", func() {
+			conf := &cnao.NetworkAddonsConfigSpec{KubeMacPool: kubeMacPool("Not_A_Namespace")}
+			err := Validate(conf, &osv1.Network{})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring(`invalid KubeMacPool namespace "Not_A_Namespace"`))
+		})
+
+		Context("and the operator manifests list namespaces of components", func() {
+			BeforeEach(func() {
+				Expect(os.Setenv("COMPONENT_NAMESPACES", "kubemacpool,bridges")).To(Succeed())
+				DeferCleanup(os.Unsetenv, "COMPONENT_NAMESPACES")
+			})
+
+			It("should accept a listed namespace and the operand namespace", func() {
+				conf := &cnao.NetworkAddonsConfigSpec{LinuxBridge: &cnao.LinuxBridge{Namespace: operandNamespace}, KubeMacPool: kubeMacPool("kubemacpool")}
+				Expect(Validate(conf, &osv1.Network{})).To(Succeed())
+			})
+
+			It("should reject a namespace the operator RBAC does not cover", func() {
+				conf := &cnao.NetworkAddonsConfigSpec{KubeMacPool: kubeMacPool("other")}
+				err := Validate(conf, &osv1.Network{})
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring(`invalid KubeMacPool namespace "other": the operator is allowed to deploy components only to namespaces listed in its COMPONENT_NAMESPACES`))
+			})
+		})
+	})
+
+	Context("when a component is moved to another namespace", func() {
+		var objs, objsToRemove []*unstructured.Unstructured
+		BeforeEach(func() {
+			prev := &cnao.NetworkAddonsConfigSpec{LinuxBridge: &cnao.LinuxBridge{}, KubeMacPool: kubeMacPool("")}
+			conf := &cnao.NetworkAddonsConfigSpec{LinuxBridge: &cnao.LinuxBridge{}, KubeMacPool: kubeMacPool("kubemacpool")}
+			objs, objsToRemove = renderObjsToRemove(prev, conf)
+		})
+
+		It("should remove its objects from the previous namespace", func() {
+			Expect(findObject(objsToRemove, "Deployment", operandNamespace, "kubemacpool-mac-controller-manager")).NotTo(BeNil())
+			Expect(findObject(objsToRemove, "Service", operandNamespace, "kubemacpool-service")).NotTo(BeNil())
+		})
+
+		It("should keep objects which are still rendered", func() {
+			Expect(findObject(objsToRemove, "ClusterRole", "", "kubemacpool-manager-role")).To(BeNil())
+			Expect(findObject(objsToRemove, "MutatingWebhookConfiguration", "", "kubemacpool-mutator")).To(BeNil())
+			for _, obj := range objsToRemove {
+				Expect(findObject(objs, obj.GetKind(), obj.GetNamespace(), obj.GetName())).To(BeNil())
+			}
+		})
+
+		It("should keep the previous namespace while it is used by other components", func() {
+			Expect(findObject(objsToRemove, "Namespace", "", operandNamespace)).To(BeNil())
+		})
+
+		It("should report the namespace it was moved to", func() {
+			prev := &cnao.NetworkAddonsConfigSpec{LinuxBridge: &cnao.LinuxBridge{}, KubeMacPool: kubeMacPool("")}
+			conf := &cnao.NetworkAddonsConfigSpec{LinuxBridge: &cnao.LinuxBridge{}, KubeMacPool: kubeMacPool("kubemacpool")}
+			Expect(MovedNamespaces([]*cnao.NetworkAddonsConfigSpec{prev}, conf)).To(Equal([]string{"kubemacpool"}))
+		})
+	})
+
+	Context("when the namespace is not used by any component anymore", func() {
+		It("should remove the namespace", func() {
+			prev := &cnao.NetworkAddonsConfigSpec{LinuxBridge: &cnao.LinuxBridge{Namespace: "bridges"}, KubeMacPool: kubeMacPool("")}
+			conf := &cnao.NetworkAddonsConfigSpec{KubeMacPool: kubeMacPool("")}
+			_, objsToRemove := renderObjsToRemove(prev, conf)
+
+			Expect(findObject(objsToRemove, "Namespace", "", "bridges")).NotTo(BeNil())
+			Expect(findObject(objsToRemove, "Namespace", "", operandNamespace)).To(BeNil())
+		})
+	})
+
+	Context("when a component stays in its namespace", func() {
+		It("should not remove any of its objects", func() {
+			prev := &cnao.NetworkAddonsConfigSpec{KubeMacPool: kubeMacPool("")}
+			conf := &cnao.NetworkAddonsConfigSpec{KubeMacPool: kubeMacPool(operandNamespace)}
+			_, objsToRemove := renderObjsToRemove(prev, conf)
+
+			Expect(findObject(objsToRemove, "Deployment", operandNamespace, "kubemacpool-mac-controller-manager")).To(BeNil())
+			Expect(MovedNamespaces([]*cnao.NetworkAddonsConfigSpec{prev}, conf)).To(BeEmpty())
+		})
+	})
+
+	Context("when macvtap-cni is removed", func() {
+		It("should remove its objects", func() {
+			prev := &cnao.NetworkAddonsConfigSpec{MacvtapCni: &cnao.MacvtapCni{}, KubeMacPool: kubeMacPool("")}
+			conf := &cnao.NetworkAddonsConfigSpec{KubeMacPool: kubeMacPool("")}
+			_, objsToRemove := renderObjsToRemove(prev, conf)
+
+			Expect(findObject(objsToRemove, "DaemonSet", operandNamespace, "macvtap-cni")).NotTo(BeNil())
+			Expect(findObject(objsToRemove, "ConfigMap", operandNamespace, "macvtap-deviceplugin-config")).NotTo(BeNil())
+		})
+	})
+
+	Context("when a component is removed", func() {
+		It("should not report it as moved", func() {
+			prev := &cnao.NetworkAddonsConfigSpec{LinuxBridge: &cnao.LinuxBridge{Namespace: "bridges"}, KubeMacPool: kubeMacPool("")}
+			conf := &cnao.NetworkAddonsConfigSpec{KubeMacPool: kubeMacPool("")}
+			Expect(MovedNamespaces([]*cnao.NetworkAddonsConfigSpec{prev}, conf)).To(BeEmpty())
+		})
+	})
+})
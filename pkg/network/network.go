@@ -39,8 +39,15 @@ func Validate(conf *cnao.NetworkAddonsConfigSpec, openshiftNetworkConfig *osv1.N
 
 	errs = append(errs, validateMultus(conf, openshiftNetworkConfig)...)
 	errs = append(errs, validateKubeMacPool(conf)...)
+	errs = append(errs, validateLinuxBridge(conf)...)
+	errs = append(errs, validateMacvtapCni(conf)...)
+	errs = append(errs, validateOvs(conf)...)
 	errs = append(errs, validateImagePullPolicy(conf)...)
+	errs = append(errs, validateCNIDirectories(conf)...)
+	errs = append(errs, validateProxy(conf)...)
 	errs = append(errs, validateSelfSignConfiguration(conf)...)
+	errs = append(errs, validateComponentNamespaces(conf)...)
+	errs = append(errs, validateManagementState(conf)...)
 
 	if len(errs) > 0 {
 		return errors.Errorf("invalid configuration:\n%s", errorListToMultiLineString(errs))
@@ -60,6 +67,8 @@ func FillDefaults(conf, previous *cnao.NetworkAddonsConfigSpec) error {
 	errs = append(errs, fillDefaultsSelfSignConfiguration(conf, previous)...)
 	errs = append(errs, fillDefaultsImagePullPolicy(conf, previous)...)
 	errs = append(errs, fillDefaultsKubeMacPool(conf, previous)...)
+	errs = append(errs, fillDefaultsLinuxBridge(conf, previous)...)
+	errs = append(errs, fillDefaultsOvs(conf, previous)...)
 
 	if len(errs) > 0 {
 		return errors.Errorf("invalid configuration:\n%s", errorListToMultiLineString(errs))
@@ -83,6 +92,11 @@ func SpecialCleanUp(conf *cnao.NetworkAddonsConfigSpec, client k8sclient.Client,
 	return nil
 }
 
+// SpecialCleanUpObjects lists objects that may be removed by SpecialCleanUp
+func SpecialCleanUpObjects() []*unstructured.Unstructured {
+	return []*unstructured.Unstructured{multusOldNameDaemonSet()}
+}
+
 // IsChangeSafe checks to see if the change between prev and next are allowed
 // FillDefaults and Validate should have been called.
 func IsChangeSafe(prev, next *cnao.NetworkAddonsConfigSpec) error {
@@ -147,18 +161,31 @@ func Render(conf *cnao.NetworkAddonsConfigSpec, manifestDir string, openshiftNet
 	objs = append(objs, o...)
 
 	// render Monitoring Service
-	o, err = monitoring.RenderMonitoring(manifestDir, clusterInfo.MonitoringAvailable)
+	o, err = monitoring.RenderMonitoring(manifestDir, clusterInfo.MonitoringAvailable, workloadNamespaces(objs, "Deployment", "DaemonSet"))
+	if err != nil {
+		return nil, err
+	}
+	objs = append(objs, o...)
+
+	// render trusted CA bundle injected by OpenShift
+	o, err = renderTrustedCABundle(manifestDir, clusterInfo, workloadNamespaces(objs, "Deployment"))
 	if err != nil {
 		return nil, err
 	}
 	objs = append(objs, o...)
 
+	// pass proxy configuration to components reaching external services
+	if err := injectProxy(objs, proxyConfig(conf, clusterInfo)); err != nil {
+		return nil, err
+	}
+
 	log.Printf("render phase done, rendered %d objects", len(objs))
 	return objs, nil
 }
 
-// RenderObjsToRemove creates list of components to be removed
-func RenderObjsToRemove(prev, conf *cnao.NetworkAddonsConfigSpec, manifestDir string, openshiftNetworkConfig *osv1.Network, clusterInfo *ClusterInfo) ([]*unstructured.Unstructured, error) {
+// RenderObjsToRemove creates list of components to be removed. Components which were disabled or
+// moved to another namespace are removed, unless their objects are still among the rendered objs.
+func RenderObjsToRemove(prev, conf *cnao.NetworkAddonsConfigSpec, objs []*unstructured.Unstructured, manifestDir string, openshiftNetworkConfig *osv1.Network, clusterInfo *ClusterInfo) ([]*unstructured.Unstructured, error) {
 	log.Print("starting rendering objects to delete phase")
 	objsToRemove := []*unstructured.Unstructured{}
 
@@ -166,7 +193,7 @@ func RenderObjsToRemove(prev, conf *cnao.NetworkAddonsConfigSpec, manifestDir st
 		return nil, nil
 	}
 
-	if conf.Multus == nil {
+	if conf.Multus == nil || (prev.Multus != nil && componentMoved(prev.Multus.Namespace, conf.Multus.Namespace)) {
 		o, err := renderMultus(prev, manifestDir, openshiftNetworkConfig, clusterInfo)
 		if err != nil {
 			return nil, err
@@ -174,7 +201,7 @@ func RenderObjsToRemove(prev, conf *cnao.NetworkAddonsConfigSpec, manifestDir st
 		objsToRemove = append(objsToRemove, o...)
 	}
 
-	if conf.LinuxBridge == nil {
+	if conf.LinuxBridge == nil || (prev.LinuxBridge != nil && componentMoved(prev.LinuxBridge.Namespace, conf.LinuxBridge.Namespace)) {
 		o, err := renderLinuxBridge(prev, manifestDir, clusterInfo)
 		if err != nil {
 			return nil, err
@@ -182,7 +209,7 @@ func RenderObjsToRemove(prev, conf *cnao.NetworkAddonsConfigSpec, manifestDir st
 		objsToRemove = append(objsToRemove, o...)
 	}
 
-	if conf.KubeMacPool == nil {
+	if conf.KubeMacPool == nil || (prev.KubeMacPool != nil && componentMoved(prev.KubeMacPool.Namespace, conf.KubeMacPool.Namespace)) {
 		o, err := renderKubeMacPool(prev, manifestDir)
 		if err != nil {
 			return nil, err
@@ -190,7 +217,7 @@ func RenderObjsToRemove(prev, conf *cnao.NetworkAddonsConfigSpec, manifestDir st
 		objsToRemove = append(objsToRemove, o...)
 	}
 
-	if conf.Ovs == nil {
+	if conf.Ovs == nil || (prev.Ovs != nil && componentMoved(prev.Ovs.Namespace, conf.Ovs.Namespace)) {
 		o, err := renderOvs(prev, manifestDir, clusterInfo)
 		if err != nil {
 			return nil, err
@@ -198,25 +225,16 @@ func RenderObjsToRemove(prev, conf *cnao.NetworkAddonsConfigSpec, manifestDir st
 		objsToRemove = append(objsToRemove, o...)
 	}
 
-	// render MacvtapCni
-	if conf.MacvtapCni == nil {
-		o, err := renderMacvtapCni(conf, manifestDir, clusterInfo)
+	if conf.MacvtapCni == nil || (prev.MacvtapCni != nil && componentMoved(prev.MacvtapCni.Namespace, conf.MacvtapCni.Namespace)) {
+		o, err := renderMacvtapCni(prev, manifestDir, clusterInfo)
 		if err != nil {
 			return nil, err
 		}
 		objsToRemove = append(objsToRemove, o...)
 	}
 
-	// Remove OPERAND_NAMESPACE occurences
-	// TODO cleanup OPERAND_NAMESPACE once there are no components using it.
-	objsToRemoveWithoutNamespace := []*unstructured.Unstructured{}
-	operandNamespace := os.Getenv("OPERAND_NAMESPACE")
-	for _, obj := range objsToRemove {
-		if !(obj.GetName() == operandNamespace && obj.GetKind() == "Namespace") {
-			objsToRemoveWithoutNamespace = append(objsToRemoveWithoutNamespace, obj)
-		}
-	}
-	objsToRemove = objsToRemoveWithoutNamespace
+	// Keep objects shared with the rendered components, including their namespaces
+	objsToRemove = withoutRenderedObjects(objsToRemove, objs)
 
 	// Do not remove CustomResourceDefinitions, they should be kept even after
 	// removal of the operator
@@ -229,7 +247,7 @@ func RenderObjsToRemove(prev, conf *cnao.NetworkAddonsConfigSpec, manifestDir st
 	objsToRemove = objsToRemoveWithoutCRDs
 
 	// Remove old CNAO managed kubernetes-nmstate
-	oldKNMStateObjects, err := cnaoKNMStateObjects(operandNamespace)
+	oldKNMStateObjects, err := cnaoKNMStateObjects(os.Getenv("OPERAND_NAMESPACE"))
 	if err != nil {
 		return nil, err
 	}
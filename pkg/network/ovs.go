@@ -1,17 +1,166 @@
 package network
 
 import (
+	"fmt"
 	"os"
+	"path"
 	"path/filepath"
+	"time"
 
 	"github.com/kubevirt/cluster-network-addons-operator/pkg/render"
 	"github.com/pkg/errors"
+	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 
 	cnao "github.com/kubevirt/cluster-network-addons-operator/pkg/apis/networkaddonsoperator/shared"
-	"github.com/kubevirt/cluster-network-addons-operator/pkg/network/cni"
+	"github.com/kubevirt/cluster-network-addons-operator/pkg/names"
 )
 
+const (
+	ovsSocketDefault                    = "/var/run/openvswitch/db.sock"
+	ovsMarkerUpdateIntervalDefault      = time.Minute
+	ovsMarkerHealthcheckIntervalDefault = time.Minute
+
+	// ovsSocketMountPath is where the directory holding the socket is mounted in the marker container
+	ovsSocketMountPath = "/host/var/run/openvswitch"
+)
+
+// ovsNodeAnnotations are set on nodes by network providers that run Open vSwitch on them
+var ovsNodeAnnotations = []string{
+	// OVN-Kubernetes
+	"k8s.ovn.org/node-chassis-id",
+	// Kube-OVN
+	"ovn.kubernetes.io/chassis",
+}
+
+// validateOvs validates the ovs-cni marker configuration
+func validateOvs(conf *cnao.NetworkAddonsConfigSpec) []error {
+	if conf.Ovs == nil || conf.Ovs.Marker == nil {
+		return []error{}
+	}
+
+	marker := conf.Ovs.Marker
+	errs := []error{}
+
+	if marker.OvsSocket != "" {
+		if !path.IsAbs(marker.OvsSocket) || path.Clean(marker.OvsSocket) != marker.OvsSocket || path.Dir(marker.OvsSocket) == "/" {
+			errs = append(errs, errors.Errorf("failed to validate ovs: marker.ovsSocket %q has to be a clean absolute path of a file outside of the root directory", marker.OvsSocket))
+		}
+	}
+
+	errs = appendOnError(errs, validateOvsMarkerInterval("updateInterval", marker.UpdateInterval))
+	errs = appendOnError(errs, validateOvsMarkerInterval("healthcheckInterval", marker.HealthcheckInterval))
+
+	return errs
+}
+
+func validateOvsMarkerInterval(name, value string) error {
+	if value == "" {
+		return nil
+	}
+
+	interval, err := time.ParseDuration(value)
+	if err != nil {
+		return errors.Wrapf(err, "failed to validate ovs: error parsing marker.%s", name)
+	}
+	if interval < time.Second {
+		return errors.Errorf("failed to validate ovs: marker.%s(%s) has to be >= 1s", name, interval)
+	}
+	return nil
+}
+
+func fillDefaultsOvs(conf, previous *cnao.NetworkAddonsConfigSpec) []error {
+	if conf.Ovs == nil {
+		return []error{}
+	}
+
+	previousMarker := &cnao.OvsMarker{}
+	if previous != nil && previous.Ovs != nil && previous.Ovs.Marker != nil {
+		previousMarker = previous.Ovs.Marker
+	}
+
+	if conf.Ovs.Marker == nil {
+		conf.Ovs.Marker = &cnao.OvsMarker{}
+	}
+	marker := conf.Ovs.Marker
+	marker.OvsSocket = firstNonEmpty(marker.OvsSocket, previousMarker.OvsSocket, ovsSocketDefault)
+	marker.UpdateInterval = firstNonEmpty(marker.UpdateInterval, previousMarker.UpdateInterval, ovsMarkerUpdateIntervalDefault.String())
+	marker.HealthcheckInterval = firstNonEmpty(marker.HealthcheckInterval, previousMarker.HealthcheckInterval, ovsMarkerHealthcheckIntervalDefault.String())
+
+	return []error{}
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// ovsMarkerRenderData translates the marker configuration to template data of the ovs-cni DaemonSet
+func ovsMarkerRenderData(marker *cnao.OvsMarker, data *render.RenderData) error {
+	if marker == nil {
+		marker = &cnao.OvsMarker{}
+	}
+
+	ovsSocket := firstNonEmpty(marker.OvsSocket, ovsSocketDefault)
+	updateInterval, err := time.ParseDuration(firstNonEmpty(marker.UpdateInterval, ovsMarkerUpdateIntervalDefault.String()))
+	if err != nil {
+		return errors.Wrap(err, "failed to parse ovs marker update interval")
+	}
+	healthcheckInterval, err := time.ParseDuration(firstNonEmpty(marker.HealthcheckInterval, ovsMarkerHealthcheckIntervalDefault.String()))
+	if err != nil {
+		return errors.Wrap(err, "failed to parse ovs marker healthcheck interval")
+	}
+
+	data.Data["OvsSocketDir"] = path.Dir(ovsSocket)
+	data.Data["OvsMarkerArgs"] = []string{
+		"-v", "3",
+		"-logtostderr",
+		"-node-name", "$(NODE_NAME)",
+		"-ovs-socket", "unix:" + path.Join(ovsSocketMountPath, path.Base(ovsSocket)),
+		"-update-interval", fmt.Sprintf("%d", int64(updateInterval.Seconds())),
+		fmt.Sprintf("-healthcheck-interval=%d", int64(healthcheckInterval.Seconds())),
+	}
+	// The marker touches the health file on every healthcheck, tolerate one missed check
+	healthyFileMaxAge := (2*healthcheckInterval + time.Minute - 1) / time.Minute
+	if healthyFileMaxAge < 2 {
+		healthyFileMaxAge = 2
+	}
+	data.Data["OvsMarkerHealthyFileMaxAge"] = int64(healthyFileMaxAge)
+
+	return nil
+}
+
+// ovsPlacement returns the placement of ovs-cni, restricted to Open vSwitch nodes if requested
+func ovsPlacement(conf *cnao.NetworkAddonsConfigSpec) *cnao.Placement {
+	placement := conf.PlacementConfiguration.Workloads
+	if !conf.Ovs.OvsNodesOnly {
+		return placement
+	}
+
+	placement = placement.DeepCopy()
+	if placement.NodeSelector == nil {
+		placement.NodeSelector = map[string]string{}
+	}
+	placement.NodeSelector[names.OVS_NODE_LABEL_KEY] = names.OVS_NODE_LABEL_VALUE
+	return placement
+}
+
+// IsOvsNode checks whether the network provider of the node runs Open vSwitch on it. Resources reported by
+// the ovs-cni marker are not considered, the marker runs only on labeled nodes when ovs-cni is restricted
+// to Open vSwitch nodes.
+func IsOvsNode(node *v1.Node) bool {
+	for _, annotation := range ovsNodeAnnotations {
+		if _, exists := node.GetAnnotations()[annotation]; exists {
+			return true
+		}
+	}
+	return false
+}
+
 // renderOvs generates the manifests of Ovs
 func renderOvs(conf *cnao.NetworkAddonsConfigSpec, manifestDir string, clusterInfo *ClusterInfo) ([]*unstructured.Unstructured, error) {
 	if conf.Ovs == nil {
@@ -20,16 +169,16 @@ func renderOvs(conf *cnao.NetworkAddonsConfigSpec, manifestDir string, clusterIn
 
 	// render the manifests on disk
 	data := render.MakeRenderData()
-	data.Data["Namespace"] = os.Getenv("OPERAND_NAMESPACE")
+	data.Data["Namespace"] = componentNamespace(conf.Ovs.Namespace)
 	data.Data["OvsCNIImage"] = os.Getenv("OVS_CNI_IMAGE")
+	data.Data["CNIHealthImage"] = os.Getenv("OPERATOR_IMAGE")
 	data.Data["ImagePullPolicy"] = conf.ImagePullPolicy
-	data.Data["Placement"] = conf.PlacementConfiguration.Workloads
-	if clusterInfo.OpenShift4 {
-		data.Data["CNIBinDir"] = cni.BinDirOpenShift4
-	} else {
-		data.Data["CNIBinDir"] = cni.BinDir
-	}
+	data.Data["Placement"] = ovsPlacement(conf)
+	data.Data["CNIBinDir"] = CNIDirectories(conf, clusterInfo).BinDir
 	data.Data["EnableSCC"] = clusterInfo.SCCAvailable
+	if err := ovsMarkerRenderData(conf.Ovs.Marker, &data); err != nil {
+		return nil, errors.Wrap(err, "failed to render ovs manifests")
+	}
 
 	objs, err := render.RenderDir(filepath.Join(manifestDir, "ovs"), &data)
 	if err != nil {
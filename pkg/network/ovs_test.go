@@ -0,0 +1,122 @@
+package network
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	cnao "github.com/kubevirt/cluster-network-addons-operator/pkg/apis/networkaddonsoperator/shared"
+	"github.com/kubevirt/cluster-network-addons-operator/pkg/names"
+)
+
+var _ = Describe("Testing ovs", func() {
+	type validateCase struct {
+		marker        *cnao.OvsMarker
+		expectedError string
+	}
+	DescribeTable("validation function",
+		func(c validateCase) {
+			conf := &cnao.NetworkAddonsConfigSpec{Ovs: &cnao.Ovs{Marker: c.marker}}
+			errorList := validateOvs(conf)
+			if len(c.expectedError) > 0 {
+				Expect(errorList).To(HaveLen(1), "validation failed due to an unexpected error: %v", errorList)
+				Expect(errorList[0].Error()).To(MatchRegexp(c.expectedError))
+			} else {
+				Expect(errorList).To(BeEmpty())
+			}
+		},
+		Entry("When marker is not configured should pass", validateCase{}),
+		Entry("When marker is fully configured should pass", validateCase{
+			marker: &cnao.OvsMarker{OvsSocket: "/run/openvswitch/db.sock", UpdateInterval: "30s", HealthcheckInterval: "2m"},
+		}),
+		Entry("When ovsSocket is relative should return an error", validateCase{
+			marker:        &cnao.OvsMarker{OvsSocket: "run/openvswitch/db.sock"},
+			expectedError: `failed to validate ovs: marker.ovsSocket "run/openvswitch/db.sock" has to be a clean absolute path`,
+		}),
+		Entry("When ovsSocket is in the root directory should return an error", validateCase{
+			marker:        &cnao.OvsMarker{OvsSocket: "/db.sock"},
+			expectedError: `failed to validate ovs: marker.ovsSocket "/db.sock" has to be a clean absolute path`,
+		}),
+		Entry("When updateInterval cannot be parsed should return an error", validateCase{
+			marker:        &cnao.OvsMarker{UpdateInterval: "foo"},
+			expectedError: "failed to validate ovs: error parsing marker.updateInterval",
+		}),
+		Entry("When healthcheckInterval is shorter than a second should return an error", validateCase{
+			marker:        &cnao.OvsMarker{HealthcheckInterval: "1ms"},
+			expectedError: `failed to validate ovs: marker.healthcheckInterval\(1ms\) has to be >= 1s`,
+		}),
+	)
+
+	Describe("fillDefaultsOvs", func() {
+		It("should prefer previous values over defaults", func() {
+			previous := &cnao.NetworkAddonsConfigSpec{Ovs: &cnao.Ovs{Marker: &cnao.OvsMarker{UpdateInterval: "2m"}}}
+			conf := &cnao.NetworkAddonsConfigSpec{Ovs: &cnao.Ovs{Marker: &cnao.OvsMarker{HealthcheckInterval: "3m"}}}
+			Expect(fillDefaultsOvs(conf, previous)).To(BeEmpty())
+			Expect(conf.Ovs.Marker).To(Equal(&cnao.OvsMarker{
+				OvsSocket:           "/var/run/openvswitch/db.sock",
+				UpdateInterval:      "2m",
+				HealthcheckInterval: "3m",
+			}))
+		})
+	})
+
+	Describe("renderOvs", func() {
+		renderDaemonSet := func(ovs *cnao.Ovs) *appsv1.DaemonSet {
+			conf := &cnao.NetworkAddonsConfigSpec{
+				Ovs:                    ovs,
+				PlacementConfiguration: &cnao.PlacementConfiguration{Workloads: &cnao.Placement{NodeSelector: map[string]string{"foo": "bar"}}},
+			}
+			objs, err := renderOvs(conf, "../../data", &ClusterInfo{})
+			Expect(err).NotTo(HaveOccurred())
+			for _, obj := range objs {
+				if obj.GetKind() == "DaemonSet" {
+					daemonSet := &appsv1.DaemonSet{}
+					Expect(runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, daemonSet)).To(Succeed())
+					return daemonSet
+				}
+			}
+			Fail("ovs-cni DaemonSet was not rendered")
+			return nil
+		}
+
+		It("should pass the marker configuration to the marker container", func() {
+			daemonSet := renderDaemonSet(&cnao.Ovs{Marker: &cnao.OvsMarker{
+				OvsSocket:           "/run/ovs/db.sock",
+				UpdateInterval:      "30s",
+				HealthcheckInterval: "5m",
+			}})
+			marker := daemonSet.Spec.Template.Spec.Containers[0]
+			Expect(marker.Args).To(ContainElements("unix:/host/var/run/openvswitch/db.sock", "30", "-healthcheck-interval=300"))
+			Expect(marker.LivenessProbe.Exec.Command[2]).To(ContainSubstring("-mmin -10 "))
+			Expect(daemonSet.Spec.Template.Spec.Volumes[1].HostPath.Path).To(Equal("/run/ovs"))
+		})
+
+		It("should keep the workloads placement when not restricted to ovs nodes", func() {
+			daemonSet := renderDaemonSet(&cnao.Ovs{})
+			Expect(daemonSet.Spec.Template.Spec.NodeSelector).To(Equal(map[string]string{"foo": "bar"}))
+		})
+
+		It("should select ovs nodes when restricted to them", func() {
+			daemonSet := renderDaemonSet(&cnao.Ovs{OvsNodesOnly: true})
+			Expect(daemonSet.Spec.Template.Spec.NodeSelector).To(Equal(map[string]string{"foo": "bar", names.OVS_NODE_LABEL_KEY: names.OVS_NODE_LABEL_VALUE}))
+		})
+	})
+
+	DescribeTable("IsOvsNode",
+		func(node *v1.Node, expected bool) {
+			Expect(IsOvsNode(node)).To(Equal(expected))
+		},
+		Entry("should detect OVN-Kubernetes nodes",
+			&v1.Node{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{"k8s.ovn.org/node-chassis-id": "id"}}}, true),
+		Entry("should detect Kube-OVN nodes",
+			&v1.Node{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{"ovn.kubernetes.io/chassis": "id"}}}, true),
+		Entry("should not rely on resources reported by the ovs-cni marker",
+			&v1.Node{Status: v1.NodeStatus{Capacity: v1.ResourceList{"ovs-cni.network.kubevirt.io/br1": resource.MustParse("1k")}}}, false),
+		Entry("should not detect other nodes", &v1.Node{}, false),
+	)
+})
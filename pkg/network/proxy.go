@@ -0,0 +1,207 @@
+package network
+
+import (
+	"net/url"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	cnao "github.com/kubevirt/cluster-network-addons-operator/pkg/apis/networkaddonsoperator/shared"
+	"github.com/kubevirt/cluster-network-addons-operator/pkg/names"
+	"github.com/kubevirt/cluster-network-addons-operator/pkg/render"
+)
+
+const (
+	trustedCABundleKey        = "ca-bundle.crt"
+	trustedCABundleVolumeName = "trusted-ca-bundle"
+	// trustedCABundleMountPath is where the system trusted bundle is looked up by components images
+	trustedCABundleMountPath = "/etc/pki/ca-trust/extracted/pem"
+	trustedCABundleFile      = "tls-ca-bundle.pem"
+)
+
+// validateProxy validates proxy configuration requested in the spec
+func validateProxy(conf *cnao.NetworkAddonsConfigSpec) []error {
+	if conf.Proxy == nil {
+		return []error{}
+	}
+
+	errs := []error{}
+
+	errs = appendOnError(errs, validateProxyURL("httpProxy", conf.Proxy.HTTPProxy, "http"))
+	errs = appendOnError(errs, validateProxyURL("httpsProxy", conf.Proxy.HTTPSProxy, "http", "https"))
+
+	if conf.Proxy.TrustedCA != "" {
+		if msgs := validation.IsDNS1123Subdomain(conf.Proxy.TrustedCA); len(msgs) > 0 {
+			errs = append(errs, errors.Errorf("failed to validate proxy: trustedCA %q is not a valid ConfigMap name: %v", conf.Proxy.TrustedCA, msgs))
+		}
+	}
+
+	return errs
+}
+
+func validateProxyURL(name, value string, schemes ...string) error {
+	if value == "" {
+		return nil
+	}
+
+	proxyURL, err := url.Parse(value)
+	if err != nil {
+		return errors.Wrapf(err, "failed to validate proxy: error parsing %s", name)
+	}
+	for _, scheme := range schemes {
+		if proxyURL.Scheme == scheme && proxyURL.Host != "" {
+			return nil
+		}
+	}
+	return errors.Errorf("failed to validate proxy: %s %q has to be an URL with one of %v schemes", name, value, schemes)
+}
+
+// proxyConfig returns proxy configuration of components. Configuration requested in the spec takes precedence
+// over the cluster-wide one, nil is returned if neither is available.
+func proxyConfig(conf *cnao.NetworkAddonsConfigSpec, clusterInfo *ClusterInfo) *cnao.Proxy {
+	if conf.Proxy != nil {
+		return conf.Proxy
+	}
+	return clusterInfo.Proxy
+}
+
+// renderTrustedCABundle generates the ConfigMap OpenShift injects the cluster trusted CA bundle into,
+// one in each of the given namespaces. Nothing is rendered unless the cluster-wide proxy sets a trusted CA.
+func renderTrustedCABundle(manifestDir string, clusterInfo *ClusterInfo, namespaces []string) ([]*unstructured.Unstructured, error) {
+	if !clusterInfo.ProxyConfigAvailable || clusterInfo.Proxy == nil || clusterInfo.Proxy.TrustedCA == "" {
+		return nil, nil
+	}
+
+	objs := []*unstructured.Unstructured{}
+	for _, namespace := range namespaces {
+		data := render.MakeRenderData()
+		data.Data["Namespace"] = namespace
+		data.Data["TrustedCABundleName"] = names.TRUSTED_CA_BUNDLE_CONFIGMAP
+		data.Data["InjectLabelKey"] = names.TRUSTED_CA_BUNDLE_INJECT_LABEL_KEY
+
+		o, err := render.RenderDir(filepath.Join(manifestDir, "proxy"), &data)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to render trusted CA bundle manifests")
+		}
+		objs = append(objs, o...)
+	}
+
+	return objs, nil
+}
+
+// injectProxy passes proxy configuration and trusted CA bundle to containers of rendered Deployments. Objects
+// are patched in place, so fields unknown to the operator are kept.
+func injectProxy(objs []*unstructured.Unstructured, proxy *cnao.Proxy) error {
+	if proxy == nil {
+		return nil
+	}
+
+	env := []interface{}{}
+	for _, variable := range []v1.EnvVar{
+		{Name: "HTTP_PROXY", Value: proxy.HTTPProxy},
+		{Name: "HTTPS_PROXY", Value: proxy.HTTPSProxy},
+		{Name: "NO_PROXY", Value: proxy.NoProxy},
+	} {
+		if variable.Value != "" {
+			env = append(env, map[string]interface{}{"name": variable.Name, "value": variable.Value})
+		}
+	}
+
+	for _, obj := range objs {
+		if obj.GetAPIVersion() != "apps/v1" || obj.GetKind() != "Deployment" {
+			continue
+		}
+
+		if err := injectProxyIntoPodSpec(obj.Object, env, proxy.TrustedCA); err != nil {
+			return errors.Wrapf(err, "failed to inject proxy configuration into Deployment %s", obj.GetName())
+		}
+	}
+
+	return nil
+}
+
+func injectProxyIntoPodSpec(deployment map[string]interface{}, env []interface{}, trustedCA string) error {
+	for _, field := range []string{"initContainers", "containers"} {
+		containersPath := []string{"spec", "template", "spec", field}
+		containers, found, err := unstructured.NestedSlice(deployment, containersPath...)
+		if err != nil {
+			return err
+		}
+		if !found {
+			continue
+		}
+
+		for i := range containers {
+			container, ok := containers[i].(map[string]interface{})
+			if !ok {
+				return errors.Errorf("unexpected type of %s[%d]", field, i)
+			}
+
+			currentEnv, _, err := unstructured.NestedSlice(container, "env")
+			if err != nil {
+				return err
+			}
+			if newEnv := setEnvVars(currentEnv, env); len(newEnv) > 0 {
+				container["env"] = newEnv
+			}
+
+			if trustedCA != "" {
+				mounts, _, err := unstructured.NestedSlice(container, "volumeMounts")
+				if err != nil {
+					return err
+				}
+				container["volumeMounts"] = append(mounts, map[string]interface{}{
+					"name":      trustedCABundleVolumeName,
+					"mountPath": trustedCABundleMountPath,
+					"readOnly":  true,
+				})
+			}
+		}
+
+		if err := unstructured.SetNestedSlice(deployment, containers, containersPath...); err != nil {
+			return err
+		}
+	}
+
+	if trustedCA != "" {
+		volumesPath := []string{"spec", "template", "spec", "volumes"}
+		volumes, _, err := unstructured.NestedSlice(deployment, volumesPath...)
+		if err != nil {
+			return err
+		}
+		volumes = append(volumes, map[string]interface{}{
+			"name": trustedCABundleVolumeName,
+			"configMap": map[string]interface{}{
+				"name":  trustedCA,
+				"items": []interface{}{map[string]interface{}{"key": trustedCABundleKey, "path": trustedCABundleFile}},
+			},
+		})
+		if err := unstructured.SetNestedSlice(deployment, volumes, volumesPath...); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// setEnvVars overrides variables of the same name and appends the rest
+func setEnvVars(current, variables []interface{}) []interface{} {
+	for _, variable := range variables {
+		name := variable.(map[string]interface{})["name"]
+		found := false
+		for i := range current {
+			if existing, ok := current[i].(map[string]interface{}); ok && existing["name"] == name {
+				current[i] = variable
+				found = true
+				break
+			}
+		}
+		if !found {
+			current = append(current, variable)
+		}
+	}
+	return current
+}
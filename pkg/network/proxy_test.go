@@ -0,0 +1,176 @@
+package network
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	cnao "github.com/kubevirt/cluster-network-addons-operator/pkg/apis/networkaddonsoperator/shared"
+	"github.com/kubevirt/cluster-network-addons-operator/pkg/names"
+	"github.com/kubevirt/cluster-network-addons-operator/pkg/util/k8s"
+)
+
+var _ = Describe("Testing proxy", func() {
+	type validateCase struct {
+		proxy         *cnao.Proxy
+		expectedError string
+	}
+	DescribeTable("validation function",
+		func(c validateCase) {
+			conf := &cnao.NetworkAddonsConfigSpec{Proxy: c.proxy}
+			errorList := validateProxy(conf)
+			if len(c.expectedError) > 0 {
+				Expect(errorList).To(HaveLen(1), "validation failed due to an unexpected error: %v", errorList)
+				Expect(errorList[0].Error()).To(MatchRegexp(c.expectedError))
+			} else {
+				Expect(errorList).To(BeEmpty())
+			}
+		},
+		Entry("When proxy is not configured should pass", validateCase{}),
+		Entry("When proxy is fully configured should pass", validateCase{
+			proxy: &cnao.Proxy{
+				HTTPProxy:  "http://proxy.example.com:3128",
+				HTTPSProxy: "https://proxy.example.com:3129",
+				NoProxy:    ".cluster.local,10.0.0.0/8",
+				TrustedCA:  "user-ca-bundle",
+			},
+		}),
+		Entry("When httpProxy has unsupported scheme should return an error", validateCase{
+			proxy:         &cnao.Proxy{HTTPProxy: "https://proxy.example.com"},
+			expectedError: `failed to validate proxy: httpProxy "https://proxy.example.com" has to be an URL with one of \[http\] schemes`,
+		}),
+		Entry("When httpsProxy has no host should return an error", validateCase{
+			proxy:         &cnao.Proxy{HTTPSProxy: "proxy.example.com"},
+			expectedError: `failed to validate proxy: httpsProxy "proxy.example.com" has to be an URL`,
+		}),
+		Entry("When trustedCA is not a valid name should return an error", validateCase{
+			proxy:         &cnao.Proxy{TrustedCA: "User CA"},
+			expectedError: `failed to validate proxy: trustedCA "User CA" is not a valid ConfigMap name`,
+		}),
+	)
+
+	Describe("proxyConfig", func() {
+		clusterProxy := &cnao.Proxy{HTTPProxy: "http://cluster:3128"}
+
+		It("should prefer configuration requested in the spec", func() {
+			specProxy := &cnao.Proxy{HTTPProxy: "http://spec:3128"}
+			Expect(proxyConfig(&cnao.NetworkAddonsConfigSpec{Proxy: specProxy}, &ClusterInfo{Proxy: clusterProxy})).To(Equal(specProxy))
+		})
+
+		It("should fall back to the cluster-wide configuration", func() {
+			Expect(proxyConfig(&cnao.NetworkAddonsConfigSpec{}, &ClusterInfo{Proxy: clusterProxy})).To(Equal(clusterProxy))
+		})
+	})
+
+	Describe("injectProxy", func() {
+		var objs []*unstructured.Unstructured
+
+		BeforeEach(func() {
+			objs = []*unstructured.Unstructured{
+				k8s.UnstructuredFromYaml(`
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: d1
+spec:
+  template:
+    spec:
+      containers:
+      - name: manager
+        unknownField: kept
+        env:
+        - name: HTTP_PROXY
+          value: stale
+        - name: POD_NAMESPACE
+          value: ns`),
+				k8s.UnstructuredFromYaml(`
+apiVersion: apps/v1
+kind: DaemonSet
+metadata:
+  name: ds1
+spec:
+  template:
+    spec:
+      containers:
+      - name: handler`),
+			}
+		})
+
+		It("should not touch objects when there is no proxy configuration", func() {
+			Expect(injectProxy(objs, nil)).To(Succeed())
+			Expect(objs[0].Object).NotTo(HaveKey("status"))
+		})
+
+		It("should pass proxy variables and the trusted CA bundle to Deployments only", func() {
+			proxy := &cnao.Proxy{HTTPProxy: "http://proxy:3128", NoProxy: ".svc", TrustedCA: "user-ca-bundle"}
+			Expect(injectProxy(objs, proxy)).To(Succeed())
+
+			deployment := &appsv1.Deployment{}
+			Expect(runtime.DefaultUnstructuredConverter.FromUnstructured(objs[0].Object, deployment)).To(Succeed())
+			podSpec := deployment.Spec.Template.Spec
+			Expect(podSpec.Containers[0].Env).To(Equal([]v1.EnvVar{
+				{Name: "HTTP_PROXY", Value: "http://proxy:3128"},
+				{Name: "POD_NAMESPACE", Value: "ns"},
+				{Name: "NO_PROXY", Value: ".svc"},
+			}))
+			Expect(podSpec.Containers[0].VolumeMounts).To(ConsistOf(v1.VolumeMount{Name: trustedCABundleVolumeName, MountPath: trustedCABundleMountPath, ReadOnly: true}))
+			Expect(podSpec.Volumes).To(HaveLen(1))
+			Expect(podSpec.Volumes[0].ConfigMap.Name).To(Equal("user-ca-bundle"))
+
+			daemonSetContainers, _, err := unstructured.NestedSlice(objs[1].Object, "spec", "template", "spec", "containers")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(daemonSetContainers[0]).NotTo(HaveKey("env"))
+		})
+
+		It("should keep the rest of the Deployment as rendered", func() {
+			Expect(injectProxy(objs, &cnao.Proxy{HTTPProxy: "http://proxy:3128"})).To(Succeed())
+
+			Expect(objs[0].Object).NotTo(HaveKey("status"))
+			Expect(objs[0].Object["metadata"]).NotTo(HaveKey("creationTimestamp"))
+			containers, _, err := unstructured.NestedSlice(objs[0].Object, "spec", "template", "spec", "containers")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(containers[0]).To(HaveKeyWithValue("unknownField", "kept"))
+			Expect(containers[0]).NotTo(HaveKey("volumeMounts"))
+			_, found, err := unstructured.NestedSlice(objs[0].Object, "spec", "template", "spec", "volumes")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(BeFalse())
+		})
+	})
+
+	Describe("renderTrustedCABundle", func() {
+		trustedCAClusterInfo := &ClusterInfo{ProxyConfigAvailable: true, Proxy: &cnao.Proxy{TrustedCA: names.TRUSTED_CA_BUNDLE_CONFIGMAP}}
+
+		It("should not render anything when cluster-wide proxy configuration is not available", func() {
+			objs, err := renderTrustedCABundle("../../data", &ClusterInfo{}, []string{"ns"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(objs).To(BeEmpty())
+		})
+
+		It("should not render anything when the cluster-wide proxy has no trusted CA", func() {
+			clusterInfo := &ClusterInfo{ProxyConfigAvailable: true, Proxy: &cnao.Proxy{HTTPProxy: "http://proxy:3128"}}
+			objs, err := renderTrustedCABundle("../../data", clusterInfo, []string{"ns"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(objs).To(BeEmpty())
+		})
+
+		It("should render a ConfigMap requesting trusted CA bundle injection", func() {
+			objs, err := renderTrustedCABundle("../../data", trustedCAClusterInfo, []string{"ns"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(objs).To(HaveLen(1))
+			Expect(objs[0].GetName()).To(Equal(names.TRUSTED_CA_BUNDLE_CONFIGMAP))
+			Expect(objs[0].GetLabels()).To(HaveKeyWithValue(names.TRUSTED_CA_BUNDLE_INJECT_LABEL_KEY, "true"))
+		})
+
+		It("should render the ConfigMap into every given namespace", func() {
+			objs, err := renderTrustedCABundle("../../data", trustedCAClusterInfo, []string{"ns", "components"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(objs).To(HaveLen(2))
+			Expect(objs[1].GetNamespace()).To(Equal("components"))
+			Expect(objs[1].GetName()).To(Equal(names.TRUSTED_CA_BUNDLE_CONFIGMAP))
+		})
+	})
+})
@@ -1,9 +1,34 @@
 package network
 
 import (
+	"crypto/tls"
+
 	ocpv1 "github.com/openshift/api/config/v1"
 )
 
+// openSSLToCipherSuites maps OpenSSL cipher names used by TLS security profiles to cipher
+// suites supported by Go. TLS 1.3 cipher suites are not configurable in Go.
+var openSSLToCipherSuites = map[string]uint16{
+	"ECDHE-ECDSA-AES128-GCM-SHA256": tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,
+	"ECDHE-RSA-AES128-GCM-SHA256":   tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
+	"ECDHE-ECDSA-AES256-GCM-SHA384": tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384,
+	"ECDHE-RSA-AES256-GCM-SHA384":   tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384,
+	"ECDHE-ECDSA-CHACHA20-POLY1305": tls.TLS_ECDHE_ECDSA_WITH_CHACHA20_POLY1305_SHA256,
+	"ECDHE-RSA-CHACHA20-POLY1305":   tls.TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305_SHA256,
+	"ECDHE-ECDSA-AES128-SHA256":     tls.TLS_ECDHE_ECDSA_WITH_AES_128_CBC_SHA256,
+	"ECDHE-RSA-AES128-SHA256":       tls.TLS_ECDHE_RSA_WITH_AES_128_CBC_SHA256,
+	"ECDHE-ECDSA-AES128-SHA":        tls.TLS_ECDHE_ECDSA_WITH_AES_128_CBC_SHA,
+	"ECDHE-RSA-AES128-SHA":          tls.TLS_ECDHE_RSA_WITH_AES_128_CBC_SHA,
+	"ECDHE-ECDSA-AES256-SHA":        tls.TLS_ECDHE_ECDSA_WITH_AES_256_CBC_SHA,
+	"ECDHE-RSA-AES256-SHA":          tls.TLS_ECDHE_RSA_WITH_AES_256_CBC_SHA,
+	"AES128-GCM-SHA256":             tls.TLS_RSA_WITH_AES_128_GCM_SHA256,
+	"AES256-GCM-SHA384":             tls.TLS_RSA_WITH_AES_256_GCM_SHA384,
+	"AES128-SHA256":                 tls.TLS_RSA_WITH_AES_128_CBC_SHA256,
+	"AES128-SHA":                    tls.TLS_RSA_WITH_AES_128_CBC_SHA,
+	"AES256-SHA":                    tls.TLS_RSA_WITH_AES_256_CBC_SHA,
+	"DES-CBC3-SHA":                  tls.TLS_RSA_WITH_3DES_EDE_CBC_SHA,
+}
+
 func SelectCipherSuitesAndMinTLSVersion(profile *ocpv1.TLSSecurityProfile) ([]string, ocpv1.TLSProtocolVersion) {
 	if profile == nil {
 		profile = &ocpv1.TLSSecurityProfile{
@@ -31,3 +56,32 @@ func TLSVersionToHumanReadable(version ocpv1.TLSProtocolVersion) string {
 		return ""
 	}
 }
+
+// TLSVersionToID converts TLS version of a security profile to its crypto/tls identifier,
+// unknown versions are converted to 0, leaving the decision to crypto/tls defaults
+func TLSVersionToID(version ocpv1.TLSProtocolVersion) uint16 {
+	switch version {
+	case ocpv1.VersionTLS10:
+		return tls.VersionTLS10
+	case ocpv1.VersionTLS11:
+		return tls.VersionTLS11
+	case ocpv1.VersionTLS12:
+		return tls.VersionTLS12
+	case ocpv1.VersionTLS13:
+		return tls.VersionTLS13
+	default:
+		return 0
+	}
+}
+
+// CipherSuitesToIDs converts OpenSSL cipher names of a security profile to crypto/tls
+// identifiers. Ciphers not supported by Go are skipped.
+func CipherSuitesToIDs(ciphers []string) []uint16 {
+	ids := []uint16{}
+	for _, cipher := range ciphers {
+		if id, found := openSSLToCipherSuites[cipher]; found {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
@@ -1,6 +1,8 @@
 package network
 
 import (
+	"crypto/tls"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
@@ -64,3 +66,24 @@ var _ = Describe("Testing TLS Security Profile", func() {
 		}),
 	)
 })
+
+var _ = Describe("Testing TLS Security Profile conversion", func() {
+	It("should convert minimal TLS version to crypto/tls identifier", func() {
+		Expect(TLSVersionToID(ocpv1.VersionTLS12)).To(Equal(uint16(tls.VersionTLS12)))
+		Expect(TLSVersionToID("foobar")).To(BeZero())
+	})
+
+	It("should convert OpenSSL cipher names supported by Go", func() {
+		Expect(CipherSuitesToIDs([]string{
+			"TLS_AES_128_GCM_SHA256",
+			"ECDHE-RSA-AES128-GCM-SHA256",
+			"DHE-RSA-AES128-GCM-SHA256",
+			"AES128-SHA",
+		})).To(Equal([]uint16{tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256, tls.TLS_RSA_WITH_AES_128_CBC_SHA}))
+	})
+
+	It("should convert all TLS 1.2 ciphers of the intermediate profile", func() {
+		ciphers, _ := SelectCipherSuitesAndMinTLSVersion(nil)
+		Expect(CipherSuitesToIDs(ciphers)).To(HaveLen(6))
+	})
+})
@@ -0,0 +1,247 @@
+package webhook
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math"
+	"math/big"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// RotationThreshold is the portion of their lifetime after which the webhook CA and serving
+// certificate are rotated
+const RotationThreshold = 0.8
+
+const (
+	caDuration   = 365 * 24 * time.Hour
+	certDuration = 30 * 24 * time.Hour
+
+	caCertKey   = "ca.crt"
+	caKeyKey    = "ca.key"
+	caBundleKey = "ca-bundle.crt"
+	tlsCertKey  = "tls.crt"
+	tlsKeyKey   = "tls.key"
+)
+
+// certificates keeps the webhook CA, the serving certificate signed by it and the CA bundle
+// clients should trust. The bundle keeps CAs replaced by rotation until they expire, so
+// serving certificates issued by them stay trusted.
+type certificates struct {
+	caCert   *x509.Certificate
+	caKey    *rsa.PrivateKey
+	caBundle []*x509.Certificate
+	cert     *x509.Certificate
+	key      *rsa.PrivateKey
+}
+
+// ensureCertificates returns certificates valid at the given time. Certificates found in data
+// are kept unless they are missing, invalid, due to rotation or their rotation is forced. The
+// second value reports whether anything has changed.
+func ensureCertificates(data map[string][]byte, dnsNames []string, now time.Time, force bool) (*certificates, bool, error) {
+	current, err := certificatesFromData(data)
+	if err != nil {
+		current = &certificates{}
+	}
+
+	changed := false
+	if current.caCert == nil || needsRotation(current.caCert, now) || force {
+		caCert, caKey, err := newCA(now)
+		if err != nil {
+			return nil, false, errors.Wrap(err, "failed to generate webhook CA")
+		}
+		current.caBundle = append([]*x509.Certificate{caCert}, current.caBundle...)
+		current.caCert = caCert
+		current.caKey = caKey
+		current.cert = nil
+		changed = true
+	}
+
+	if current.cert == nil || needsRotation(current.cert, now) || current.cert.CheckSignatureFrom(current.caCert) != nil {
+		cert, key, err := newServingCert(current.caCert, current.caKey, dnsNames, now)
+		if err != nil {
+			return nil, false, errors.Wrap(err, "failed to generate webhook serving certificate")
+		}
+		current.cert = cert
+		current.key = key
+		changed = true
+	}
+
+	validBundle := []*x509.Certificate{}
+	for _, ca := range current.caBundle {
+		if now.Before(ca.NotAfter) {
+			validBundle = append(validBundle, ca)
+		}
+	}
+	if len(validBundle) != len(current.caBundle) {
+		changed = true
+	}
+	current.caBundle = validBundle
+
+	return current, changed, nil
+}
+
+func certificatesFromData(data map[string][]byte) (*certificates, error) {
+	caCerts, err := parseCertificates(data[caCertKey])
+	if err != nil || len(caCerts) != 1 {
+		return nil, errors.New("failed to parse webhook CA certificate")
+	}
+	caKey, err := parsePrivateKey(data[caKeyKey])
+	if err != nil {
+		return nil, err
+	}
+	caBundle, err := parseCertificates(data[caBundleKey])
+	if err != nil {
+		return nil, err
+	}
+
+	if len(caBundle) == 0 {
+		caBundle = caCerts
+	}
+
+	parsed := &certificates{caCert: caCerts[0], caKey: caKey, caBundle: caBundle}
+
+	// A broken serving certificate is reissued by the current CA
+	certs, err := parseCertificates(data[tlsCertKey])
+	if err != nil || len(certs) == 0 {
+		return parsed, nil
+	}
+	key, err := parsePrivateKey(data[tlsKeyKey])
+	if err != nil {
+		return parsed, nil
+	}
+	parsed.cert = certs[0]
+	parsed.key = key
+	return parsed, nil
+}
+
+func (c *certificates) data() map[string][]byte {
+	return map[string][]byte{
+		caCertKey:   encodeCertificates(c.caCert),
+		caKeyKey:    encodePrivateKey(c.caKey),
+		caBundleKey: encodeCertificates(c.caBundle...),
+		tlsCertKey:  encodeCertificates(c.cert),
+		tlsKeyKey:   encodePrivateKey(c.key),
+	}
+}
+
+func (c *certificates) tlsCertificate() (*tls.Certificate, error) {
+	cert, err := tls.X509KeyPair(encodeCertificates(c.cert), encodePrivateKey(c.key))
+	if err != nil {
+		return nil, err
+	}
+	return &cert, nil
+}
+
+// signedByAny checks whether the certificate is signed by any of the given CAs
+func signedByAny(cert *x509.Certificate, cas []*x509.Certificate) bool {
+	for _, ca := range cas {
+		if cert.CheckSignatureFrom(ca) == nil {
+			return true
+		}
+	}
+	return false
+}
+
+func needsRotation(cert *x509.Certificate, now time.Time) bool {
+	lifetime := cert.NotAfter.Sub(cert.NotBefore)
+	rotationTime := cert.NotBefore.Add(time.Duration(float64(lifetime) * RotationThreshold))
+	return !now.Before(rotationTime)
+}
+
+func newCA(now time.Time) (*x509.Certificate, *rsa.PrivateKey, error) {
+	template := &x509.Certificate{
+		Subject:               pkix.Name{CommonName: "cluster-network-addons-operator-webhook-ca"},
+		NotBefore:             now,
+		NotAfter:              now.Add(caDuration),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	return newCertificate(template, nil, nil)
+}
+
+func newServingCert(caCert *x509.Certificate, caKey *rsa.PrivateKey, dnsNames []string, now time.Time) (*x509.Certificate, *rsa.PrivateKey, error) {
+	notAfter := now.Add(certDuration)
+	if notAfter.After(caCert.NotAfter) {
+		notAfter = caCert.NotAfter
+	}
+	template := &x509.Certificate{
+		Subject:     pkix.Name{CommonName: dnsNames[0]},
+		DNSNames:    dnsNames,
+		NotBefore:   now,
+		NotAfter:    notAfter,
+		KeyUsage:    x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	return newCertificate(template, caCert, caKey)
+}
+
+// newCertificate signs the template by the given parent, or self-signs it when no parent is given
+func newCertificate(template, parent *x509.Certificate, parentKey *rsa.PrivateKey) (*x509.Certificate, *rsa.PrivateKey, error) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return nil, nil, err
+	}
+	serial, err := rand.Int(rand.Reader, big.NewInt(math.MaxInt64))
+	if err != nil {
+		return nil, nil, err
+	}
+	template.SerialNumber = serial
+
+	if parent == nil {
+		parent = template
+		parentKey = key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, nil, err
+	}
+	return cert, key, nil
+}
+
+func encodeCertificates(certs ...*x509.Certificate) []byte {
+	encoded := bytes.Buffer{}
+	for _, cert := range certs {
+		_ = pem.Encode(&encoded, &pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw})
+	}
+	return encoded.Bytes()
+}
+
+func encodePrivateKey(key *rsa.PrivateKey) []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+}
+
+func parseCertificates(data []byte) ([]*x509.Certificate, error) {
+	certs := []*x509.Certificate{}
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			return certs, nil
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		certs = append(certs, cert)
+	}
+}
+
+func parsePrivateKey(data []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("failed to decode private key")
+	}
+	return x509.ParsePKCS1PrivateKey(block.Bytes)
+}
@@ -0,0 +1,115 @@
+package webhook
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Webhook certificates", func() {
+	dnsNames := []string{"webhook.ns.svc"}
+	now := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	issue := func(data map[string][]byte, now time.Time) (*certificates, bool) {
+		certs, changed, err := ensureCertificates(data, dnsNames, now, false)
+		Expect(err).NotTo(HaveOccurred())
+		return certs, changed
+	}
+
+	verify := func(certs *certificates, now time.Time) error {
+		roots := x509.NewCertPool()
+		for _, ca := range certs.caBundle {
+			roots.AddCert(ca)
+		}
+		_, err := certs.cert.Verify(x509.VerifyOptions{DNSName: dnsNames[0], Roots: roots, CurrentTime: now})
+		return err
+	}
+
+	It("should issue a serving certificate trusted by the CA bundle", func() {
+		certs, changed := issue(nil, now)
+		Expect(changed).To(BeTrue())
+		Expect(verify(certs, now)).To(Succeed())
+
+		_, err := certs.tlsCertificate()
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should keep stored certificates until they are due to rotation", func() {
+		certs, _ := issue(nil, now)
+		kept, changed := issue(certs.data(), now.Add(certDuration/2))
+		Expect(changed).To(BeFalse())
+		Expect(kept.data()).To(Equal(certs.data()))
+	})
+
+	It("should rotate the serving certificate keeping the CA", func() {
+		certs, _ := issue(nil, now)
+		later := now.Add(certDuration * 9 / 10)
+		rotated, changed := issue(certs.data(), later)
+		Expect(changed).To(BeTrue())
+		Expect(rotated.caCert.Equal(certs.caCert)).To(BeTrue())
+		Expect(rotated.cert.Equal(certs.cert)).To(BeFalse())
+		Expect(verify(rotated, later)).To(Succeed())
+	})
+
+	It("should keep the replaced CA in the bundle until it expires", func() {
+		initial, _ := issue(nil, now)
+		caRotation := now.Add(caDuration * 8 / 10)
+		certs, _ := issue(initial.data(), caRotation.Add(-time.Hour))
+		Expect(certs.caCert.Equal(initial.caCert)).To(BeTrue())
+
+		later := caRotation.Add(time.Hour)
+		rotated, changed := issue(certs.data(), later)
+		Expect(changed).To(BeTrue())
+		Expect(rotated.caCert.Equal(certs.caCert)).To(BeFalse())
+		Expect(rotated.caBundle).To(HaveLen(2))
+		Expect(verify(rotated, later)).To(Succeed())
+		certs.caBundle = rotated.caBundle
+		Expect(verify(certs, later)).To(Succeed(), "certificate issued by the old CA should stay trusted")
+
+		afterExpiration := now.Add(caDuration + time.Hour)
+		cleaned, changed := issue(rotated.data(), afterExpiration)
+		Expect(changed).To(BeTrue())
+		Expect(cleaned.caBundle).To(HaveLen(1))
+		Expect(cleaned.caBundle[0].Equal(rotated.caCert)).To(BeTrue())
+	})
+
+	It("should rotate the CA and the serving certificate when forced", func() {
+		certs, _ := issue(nil, now)
+		later := now.Add(time.Hour)
+		rotated, changed, err := ensureCertificates(certs.data(), dnsNames, later, true)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(changed).To(BeTrue())
+		Expect(rotated.caCert.Equal(certs.caCert)).To(BeFalse())
+		Expect(rotated.cert.Equal(certs.cert)).To(BeFalse())
+		Expect(rotated.caBundle).To(HaveLen(2))
+		Expect(verify(rotated, later)).To(Succeed())
+		certs.caBundle = rotated.caBundle
+		Expect(verify(certs, later)).To(Succeed(), "certificate issued by the old CA should stay trusted")
+	})
+
+	It("should replace broken certificates", func() {
+		certs, _ := issue(nil, now)
+		data := certs.data()
+		data[tlsCertKey] = []byte("broken")
+		reissued, changed := issue(data, now)
+		Expect(changed).To(BeTrue())
+		Expect(reissued.caCert.Equal(certs.caCert)).To(BeTrue())
+		Expect(verify(reissued, now)).To(Succeed())
+	})
+
+	Context("TLS configuration", func() {
+		It("should follow the intermediate profile by default", func() {
+			certs, _ := issue(nil, now)
+			certificate, err := certs.tlsCertificate()
+			Expect(err).NotTo(HaveOccurred())
+
+			config := tlsConfig(nil, certificate)
+			Expect(config.MinVersion).To(Equal(uint16(tls.VersionTLS12)))
+			Expect(config.CipherSuites).To(ContainElement(tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256))
+			Expect(config.Certificates).To(HaveLen(1))
+		})
+	})
+})
@@ -0,0 +1,451 @@
+package webhook
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	ocpv1 "github.com/openshift/api/config/v1"
+	"github.com/pkg/errors"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/wait"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	"github.com/kubevirt/cluster-network-addons-operator/pkg/apply"
+	"github.com/kubevirt/cluster-network-addons-operator/pkg/components"
+	"github.com/kubevirt/cluster-network-addons-operator/pkg/names"
+	"github.com/kubevirt/cluster-network-addons-operator/pkg/network"
+	"github.com/kubevirt/cluster-network-addons-operator/pkg/util/k8s"
+)
+
+const (
+	// syncPeriod is the interval in which certificates and webhook configuration are reconciled
+	syncPeriod = time.Minute
+	// certificateLoadPeriod is the interval in which replicas load certificates issued by the leader
+	certificateLoadPeriod = 10 * time.Second
+	// cleanUpTimeout bounds the removal of the webhook configuration once the operator is removed
+	cleanUpTimeout = 30 * time.Second
+)
+
+// Server serves admission and conversion webhooks of the operator on every replica. Its CA and
+// serving certificate are issued and rotated by the Issuer running in the elected leader only, the
+// CA bundle is propagated to the webhook configuration and to converted CustomResourceDefinitions.
+// Other replicas serve certificates stored by the leader once their CA is trusted.
+// TLS settings follow the security profile returned by tlsSecurityProfile on every handshake.
+type Server struct {
+	client             k8sclient.Client
+	reader             k8sclient.Reader
+	namespace          string
+	mux                *http.ServeMux
+	validatingWebhooks []admissionregistrationv1.ValidatingWebhook
+	conversionWebhooks []conversionWebhook
+	tlsSecurityProfile func(context.Context) (*ocpv1.TLSSecurityProfile, error)
+
+	lock        sync.RWMutex
+	certificate *tls.Certificate
+}
+
+// NewServer creates a webhook Server for operator running in the given namespace, it has to be
+// added to the manager in order to be started
+func NewServer(mgr manager.Manager, namespace string, tlsSecurityProfile func(context.Context) (*ocpv1.TLSSecurityProfile, error)) *Server {
+	return &Server{
+		client:             mgr.GetClient(),
+		reader:             mgr.GetAPIReader(),
+		namespace:          namespace,
+		mux:                http.NewServeMux(),
+		tlsSecurityProfile: tlsSecurityProfile,
+	}
+}
+
+// RegisterValidatingWebhook serves handler at the given path and registers it in the validating
+// webhook configuration. The client configuration of webhook is filled by the server.
+func (s *Server) RegisterValidatingWebhook(path string, webhook admissionregistrationv1.ValidatingWebhook, handler http.Handler) {
+	port := int32(names.WEBHOOK_PORT)
+	webhook.ClientConfig = admissionregistrationv1.WebhookClientConfig{
+		Service: &admissionregistrationv1.ServiceReference{
+			Namespace: s.namespace,
+			Name:      names.WEBHOOK_SERVICE,
+			Path:      &path,
+			Port:      &port,
+		},
+	}
+	s.validatingWebhooks = append(s.validatingWebhooks, webhook)
+	s.mux.Handle(path, handler)
+}
+
+// conversionWebhook converts versions of a CustomResourceDefinition
+type conversionWebhook struct {
+	crdName string
+	path    string
+}
+
+// RegisterConversionWebhook serves handler at the given path and configures the given
+// CustomResourceDefinition to convert its versions through it
+func (s *Server) RegisterConversionWebhook(path, crdName string, handler http.Handler) {
+	s.conversionWebhooks = append(s.conversionWebhooks, conversionWebhook{crdName: crdName, path: path})
+	s.mux.Handle(path, handler)
+}
+
+// NeedLeaderElection implements the LeaderElectionRunnable interface, webhooks are served by
+// every replica of the operator
+func (s *Server) NeedLeaderElection() bool {
+	return false
+}
+
+// Start serves webhooks with certificates issued by the leader until the context is done
+func (s *Server) Start(ctx context.Context) error {
+	go wait.UntilWithContext(ctx, func(ctx context.Context) {
+		if err := s.loadCertificate(ctx); err != nil {
+			log.Printf("failed to load admission webhook certificates: %v", err)
+		}
+	}, certificateLoadPeriod)
+
+	listener, err := tls.Listen("tcp", fmt.Sprintf(":%d", names.WEBHOOK_PORT), &tls.Config{
+		GetConfigForClient: s.tlsConfigForClient,
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to listen for admission webhook requests")
+	}
+
+	server := &http.Server{Handler: s.mux}
+	go func() {
+		<-ctx.Done()
+		if err := server.Shutdown(context.Background()); err != nil {
+			log.Printf("failed to shut down admission webhook server: %v", err)
+		}
+	}()
+
+	log.Printf("serving admission webhooks on port %d", names.WEBHOOK_PORT)
+	if err := server.Serve(listener); err != nil && err != http.ErrServerClosed {
+		return err
+	}
+	return nil
+}
+
+// Issuer returns the runnable issuing certificates and maintaining the webhook Service and
+// configuration, it has to be added to the manager next to the server
+func (s *Server) Issuer() manager.Runnable {
+	return &issuer{server: s}
+}
+
+// issuer runs in the elected leader only, so replicas do not race on certificates
+type issuer struct {
+	server *Server
+}
+
+// NeedLeaderElection implements the LeaderElectionRunnable interface
+func (i *issuer) NeedLeaderElection() bool {
+	return true
+}
+
+// Start keeps certificates and the webhook configuration up to date until the context is done,
+// the webhook configuration is removed afterwards if the operator is being removed
+func (i *issuer) Start(ctx context.Context) error {
+	wait.UntilWithContext(ctx, func(ctx context.Context) {
+		if err := i.server.sync(ctx); err != nil {
+			log.Printf("failed to sync admission webhooks: %v", err)
+		}
+	}, syncPeriod)
+
+	cleanUpCtx, cancel := context.WithTimeout(context.Background(), cleanUpTimeout)
+	defer cancel()
+	return i.server.cleanUp(cleanUpCtx)
+}
+
+// loadCertificate serves the certificate stored in the Secret, once its CA is trusted by the
+// webhook configuration
+func (s *Server) loadCertificate(ctx context.Context) error {
+	secret := &corev1.Secret{}
+	if err := s.reader.Get(ctx, types.NamespacedName{Namespace: s.namespace, Name: names.WEBHOOK_CERT_SECRET}, secret); err != nil {
+		return errors.Wrap(err, "failed to read webhook certificates")
+	}
+	certs, err := certificatesFromData(secret.Data)
+	if err != nil || certs.cert == nil {
+		return errors.New("webhook certificates are not issued yet")
+	}
+
+	if len(s.validatingWebhooks) > 0 {
+		configuration := &admissionregistrationv1.ValidatingWebhookConfiguration{}
+		if err := s.reader.Get(ctx, types.NamespacedName{Name: names.VALIDATING_WEBHOOK_CONFIGURATION}, configuration); err != nil {
+			return errors.Wrap(err, "failed to read validating webhook configuration")
+		}
+		trusted, err := parseCertificates(configuration.Webhooks[0].ClientConfig.CABundle)
+		if err != nil {
+			return errors.Wrap(err, "failed to parse CA bundle of validating webhook configuration")
+		}
+		if !signedByAny(certs.cert, trusted) {
+			return errors.New("CA of webhook serving certificate is not trusted yet")
+		}
+	}
+
+	return s.setCertificate(certs)
+}
+
+func (s *Server) setCertificate(certs *certificates) error {
+	certificate, err := certs.tlsCertificate()
+	if err != nil {
+		return errors.Wrap(err, "failed to load webhook serving certificate")
+	}
+	s.lock.Lock()
+	s.certificate = certificate
+	s.lock.Unlock()
+	return nil
+}
+
+func (s *Server) tlsConfigForClient(hello *tls.ClientHelloInfo) (*tls.Config, error) {
+	s.lock.RLock()
+	certificate := s.certificate
+	s.lock.RUnlock()
+	if certificate == nil {
+		return nil, errors.New("admission webhook serving certificate is not available yet")
+	}
+
+	profile, err := s.tlsSecurityProfile(hello.Context())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to obtain TLS security profile")
+	}
+	return tlsConfig(profile, certificate), nil
+}
+
+func tlsConfig(profile *ocpv1.TLSSecurityProfile, certificate *tls.Certificate) *tls.Config {
+	ciphers, minTLSVersion := network.SelectCipherSuitesAndMinTLSVersion(profile)
+	config := &tls.Config{
+		Certificates: []tls.Certificate{*certificate},
+		MinVersion:   network.TLSVersionToID(minTLSVersion),
+	}
+	if cipherSuites := network.CipherSuitesToIDs(ciphers); len(cipherSuites) > 0 {
+		config.CipherSuites = cipherSuites
+	}
+	return config
+}
+
+// sync rotates certificates if needed and propagates the CA bundle to the webhook configuration
+func (s *Server) sync(ctx context.Context) error {
+	deploymentOwners, clusterRoleOwners, err := s.owners(ctx)
+	if err != nil {
+		return err
+	}
+
+	certs, err := s.ensureCertificatesSecret(ctx, time.Now(), deploymentOwners)
+	if err != nil {
+		return err
+	}
+
+	service := webhookService(s.namespace)
+	service.OwnerReferences = deploymentOwners
+	unstructuredService, err := k8s.ToUnstructured(service)
+	if err != nil {
+		return err
+	}
+	if err := apply.ApplyObject(ctx, s.client, unstructuredService); err != nil {
+		return errors.Wrap(err, "failed to apply webhook service")
+	}
+
+	configuration := validatingWebhookConfiguration(s.validatingWebhooks, encodeCertificates(certs.caBundle...))
+	configuration.OwnerReferences = clusterRoleOwners
+	unstructuredConfiguration, err := k8s.ToUnstructured(configuration)
+	if err != nil {
+		return err
+	}
+	if err := apply.ApplyObject(ctx, s.client, unstructuredConfiguration); err != nil {
+		return errors.Wrap(err, "failed to apply validating webhook configuration")
+	}
+
+	for _, webhook := range s.conversionWebhooks {
+		if err := s.ensureConversion(ctx, webhook, encodeCertificates(certs.caBundle...)); err != nil {
+			return err
+		}
+	}
+
+	// The certificate is served only after its CA is trusted by the webhook configuration
+	return s.setCertificate(certs)
+}
+
+// owners returns owners of objects maintained by the server, so they are garbage collected once the
+// operator is removed. Namespaced objects are owned by the operator Deployment, cluster-scoped ones
+// by the operator ClusterRole. No owners are returned when the operator runs outside of its Deployment.
+func (s *Server) owners(ctx context.Context) ([]metav1.OwnerReference, []metav1.OwnerReference, error) {
+	deployment := &appsv1.Deployment{}
+	if err := s.reader.Get(ctx, types.NamespacedName{Namespace: s.namespace, Name: components.Name}, deployment); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil, nil
+		}
+		return nil, nil, errors.Wrap(err, "failed to read operator Deployment")
+	}
+	clusterRole := &rbacv1.ClusterRole{}
+	if err := s.reader.Get(ctx, types.NamespacedName{Name: components.Name}, clusterRole); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil, nil
+		}
+		return nil, nil, errors.Wrap(err, "failed to read operator ClusterRole")
+	}
+
+	return []metav1.OwnerReference{ownerReference(appsv1.SchemeGroupVersion.String(), "Deployment", deployment)},
+		[]metav1.OwnerReference{ownerReference(rbacv1.SchemeGroupVersion.String(), "ClusterRole", clusterRole)},
+		nil
+}
+
+func ownerReference(apiVersion, kind string, owner metav1.Object) metav1.OwnerReference {
+	return metav1.OwnerReference{
+		APIVersion: apiVersion,
+		Kind:       kind,
+		Name:       owner.GetName(),
+		UID:        owner.GetUID(),
+	}
+}
+
+// cleanUp removes the webhook configuration once the operator Deployment is removed, so the API server
+// does not call webhooks which are not served anymore. It is kept when the operator is only restarted.
+// The Service and the Secret are garbage collected with the Deployment owning them.
+func (s *Server) cleanUp(ctx context.Context) error {
+	deployment := &appsv1.Deployment{}
+	err := s.reader.Get(ctx, types.NamespacedName{Namespace: s.namespace, Name: components.Name}, deployment)
+	if err == nil && deployment.GetDeletionTimestamp() == nil {
+		return nil
+	}
+	if err != nil && !apierrors.IsNotFound(err) {
+		return errors.Wrap(err, "failed to read operator Deployment")
+	}
+
+	log.Print("operator is being removed, removing its validating webhook configuration")
+	configuration := &admissionregistrationv1.ValidatingWebhookConfiguration{ObjectMeta: metav1.ObjectMeta{Name: names.VALIDATING_WEBHOOK_CONFIGURATION}}
+	if err := s.client.Delete(ctx, configuration); err != nil && !apierrors.IsNotFound(err) {
+		return errors.Wrap(err, "failed to remove validating webhook configuration")
+	}
+	return nil
+}
+
+// ensureCertificatesSecret reads certificates from their Secret, rotates them when needed and
+// stores the result back. The API reader is used so Secrets do not need to be cached.
+func (s *Server) ensureCertificatesSecret(ctx context.Context, now time.Time, owners []metav1.OwnerReference) (*certificates, error) {
+	secret := &corev1.Secret{}
+	err := s.reader.Get(ctx, types.NamespacedName{Namespace: s.namespace, Name: names.WEBHOOK_CERT_SECRET}, secret)
+	found := true
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			return nil, errors.Wrap(err, "failed to read webhook certificates")
+		}
+		found = false
+		secret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: s.namespace,
+				Name:      names.WEBHOOK_CERT_SECRET,
+			},
+		}
+	}
+
+	// Rotation is requested by the reconciler through an annotation of the Secret
+	_, forced := secret.GetAnnotations()[names.ROTATE_CERTIFICATES_ANNOTATION]
+	certs, changed, err := ensureCertificates(secret.Data, serviceDNSNames(s.namespace), now, forced)
+	if err != nil {
+		return nil, err
+	}
+	ownersChanged := len(owners) > 0 && !equality.Semantic.DeepEqual(secret.OwnerReferences, owners)
+	if !changed && !ownersChanged {
+		return certs, nil
+	}
+
+	if changed {
+		log.Printf("issuing webhook certificates valid until %s", certs.cert.NotAfter)
+	}
+	secret.Data = certs.data()
+	if len(owners) > 0 {
+		secret.OwnerReferences = owners
+	}
+	delete(secret.Annotations, names.ROTATE_CERTIFICATES_ANNOTATION)
+	if found {
+		err = s.client.Update(ctx, secret)
+	} else {
+		err = s.client.Create(ctx, secret)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to store webhook certificates")
+	}
+	return certs, nil
+}
+
+// ensureConversion points conversion of the CustomResourceDefinition to the webhook. The CRD is
+// installed with the operator, so only its conversion is patched.
+func (s *Server) ensureConversion(ctx context.Context, webhook conversionWebhook, caBundle []byte) error {
+	crd := &extv1.CustomResourceDefinition{}
+	if err := s.reader.Get(ctx, types.NamespacedName{Name: webhook.crdName}, crd); err != nil {
+		return errors.Wrapf(err, "failed to read CustomResourceDefinition %s", webhook.crdName)
+	}
+
+	conversion := components.GetCrdConversion(s.namespace, webhook.path, caBundle)
+	if equality.Semantic.DeepEqual(crd.Spec.Conversion, conversion) {
+		return nil
+	}
+
+	patch := k8sclient.MergeFrom(crd.DeepCopy())
+	crd.Spec.Conversion = conversion
+	if err := s.client.Patch(ctx, crd, patch); err != nil {
+		return errors.Wrapf(err, "failed to configure conversion webhook of CustomResourceDefinition %s", webhook.crdName)
+	}
+	return nil
+}
+
+func serviceDNSNames(namespace string) []string {
+	return []string{
+		fmt.Sprintf("%s.%s.svc", names.WEBHOOK_SERVICE, namespace),
+		fmt.Sprintf("%s.%s.svc.cluster.local", names.WEBHOOK_SERVICE, namespace),
+	}
+}
+
+func webhookService(namespace string) *corev1.Service {
+	return &corev1.Service{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "v1",
+			Kind:       "Service",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      names.WEBHOOK_SERVICE,
+			Namespace: namespace,
+		},
+		Spec: corev1.ServiceSpec{
+			Selector: map[string]string{
+				"name": components.Name,
+			},
+			Ports: []corev1.ServicePort{
+				{
+					Name:       "webhook",
+					Protocol:   corev1.ProtocolTCP,
+					Port:       names.WEBHOOK_PORT,
+					TargetPort: intstr.FromString("webhook"),
+				},
+			},
+		},
+	}
+}
+
+func validatingWebhookConfiguration(webhooks []admissionregistrationv1.ValidatingWebhook, caBundle []byte) *admissionregistrationv1.ValidatingWebhookConfiguration {
+	configuredWebhooks := []admissionregistrationv1.ValidatingWebhook{}
+	for _, webhook := range webhooks {
+		webhook.ClientConfig.CABundle = caBundle
+		configuredWebhooks = append(configuredWebhooks, webhook)
+	}
+	return &admissionregistrationv1.ValidatingWebhookConfiguration{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "admissionregistration.k8s.io/v1",
+			Kind:       "ValidatingWebhookConfiguration",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: names.VALIDATING_WEBHOOK_CONFIGURATION,
+		},
+		Webhooks: configuredWebhooks,
+	}
+}
@@ -0,0 +1,178 @@
+package webhook
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	"github.com/kubevirt/cluster-network-addons-operator/pkg/components"
+	"github.com/kubevirt/cluster-network-addons-operator/pkg/names"
+)
+
+var _ = Describe("Webhook server", func() {
+	It("should store issued certificates and reuse them", func() {
+		client := fake.NewClientBuilder().Build()
+		server := &Server{client: client, reader: client, namespace: "ns"}
+
+		certs, err := server.ensureCertificatesSecret(context.TODO(), time.Now(), nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(certs.cert.DNSNames).To(ContainElement(names.WEBHOOK_SERVICE + ".ns.svc"))
+
+		secret := &corev1.Secret{}
+		Expect(client.Get(context.TODO(), types.NamespacedName{Namespace: "ns", Name: names.WEBHOOK_CERT_SECRET}, secret)).To(Succeed())
+		Expect(secret.Data).To(Equal(certs.data()))
+
+		stored, err := server.ensureCertificatesSecret(context.TODO(), time.Now(), nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(stored.cert.Equal(certs.cert)).To(BeTrue())
+	})
+
+	It("should rotate certificates when requested through the Secret annotation", func() {
+		client := fake.NewClientBuilder().Build()
+		server := &Server{client: client, reader: client, namespace: "ns"}
+		certs, err := server.ensureCertificatesSecret(context.TODO(), time.Now(), nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		secret := &corev1.Secret{}
+		key := types.NamespacedName{Namespace: "ns", Name: names.WEBHOOK_CERT_SECRET}
+		Expect(client.Get(context.TODO(), key, secret)).To(Succeed())
+		secret.Annotations = map[string]string{names.ROTATE_CERTIFICATES_ANNOTATION: ""}
+		Expect(client.Update(context.TODO(), secret)).To(Succeed())
+
+		rotated, err := server.ensureCertificatesSecret(context.TODO(), time.Now(), nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(rotated.caCert.Equal(certs.caCert)).To(BeFalse())
+		Expect(rotated.caBundle).To(HaveLen(2))
+
+		Expect(client.Get(context.TODO(), key, secret)).To(Succeed())
+		Expect(secret.Annotations).NotTo(HaveKey(names.ROTATE_CERTIFICATES_ANNOTATION))
+	})
+
+	It("should propagate the CA bundle to registered webhooks", func() {
+		server := &Server{namespace: "ns", mux: http.NewServeMux()}
+		server.RegisterValidatingWebhook("/validate", admissionregistrationv1.ValidatingWebhook{Name: "validator.example.com"}, http.NotFoundHandler())
+
+		configuration := validatingWebhookConfiguration(server.validatingWebhooks, []byte("bundle"))
+		Expect(configuration.Name).To(Equal(names.VALIDATING_WEBHOOK_CONFIGURATION))
+		Expect(configuration.Webhooks).To(HaveLen(1))
+		clientConfig := configuration.Webhooks[0].ClientConfig
+		Expect(clientConfig.CABundle).To(Equal([]byte("bundle")))
+		Expect(clientConfig.Service.Name).To(Equal(names.WEBHOOK_SERVICE))
+		Expect(clientConfig.Service.Namespace).To(Equal("ns"))
+		Expect(*clientConfig.Service.Path).To(Equal("/validate"))
+	})
+
+	It("should point conversion of registered CRDs to the webhook", func() {
+		scheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		Expect(extv1.AddToScheme(scheme)).To(Succeed())
+		crd := &extv1.CustomResourceDefinition{
+			ObjectMeta: metav1.ObjectMeta{Name: "things.example.com"},
+			Spec: extv1.CustomResourceDefinitionSpec{
+				Conversion: &extv1.CustomResourceConversion{Strategy: extv1.NoneConverter},
+			},
+		}
+		client := fake.NewClientBuilder().WithScheme(scheme).WithObjects(crd).Build()
+		server := &Server{client: client, reader: client, namespace: "ns", mux: http.NewServeMux()}
+		server.RegisterConversionWebhook("/convert", crd.Name, http.NotFoundHandler())
+
+		Expect(server.ensureConversion(context.TODO(), server.conversionWebhooks[0], []byte("bundle"))).To(Succeed())
+
+		Expect(client.Get(context.TODO(), types.NamespacedName{Name: crd.Name}, crd)).To(Succeed())
+		Expect(crd.Spec.Conversion.Strategy).To(Equal(extv1.WebhookConverter))
+		clientConfig := crd.Spec.Conversion.Webhook.ClientConfig
+		Expect(clientConfig.CABundle).To(Equal([]byte("bundle")))
+		Expect(clientConfig.Service.Name).To(Equal(names.WEBHOOK_SERVICE))
+		Expect(clientConfig.Service.Namespace).To(Equal("ns"))
+		Expect(*clientConfig.Service.Path).To(Equal("/convert"))
+		Expect(crd.Spec.Conversion.Webhook.ConversionReviewVersions).To(Equal([]string{"v1"}))
+
+		By("keeping the CRD untouched when the conversion is up to date")
+		resourceVersion := crd.ResourceVersion
+		Expect(server.ensureConversion(context.TODO(), server.conversionWebhooks[0], []byte("bundle"))).To(Succeed())
+		Expect(client.Get(context.TODO(), types.NamespacedName{Name: crd.Name}, crd)).To(Succeed())
+		Expect(crd.ResourceVersion).To(Equal(resourceVersion))
+	})
+
+	Context("with the operator deployed", func() {
+		const namespace = "ns"
+		var client k8sclient.Client
+		var server *Server
+
+		BeforeEach(func() {
+			scheme := runtime.NewScheme()
+			Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+			Expect(extv1.AddToScheme(scheme)).To(Succeed())
+			client = fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+				&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: components.Name, UID: "deployment-uid"}},
+				&rbacv1.ClusterRole{ObjectMeta: metav1.ObjectMeta{Name: components.Name, UID: "cluster-role-uid"}},
+			).Build()
+			server = &Server{client: client, reader: client, namespace: namespace, mux: http.NewServeMux()}
+			server.RegisterValidatingWebhook("/validate", admissionregistrationv1.ValidatingWebhook{Name: "validator.example.com"}, http.NotFoundHandler())
+		})
+
+		It("should elect a single replica to issue certificates", func() {
+			Expect(server.NeedLeaderElection()).To(BeFalse())
+			Expect(server.Issuer().(manager.LeaderElectionRunnable).NeedLeaderElection()).To(BeTrue())
+		})
+
+		It("should let the operator own objects it maintains", func() {
+			Expect(server.sync(context.TODO())).To(Succeed())
+
+			secret := &corev1.Secret{}
+			Expect(client.Get(context.TODO(), types.NamespacedName{Namespace: namespace, Name: names.WEBHOOK_CERT_SECRET}, secret)).To(Succeed())
+			Expect(secret.OwnerReferences).To(ConsistOf(HaveField("UID", types.UID("deployment-uid"))))
+			service := &corev1.Service{}
+			Expect(client.Get(context.TODO(), types.NamespacedName{Namespace: namespace, Name: names.WEBHOOK_SERVICE}, service)).To(Succeed())
+			Expect(service.OwnerReferences).To(ConsistOf(HaveField("UID", types.UID("deployment-uid"))))
+			configuration := &admissionregistrationv1.ValidatingWebhookConfiguration{}
+			Expect(client.Get(context.TODO(), types.NamespacedName{Name: names.VALIDATING_WEBHOOK_CONFIGURATION}, configuration)).To(Succeed())
+			Expect(configuration.OwnerReferences).To(ConsistOf(HaveField("UID", types.UID("cluster-role-uid"))))
+		})
+
+		It("should serve certificates issued by the leader once their CA is trusted", func() {
+			follower := &Server{client: client, reader: client, namespace: namespace, validatingWebhooks: server.validatingWebhooks}
+			Expect(follower.loadCertificate(context.TODO())).NotTo(Succeed())
+
+			_, err := server.ensureCertificatesSecret(context.TODO(), time.Now(), nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(follower.loadCertificate(context.TODO())).NotTo(Succeed(), "the webhook configuration does not exist yet")
+
+			Expect(server.sync(context.TODO())).To(Succeed())
+			Expect(follower.loadCertificate(context.TODO())).To(Succeed())
+			Expect(follower.certificate).NotTo(BeNil())
+		})
+
+		It("should keep the webhook configuration when the operator is restarted", func() {
+			Expect(server.sync(context.TODO())).To(Succeed())
+			Expect(server.cleanUp(context.TODO())).To(Succeed())
+			Expect(client.Get(context.TODO(), types.NamespacedName{Name: names.VALIDATING_WEBHOOK_CONFIGURATION}, &admissionregistrationv1.ValidatingWebhookConfiguration{})).To(Succeed())
+		})
+
+		It("should remove the webhook configuration when the operator is removed", func() {
+			Expect(server.sync(context.TODO())).To(Succeed())
+			Expect(client.Delete(context.TODO(), &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: components.Name}})).To(Succeed())
+
+			Expect(server.cleanUp(context.TODO())).To(Succeed())
+			err := client.Get(context.TODO(), types.NamespacedName{Name: names.VALIDATING_WEBHOOK_CONFIGURATION}, &admissionregistrationv1.ValidatingWebhookConfiguration{})
+			Expect(apierrors.IsNotFound(err)).To(BeTrue())
+		})
+	})
+})
@@ -494,6 +494,22 @@ func CheckOperatorPodStability(continuesDuration time.Duration) {
 	}
 }
 
+// CheckOperatorHasNoForbiddenErrors makes sure that the operator has not been denied any API request,
+// which would mean that its ClusterRole misses a rule
+func CheckOperatorHasNoForbiddenErrors() {
+	By("Checking that cnao operator has not been denied any API request")
+	logs, stderr, err := Kubectl("-n", components.Namespace, "logs", "deployment/"+components.Name, "--all-containers")
+	Expect(err).NotTo(HaveOccurred(), "should succeed reading the cnao operator logs: %s", stderr)
+
+	forbidden := []string{}
+	for _, line := range strings.Split(logs, "\n") {
+		if strings.Contains(line, "is forbidden") {
+			forbidden = append(forbidden, line)
+		}
+	}
+	Expect(forbidden).To(BeEmpty(), "cnao operator RBAC is missing rules")
+}
+
 func PrintOperatorPodStability() {
 	if err := CalculateOperatorPodStability(); err != nil {
 		fmt.Fprintln(GinkgoWriter, "WARNING: CNAO operator pod is not stable: "+err.Error())
@@ -40,6 +40,7 @@ var _ = BeforeSuite(func() {
 
 var _ = AfterSuite(func() {
 	CheckOperatorPodStability(time.Minute)
+	CheckOperatorHasNoForbiddenErrors()
 })
 
 var _ = AfterEach(func() {
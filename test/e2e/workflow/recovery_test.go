@@ -17,10 +17,11 @@ var _ = Describe("NetworkAddonsConfig", func() {
 		BeforeEach(func() {
 			configSpec := cnao.NetworkAddonsConfigSpec{
 				KubeMacPool: &cnao.KubeMacPool{
-					RangeStart: "this:aint:right",
+					RangeStart: "02:FF:FF:FF:FF:FF",
+					RangeEnd:   "02:00:00:00:00:00",
 				},
 			}
-			CreateConfig(gvk, configSpec)
+			CreateConfigWithoutAdmission(gvk, configSpec)
 			CheckConfigCondition(gvk, ConditionDegraded, ConditionTrue, 5*time.Second, CheckDoNotRepeat)
 			CheckFailedEvent(gvk, "FailedToValidate")
 		})
@@ -38,4 +39,29 @@ var _ = Describe("NetworkAddonsConfig", func() {
 			})
 		})
 	})
+
+	Context("when invalid config is rejected", func() {
+		BeforeEach(func() {
+			configSpec := cnao.NetworkAddonsConfigSpec{
+				KubeMacPool: &cnao.KubeMacPool{
+					RangeStart: "02:FF:FF:FF:FF:FF",
+					RangeEnd:   "02:00:00:00:00:00",
+				},
+			}
+			CheckConfigRejected(gvk, configSpec, "invalid range")
+		})
+
+		Context("and a valid config is created instead", func() {
+			BeforeEach(func() {
+				configSpec := cnao.NetworkAddonsConfigSpec{}
+				CreateConfig(gvk, configSpec)
+			})
+
+			It("should become Available", func() {
+				CheckAvailableEvent(gvk)
+				CheckConfigCondition(gvk, ConditionAvailable, ConditionTrue, 5*time.Second, CheckDoNotRepeat)
+				CheckConfigCondition(gvk, ConditionDegraded, ConditionFalse, CheckImmediately, CheckDoNotRepeat)
+			})
+		})
+	})
 })
@@ -21,14 +21,15 @@ var _ = Describe("NetworkAddonsConfig", func() {
 				configSpec := cnao.NetworkAddonsConfigSpec{
 					ImagePullPolicy: v1.PullAlways,
 					KubeMacPool: &cnao.KubeMacPool{
-						RangeStart: "this:aint:right",
+						RangeStart: "02:FF:FF:FF:FF:FF",
+						RangeEnd:   "02:00:00:00:00:00",
 					},
 					LinuxBridge: &cnao.LinuxBridge{},
 					Multus:      &cnao.Multus{},
 					Ovs:         &cnao.Ovs{},
 					MacvtapCni:  &cnao.MacvtapCni{},
 				}
-				CreateConfig(gvk, configSpec)
+				CreateConfigWithoutAdmission(gvk, configSpec)
 			})
 
 			It("should report Failing condition and Available must be set to False", func() {
@@ -36,6 +37,32 @@ var _ = Describe("NetworkAddonsConfig", func() {
 				CheckConfigCondition(gvk, ConditionAvailable, ConditionFalse, CheckImmediately, CheckDoNotRepeat)
 			})
 		})
+
+		Context("and an invalid config is created while the operator is running", func() {
+			It("should be rejected by the validating webhook", func() {
+				configSpec := cnao.NetworkAddonsConfigSpec{
+					KubeMacPool: &cnao.KubeMacPool{
+						RangeStart: "02:FF:FF:FF:FF:FF",
+						RangeEnd:   "02:00:00:00:00:00",
+					},
+				}
+				CheckConfigRejected(gvk, configSpec, "invalid range")
+				Expect(GetConfig(gvk)).To(BeNil())
+			})
+		})
+
+		Context("and a malformed config is created", func() {
+			It("should be rejected by the API server", func() {
+				configSpec := cnao.NetworkAddonsConfigSpec{
+					KubeMacPool: &cnao.KubeMacPool{
+						RangeStart: "this:aint:right",
+						RangeEnd:   "02:FF:FF:FF:FF:FF",
+					},
+				}
+				CheckConfigCreateRejected(gvk, configSpec, "spec.kubeMacPool.rangeStart")
+				Expect(GetConfig(gvk)).To(BeNil())
+			})
+		})
 	})
 
 	Context("when a valid config is deployed", func() {
@@ -73,5 +100,16 @@ var _ = Describe("NetworkAddonsConfig", func() {
 				CheckConfigCondition(gvk, ConditionDegraded, ConditionFalse, CheckImmediately, time.Minute)
 			})
 		})
+
+		Context("and it is updated with a malformed value", func() {
+			It("should be rejected by the API server", func() {
+				configSpec := cnao.NetworkAddonsConfigSpec{
+					LinuxBridge: &cnao.LinuxBridge{
+						BridgeMarker: &cnao.BridgeMarker{UpdateInterval: "1 minute"},
+					},
+				}
+				CheckConfigUpdateRejected(gvk, configSpec, "spec.linuxBridge.bridgeMarker.updateInterval")
+			})
+		})
 	})
 })
@@ -0,0 +1,133 @@
+package integration
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	osconfv1 "github.com/openshift/api/config/v1"
+	osv1 "github.com/openshift/api/operator/v1"
+	corev1 "k8s.io/api/core/v1"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/envtest"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	cnaov1 "github.com/kubevirt/cluster-network-addons-operator/pkg/apis/networkaddonsoperator/v1"
+	cnaov1alpha1 "github.com/kubevirt/cluster-network-addons-operator/pkg/apis/networkaddonsoperator/v1alpha1"
+	"github.com/kubevirt/cluster-network-addons-operator/pkg/components"
+	"github.com/kubevirt/cluster-network-addons-operator/pkg/controller"
+	"github.com/kubevirt/cluster-network-addons-operator/pkg/util/k8s"
+)
+
+const operatorVersion = "99.0.0"
+
+var (
+	testEnv     *envtest.Environment
+	k8sClient   client.Client
+	tempDir     string
+	stopManager context.CancelFunc
+)
+
+func TestIntegration(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "integration Test Suite")
+}
+
+var _ = BeforeSuite(func() {
+	// A skipped suite would pass unnoticed in CI
+	if os.Getenv("KUBEBUILDER_ASSETS") == "" {
+		Fail("KUBEBUILDER_ASSETS is not set, run the suite through make test/integration")
+	}
+
+	// The operator renders templates relative to the root directory
+	Expect(os.Chdir("../../")).To(Succeed())
+
+	By("Starting a local API server")
+	testEnv = &envtest.Environment{
+		CRDs: []*extv1.CustomResourceDefinition{components.GetCrd()},
+	}
+	cfg, err := testEnv.Start()
+	Expect(err).ToNot(HaveOccurred())
+
+	// The operator authenticates as its ServiceAccount, so it is authorized only by the RBAC generated
+	// for its release manifests. The controller builds its own clients from the kubeconfig.
+	tempDir, err = os.MkdirTemp("", "cnao-integration")
+	Expect(err).ToNot(HaveOccurred())
+	operator, err := testEnv.AddUser(envtest.User{Name: operatorUser, Groups: operatorGroups}, nil)
+	Expect(err).ToNot(HaveOccurred())
+	kubeconfig, err := operator.KubeConfig()
+	Expect(err).ToNot(HaveOccurred())
+	kubeconfigPath := filepath.Join(tempDir, "kubeconfig")
+	Expect(os.WriteFile(kubeconfigPath, kubeconfig, 0600)).To(Succeed())
+	Expect(os.Setenv("KUBECONFIG", kubeconfigPath)).To(Succeed())
+
+	setOperatorEnv()
+
+	scheme := runtime.NewScheme()
+	Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+	Expect(extv1.AddToScheme(scheme)).To(Succeed())
+	Expect(cnaov1.AddToScheme(scheme)).To(Succeed())
+	Expect(cnaov1alpha1.AddToScheme(scheme)).To(Succeed())
+	Expect(osv1.Install(scheme)).To(Succeed())
+	Expect(osconfv1.Install(scheme)).To(Succeed())
+
+	k8sClient, err = client.New(cfg, client.Options{Scheme: scheme})
+	Expect(err).ToNot(HaveOccurred())
+
+	// The namespace exists before the operator starts, as it does when deployed by its manifests
+	namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: components.Namespace}}
+	Expect(k8sClient.Create(context.TODO(), namespace)).To(Succeed())
+
+	By("Deploying the operator RBAC generated for its release manifests")
+	for _, obj := range generatedRBAC() {
+		Expect(k8sClient.Create(context.TODO(), obj)).To(Succeed())
+	}
+
+	By("Starting the operator manager as the operator ServiceAccount")
+	mgr, err := manager.New(operator.Config(), manager.Options{
+		Scheme:             scheme,
+		MetricsBindAddress: "0",
+		MapperProvider:     k8s.NewDynamicRESTMapper,
+	})
+	Expect(err).ToNot(HaveOccurred())
+	Expect(controller.AddToManager(mgr)).To(Succeed())
+
+	var ctx context.Context
+	ctx, stopManager = context.WithCancel(context.Background())
+	go func() {
+		defer GinkgoRecover()
+		Expect(mgr.Start(ctx)).To(Succeed())
+	}()
+})
+
+var _ = AfterSuite(func() {
+	if testEnv == nil {
+		return
+	}
+	if stopManager != nil {
+		stopManager()
+	}
+	Expect(testEnv.Stop()).To(Succeed())
+	os.RemoveAll(tempDir)
+})
+
+// setOperatorEnv sets the environment the operator deployment passes to the operator, including
+// default images of components
+func setOperatorEnv() {
+	deployment := components.GetDeployment(operatorVersion, operatorVersion, components.Namespace, "", "", "", "", (&components.AddonsImages{}).FillDefaults(), []string{componentNamespace})
+	for _, envVar := range deployment.Spec.Template.Spec.Containers[0].Env {
+		if envVar.ValueFrom == nil {
+			Expect(os.Setenv(envVar.Name, envVar.Value)).To(Succeed())
+		}
+	}
+	Expect(os.Setenv("OPERATOR_NAMESPACE", components.Namespace)).To(Succeed())
+	Expect(os.Setenv("OPERAND_NAMESPACE", components.Namespace)).To(Succeed())
+}
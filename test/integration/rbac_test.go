@@ -0,0 +1,180 @@
+package integration
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/yaml"
+
+	cnao "github.com/kubevirt/cluster-network-addons-operator/pkg/apis/networkaddonsoperator/shared"
+	"github.com/kubevirt/cluster-network-addons-operator/pkg/components"
+	"github.com/kubevirt/cluster-network-addons-operator/pkg/names"
+	"github.com/kubevirt/cluster-network-addons-operator/pkg/network"
+)
+
+// componentNamespace is a namespace, besides the operator namespace, the operator manifests allow
+// components to be deployed to
+const componentNamespace = "cnao-components"
+
+var (
+	// operatorUser is the user of the ServiceAccount the operator runs as
+	operatorUser = fmt.Sprintf("system:serviceaccount:%s:%s", components.Namespace, components.Name)
+	// operatorGroups are the groups the API server puts the ServiceAccount to
+	operatorGroups = []string{"system:serviceaccounts", "system:serviceaccounts:" + components.Namespace, "system:authenticated"}
+)
+
+// generatedRBAC renders the operator manifests the way they are released and returns the operator
+// ServiceAccount and its RBAC objects
+func generatedRBAC() []*unstructured.Unstructured {
+	manifests, err := exec.Command("go", "run", "./tools/manifest-templator",
+		"--input-file=templates/cluster-network-addons/VERSION/operator.yaml.in",
+		"--namespace="+components.Namespace,
+		"--component-namespaces="+componentNamespace,
+		"--data-dir=data",
+	).Output()
+	ExpectWithOffset(1, err).ToNot(HaveOccurred())
+
+	objs := []*unstructured.Unstructured{}
+	decoder := yaml.NewYAMLOrJSONDecoder(bytes.NewReader(manifests), 4096)
+	for {
+		obj := &unstructured.Unstructured{}
+		if err := decoder.Decode(&obj.Object); err == io.EOF {
+			break
+		} else {
+			ExpectWithOffset(1, err).ToNot(HaveOccurred())
+		}
+		switch obj.GetKind() {
+		case "ServiceAccount", "ClusterRole", "ClusterRoleBinding", "Role", "RoleBinding":
+			objs = append(objs, obj)
+		}
+	}
+	return objs
+}
+
+// renderAllComponents renders all components with all optional objects, Kubemacpool is deployed to
+// componentNamespace
+func renderAllComponents() []*unstructured.Unstructured {
+	conf := &cnao.NetworkAddonsConfigSpec{
+		Multus:      &cnao.Multus{},
+		LinuxBridge: &cnao.LinuxBridge{},
+		KubeMacPool: &cnao.KubeMacPool{Namespace: componentNamespace},
+		Ovs:         &cnao.Ovs{},
+		MacvtapCni:  &cnao.MacvtapCni{},
+	}
+	ExpectWithOffset(1, network.FillDefaults(conf, nil)).To(Succeed())
+	clusterInfo := &network.ClusterInfo{
+		SCCAvailable:         true,
+		MonitoringAvailable:  true,
+		ProxyConfigAvailable: true,
+		Proxy:                &cnao.Proxy{TrustedCA: names.TRUSTED_CA_BUNDLE_CONFIGMAP},
+	}
+
+	objs, err := network.Render(conf, "data", nil, clusterInfo)
+	ExpectWithOffset(1, err).ToNot(HaveOccurred())
+	objsToRemove, err := network.RenderObjsToRemove(conf, &cnao.NetworkAddonsConfigSpec{}, nil, "data", nil, clusterInfo)
+	ExpectWithOffset(1, err).ToNot(HaveOccurred())
+	hostCleanupObjs, err := network.RenderHostCleanup(conf, &cnao.NetworkAddonsConfigSpec{}, "data", nil, clusterInfo)
+	ExpectWithOffset(1, err).ToNot(HaveOccurred())
+	return append(append(objs, objsToRemove...), hostCleanupObjs...)
+}
+
+// operatorAllowed asks the API server whether the operator is authorized to access the resource
+func operatorAllowed(attributes authorizationv1.ResourceAttributes) bool {
+	review := &authorizationv1.SubjectAccessReview{
+		Spec: authorizationv1.SubjectAccessReviewSpec{
+			User:               operatorUser,
+			Groups:             operatorGroups,
+			ResourceAttributes: &attributes,
+		},
+	}
+	ExpectWithOffset(1, k8sClient.Create(context.TODO(), review)).To(Succeed())
+	return review.Status.Allowed
+}
+
+var _ = Describe("Operator RBAC", func() {
+	It("should allow the operator to apply and remove all components", func() {
+		denied := []string{}
+		for _, obj := range renderAllComponents() {
+			groupResource := components.ResourceForKind(obj.GroupVersionKind())
+			attributes := authorizationv1.ResourceAttributes{
+				Namespace: obj.GetNamespace(),
+				Group:     groupResource.Group,
+				Resource:  groupResource.Resource,
+				Verb:      "create",
+			}
+			if !operatorAllowed(attributes) {
+				denied = append(denied, fmt.Sprintf("create %s in %q", groupResource, obj.GetNamespace()))
+			}
+
+			attributes.Name = obj.GetName()
+			for _, verb := range []string{"get", "update", "delete"} {
+				attributes.Verb = verb
+				if !operatorAllowed(attributes) {
+					denied = append(denied, fmt.Sprintf("%s %s %s/%s", verb, groupResource, obj.GetNamespace(), obj.GetName()))
+				}
+			}
+		}
+		Expect(denied).To(BeEmpty())
+	})
+
+	DescribeTable("should allow the operator to manage its own objects",
+		func(attributes authorizationv1.ResourceAttributes) {
+			Expect(operatorAllowed(attributes)).To(BeTrue())
+		},
+		Entry("electing a leader", authorizationv1.ResourceAttributes{
+			Namespace: components.Namespace, Group: "coordination.k8s.io", Resource: "leases", Name: components.Name + "-lock", Verb: "update"}),
+		Entry("issuing its webhook certificates", authorizationv1.ResourceAttributes{
+			Namespace: components.Namespace, Resource: "secrets", Name: names.WEBHOOK_CERT_SECRET, Verb: "update"}),
+		Entry("recording applied revisions", authorizationv1.ResourceAttributes{
+			Namespace: components.Namespace, Resource: "configmaps", Name: names.APPLIED_PREFIX + names.OPERATOR_CONFIG, Verb: "update"}),
+		Entry("registering its webhooks", authorizationv1.ResourceAttributes{
+			Group: "admissionregistration.k8s.io", Resource: "validatingwebhookconfigurations", Name: names.VALIDATING_WEBHOOK_CONFIGURATION, Verb: "update"}),
+		Entry("removing its webhooks", authorizationv1.ResourceAttributes{
+			Group: "admissionregistration.k8s.io", Resource: "validatingwebhookconfigurations", Name: names.VALIDATING_WEBHOOK_CONFIGURATION, Verb: "delete"}),
+		Entry("owning its webhooks", authorizationv1.ResourceAttributes{
+			Group: "rbac.authorization.k8s.io", Resource: "clusterroles", Name: components.Name, Verb: "get"}),
+		Entry("converting its custom resources", authorizationv1.ResourceAttributes{
+			Group: "apiextensions.k8s.io", Resource: "customresourcedefinitions", Name: names.NETWORK_ADDONS_CONFIG_CRD, Verb: "patch"}),
+		Entry("reporting status", authorizationv1.ResourceAttributes{
+			Group: "networkaddonsoperator.network.kubevirt.io", Resource: "networkaddonsconfigs", Subresource: "status", Name: names.OPERATOR_CONFIG, Verb: "patch"}),
+		Entry("labeling Open vSwitch nodes", authorizationv1.ResourceAttributes{
+			Resource: "nodes", Name: "node", Verb: "patch"}),
+		Entry("caching DaemonSets", authorizationv1.ResourceAttributes{Group: "apps", Resource: "daemonsets", Verb: "watch"}),
+		Entry("caching Deployments", authorizationv1.ResourceAttributes{Group: "apps", Resource: "deployments", Verb: "watch"}),
+		Entry("caching pods", authorizationv1.ResourceAttributes{Resource: "pods", Verb: "watch"}),
+		Entry("caching namespaces", authorizationv1.ResourceAttributes{Resource: "namespaces", Verb: "watch"}),
+		Entry("caching ConfigMaps", authorizationv1.ResourceAttributes{Resource: "configmaps", Verb: "watch"}),
+		Entry("caching nodes", authorizationv1.ResourceAttributes{Resource: "nodes", Verb: "watch"}),
+		Entry("caching its configuration", authorizationv1.ResourceAttributes{
+			Group: "networkaddonsoperator.network.kubevirt.io", Resource: "networkaddonsconfigs", Verb: "watch"}),
+		Entry("recording events", authorizationv1.ResourceAttributes{Namespace: components.Namespace, Resource: "events", Verb: "create"}),
+	)
+
+	DescribeTable("should not allow the operator to access objects it does not manage",
+		func(attributes authorizationv1.ResourceAttributes) {
+			Expect(operatorAllowed(attributes)).To(BeFalse())
+		},
+		Entry("reading Secrets of other namespaces", authorizationv1.ResourceAttributes{
+			Namespace: "default", Resource: "secrets", Name: "credentials", Verb: "get"}),
+		Entry("listing Secrets", authorizationv1.ResourceAttributes{Resource: "secrets", Verb: "list"}),
+		Entry("modifying ConfigMaps of other namespaces", authorizationv1.ResourceAttributes{
+			Namespace: "default", Resource: "configmaps", Name: "config", Verb: "update"}),
+		Entry("removing other namespaces", authorizationv1.ResourceAttributes{Resource: "namespaces", Name: "default", Verb: "delete"}),
+		Entry("escalating other roles", authorizationv1.ResourceAttributes{
+			Group: "rbac.authorization.k8s.io", Resource: "clusterroles", Name: "cluster-admin", Verb: "escalate"}),
+		Entry("binding other roles", authorizationv1.ResourceAttributes{
+			Group: "rbac.authorization.k8s.io", Resource: "clusterroles", Name: "cluster-admin", Verb: "bind"}),
+		Entry("modifying network attachment definitions", authorizationv1.ResourceAttributes{
+			Group: "k8s.cni.cncf.io", Resource: "network-attachment-definitions", Verb: "create"}),
+		Entry("modifying other webhooks", authorizationv1.ResourceAttributes{
+			Group: "admissionregistration.k8s.io", Resource: "mutatingwebhookconfigurations", Name: "other", Verb: "update"}),
+	)
+})
@@ -0,0 +1,236 @@
+package integration
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	osv1 "github.com/openshift/api/operator/v1"
+	conditionsv1 "github.com/openshift/custom-resource-status/conditions/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	cnao "github.com/kubevirt/cluster-network-addons-operator/pkg/apis/networkaddonsoperator/shared"
+	cnaov1 "github.com/kubevirt/cluster-network-addons-operator/pkg/apis/networkaddonsoperator/v1"
+	"github.com/kubevirt/cluster-network-addons-operator/pkg/components"
+	"github.com/kubevirt/cluster-network-addons-operator/pkg/eventemitter"
+	"github.com/kubevirt/cluster-network-addons-operator/pkg/names"
+)
+
+const (
+	timeout  = 30 * time.Second
+	interval = 250 * time.Millisecond
+)
+
+var configKey = types.NamespacedName{Name: names.OPERATOR_CONFIG}
+
+func getConfig() *cnaov1.NetworkAddonsConfig {
+	config := &cnaov1.NetworkAddonsConfig{}
+	ExpectWithOffset(1, k8sClient.Get(context.TODO(), configKey, config)).To(Succeed())
+	return config
+}
+
+func conditionStatus(conditionType conditionsv1.ConditionType) func() corev1.ConditionStatus {
+	return func() corev1.ConditionStatus {
+		config := &cnaov1.NetworkAddonsConfig{}
+		if err := k8sClient.Get(context.TODO(), configKey, config); err != nil {
+			return ""
+		}
+		condition := conditionsv1.FindStatusCondition(config.Status.Conditions, conditionType)
+		if condition == nil {
+			return ""
+		}
+		return condition.Status
+	}
+}
+
+func objectExists(obj client.Object, key types.NamespacedName) func() bool {
+	return func() bool {
+		err := k8sClient.Get(context.TODO(), key, obj)
+		ExpectWithOffset(1, err == nil || apierrors.IsNotFound(err)).To(BeTrue(), "unexpected error: %v", err)
+		return err == nil
+	}
+}
+
+// configEventReasons lists reasons of events the operator emitted for the NetworkAddonsConfig
+func configEventReasons() []string {
+	events := &corev1.EventList{}
+	ExpectWithOffset(1, k8sClient.List(context.TODO(), events, client.MatchingFields{
+		"involvedObject.kind": "NetworkAddonsConfig",
+		"involvedObject.name": names.OPERATOR_CONFIG,
+	})).To(Succeed())
+	reasons := []string{}
+	for _, event := range events.Items {
+		reasons = append(reasons, event.Reason)
+	}
+	return reasons
+}
+
+// markDaemonSetsReady fakes the status the DaemonSet controller reports once pods run on a single node,
+// there is no such controller running next to the local API server
+func markDaemonSetsReady() {
+	daemonSets := &appsv1.DaemonSetList{}
+	ExpectWithOffset(1, k8sClient.List(context.TODO(), daemonSets, client.InNamespace(components.Namespace))).To(Succeed())
+	for i := range daemonSets.Items {
+		daemonSet := &daemonSets.Items[i]
+		daemonSet.Status = appsv1.DaemonSetStatus{
+			ObservedGeneration:     daemonSet.Generation,
+			CurrentNumberScheduled: 1,
+			DesiredNumberScheduled: 1,
+			NumberReady:            1,
+			NumberAvailable:        1,
+			UpdatedNumberScheduled: 1,
+		}
+		ExpectWithOffset(1, k8sClient.Status().Update(context.TODO(), daemonSet)).To(Succeed())
+	}
+}
+
+// markDeploymentsReady fakes the status the Deployment controller reports once all replicas run
+func markDeploymentsReady() {
+	deployments := &appsv1.DeploymentList{}
+	ExpectWithOffset(1, k8sClient.List(context.TODO(), deployments, client.InNamespace(components.Namespace))).To(Succeed())
+	for i := range deployments.Items {
+		deployment := &deployments.Items[i]
+		replicas := int32(1)
+		if deployment.Spec.Replicas != nil {
+			replicas = *deployment.Spec.Replicas
+		}
+		deployment.Status = appsv1.DeploymentStatus{
+			ObservedGeneration: deployment.Generation,
+			Replicas:           replicas,
+			UpdatedReplicas:    replicas,
+			ReadyReplicas:      replicas,
+			AvailableReplicas:  replicas,
+		}
+		ExpectWithOffset(1, k8sClient.Status().Update(context.TODO(), deployment)).To(Succeed())
+	}
+}
+
+var _ = Describe("NetworkAddonsConfig reconciliation", Ordered, func() {
+	bridgeMarker := types.NamespacedName{Namespace: components.Namespace, Name: "bridge-marker"}
+	kubeMacPoolManager := types.NamespacedName{Namespace: components.Namespace, Name: "kubemacpool-mac-controller-manager"}
+
+	It("should deploy components requested by a new config", func() {
+		config := components.GetCRV1()
+		config.Spec = cnao.NetworkAddonsConfigSpec{
+			LinuxBridge:     &cnao.LinuxBridge{},
+			KubeMacPool:     &cnao.KubeMacPool{},
+			ImagePullPolicy: corev1.PullIfNotPresent,
+		}
+		Expect(k8sClient.Create(context.TODO(), config)).To(Succeed())
+
+		Eventually(objectExists(&appsv1.DaemonSet{}, bridgeMarker), timeout, interval).Should(BeTrue())
+		Eventually(objectExists(&appsv1.Deployment{}, kubeMacPoolManager), timeout, interval).Should(BeTrue())
+
+		daemonSet := &appsv1.DaemonSet{}
+		Expect(k8sClient.Get(context.TODO(), bridgeMarker, daemonSet)).To(Succeed())
+		Expect(daemonSet.GetOwnerReferences()).To(ContainElement(HaveField("Name", names.OPERATOR_CONFIG)))
+	})
+
+	It("should report progress while pods are not running", func() {
+		Eventually(conditionStatus(conditionsv1.ConditionProgressing), timeout, interval).Should(Equal(corev1.ConditionTrue))
+		Expect(conditionStatus(conditionsv1.ConditionAvailable)()).ToNot(Equal(corev1.ConditionTrue))
+		Eventually(configEventReasons, timeout, interval).Should(ContainElement(eventemitter.ProgressingReason))
+	})
+
+	It("should turn available once all pods are running", func() {
+		markDaemonSetsReady()
+		markDeploymentsReady()
+
+		Eventually(conditionStatus(conditionsv1.ConditionAvailable), timeout, interval).Should(Equal(corev1.ConditionTrue))
+		Expect(conditionStatus(conditionsv1.ConditionProgressing)()).To(Equal(corev1.ConditionFalse))
+		Expect(conditionStatus(conditionsv1.ConditionDegraded)()).To(Equal(corev1.ConditionFalse))
+		Eventually(configEventReasons, timeout, interval).Should(ContainElements(eventemitter.ModifiedReason, eventemitter.AvailableReason))
+
+		config := getConfig()
+		Expect(config.Status.ObservedVersion).To(Equal(config.Status.TargetVersion))
+		Expect(config.Status.Containers).ToNot(BeEmpty())
+	})
+
+	It("should not record a new revision while reconciling an unchanged config", func() {
+		revision := getConfig().Status.AppliedRevision
+		Expect(revision).To(BeEquivalentTo(1))
+
+		// status updates of owned objects trigger reconciliations
+		markDaemonSetsReady()
+		markDeploymentsReady()
+		Consistently(func() int64 { return getConfig().Status.AppliedRevision }, 2*time.Second, interval).Should(Equal(revision))
+	})
+
+	It("should remove components dropped from the config", func() {
+		config := getConfig()
+		config.Spec.KubeMacPool = nil
+		Expect(k8sClient.Update(context.TODO(), config)).To(Succeed())
+
+		Eventually(objectExists(&appsv1.Deployment{}, kubeMacPoolManager), timeout, interval).Should(BeFalse())
+		Expect(objectExists(&appsv1.DaemonSet{}, bridgeMarker)()).To(BeTrue())
+	})
+
+	It("should report progress of an updated DaemonSet until its pods are updated", func() {
+		markDaemonSetsReady()
+		Eventually(conditionStatus(conditionsv1.ConditionAvailable), timeout, interval).Should(Equal(corev1.ConditionTrue))
+
+		daemonSet := &appsv1.DaemonSet{}
+		Expect(k8sClient.Get(context.TODO(), bridgeMarker, daemonSet)).To(Succeed())
+		daemonSet.Status.UpdatedNumberScheduled = 0
+		Expect(k8sClient.Status().Update(context.TODO(), daemonSet)).To(Succeed())
+		Eventually(conditionStatus(conditionsv1.ConditionProgressing), timeout, interval).Should(Equal(corev1.ConditionTrue))
+
+		markDaemonSetsReady()
+		Eventually(conditionStatus(conditionsv1.ConditionProgressing), timeout, interval).Should(Equal(corev1.ConditionFalse))
+	})
+
+	It("should report a failure when an invalid config is requested", func() {
+		config := getConfig()
+		config.Spec.CNIBinDir = "relative/bin"
+		Expect(k8sClient.Update(context.TODO(), config)).To(Succeed())
+
+		Eventually(conditionStatus(conditionsv1.ConditionDegraded), timeout, interval).Should(Equal(corev1.ConditionTrue))
+
+		config = getConfig()
+		config.Spec.CNIBinDir = ""
+		Expect(k8sClient.Update(context.TODO(), config)).To(Succeed())
+		Eventually(conditionStatus(conditionsv1.ConditionDegraded), timeout, interval).Should(Equal(corev1.ConditionFalse))
+	})
+
+	It("should leave components as they are while the config is Unmanaged", func() {
+		config := getConfig()
+		config.Spec.ManagementState = osv1.Unmanaged
+		Expect(k8sClient.Update(context.TODO(), config)).To(Succeed())
+
+		// an unmanaged config does not reconcile a lost DaemonSet
+		daemonSet := &appsv1.DaemonSet{}
+		Expect(k8sClient.Get(context.TODO(), bridgeMarker, daemonSet)).To(Succeed())
+		Expect(k8sClient.Delete(context.TODO(), daemonSet)).To(Succeed())
+		Consistently(objectExists(&appsv1.DaemonSet{}, bridgeMarker), 2*time.Second, interval).Should(BeFalse())
+
+		config = getConfig()
+		config.Spec.ManagementState = osv1.Managed
+		Expect(k8sClient.Update(context.TODO(), config)).To(Succeed())
+		Eventually(objectExists(&appsv1.DaemonSet{}, bridgeMarker), timeout, interval).Should(BeTrue())
+	})
+
+	It("should remove components of a Removed config", func() {
+		config := getConfig()
+		config.Spec.ManagementState = osv1.Removed
+		Expect(k8sClient.Update(context.TODO(), config)).To(Succeed())
+
+		Eventually(objectExists(&appsv1.DaemonSet{}, bridgeMarker), timeout, interval).Should(BeFalse())
+		Expect(getConfig().Spec.LinuxBridge).ToNot(BeNil())
+	})
+
+	It("should stop tracking components once the config is removed", func() {
+		Expect(k8sClient.Delete(context.TODO(), getConfig())).To(Succeed())
+		Eventually(objectExists(&cnaov1.NetworkAddonsConfig{}, configKey), timeout, interval).Should(BeFalse())
+
+		// There is no garbage collector next to the local API server, owned objects are left behind
+		// and changes of their status must not resurrect the config
+		markDaemonSetsReady()
+		Consistently(objectExists(&cnaov1.NetworkAddonsConfig{}, configKey), 2*time.Second, interval).Should(BeFalse())
+	})
+})
@@ -20,6 +20,7 @@ import (
 	cnao "github.com/kubevirt/cluster-network-addons-operator/pkg/apis/networkaddonsoperator/shared"
 	cnaov1 "github.com/kubevirt/cluster-network-addons-operator/pkg/apis/networkaddonsoperator/v1"
 	cnaov1alpha1 "github.com/kubevirt/cluster-network-addons-operator/pkg/apis/networkaddonsoperator/v1alpha1"
+	"github.com/kubevirt/cluster-network-addons-operator/pkg/components"
 	"github.com/kubevirt/cluster-network-addons-operator/pkg/names"
 	testenv "github.com/kubevirt/cluster-network-addons-operator/test/env"
 )
@@ -37,7 +38,71 @@ func GetConfig(gvk schema.GroupVersionKind) *unstructured.Unstructured {
 
 func CreateConfig(gvk schema.GroupVersionKind, configSpec cnao.NetworkAddonsConfigSpec) {
 	By(fmt.Sprintf("Applying NetworkAddonsConfig spec:\n%s", configSpecToYaml(configSpec)))
+	err := createConfig(gvk, configSpec)
+	Expect(err).NotTo(HaveOccurred(), "Failed to create the Config")
+}
+
+// CreateConfigWithoutAdmission creates a Config while the operator is scaled down, so that the Config
+// is not checked by the operator validating webhook but only by the reconciler once it is back
+func CreateConfigWithoutAdmission(gvk schema.GroupVersionKind, configSpec cnao.NetworkAddonsConfigSpec) {
+	By(fmt.Sprintf("Applying NetworkAddonsConfig spec while the operator is down:\n%s", configSpecToYaml(configSpec)))
+	ScaleDeployment(components.Name, components.Namespace, 0)
+
+	// The webhook keeps rejecting the Config until the operator pods are gone
+	EventuallyWithOffset(1, func() error {
+		return createConfig(gvk, configSpec)
+	}, 2*time.Minute, time.Second).Should(Succeed(), "Failed to create the Config")
+
+	ScaleDeployment(components.Name, components.Namespace, 1)
+	EventuallyWithOffset(1, func() int32 {
+		deployment := appsv1.Deployment{}
+		err := testenv.Client.Get(context.TODO(), types.NamespacedName{Name: components.Name, Namespace: components.Namespace}, &deployment)
+		Expect(err).NotTo(HaveOccurred())
+		return deployment.Status.ReadyReplicas
+	}, 2*time.Minute, time.Second).Should(Equal(int32(1)), "Timed out waiting for the operator to become ready")
+}
+
+// GetOperatorImage returns the image the deployed operator runs
+func GetOperatorImage() string {
+	deployment := appsv1.Deployment{}
+	err := testenv.Client.Get(context.TODO(), types.NamespacedName{Name: components.Name, Namespace: components.Namespace}, &deployment)
+	ExpectWithOffset(1, err).NotTo(HaveOccurred(), "Failed to fetch the operator Deployment")
+	return deployment.Spec.Template.Spec.Containers[0].Image
+}
+
+// CheckConfigRejected verifies that the operator validating webhook refuses to create a Config with
+// the given spec
+func CheckConfigRejected(gvk schema.GroupVersionKind, configSpec cnao.NetworkAddonsConfigSpec, expectedError string) {
+	By(fmt.Sprintf("Applying invalid NetworkAddonsConfig spec:\n%s", configSpecToYaml(configSpec)))
+	err := createConfig(gvk, configSpec)
+	ExpectWithOffset(1, err).To(HaveOccurred(), "Invalid Config should be rejected")
+	ExpectWithOffset(1, apierrors.IsForbidden(err)).To(BeTrue(), "Invalid Config should be rejected by the validating webhook, got: %v", err)
+	ExpectWithOffset(1, err.Error()).To(ContainSubstring(expectedError))
+}
+
+// CheckConfigCreateRejected verifies that the API server refuses to create a Config with the given
+// spec based on the CRD validation rules
+func CheckConfigCreateRejected(gvk schema.GroupVersionKind, configSpec cnao.NetworkAddonsConfigSpec, expectedError string) {
+	By(fmt.Sprintf("Applying malformed NetworkAddonsConfig spec:\n%s", configSpecToYaml(configSpec)))
+	err := createConfig(gvk, configSpec)
+	ExpectWithOffset(1, err).To(HaveOccurred(), "Malformed Config should be rejected")
+	ExpectWithOffset(1, apierrors.IsInvalid(err)).To(BeTrue(), "Malformed Config should be rejected by the CRD validation, got: %v", err)
+	ExpectWithOffset(1, err.Error()).To(ContainSubstring(expectedError))
+}
 
+// CheckConfigUpdateRejected verifies that the API server refuses to update the Config with the given
+// spec based on the CRD validation rules
+func CheckConfigUpdateRejected(gvk schema.GroupVersionKind, configSpec cnao.NetworkAddonsConfigSpec, expectedError string) {
+	By(fmt.Sprintf("Updating NetworkAddonsConfig with invalid spec:\n%s", configSpecToYaml(configSpec)))
+	config := GetConfig(gvk)
+	config.Object["spec"] = configSpec
+	err := testenv.Client.Update(context.TODO(), config)
+	ExpectWithOffset(1, err).To(HaveOccurred(), "Invalid Config should be rejected")
+	ExpectWithOffset(1, apierrors.IsInvalid(err)).To(BeTrue(), "Invalid Config should be rejected by the CRD validation, got: %v", err)
+	ExpectWithOffset(1, err.Error()).To(ContainSubstring(expectedError))
+}
+
+func createConfig(gvk schema.GroupVersionKind, configSpec cnao.NetworkAddonsConfigSpec) error {
 	config := &unstructured.Unstructured{}
 	config.SetGroupVersionKind(gvk)
 	config.SetName(names.OPERATOR_CONFIG)
@@ -46,8 +111,7 @@ func CreateConfig(gvk schema.GroupVersionKind, configSpec cnao.NetworkAddonsConf
 	Expect(err).NotTo(HaveOccurred(), "Failed to convert config spec to unstructured")
 	config.Object["spec"] = unstructuredConfigSpec
 
-	err = testenv.Client.Create(context.TODO(), config)
-	Expect(err).NotTo(HaveOccurred(), "Failed to create the Config")
+	return testenv.Client.Create(context.TODO(), config)
 }
 
 func UpdateConfig(gvk schema.GroupVersionKind, configSpec cnao.NetworkAddonsConfigSpec) {
@@ -86,7 +150,10 @@ func GetConfigStatus(gvk schema.GroupVersionKind) *cnao.NetworkAddonsConfigStatu
 		case GetCnaoV1GroupVersionKind():
 			return &ConvertToConfigV1(config).Status
 		case GetCnaoV1alpha1GroupVersionKind():
-			return &ConvertToConfigV1alpha1(config).Status
+			configV1 := &cnaov1.NetworkAddonsConfig{}
+			err := ConvertToConfigV1alpha1(config).ConvertTo(configV1)
+			Expect(err).NotTo(HaveOccurred(), "Failed to convert cnaov1alpha1 Config to cnaov1 Config")
+			return &configV1.Status
 		}
 
 		Fail(fmt.Sprintf("gvk %v not supported", gvk))
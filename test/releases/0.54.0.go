@@ -73,7 +73,6 @@ func init() {
 			KubeMacPool: &cnao.KubeMacPool{},
 			LinuxBridge: &cnao.LinuxBridge{},
 			Multus:      &cnao.Multus{},
-			NMState:     &cnao.NMState{},
 			Ovs:         &cnao.Ovs{},
 		},
 		Manifests: []string{
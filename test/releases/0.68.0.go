@@ -80,7 +80,6 @@ func init() {
 			KubeMacPool: &cnao.KubeMacPool{},
 			LinuxBridge: &cnao.LinuxBridge{},
 			Multus:      &cnao.Multus{},
-			NMState:     &cnao.NMState{},
 			Ovs:         &cnao.Ovs{},
 		},
 		Manifests: []string{
@@ -9,6 +9,18 @@ func init() {
 	release := Release{
 		Version: "99.0.0",
 		Containers: []cnao.Container{
+			{
+				ParentName: "multus",
+				ParentKind: "DaemonSet",
+				Name:       "install-multus-binary",
+				Image:      "ghcr.io/k8snetworkplumbingwg/multus-cni@sha256:829c27e9392d013eee5086ca7670d7326d723ebaec526237215e86086b5a3234",
+			},
+			{
+				ParentName: "multus",
+				ParentKind: "DaemonSet",
+				Name:       "install-cni-health",
+				Image:      OperatorImage,
+			},
 			{
 				ParentName: "multus",
 				ParentKind: "DaemonSet",
@@ -18,14 +30,14 @@ func init() {
 			{
 				ParentName: "multus",
 				ParentKind: "DaemonSet",
-				Name:       "install-multus-binary",
+				Name:       "cni-health",
 				Image:      "ghcr.io/k8snetworkplumbingwg/multus-cni@sha256:829c27e9392d013eee5086ca7670d7326d723ebaec526237215e86086b5a3234",
 			},
 			{
-				ParentName: "bridge-marker",
+				ParentName: "kube-cni-linux-bridge-plugin",
 				ParentKind: "DaemonSet",
-				Name:       "bridge-marker",
-				Image:      "quay.io/kubevirt/bridge-marker@sha256:5d24c6d1ecb0556896b7b81c7e5260b54173858425777b7a84df8a706c07e6d2",
+				Name:       "install-cni-health",
+				Image:      OperatorImage,
 			},
 			{
 				ParentName: "kube-cni-linux-bridge-plugin",
@@ -34,7 +46,19 @@ func init() {
 				Image:      "quay.io/kubevirt/cni-default-plugins@sha256:5d9442c26f8750d44f97175f36dbd74bef503f782b9adefcfd08215d065c437a",
 			},
 			{
-				ParentName: "kubemacpool-mac-controller-manager",
+				ParentName: "kube-cni-linux-bridge-plugin",
+				ParentKind: "DaemonSet",
+				Name:       "cni-health",
+				Image:      "quay.io/kubevirt/cni-default-plugins@sha256:5d9442c26f8750d44f97175f36dbd74bef503f782b9adefcfd08215d065c437a",
+			},
+			{
+				ParentName: "bridge-marker",
+				ParentKind: "DaemonSet",
+				Name:       "bridge-marker",
+				Image:      "quay.io/kubevirt/bridge-marker@sha256:5d24c6d1ecb0556896b7b81c7e5260b54173858425777b7a84df8a706c07e6d2",
+			},
+			{
+				ParentName: "kubemacpool-cert-manager",
 				ParentKind: "Deployment",
 				Name:       "manager",
 				Image:      "quay.io/kubevirt/kubemacpool@sha256:fb07b1be9e0990e3846ef628e993694bf0765602af5907abf98f7e218db0cb4a",
@@ -42,14 +66,14 @@ func init() {
 			{
 				ParentName: "kubemacpool-mac-controller-manager",
 				ParentKind: "Deployment",
-				Name:       "kube-rbac-proxy",
-				Image:      components.KubeRbacProxyImageDefault,
+				Name:       "manager",
+				Image:      "quay.io/kubevirt/kubemacpool@sha256:fb07b1be9e0990e3846ef628e993694bf0765602af5907abf98f7e218db0cb4a",
 			},
 			{
-				ParentName: "kubemacpool-cert-manager",
+				ParentName: "kubemacpool-mac-controller-manager",
 				ParentKind: "Deployment",
-				Name:       "manager",
-				Image:      "quay.io/kubevirt/kubemacpool@sha256:fb07b1be9e0990e3846ef628e993694bf0765602af5907abf98f7e218db0cb4a",
+				Name:       "kube-rbac-proxy",
+				Image:      components.KubeRbacProxyImageDefault,
 			},
 			{
 				ParentName: "ovs-cni-amd64",
@@ -57,12 +81,24 @@ func init() {
 				Name:       "ovs-cni-plugin",
 				Image:      "quay.io/kubevirt/ovs-cni-plugin@sha256:3654b80dd5e459c3e73dd027d732620ed8b488b8a15dfe7922457d16c7e834c3",
 			},
+			{
+				ParentName: "ovs-cni-amd64",
+				ParentKind: "DaemonSet",
+				Name:       "install-cni-health",
+				Image:      OperatorImage,
+			},
 			{
 				ParentName: "ovs-cni-amd64",
 				ParentKind: "DaemonSet",
 				Name:       "ovs-cni-marker",
 				Image:      "quay.io/kubevirt/ovs-cni-plugin@sha256:3654b80dd5e459c3e73dd027d732620ed8b488b8a15dfe7922457d16c7e834c3",
 			},
+			{
+				ParentName: "ovs-cni-amd64",
+				ParentKind: "DaemonSet",
+				Name:       "cni-health",
+				Image:      "quay.io/kubevirt/ovs-cni-plugin@sha256:3654b80dd5e459c3e73dd027d732620ed8b488b8a15dfe7922457d16c7e834c3",
+			},
 		},
 		SupportedSpec: cnao.NetworkAddonsConfigSpec{
 			KubeMacPool: &cnao.KubeMacPool{},
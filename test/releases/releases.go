@@ -31,6 +31,10 @@ type Release struct {
 	CrdCleanUp []string
 }
 
+// OperatorImage stands for the image of the deployed operator in containers of a release, the
+// image depends on the registry the operator was pushed to
+const OperatorImage = "<operator-image>"
+
 // Releases are populated by respective release modules using init()
 var releases = []Release{}
 var releasesProcessed = false
@@ -140,7 +144,14 @@ func UninstallOperator(release Release) {
 func CheckReleaseUsesExpectedContainerImages(gvk schema.GroupVersionKind, release Release) {
 	By(fmt.Sprintf("Checking that all deployed images match release %s", release.Version))
 
-	expectedContainers := sortContainers(release.Containers)
+	expectedContainers := []cnao.Container{}
+	for _, container := range release.Containers {
+		if container.Image == OperatorImage {
+			container.Image = GetOperatorImage()
+		}
+		expectedContainers = append(expectedContainers, container)
+	}
+	expectedContainers = sortContainers(expectedContainers)
 	configStatus := GetConfigStatus(gvk)
 	deployedContainers := sortContainers(configStatus.Containers)
 
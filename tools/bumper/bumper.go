@@ -17,6 +17,10 @@ type inputParams struct {
 	componentsConfigPath string
 	gitToken             string
 	baseBranch           string
+	repoUrl              string
+	forge                string
+	dryRun               bool
+	local                bool
 }
 
 const (
@@ -32,15 +36,22 @@ func main() {
 	inputArgs := inputParams{}
 	initFlags(&inputArgs)
 
-	githubApi, err := newGithubApi(inputArgs.gitToken)
-	if err != nil {
-		exitWithError(errors.Wrap(err, "Failed to create github api instance"))
+	var cnaoForge forge
+	if !inputArgs.local {
+		var err error
+		cnaoForge, err = newForge(inputArgs.repoUrl, inputArgs.forge, inputArgs.gitToken)
+		if err != nil {
+			exitWithError(errors.Wrap(err, "Failed to create forge api instance"))
+		}
 	}
 
-	cnaoRepo, err := getCnaoRepo(githubApi, inputArgs.baseBranch)
+	cnaoRepo, err := getCnaoRepo(cnaoForge, inputArgs.repoUrl, inputArgs.baseBranch)
 	if err != nil {
 		exitWithError(errors.Wrap(err, "Failed to clone cnao repo"))
 	}
+	if inputArgs.local {
+		cnaoRepo.forge = newLocalGitApi(cnaoRepo.gitRepo.repo)
+	}
 
 	logger.Printf("Parsing %s", inputArgs.componentsConfigPath)
 	componentsConfig, err := cnaoRepo.getComponentsConfig(inputArgs.componentsConfigPath)
@@ -57,7 +68,12 @@ func main() {
 			exitWithError(errors.Wrapf(err, "Failed to print component %s", componentName))
 		}
 
-		gitComponent, err := newGitComponent(githubApi, componentName, &component)
+		var gitComponent *gitComponent
+		if inputArgs.local {
+			gitComponent, err = newLocalGitComponent(componentName, &component)
+		} else {
+			gitComponent, err = newRemoteGitComponent(cnaoForge, inputArgs.repoUrl, componentName, &component)
+		}
 		if err != nil {
 			exitWithError(errors.Wrapf(err, "Failed to clone %s", componentName))
 		}
@@ -73,6 +89,18 @@ func main() {
 			exitWithError(errors.Wrapf(err, "Failed to get latest release version tag from %s", componentName))
 		}
 
+		releaseAboveRange, err := gitComponent.getReleaseAboveRange()
+		if err != nil {
+			exitWithError(errors.Wrapf(err, "Failed to get release out of update-policy range from %s", componentName))
+		}
+		if releaseAboveRange != "" {
+			err = cnaoRepo.fileNewerReleaseNotice(componentName, component.Updatepolicy, releaseAboveRange, inputArgs.dryRun)
+			if err != nil {
+				logger.Printf("Notice about %s %s was not filed. err = %s", componentName, releaseAboveRange, err)
+				failedComponents = append(failedComponents, componentName)
+			}
+		}
+
 		proposedPrTitle := fmt.Sprintf("bump %s to %s", componentName, updatedReleaseTag)
 		componentBumpNeeded, err := cnaoRepo.isComponentBumpNeeded(currentReleaseTag, updatedReleaseTag, component.Updatepolicy, proposedPrTitle)
 		if err != nil {
@@ -82,7 +110,7 @@ func main() {
 		if componentBumpNeeded {
 			logger.Printf("Bumping %s from %s to %s", componentName, currentReleaseTag, updatedReleaseTag)
 
-			err = handleBump(cnaoRepo, component, componentName, inputArgs.componentsConfigPath, updatedReleaseTag, updatedReleaseCommit, proposedPrTitle)
+			err = handleBump(cnaoRepo, component, componentName, inputArgs.componentsConfigPath, updatedReleaseTag, updatedReleaseCommit, proposedPrTitle, inputArgs.dryRun)
 			if err != nil {
 				logger.Printf("Bump %s component was initiated but did not succeed. err = %s", componentName, err)
 				failedComponents = append(failedComponents, componentName)
@@ -97,7 +125,7 @@ func main() {
 	}
 }
 
-func handleBump(cnaoRepo *gitCnaoRepo, component component, componentName, componentsConfigPath, updatedReleaseTag, updatedReleaseCommit, proposedPrTitle string) error {
+func handleBump(cnaoRepo *gitCnaoRepo, component component, componentName, componentsConfigPath, updatedReleaseTag, updatedReleaseCommit, proposedPrTitle string, dryRun bool) error {
 	defer func() {
 		err := cnaoRepo.reset()
 		if err != nil {
@@ -120,7 +148,7 @@ func handleBump(cnaoRepo *gitCnaoRepo, component component, componentName, compo
 		return errors.Wrap(err, "Failed to update components yaml")
 	}
 
-	err = cnaoRepo.bumpComponent(componentName)
+	err = cnaoRepo.bumpComponent(componentName, dryRun)
 	if err != nil {
 		return errors.Wrap(err, "Failed to bump component")
 	}
@@ -135,6 +163,11 @@ func handleBump(cnaoRepo *gitCnaoRepo, component component, componentName, compo
 		return nil
 	}
 
+	if dryRun {
+		logger.Printf("Dry run, skipping Bump PR %q", proposedPrTitle)
+		return cnaoRepo.printBumpPreview(componentsConfigPath, bumpFilesList)
+	}
+
 	logger.Printf("Generate Bump PR using GithubAPI")
 	_, err = cnaoRepo.generateBumpPr(proposedPrTitle, bumpFilesList)
 	if err != nil {
@@ -155,12 +188,19 @@ func initFlags(paramArgs *inputParams) {
 	flag.StringVar(&paramArgs.componentsConfigPath, "config-path", "", "relative path to components yaml from CNAO repo")
 	flag.StringVar(&paramArgs.gitToken, "token", "", "git Token")
 	flag.StringVar(&paramArgs.baseBranch, "base-branch", "main", "the branch CNAO is running the bumper script on, and on which the PRs will be opened")
+	flag.StringVar(&paramArgs.repoUrl, "repo-url", repoUrl, "url of the CNAO repo the PRs are opened on")
+	flag.StringVar(&paramArgs.forge, "forge", "", "forge hosting the CNAO repo, github or gitlab. Selected by the host of repo-url if not set")
+	flag.BoolVar(&paramArgs.dryRun, "dry-run", false, "bump components in the local CNAO repo and print the changes instead of opening PRs")
+	flag.BoolVar(&paramArgs.local, "local", false, "resolve releases from the cloned component repos instead of the github API, implies dry-run")
 	flag.Parse()
+	if paramArgs.local {
+		paramArgs.dryRun = true
+	}
 	if paramArgs.componentsConfigPath == "" {
 		exitWithError(fmt.Errorf("config-path mandatory input paramter not entered. Use --help for usage"))
 	}
-	if paramArgs.gitToken == "" {
-		exitWithError(fmt.Errorf("github token mandatory input paramter not entered. Use --help for usage"))
+	if paramArgs.gitToken == "" && !paramArgs.dryRun {
+		exitWithError(fmt.Errorf("forge token mandatory input paramter not entered. Use --help for usage"))
 	}
 }
 
@@ -22,7 +22,7 @@ import (
 type gitCnaoRepo struct {
 	configParams *component
 
-	githubInterface githubInterface
+	forge forge
 
 	gitRepo *gitRepo
 }
@@ -32,21 +32,21 @@ const (
 	allowListString = "components.yaml,data/*,test/releases/99.0.0.go,pkg/components/components.go"
 )
 
-func getCnaoRepo(api *githubApi, baseBranch string) (*gitCnaoRepo, error) {
+func getCnaoRepo(api forge, cnaoRepoUrl, baseBranch string) (*gitCnaoRepo, error) {
 	cnaoGitRepo, err := openGitRepo(".")
 	if err != nil {
 		return nil, errors.Wrapf(err, "Failed to get git repo for cnao repo")
 	}
 
 	cnaoComponentParams := &component{
-		Url:    repoUrl,
+		Url:    cnaoRepoUrl,
 		Branch: baseBranch,
 	}
 
 	gitCnaoRepo := &gitCnaoRepo{
-		configParams:    cnaoComponentParams,
-		githubInterface: api,
-		gitRepo:         cnaoGitRepo,
+		configParams: cnaoComponentParams,
+		forge:        api,
+		gitRepo:      cnaoGitRepo,
 	}
 
 	return gitCnaoRepo, nil
@@ -104,9 +104,13 @@ func (cnaoRepoOps *gitCnaoRepo) resetInAllowedList(allowList []string) error {
 	return nil
 }
 
-func (cnaoRepoOps *gitCnaoRepo) bumpComponent(componentName string) error {
+// bumpComponent runs the bump script of the component, a dry run does not push images the script builds
+func (cnaoRepoOps *gitCnaoRepo) bumpComponent(componentName string, dryRun bool) error {
 	logger.Printf("Running bump-%s script", componentName)
 	cmd := exec.Command("make", "-C", cnaoRepoOps.gitRepo.localDir, fmt.Sprintf("bump-%s", componentName))
+	if dryRun {
+		cmd.Env = append(os.Environ(), "PUSH_IMAGES=")
+	}
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
 	err := cmd.Run()
@@ -155,7 +159,7 @@ func (cnaoRepoOps *gitCnaoRepo) isComponentBumpNeeded(currentReleaseVersion, lat
 func (cnaoRepoOps *gitCnaoRepo) isPrAlreadyOpened(proposedPrTitle string) (bool, error) {
 	logger.Printf("checking if there is an already open bump PR for this release")
 
-	prList, _, err := cnaoRepoOps.githubInterface.listPullRequests(cnaoRepoOps.getCnaoRepoOwnerFromUrl(), cnaoRepoOps.getCnaoRepoNameFromUrl(), cnaoRepoOps.configParams.Branch)
+	prList, _, err := cnaoRepoOps.forge.listPullRequests(cnaoRepoOps.getCnaoRepoOwnerFromUrl(), cnaoRepoOps.getCnaoRepoNameFromUrl(), cnaoRepoOps.configParams.Branch)
 	if err != nil {
 		return false, errors.Wrapf(err, "Failed to get list of PRs from %s/%s repo", cnaoRepoOps.getCnaoRepoOwnerFromUrl(), cnaoRepoOps.getCnaoRepoNameFromUrl())
 	}
@@ -169,6 +173,37 @@ func (cnaoRepoOps *gitCnaoRepo) isPrAlreadyOpened(proposedPrTitle string) (bool,
 	return false, nil
 }
 
+// fileNewerReleaseNotice opens an issue about a component release the update policy does not allow,
+// unless such an issue is already open. A dry run only logs the notice.
+func (cnaoRepoOps *gitCnaoRepo) fileNewerReleaseNotice(componentName, updatePolicy, release string, dryRun bool) error {
+	title := fmt.Sprintf("%s %s is out of update-policy %s", componentName, release, updatePolicy)
+	logger.Printf("Notice: %s", title)
+	if dryRun {
+		return nil
+	}
+
+	issues, _, err := cnaoRepoOps.forge.listIssues(cnaoRepoOps.getCnaoRepoOwnerFromUrl(), cnaoRepoOps.getCnaoRepoNameFromUrl())
+	if err != nil {
+		return errors.Wrapf(err, "Failed to get list of issues from %s/%s repo", cnaoRepoOps.getCnaoRepoOwnerFromUrl(), cnaoRepoOps.getCnaoRepoNameFromUrl())
+	}
+	for _, issue := range issues {
+		if issue.GetTitle() == title {
+			logger.Printf("Notice issue already exists")
+			return nil
+		}
+	}
+
+	body := fmt.Sprintf("%s\nThe bumper does not propose it since it is out of the range allowed by update-policy %s of %s.\n"+
+		"Bump it manually, or update the update-policy in components.yaml once CNAO is ready for it.\n\nExecuted by Bumper script", title, updatePolicy, componentName)
+	issue, _, err := cnaoRepoOps.forge.createIssue(cnaoRepoOps.getCnaoRepoOwnerFromUrl(), cnaoRepoOps.getCnaoRepoNameFromUrl(), &github.IssueRequest{Title: &title, Body: &body})
+	if err != nil {
+		return errors.Wrap(err, "Failed to create notice issue")
+	}
+
+	logger.Printf("Notice issue created: %s\n", issue.GetHTMLURL())
+	return nil
+}
+
 // collectBumpFile is a wrapper for collectModifiedToTreeList
 func (cnaoRepoOps *gitCnaoRepo) collectBumpFile() ([]*github.TreeEntry, error) {
 	return cnaoRepoOps.collectModifiedToTreeList(getAllowedList())
@@ -216,6 +251,21 @@ func (cnaoRepoOps *gitCnaoRepo) collectModifiedToTreeList(allowedList []string)
 	return entries, nil
 }
 
+// printBumpPreview prints the change of the components config and the files a bump PR would carry
+func (cnaoRepoOps *gitCnaoRepo) printBumpPreview(componentsConfigPath string, bumpFilesList []*github.TreeEntry) error {
+	logger.Printf("Changes of %s:", componentsConfigPath)
+	err := runExternalGitCommand([]string{"-C", cnaoRepoOps.gitRepo.localDir, "--no-pager", "diff", "--", componentsConfigPath})
+	if err != nil {
+		return errors.Wrapf(err, "Failed to print changes of %s", componentsConfigPath)
+	}
+
+	logger.Printf("Files changed by the bump:")
+	for _, entry := range bumpFilesList {
+		fmt.Println(entry.GetPath())
+	}
+	return nil
+}
+
 // getNewBumpBranch creates a new bump branch.
 // Since we don't want to use already open branch
 // that may hold outdated/needs rebasing changes
@@ -224,7 +274,7 @@ func (cnaoRepoOps *gitCnaoRepo) getNewBumpBranch(prTitle string) (*github.Refere
 	branchBaseName := strings.Replace(strings.ToLower(prTitle), " ", "_", -1)
 
 	branchNameWithRandSuffix := fmt.Sprintf("%s_%s", branchBaseName, generateRandString())
-	_, resp, err := cnaoRepoOps.githubInterface.getBranchRef(cnaoRepoOps.getCnaoRepoOwnerFromUrl(), cnaoRepoOps.getCnaoRepoNameFromUrl(), "refs/heads/"+branchNameWithRandSuffix)
+	_, resp, err := cnaoRepoOps.forge.getBranchRef(cnaoRepoOps.getCnaoRepoOwnerFromUrl(), cnaoRepoOps.getCnaoRepoNameFromUrl(), "refs/heads/"+branchNameWithRandSuffix)
 	if err != nil {
 		if resp.Response.StatusCode == http.StatusNotFound {
 			return cnaoRepoOps.createNewGithubBranch(branchNameWithRandSuffix)
@@ -236,14 +286,14 @@ func (cnaoRepoOps *gitCnaoRepo) getNewBumpBranch(prTitle string) (*github.Refere
 }
 
 func (cnaoRepoOps *gitCnaoRepo) createNewGithubBranch(newBranchName string) (*github.Reference, string, error) {
-	logger.Printf("Creating new branch with githubApi: %s", newBranchName)
-	baseRef, _, err := cnaoRepoOps.githubInterface.getBranchRef(cnaoRepoOps.getCnaoRepoOwnerFromUrl(), cnaoRepoOps.getCnaoRepoNameFromUrl(), "refs/heads/"+cnaoRepoOps.configParams.Branch)
+	logger.Printf("Creating new branch with forge api: %s", newBranchName)
+	baseRef, _, err := cnaoRepoOps.forge.getBranchRef(cnaoRepoOps.getCnaoRepoOwnerFromUrl(), cnaoRepoOps.getCnaoRepoNameFromUrl(), "refs/heads/"+cnaoRepoOps.configParams.Branch)
 	if err != nil {
 		return nil, "", errors.Wrapf(err, "Failed to get origin/%s github ref", cnaoRepoOps.configParams.Branch)
 	}
 
 	newRef := &github.Reference{Ref: github.String("refs/heads/" + newBranchName), Object: &github.GitObject{SHA: baseRef.Object.SHA}}
-	newBranchRef, _, err := cnaoRepoOps.githubInterface.createBranchRef(cnaoRepoOps.getCnaoRepoOwnerFromUrl(), cnaoRepoOps.getCnaoRepoNameFromUrl(), newRef)
+	newBranchRef, _, err := cnaoRepoOps.forge.createBranchRef(cnaoRepoOps.getCnaoRepoOwnerFromUrl(), cnaoRepoOps.getCnaoRepoNameFromUrl(), newRef)
 	if err != nil {
 		return nil, "", errors.Wrap(err, "Failed to create new branch ref")
 	}
@@ -255,7 +305,7 @@ func (cnaoRepoOps *gitCnaoRepo) createNewGithubBranch(newBranchName string) (*gi
 func (cnaoRepoOps *gitCnaoRepo) pushCommit(commitTitle string, branch *github.Reference, tree *github.Tree) error {
 	logger.Printf("Pushing new commit")
 	// Get the parent commit to attach the commit to.
-	parent, _, err := cnaoRepoOps.githubInterface.getCommit(cnaoRepoOps.getCnaoRepoOwnerFromUrl(), cnaoRepoOps.getCnaoRepoNameFromUrl(), *branch.Object.SHA)
+	parent, _, err := cnaoRepoOps.forge.getCommit(cnaoRepoOps.getCnaoRepoOwnerFromUrl(), cnaoRepoOps.getCnaoRepoNameFromUrl(), *branch.Object.SHA)
 	if err != nil {
 		return errors.Wrap(err, "Failed to get parent commit")
 	}
@@ -276,13 +326,13 @@ func (cnaoRepoOps *gitCnaoRepo) pushCommit(commitTitle string, branch *github.Re
 
 	author := &github.CommitAuthor{Date: &date, Name: &authorName, Email: &authorEmail}
 	commit := &github.Commit{Author: author, Message: &commitMessage, Tree: tree, Parents: []*github.Commit{parent}}
-	newCommit, _, err := cnaoRepoOps.githubInterface.createCommit(cnaoRepoOps.getCnaoRepoOwnerFromUrl(), cnaoRepoOps.getCnaoRepoNameFromUrl(), commit)
+	newCommit, _, err := cnaoRepoOps.forge.createCommit(cnaoRepoOps.getCnaoRepoOwnerFromUrl(), cnaoRepoOps.getCnaoRepoNameFromUrl(), commit)
 	if err != nil {
 		return errors.Wrap(err, "Failed to create new commit")
 	}
 
 	branch.Object.SHA = newCommit.SHA
-	_, _, err = cnaoRepoOps.githubInterface.updateRef(cnaoRepoOps.getCnaoRepoOwnerFromUrl(), cnaoRepoOps.getCnaoRepoNameFromUrl(), branch, false)
+	_, _, err = cnaoRepoOps.forge.updateRef(cnaoRepoOps.getCnaoRepoOwnerFromUrl(), cnaoRepoOps.getCnaoRepoNameFromUrl(), branch, false)
 	if err != nil {
 		return errors.Wrap(err, "Failed to attach the commit to the branch.")
 	}
@@ -301,7 +351,7 @@ func (cnaoRepoOps *gitCnaoRepo) createPR(prTitle, branchName string) (*github.Pu
 		MaintainerCanModify: github.Bool(true),
 	}
 
-	pr, _, err := cnaoRepoOps.githubInterface.createPullRequest(cnaoRepoOps.getCnaoRepoOwnerFromUrl(), cnaoRepoOps.getCnaoRepoNameFromUrl(), newPR)
+	pr, _, err := cnaoRepoOps.forge.createPullRequest(cnaoRepoOps.getCnaoRepoOwnerFromUrl(), cnaoRepoOps.getCnaoRepoNameFromUrl(), newPR)
 	if err != nil {
 		return nil, err
 	}
@@ -317,7 +367,7 @@ func (cnaoRepoOps *gitCnaoRepo) generateBumpPr(prTitle string, modifiedFilesList
 		return nil, errors.Wrap(err, "Failed to create bump branch")
 	}
 
-	fileTree, _, err := cnaoRepoOps.githubInterface.createTree(cnaoRepoOps.getCnaoRepoOwnerFromUrl(), cnaoRepoOps.getCnaoRepoNameFromUrl(), *branchRef.Object.SHA, modifiedFilesList)
+	fileTree, _, err := cnaoRepoOps.forge.createTree(cnaoRepoOps.getCnaoRepoOwnerFromUrl(), cnaoRepoOps.getCnaoRepoNameFromUrl(), *branchRef.Object.SHA, modifiedFilesList)
 	if err != nil {
 		return nil, errors.Wrap(err, "Failed to generate github file tree")
 	}
@@ -42,15 +42,15 @@ var _ = Describe("Testing internal git CNAO Repo", func() {
 		BeforeEach(func() {
 
 			newPr := getFakePrWithTitle("CNAO test-component to 0.0.2", "main")
-			_, _, err := gitCnaoRepo.githubInterface.createPullRequest(dummyOwner, dummyRepo, newPr)
+			_, _, err := gitCnaoRepo.forge.createPullRequest(dummyOwner, dummyRepo, newPr)
 			Expect(err).ToNot(HaveOccurred(), "should succeed creating fake PR")
 
 			newPr = getFakePrWithTitle("CNAO test-component to 1.0.0", "release-v1.0.0")
-			_, _, err = gitCnaoRepo.githubInterface.createPullRequest(dummyOwner, dummyRepo, newPr)
+			_, _, err = gitCnaoRepo.forge.createPullRequest(dummyOwner, dummyRepo, newPr)
 			Expect(err).ToNot(HaveOccurred(), "should succeed creating fake PR")
 
 			newPr = getFakePrWithTitle("CNAO test-component to 1.0.1", "release-v1.0.0")
-			_, _, err = gitCnaoRepo.githubInterface.createPullRequest(dummyOwner, dummyRepo, newPr)
+			_, _, err = gitCnaoRepo.forge.createPullRequest(dummyOwner, dummyRepo, newPr)
 			Expect(err).ToNot(HaveOccurred(), "should succeed creating fake PR")
 		})
 
@@ -105,6 +105,36 @@ var _ = Describe("Testing internal git CNAO Repo", func() {
 		)
 	})
 
+	Context("Filing notices about releases out of update-policy", func() {
+		AfterEach(func() {
+			os.RemoveAll(gitCnaoRepo.gitRepo.localDir)
+		})
+
+		It("should file a notice only once", func() {
+			gitCnaoRepo.configParams.Url = repoDir
+
+			By("Filing the notice")
+			Expect(gitCnaoRepo.fileNewerReleaseNotice("test-component", "tagged:^v3", "v4.0.0", false)).To(Succeed())
+			Expect(githubApi.fakeIssueList).To(HaveLen(1), "should create a notice issue")
+			Expect(githubApi.fakeIssueList[0].GetTitle()).To(Equal("test-component v4.0.0 is out of update-policy tagged:^v3"))
+
+			By("Filing the same notice again")
+			Expect(gitCnaoRepo.fileNewerReleaseNotice("test-component", "tagged:^v3", "v4.0.0", false)).To(Succeed())
+			Expect(githubApi.fakeIssueList).To(HaveLen(1), "should not duplicate the notice issue")
+
+			By("Filing a notice about a newer release")
+			Expect(gitCnaoRepo.fileNewerReleaseNotice("test-component", "tagged:^v3", "v4.1.0", false)).To(Succeed())
+			Expect(githubApi.fakeIssueList).To(HaveLen(2), "should create another notice issue")
+		})
+
+		It("should not file a notice on dry run", func() {
+			gitCnaoRepo.configParams.Url = repoDir
+
+			Expect(gitCnaoRepo.fileNewerReleaseNotice("test-component", "tagged:^v3", "v4.0.0", true)).To(Succeed())
+			Expect(githubApi.fakeIssueList).To(BeEmpty(), "should not create a notice issue")
+		})
+	})
+
 	type canonicalizeVersionParams struct {
 		version        string
 		expectedResult *semver.Version
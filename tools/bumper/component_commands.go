@@ -1,7 +1,6 @@
 package main
 
 import (
-	"context"
 	"fmt"
 	"io/ioutil"
 	"os"
@@ -12,112 +11,61 @@ import (
 	"github.com/go-git/go-git/v5/plumbing/object"
 	"github.com/google/go-github/v32/github"
 	"github.com/pkg/errors"
-	"golang.org/x/oauth2"
 )
 
 type gitComponent struct {
 	configParams *component
 
-	githubInterface githubInterface
+	forge forge
 
 	gitRepo *gitRepo
 }
 
-type githubApi struct {
-	client *github.Client
-
-	// context needed for github api
-	ctx context.Context
-}
-
-type githubInterface interface {
-	listMatchingRefs(owner, repo string, opts *github.ReferenceListOptions) ([]*github.Reference, *github.Response, error)
-	listCommits(owner, repo string, opts *github.CommitsListOptions) ([]*github.RepositoryCommit, *github.Response, error)
-	getBranchRef(owner string, repo string, ref string) (*github.Reference, *github.Response, error)
-	createBranchRef(owner string, repo string, ref *github.Reference) (*github.Reference, *github.Response, error)
-	createTree(owner string, repo string, baseTree string, entries []*github.TreeEntry) (*github.Tree, *github.Response, error)
-	getCommit(owner string, repo string, sha string) (*github.Commit, *github.Response, error)
-	createCommit(owner string, repo string, commit *github.Commit) (*github.Commit, *github.Response, error)
-	updateRef(owner string, repo string, ref *github.Reference, force bool) (*github.Reference, *github.Response, error)
-	listPullRequests(owner string, repo string, branch string) ([]*github.PullRequest, *github.Response, error)
-	createPullRequest(owner string, repo string, pull *github.NewPullRequest) (*github.PullRequest, *github.Response, error)
-}
-
-func (g githubApi) listMatchingRefs(owner, repo string, opts *github.ReferenceListOptions) ([]*github.Reference, *github.Response, error) {
-	return g.client.Git.ListMatchingRefs(g.ctx, owner, repo, opts)
-}
-
-func (g githubApi) listCommits(owner, repo string, opts *github.CommitsListOptions) ([]*github.RepositoryCommit, *github.Response, error) {
-	return g.client.Repositories.ListCommits(g.ctx, owner, repo, opts)
-}
-
-func (g githubApi) getBranchRef(owner string, repo string, ref string) (*github.Reference, *github.Response, error) {
-	return g.client.Git.GetRef(g.ctx, owner, repo, ref)
-}
-
-func (g githubApi) createBranchRef(owner string, repo string, newRef *github.Reference) (*github.Reference, *github.Response, error) {
-	return g.client.Git.CreateRef(g.ctx, owner, repo, newRef)
-}
-
-func (g githubApi) createTree(owner string, repo string, baseTree string, entries []*github.TreeEntry) (*github.Tree, *github.Response, error) {
-	return g.client.Git.CreateTree(g.ctx, owner, repo, baseTree, entries)
-}
-
-func (g githubApi) getCommit(owner string, repo string, sha string) (*github.Commit, *github.Response, error) {
-	return g.client.Git.GetCommit(g.ctx, owner, repo, sha)
-}
-
-func (g githubApi) createCommit(owner string, repo string, commit *github.Commit) (*github.Commit, *github.Response, error) {
-	return g.client.Git.CreateCommit(g.ctx, owner, repo, commit)
-}
-
-func (g githubApi) updateRef(owner string, repo string, ref *github.Reference, force bool) (*github.Reference, *github.Response, error) {
-	return g.client.Git.UpdateRef(g.ctx, owner, repo, ref, force)
-}
-
-func (g githubApi) listPullRequests(owner string, repo string, branch string) ([]*github.PullRequest, *github.Response, error) {
-	return g.client.PullRequests.List(g.ctx, owner, repo, &github.PullRequestListOptions{State: "open", Base: branch})
-}
-
-func (g githubApi) createPullRequest(owner string, repo string, pull *github.NewPullRequest) (*github.PullRequest, *github.Response, error) {
-	return g.client.PullRequests.Create(g.ctx, owner, repo, pull)
-}
-
 type gitRepo struct {
 	repo *git.Repository
 
 	localDir string
 }
 
-func newGitComponent(api *githubApi, componentName string, componentParams *component) (*gitComponent, error) {
+func newGitComponent(api forge, componentName string, componentParams *component) (*gitComponent, error) {
 	componentGitRepo, err := newGitRepo(componentName, componentParams)
 	if err != nil {
 		return nil, errors.Wrapf(err, "Failed to clone git repo for component %s", componentName)
 	}
 
 	gitComponent := &gitComponent{
-		configParams:    componentParams,
-		githubInterface: api,
-		gitRepo:         componentGitRepo,
+		configParams: componentParams,
+		forge:        api,
+		gitRepo:      componentGitRepo,
 	}
 
 	return gitComponent, nil
 }
 
-// newGithubApi establishes connection with the github Api server using the token
-func newGithubApi(token string) (*githubApi, error) {
-	ctx := context.Background()
-	ts := oauth2.StaticTokenSource(
-		&oauth2.Token{AccessToken: token},
-	)
-	tc := oauth2.NewClient(ctx, ts)
-
-	githubApi := &githubApi{
-		client: github.NewClient(tc),
-		ctx:    ctx,
+// newRemoteGitComponent clones the component repository and resolves its releases through the forge hosting it
+func newRemoteGitComponent(cnaoForge forge, cnaoRepoUrl, componentName string, componentParams *component) (*gitComponent, error) {
+	componentForge, err := getComponentForge(cnaoForge, cnaoRepoUrl, componentParams.Url)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to get forge of component %s", componentName)
 	}
 
-	return githubApi, nil
+	return newGitComponent(componentForge, componentName, componentParams)
+}
+
+// newLocalGitComponent clones the component repository and resolves its releases from the clone
+func newLocalGitComponent(componentName string, componentParams *component) (*gitComponent, error) {
+	componentGitRepo, err := newGitRepo(componentName, componentParams)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to clone git repo for component %s", componentName)
+	}
+
+	gitComponent := &gitComponent{
+		configParams: componentParams,
+		forge:        newLocalGitApi(componentGitRepo.repo),
+		gitRepo:      componentGitRepo,
+	}
+
+	return gitComponent, nil
 }
 
 // newGitRepo clones the repository on a local temp directory.
@@ -150,7 +98,7 @@ func (componentOps *gitComponent) getCurrentReleaseTag() (string, error) {
 	owner := componentOps.getComponentOwnerFromUrl()
 	logger.Printf("Getting current tag in repo %s sha %s", repo, componentOps.configParams.Commit)
 
-	tagRefs, _, err := componentOps.githubInterface.listMatchingRefs(owner, repo, &github.ReferenceListOptions{Ref: "refs/tags"})
+	tagRefs, _, err := componentOps.forge.listMatchingRefs(owner, repo, &github.ReferenceListOptions{Ref: "refs/tags"})
 	if err != nil {
 		return "", errors.Wrap(err, "Failed to get release tag refs from github client API")
 	}
@@ -178,10 +126,14 @@ func (componentOps *gitComponent) getCurrentReleaseTag() (string, error) {
 func (componentOps *gitComponent) getUpdatedReleaseInfo() (string, string, error) {
 	repo := componentOps.getComponentNameFromUrl()
 	owner := componentOps.getComponentOwnerFromUrl()
-	switch componentOps.configParams.Updatepolicy {
+	policy, err := parseUpdatePolicy(componentOps.configParams.Updatepolicy)
+	if err != nil {
+		return "", "", err
+	}
+	switch policy.name {
 	case updatePolicyTagged:
-		logger.Printf("update policy %s will updated to the latest tagged commit in the referenced branch", updatePolicyTagged)
-		return componentOps.getLatestTaggedFromBranch(repo, owner, componentOps.configParams.Branch, componentOps.gitRepo.localDir)
+		logger.Printf("update policy %s will updated to the latest tagged commit in the referenced branch", componentOps.configParams.Updatepolicy)
+		return componentOps.getLatestTaggedFromBranch(repo, owner, componentOps.configParams.Branch, componentOps.gitRepo.localDir, policy.versionRange)
 	case updatePolicyLatest:
 		logger.Printf("update policy %s will update to the latest HEAD in the referenced branch", updatePolicyTagged)
 		return componentOps.getLatestFromBranch(repo, owner, componentOps.configParams.Branch, componentOps.gitRepo.localDir)
@@ -193,21 +145,67 @@ func (componentOps *gitComponent) getUpdatedReleaseInfo() (string, string, error
 	}
 }
 
+// getReleaseAboveRange gets the latest tag in the referenced branch which is newer than the version range of
+// the update policy allows. An empty tag is returned if there is no such release or the policy has no range.
+func (componentOps *gitComponent) getReleaseAboveRange() (string, error) {
+	policy, err := parseUpdatePolicy(componentOps.configParams.Updatepolicy)
+	if err != nil || policy.versionRange == nil {
+		return "", err
+	}
+
+	repo := componentOps.getComponentNameFromUrl()
+	owner := componentOps.getComponentOwnerFromUrl()
+	tagRefs, branchCommits, err := componentOps.getTagsAndBranchCommits(repo, owner, componentOps.configParams.Branch)
+	if err != nil {
+		return "", err
+	}
+
+	tagName, _, err := componentOps.findLatestTagInBranch(filterTags(tagRefs, policy.versionRange.isExceededBy), branchCommits)
+	return tagName, err
+}
+
 // getLatestTaggedFromBranch get the latest updated tag and associated commit-sha under a given branch, using "tagged" Update policy
 // since some tags are represented by the tag sha and not the commit sha, we also need to convert it to commit sha.
-func (componentOps *gitComponent) getLatestTaggedFromBranch(repo, owner, branch, repoDir string) (string, string, error) {
+// If the policy has a version range, only tags inside of it are considered.
+func (componentOps *gitComponent) getLatestTaggedFromBranch(repo, owner, branch, repoDir string, versionRange *versionRange) (string, string, error) {
 	logger.Printf("Getting latest tagged from branch %s in repo %s", branch, repo)
-	tagRefs, _, err := componentOps.githubInterface.listMatchingRefs(owner, repo, &github.ReferenceListOptions{Ref: "refs/tags"})
+	tagRefs, branchCommits, err := componentOps.getTagsAndBranchCommits(repo, owner, branch)
 	if err != nil {
-		return "", "", errors.Wrap(err, "Failed to get release tag refs from github client API")
+		return "", "", err
+	}
+
+	if versionRange != nil {
+		logger.Printf("Considering only tags in version range %s", versionRange.constraint)
+		tagRefs = filterTags(tagRefs, versionRange.contains)
 	}
 
-	branchCommits, _, err := componentOps.githubInterface.listCommits(owner, repo, &github.CommitsListOptions{ListOptions: github.ListOptions{PerPage: 100}, SHA: branch})
+	tagName, commitSha, err := componentOps.findLatestTagInBranch(tagRefs, branchCommits)
 	if err != nil {
-		return "", "", errors.Wrap(err, "Failed to get release tag refs from github client API")
+		return "", "", err
 	}
+	if tagName == "" {
+		return "", "", fmt.Errorf("Error: tag not found in branch %s\n tagRefs=\n%v\nbranchCommits=\n%v\n", branch, tagRefs, branchCommits)
+	}
+
+	return tagName, commitSha, nil
+}
+
+func (componentOps *gitComponent) getTagsAndBranchCommits(repo, owner, branch string) ([]*github.Reference, []*github.RepositoryCommit, error) {
+	tagRefs, _, err := componentOps.forge.listMatchingRefs(owner, repo, &github.ReferenceListOptions{Ref: "refs/tags"})
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "Failed to get release tag refs from github client API")
+	}
+
+	branchCommits, _, err := componentOps.forge.listCommits(owner, repo, &github.CommitsListOptions{ListOptions: github.ListOptions{PerPage: 100}, SHA: branch})
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "Failed to get release tag refs from github client API")
+	}
+
+	return tagRefs, branchCommits, nil
+}
 
-	// look for the first tag that belongs to the chosen branch, going over from last tag
+// findLatestTagInBranch looks for the first tag that belongs to the branch, going over from last tag
+func (componentOps *gitComponent) findLatestTagInBranch(tagRefs []*github.Reference, branchCommits []*github.RepositoryCommit) (string, string, error) {
 	for i := len(tagRefs) - 1; i >= 0; i-- {
 		tag := tagRefs[i]
 		tagName := strings.Replace(tag.GetRef(), "refs/tags/", "", 1)
@@ -223,14 +221,14 @@ func (componentOps *gitComponent) getLatestTaggedFromBranch(repo, owner, branch,
 		}
 	}
 
-	return "", "", fmt.Errorf("Error: tag not found in branch %s\n tagRefs=\n%v\nbranchCommits=\n%v\n", branch, tagRefs, branchCommits)
+	return "", "", nil
 }
 
 // getLatestFromBranch get the latest HEAD commit-sha under a given branch, using "latest" Update policy
 // since a this commit-sha is not necessarily tagged, we use the v-tag format in case needed.
 func (componentOps *gitComponent) getLatestFromBranch(repo, owner, branch, repoDir string) (string, string, error) {
 	logger.Printf("Getting Latest HEAD from branch %s in repo %s", branch, repo)
-	branchRef, _, err := componentOps.githubInterface.getBranchRef(owner, repo, "refs/heads/"+branch)
+	branchRef, _, err := componentOps.forge.getBranchRef(owner, repo, "refs/heads/"+branch)
 	if err != nil {
 		return "", "", errors.Wrapf(err, "Failed to get latest HEAD ref of branch %s from github client API", branch)
 	}
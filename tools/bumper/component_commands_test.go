@@ -196,6 +196,30 @@ var _ = Describe("Testing internal git component", func() {
 			expectedTagKey:  "",
 			shouldReturnErr: true,
 		}),
+		Entry("Update-policy tagged with range: should return latest tag in range in release branch. current: v0.0.1", updatedReleaseParams{
+			comp:            &component{Updatepolicy: updatePolicyTagged + ":^v0", Branch: "release-v1.0.0", Metadata: "v0.0.1"},
+			currentTagKey:   "v0.0.1",
+			expectedTagKey:  "v0.0.2",
+			shouldReturnErr: false,
+		}),
+		Entry("Update-policy tagged with range: should return latest patch tag in release branch. current: v1.0.0", updatedReleaseParams{
+			comp:            &component{Updatepolicy: updatePolicyTagged + ":~v1.0", Branch: "release-v1.0.0", Metadata: "v1.0.0"},
+			currentTagKey:   "v1.0.0",
+			expectedTagKey:  "v1.0.2",
+			shouldReturnErr: false,
+		}),
+		Entry("Update-policy tagged with range: should fail if no tag is in range", updatedReleaseParams{
+			comp:            &component{Updatepolicy: updatePolicyTagged + ":^v2", Branch: "release-v1.0.0", Metadata: "v1.0.0"},
+			currentTagKey:   "",
+			expectedTagKey:  "",
+			shouldReturnErr: true,
+		}),
+		Entry("Update-policy latest with range: should fail since ranges are supported only by tagged", updatedReleaseParams{
+			comp:            &component{Updatepolicy: updatePolicyLatest + ":^v1", Branch: "release-v1.0.0", Metadata: "v1.0.0"},
+			currentTagKey:   "",
+			expectedTagKey:  "",
+			shouldReturnErr: true,
+		}),
 		Entry("Update-policy latest: should return latest HEAD in main branch. current: v1.0.0", updatedReleaseParams{
 			comp:            &component{Updatepolicy: updatePolicyLatest, Branch: "main", Metadata: "v1.0.0"},
 			currentTagKey:   "v1.0.0",
@@ -215,4 +239,40 @@ var _ = Describe("Testing internal git component", func() {
 			shouldReturnErr: true,
 		}),
 	)
+
+	type releaseAboveRangeParams struct {
+		comp           *component
+		expectedTagKey string
+	}
+	DescribeTable("getReleaseAboveRange function",
+		func(r releaseAboveRangeParams) {
+			defer os.RemoveAll(gitComponent.gitRepo.localDir)
+
+			gitComponent.configParams = r.comp
+			gitComponent.configParams.Url = repoDir
+
+			By("Running api to get the latest release out of the update-policy range")
+			releaseAboveRange, err := gitComponent.getReleaseAboveRange()
+			Expect(err).ToNot(HaveOccurred(), "should not fail to run getReleaseAboveRange")
+
+			By("Checking that tag is as expected")
+			Expect(releaseAboveRange).To(Equal(r.expectedTagKey), "tag should be same as expected")
+		},
+		Entry("Should return newer major in release branch", releaseAboveRangeParams{
+			comp:           &component{Updatepolicy: updatePolicyTagged + ":^v0", Branch: "release-v1.0.0"},
+			expectedTagKey: "v1.0.2",
+		}),
+		Entry("Should return nothing if newer major is not in the branch", releaseAboveRangeParams{
+			comp:           &component{Updatepolicy: updatePolicyTagged + ":^v0", Branch: "main"},
+			expectedTagKey: "",
+		}),
+		Entry("Should return nothing if the latest release is in range", releaseAboveRangeParams{
+			comp:           &component{Updatepolicy: updatePolicyTagged + ":~v1.0", Branch: "release-v1.0.0"},
+			expectedTagKey: "",
+		}),
+		Entry("Should return nothing if update-policy has no range", releaseAboveRangeParams{
+			comp:           &component{Updatepolicy: updatePolicyTagged, Branch: "release-v1.0.0"},
+			expectedTagKey: "",
+		}),
+	)
 })
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/google/go-github/v32/github"
+	"github.com/pkg/errors"
+)
+
+// forge is the service hosting a git repository. The bumper reads releases of components and opens bump PRs
+// through it. The methods follow the GitHub API, forges other than GitHub translate from and to its types.
+type forge interface {
+	listMatchingRefs(owner, repo string, opts *github.ReferenceListOptions) ([]*github.Reference, *github.Response, error)
+	listCommits(owner, repo string, opts *github.CommitsListOptions) ([]*github.RepositoryCommit, *github.Response, error)
+	getBranchRef(owner string, repo string, ref string) (*github.Reference, *github.Response, error)
+	createBranchRef(owner string, repo string, ref *github.Reference) (*github.Reference, *github.Response, error)
+	createTree(owner string, repo string, baseTree string, entries []*github.TreeEntry) (*github.Tree, *github.Response, error)
+	getCommit(owner string, repo string, sha string) (*github.Commit, *github.Response, error)
+	createCommit(owner string, repo string, commit *github.Commit) (*github.Commit, *github.Response, error)
+	updateRef(owner string, repo string, ref *github.Reference, force bool) (*github.Reference, *github.Response, error)
+	listPullRequests(owner string, repo string, branch string) ([]*github.PullRequest, *github.Response, error)
+	createPullRequest(owner string, repo string, pull *github.NewPullRequest) (*github.PullRequest, *github.Response, error)
+	listIssues(owner string, repo string) ([]*github.Issue, *github.Response, error)
+	createIssue(owner string, repo string, issue *github.IssueRequest) (*github.Issue, *github.Response, error)
+}
+
+const (
+	forgeGithub = "github"
+	forgeGitlab = "gitlab"
+)
+
+// newForge connects to the forge hosting the repo. The forge is selected by the host of repoUrl,
+// unless forgeType is set.
+func newForge(repoUrl, forgeType, token string) (forge, error) {
+	if forgeType == "" {
+		var err error
+		forgeType, err = forgeTypeFromUrl(repoUrl)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	switch forgeType {
+	case forgeGithub:
+		return newGithubApi(token)
+	case forgeGitlab:
+		return newGitlabApi(repoUrl, token)
+	default:
+		return nil, fmt.Errorf("Error: forge %s not supported", forgeType)
+	}
+}
+
+// getComponentForge returns the forge of a component repo. Components hosted along with the CNAO repo share its
+// forge, others are reached anonymously.
+func getComponentForge(cnaoForge forge, cnaoRepoUrl, componentUrl string) (forge, error) {
+	cnaoHost, err := urlHost(cnaoRepoUrl)
+	if err != nil {
+		return nil, err
+	}
+	componentHost, err := urlHost(componentUrl)
+	if err != nil {
+		return nil, err
+	}
+
+	if componentHost == cnaoHost {
+		return cnaoForge, nil
+	}
+	return newForge(componentUrl, "", "")
+}
+
+func forgeTypeFromUrl(repoUrl string) (string, error) {
+	host, err := urlHost(repoUrl)
+	if err != nil {
+		return "", err
+	}
+
+	switch {
+	case host == "github.com":
+		return forgeGithub, nil
+	case strings.Contains(host, "gitlab"):
+		return forgeGitlab, nil
+	default:
+		return "", fmt.Errorf("Error: failed to select forge of %s by its host, set the forge explicitly", repoUrl)
+	}
+}
+
+func urlHost(repoUrl string) (string, error) {
+	u, err := url.Parse(repoUrl)
+	if err != nil {
+		return "", errors.Wrapf(err, "Failed to parse repo url %s", repoUrl)
+	}
+	return strings.ToLower(u.Hostname()), nil
+}
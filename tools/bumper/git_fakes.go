@@ -23,8 +23,9 @@ import (
 )
 
 type mockGithubApi struct {
-	repoDir    string
-	fakePRList []*github.PullRequest
+	repoDir       string
+	fakePRList    []*github.PullRequest
+	fakeIssueList []*github.Issue
 }
 
 func (g mockGithubApi) listMatchingRefs(owner, repo string, opts *github.ReferenceListOptions) ([]*github.Reference, *github.Response, error) {
@@ -95,6 +96,17 @@ func (g *mockGithubApi) createPullRequest(owner string, repo string, pull *githu
 	return pullRequest, nil, nil
 }
 
+func (g *mockGithubApi) listIssues(owner string, repo string) ([]*github.Issue, *github.Response, error) {
+	return g.fakeIssueList, nil, nil
+}
+
+func (g *mockGithubApi) createIssue(owner string, repo string, issue *github.IssueRequest) (*github.Issue, *github.Response, error) {
+	newIssue := &github.Issue{Title: issue.Title, Body: issue.Body}
+	g.fakeIssueList = append(g.fakeIssueList, newIssue)
+
+	return newIssue, nil, nil
+}
+
 type gitCommitMock struct {
 	Commit string `json:"commit"`
 	Refs   string `json:"refs"`
@@ -259,8 +271,9 @@ func getNewMockReference(commitObj *gitCommitMock) *github.Reference {
 // newFakeGithubApi creates a fake interface
 func newFakeGithubApi(repoDir string) *mockGithubApi {
 	return &mockGithubApi{
-		repoDir:    repoDir,
-		fakePRList: []*github.PullRequest{},
+		repoDir:       repoDir,
+		fakePRList:    []*github.PullRequest{},
+		fakeIssueList: []*github.Issue{},
 	}
 }
 
@@ -268,9 +281,9 @@ func newFakeGitComponent(api *mockGithubApi, repoDir string, componentParams *co
 	componentGitRepo := newLocalGitRepo(repoDir, tagCommitMap)
 
 	gitComponent := &gitComponent{
-		configParams:    componentParams,
-		githubInterface: api,
-		gitRepo:         componentGitRepo,
+		configParams: componentParams,
+		forge:        api,
+		gitRepo:      componentGitRepo,
 	}
 
 	return gitComponent
@@ -280,9 +293,9 @@ func newFakeGitCnaoRepo(api *mockGithubApi, repoDir string, componentParams *com
 	componentGitRepo := newLocalGitRepo(repoDir, tagCommitMap)
 
 	gitComponent := &gitCnaoRepo{
-		configParams:    componentParams,
-		githubInterface: api,
-		gitRepo:         componentGitRepo,
+		configParams: componentParams,
+		forge:        api,
+		gitRepo:      componentGitRepo,
 	}
 
 	return gitComponent
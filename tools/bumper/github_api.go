@@ -0,0 +1,85 @@
+package main
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/google/go-github/v32/github"
+	"golang.org/x/oauth2"
+)
+
+// githubApi is the forge of repositories hosted on GitHub
+type githubApi struct {
+	client *github.Client
+
+	// context needed for github api
+	ctx context.Context
+}
+
+func (g githubApi) listMatchingRefs(owner, repo string, opts *github.ReferenceListOptions) ([]*github.Reference, *github.Response, error) {
+	return g.client.Git.ListMatchingRefs(g.ctx, owner, repo, opts)
+}
+
+func (g githubApi) listCommits(owner, repo string, opts *github.CommitsListOptions) ([]*github.RepositoryCommit, *github.Response, error) {
+	return g.client.Repositories.ListCommits(g.ctx, owner, repo, opts)
+}
+
+func (g githubApi) getBranchRef(owner string, repo string, ref string) (*github.Reference, *github.Response, error) {
+	return g.client.Git.GetRef(g.ctx, owner, repo, ref)
+}
+
+func (g githubApi) createBranchRef(owner string, repo string, newRef *github.Reference) (*github.Reference, *github.Response, error) {
+	return g.client.Git.CreateRef(g.ctx, owner, repo, newRef)
+}
+
+func (g githubApi) createTree(owner string, repo string, baseTree string, entries []*github.TreeEntry) (*github.Tree, *github.Response, error) {
+	return g.client.Git.CreateTree(g.ctx, owner, repo, baseTree, entries)
+}
+
+func (g githubApi) getCommit(owner string, repo string, sha string) (*github.Commit, *github.Response, error) {
+	return g.client.Git.GetCommit(g.ctx, owner, repo, sha)
+}
+
+func (g githubApi) createCommit(owner string, repo string, commit *github.Commit) (*github.Commit, *github.Response, error) {
+	return g.client.Git.CreateCommit(g.ctx, owner, repo, commit)
+}
+
+func (g githubApi) updateRef(owner string, repo string, ref *github.Reference, force bool) (*github.Reference, *github.Response, error) {
+	return g.client.Git.UpdateRef(g.ctx, owner, repo, ref, force)
+}
+
+func (g githubApi) listPullRequests(owner string, repo string, branch string) ([]*github.PullRequest, *github.Response, error) {
+	return g.client.PullRequests.List(g.ctx, owner, repo, &github.PullRequestListOptions{State: "open", Base: branch})
+}
+
+func (g githubApi) createPullRequest(owner string, repo string, pull *github.NewPullRequest) (*github.PullRequest, *github.Response, error) {
+	return g.client.PullRequests.Create(g.ctx, owner, repo, pull)
+}
+
+func (g githubApi) listIssues(owner string, repo string) ([]*github.Issue, *github.Response, error) {
+	return g.client.Issues.ListByRepo(g.ctx, owner, repo, &github.IssueListByRepoOptions{State: "open"})
+}
+
+func (g githubApi) createIssue(owner string, repo string, issue *github.IssueRequest) (*github.Issue, *github.Response, error) {
+	return g.client.Issues.Create(g.ctx, owner, repo, issue)
+}
+
+// newGithubApi establishes connection with the github Api server using the token,
+// without a token the connection is anonymous and limited to reads
+func newGithubApi(token string) (*githubApi, error) {
+	ctx := context.Background()
+	var tc *http.Client
+	if token != "" {
+		ts := oauth2.StaticTokenSource(
+			&oauth2.Token{AccessToken: token},
+		)
+		tc = oauth2.NewClient(ctx, ts)
+	}
+
+	githubApi := &githubApi{
+		client: github.NewClient(tc),
+		ctx:    ctx,
+	}
+
+	return githubApi, nil
+}
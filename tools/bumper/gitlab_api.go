@@ -0,0 +1,396 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/google/go-github/v32/github"
+	"github.com/pkg/errors"
+)
+
+// gitlabApi is the forge of repositories hosted on GitLab, bump PRs are opened as merge requests.
+// GitLab creates commits from a list of file actions in a single call, so trees and commits created
+// through it are kept pending until updateRef attaches them to a branch.
+type gitlabApi struct {
+	client *http.Client
+
+	// context needed for gitlab api
+	ctx context.Context
+
+	// baseUrl is the url of the GitLab REST API, e.g. https://gitlab.example.com/api/v4
+	baseUrl string
+	token   string
+
+	// projectPath is the full path of the repo the api was created for, it may be nested in subgroups
+	projectPath string
+
+	pendingTrees   map[string][]*github.TreeEntry
+	pendingCommits map[string]*github.Commit
+}
+
+type gitlabTag struct {
+	Name   string `json:"name"`
+	Target string `json:"target"`
+}
+
+type gitlabCommit struct {
+	ID string `json:"id"`
+}
+
+type gitlabBranch struct {
+	Name   string       `json:"name"`
+	Commit gitlabCommit `json:"commit"`
+}
+
+type gitlabCommitAction struct {
+	Action   string `json:"action"`
+	FilePath string `json:"file_path"`
+	Content  string `json:"content,omitempty"`
+	Encoding string `json:"encoding,omitempty"`
+}
+
+type gitlabNewCommit struct {
+	Branch        string               `json:"branch"`
+	CommitMessage string               `json:"commit_message"`
+	AuthorName    string               `json:"author_name,omitempty"`
+	AuthorEmail   string               `json:"author_email,omitempty"`
+	Actions       []gitlabCommitAction `json:"actions"`
+}
+
+type gitlabMergeRequest struct {
+	Title        string `json:"title"`
+	Description  string `json:"description,omitempty"`
+	SourceBranch string `json:"source_branch"`
+	TargetBranch string `json:"target_branch"`
+	WebUrl       string `json:"web_url,omitempty"`
+}
+
+type gitlabIssue struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	WebUrl      string `json:"web_url,omitempty"`
+}
+
+// newGitlabApi establishes connection with the GitLab API server hosting the repo using the token,
+// without a token the connection is anonymous and limited to reads
+func newGitlabApi(repoUrl, token string) (*gitlabApi, error) {
+	u, err := url.Parse(repoUrl)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to parse repo url %s", repoUrl)
+	}
+
+	gitlabApi := &gitlabApi{
+		client:         http.DefaultClient,
+		ctx:            context.Background(),
+		baseUrl:        fmt.Sprintf("%s://%s/api/v4", u.Scheme, u.Host),
+		token:          token,
+		projectPath:    strings.TrimSuffix(strings.Trim(u.Path, "/"), ".git"),
+		pendingTrees:   map[string][]*github.TreeEntry{},
+		pendingCommits: map[string]*github.Commit{},
+	}
+
+	return gitlabApi, nil
+}
+
+func (g *gitlabApi) listMatchingRefs(owner, repo string, opts *github.ReferenceListOptions) ([]*github.Reference, *github.Response, error) {
+	if opts.Ref != "refs/tags" && opts.Ref != "refs/tags/" {
+		return nil, nil, fmt.Errorf("Error: listing %s refs is not supported on GitLab", opts.Ref)
+	}
+
+	var refs []*github.Reference
+	var resp *github.Response
+	for page := "1"; page != ""; page = resp.Header.Get("X-Next-Page") {
+		var tags []gitlabTag
+		var err error
+		resp, err = g.do(http.MethodGet, g.projectUrl(owner, repo, "repository/tags"), url.Values{"per_page": {"100"}, "page": {page}}, nil, &tags)
+		if err != nil {
+			return nil, resp, err
+		}
+		for _, tag := range tags {
+			refs = append(refs, &github.Reference{
+				Ref:    github.String("refs/tags/" + tag.Name),
+				Object: &github.GitObject{SHA: github.String(tag.Target)},
+			})
+		}
+	}
+
+	// Github lists references sorted by their name
+	sort.Slice(refs, func(i, j int) bool {
+		return refs[i].GetRef() < refs[j].GetRef()
+	})
+	return refs, resp, nil
+}
+
+func (g *gitlabApi) listCommits(owner, repo string, opts *github.CommitsListOptions) ([]*github.RepositoryCommit, *github.Response, error) {
+	query := url.Values{"ref_name": {opts.SHA}}
+	if opts.PerPage > 0 {
+		query.Set("per_page", strconv.Itoa(opts.PerPage))
+	}
+
+	var gitlabCommits []gitlabCommit
+	resp, err := g.do(http.MethodGet, g.projectUrl(owner, repo, "repository/commits"), query, nil, &gitlabCommits)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	var commits []*github.RepositoryCommit
+	for _, commit := range gitlabCommits {
+		commits = append(commits, &github.RepositoryCommit{SHA: github.String(commit.ID)})
+	}
+	return commits, resp, nil
+}
+
+func (g *gitlabApi) getBranchRef(owner string, repo string, ref string) (*github.Reference, *github.Response, error) {
+	branchName := strings.TrimPrefix(ref, "refs/heads/")
+
+	branch := gitlabBranch{}
+	resp, err := g.do(http.MethodGet, g.projectUrl(owner, repo, "repository/branches/"+url.PathEscape(branchName)), nil, nil, &branch)
+	if err != nil {
+		return nil, resp, err
+	}
+	return newGithubBranchReference(branch), resp, nil
+}
+
+func (g *gitlabApi) createBranchRef(owner string, repo string, newRef *github.Reference) (*github.Reference, *github.Response, error) {
+	query := url.Values{
+		"branch": {strings.TrimPrefix(newRef.GetRef(), "refs/heads/")},
+		"ref":    {newRef.GetObject().GetSHA()},
+	}
+
+	branch := gitlabBranch{}
+	resp, err := g.do(http.MethodPost, g.projectUrl(owner, repo, "repository/branches"), query, nil, &branch)
+	if err != nil {
+		return nil, resp, err
+	}
+	return newGithubBranchReference(branch), resp, nil
+}
+
+// createTree keeps the entries until a commit using the tree is attached to a branch
+func (g *gitlabApi) createTree(owner string, repo string, baseTree string, entries []*github.TreeEntry) (*github.Tree, *github.Response, error) {
+	treeSha := fmt.Sprintf("pending-tree-%d", len(g.pendingTrees))
+	g.pendingTrees[treeSha] = entries
+	return &github.Tree{SHA: github.String(treeSha), Entries: entries}, nil, nil
+}
+
+func (g *gitlabApi) getCommit(owner string, repo string, sha string) (*github.Commit, *github.Response, error) {
+	commit := gitlabCommit{}
+	resp, err := g.do(http.MethodGet, g.projectUrl(owner, repo, "repository/commits/"+url.PathEscape(sha)), nil, nil, &commit)
+	if err != nil {
+		return nil, resp, err
+	}
+	return &github.Commit{SHA: github.String(commit.ID)}, resp, nil
+}
+
+// createCommit keeps the commit until it is attached to a branch
+func (g *gitlabApi) createCommit(owner string, repo string, commit *github.Commit) (*github.Commit, *github.Response, error) {
+	if _, found := g.pendingTrees[commit.GetTree().GetSHA()]; !found {
+		return nil, nil, fmt.Errorf("Error: tree %s was not created by the bumper", commit.GetTree().GetSHA())
+	}
+	if len(commit.Parents) != 1 {
+		return nil, nil, fmt.Errorf("Error: commits on GitLab need exactly one parent")
+	}
+
+	commitSha := fmt.Sprintf("pending-commit-%d", len(g.pendingCommits))
+	pendingCommit := *commit
+	pendingCommit.SHA = github.String(commitSha)
+	g.pendingCommits[commitSha] = &pendingCommit
+	return &pendingCommit, nil, nil
+}
+
+// updateRef creates the pending commit on the branch. GitLab does not allow moving branches to existing commits.
+func (g *gitlabApi) updateRef(owner string, repo string, ref *github.Reference, force bool) (*github.Reference, *github.Response, error) {
+	commit, found := g.pendingCommits[ref.GetObject().GetSHA()]
+	if !found {
+		return nil, nil, fmt.Errorf("Error: GitLab branches can only be updated with commits created by the bumper")
+	}
+	parentSha := commit.Parents[0].GetSHA()
+
+	var actions []gitlabCommitAction
+	for _, entry := range g.pendingTrees[commit.GetTree().GetSHA()] {
+		action := gitlabCommitAction{FilePath: entry.GetPath()}
+		if entry.Content == nil {
+			action.Action = "delete"
+		} else {
+			exists, err := g.fileExists(owner, repo, entry.GetPath(), parentSha)
+			if err != nil {
+				return nil, nil, err
+			}
+			action.Action = "create"
+			if exists {
+				action.Action = "update"
+			}
+			action.Content = base64.StdEncoding.EncodeToString([]byte(entry.GetContent()))
+			action.Encoding = "base64"
+		}
+		actions = append(actions, action)
+	}
+
+	newCommit := gitlabNewCommit{
+		Branch:        strings.TrimPrefix(ref.GetRef(), "refs/heads/"),
+		CommitMessage: commit.GetMessage(),
+		AuthorName:    commit.GetAuthor().GetName(),
+		AuthorEmail:   commit.GetAuthor().GetEmail(),
+		Actions:       actions,
+	}
+	createdCommit := gitlabCommit{}
+	resp, err := g.do(http.MethodPost, g.projectUrl(owner, repo, "repository/commits"), nil, newCommit, &createdCommit)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	delete(g.pendingCommits, commit.GetSHA())
+	delete(g.pendingTrees, commit.GetTree().GetSHA())
+	return &github.Reference{Ref: ref.Ref, Object: &github.GitObject{SHA: github.String(createdCommit.ID)}}, resp, nil
+}
+
+func (g *gitlabApi) listPullRequests(owner string, repo string, branch string) ([]*github.PullRequest, *github.Response, error) {
+	var mergeRequests []gitlabMergeRequest
+	query := url.Values{"state": {"opened"}, "target_branch": {branch}}
+	resp, err := g.do(http.MethodGet, g.projectUrl(owner, repo, "merge_requests"), query, nil, &mergeRequests)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	var pullRequests []*github.PullRequest
+	for _, mergeRequest := range mergeRequests {
+		pullRequests = append(pullRequests, newGithubPullRequest(mergeRequest))
+	}
+	return pullRequests, resp, nil
+}
+
+func (g *gitlabApi) createPullRequest(owner string, repo string, pull *github.NewPullRequest) (*github.PullRequest, *github.Response, error) {
+	mergeRequest := gitlabMergeRequest{
+		Title:        pull.GetTitle(),
+		Description:  pull.GetBody(),
+		SourceBranch: pull.GetHead(),
+		TargetBranch: pull.GetBase(),
+	}
+	resp, err := g.do(http.MethodPost, g.projectUrl(owner, repo, "merge_requests"), nil, mergeRequest, &mergeRequest)
+	if err != nil {
+		return nil, resp, err
+	}
+	return newGithubPullRequest(mergeRequest), resp, nil
+}
+
+func (g *gitlabApi) listIssues(owner string, repo string) ([]*github.Issue, *github.Response, error) {
+	var gitlabIssues []gitlabIssue
+	resp, err := g.do(http.MethodGet, g.projectUrl(owner, repo, "issues"), url.Values{"state": {"opened"}}, nil, &gitlabIssues)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	var issues []*github.Issue
+	for _, issue := range gitlabIssues {
+		issues = append(issues, newGithubIssue(issue))
+	}
+	return issues, resp, nil
+}
+
+func (g *gitlabApi) createIssue(owner string, repo string, issue *github.IssueRequest) (*github.Issue, *github.Response, error) {
+	newIssue := gitlabIssue{Title: issue.GetTitle(), Description: issue.GetBody()}
+	resp, err := g.do(http.MethodPost, g.projectUrl(owner, repo, "issues"), nil, newIssue, &newIssue)
+	if err != nil {
+		return nil, resp, err
+	}
+	return newGithubIssue(newIssue), resp, nil
+}
+
+func (g *gitlabApi) fileExists(owner, repo, path, ref string) (bool, error) {
+	resp, err := g.do(http.MethodHead, g.projectUrl(owner, repo, "repository/files/"+url.PathEscape(path)), url.Values{"ref": {ref}}, nil, nil)
+	if err != nil {
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			return false, nil
+		}
+		return false, errors.Wrapf(err, "Failed to check if file %s exists", path)
+	}
+	return true, nil
+}
+
+// projectUrl returns the url of a project resource. GitLab identifies projects by their url-encoded path.
+// Owner and repo are the last parts of the path, so the full path the api was created for is used if it matches.
+func (g *gitlabApi) projectUrl(owner, repo, resource string) string {
+	path := owner + "/" + repo
+	if g.projectPath == path || strings.HasSuffix(g.projectPath, "/"+path) {
+		path = g.projectPath
+	}
+	return fmt.Sprintf("%s/projects/%s/%s", g.baseUrl, url.PathEscape(path), resource)
+}
+
+// do sends the request to GitLab and decodes the response to out. Failed requests return the response,
+// so the caller can check the status code.
+func (g *gitlabApi) do(method, requestUrl string, query url.Values, in, out interface{}) (*github.Response, error) {
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return nil, errors.Wrap(err, "Failed to marshal GitLab request")
+		}
+		body = bytes.NewReader(data)
+	}
+	if len(query) > 0 {
+		requestUrl = requestUrl + "?" + query.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(g.ctx, method, requestUrl, body)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to create GitLab request")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if g.token != "" {
+		req.Header.Set("PRIVATE-TOKEN", g.token)
+	}
+
+	httpResp, err := g.client.Do(req)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to send GitLab request %s %s", method, requestUrl)
+	}
+	defer httpResp.Body.Close()
+
+	resp := &github.Response{Response: httpResp}
+	if httpResp.StatusCode < 200 || httpResp.StatusCode >= 300 {
+		message, _ := ioutil.ReadAll(httpResp.Body)
+		return resp, fmt.Errorf("GitLab request %s %s failed with %s: %s", method, requestUrl, httpResp.Status, strings.TrimSpace(string(message)))
+	}
+
+	if out != nil && method != http.MethodHead {
+		if err := json.NewDecoder(httpResp.Body).Decode(out); err != nil {
+			return resp, errors.Wrapf(err, "Failed to decode GitLab response of %s %s", method, requestUrl)
+		}
+	}
+	return resp, nil
+}
+
+func newGithubBranchReference(branch gitlabBranch) *github.Reference {
+	return &github.Reference{
+		Ref:    github.String("refs/heads/" + branch.Name),
+		Object: &github.GitObject{SHA: github.String(branch.Commit.ID)},
+	}
+}
+
+func newGithubPullRequest(mergeRequest gitlabMergeRequest) *github.PullRequest {
+	return &github.PullRequest{
+		Title:   github.String(mergeRequest.Title),
+		Body:    github.String(mergeRequest.Description),
+		HTMLURL: github.String(mergeRequest.WebUrl),
+		Head:    &github.PullRequestBranch{Ref: github.String(mergeRequest.SourceBranch)},
+		Base:    &github.PullRequestBranch{Ref: github.String(mergeRequest.TargetBranch)},
+	}
+}
+
+func newGithubIssue(issue gitlabIssue) *github.Issue {
+	return &github.Issue{
+		Title:   github.String(issue.Title),
+		Body:    github.String(issue.Description),
+		HTMLURL: github.String(issue.WebUrl),
+	}
+}
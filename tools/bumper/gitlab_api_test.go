@@ -0,0 +1,271 @@
+package main
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/google/go-github/v32/github"
+)
+
+// fakeGitlab serves the parts of GitLab REST API used by the bumper
+type fakeGitlab struct {
+	server *httptest.Server
+
+	tags          []gitlabTag
+	branches      map[string]string
+	files         map[string]bool
+	commits       []gitlabNewCommit
+	mergeRequests []gitlabMergeRequest
+	issues        []gitlabIssue
+
+	projects []string
+	tokens   []string
+}
+
+func newFakeGitlab() *fakeGitlab {
+	f := &fakeGitlab{
+		tags: []gitlabTag{
+			{Name: "v0.2.0", Target: "tag-sha-2"},
+			{Name: "v0.1.0", Target: "commit-sha-1"},
+			{Name: "v0.3.0", Target: "commit-sha-3"},
+		},
+		branches: map[string]string{"main": "commit-sha-3"},
+		files:    map[string]bool{"components.yaml": true},
+	}
+	f.server = httptest.NewServer(http.HandlerFunc(f.serve))
+	return f
+}
+
+func (f *fakeGitlab) serve(w http.ResponseWriter, r *http.Request) {
+	// /api/v4/projects/<url-encoded project path>/<resource>
+	pathParts := strings.SplitN(r.URL.EscapedPath(), "/", 6)
+	if len(pathParts) != 6 || pathParts[1] != "api" || pathParts[2] != "v4" || pathParts[3] != "projects" {
+		http.NotFound(w, r)
+		return
+	}
+	project, _ := url.PathUnescape(pathParts[4])
+	f.projects = append(f.projects, project)
+	f.tokens = append(f.tokens, r.Header.Get("PRIVATE-TOKEN"))
+	resource := pathParts[5]
+
+	switch {
+	case r.Method == http.MethodGet && resource == "repository/tags":
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		pageSize := 2
+		start, end := (page-1)*pageSize, page*pageSize
+		if end < len(f.tags) {
+			w.Header().Set("X-Next-Page", strconv.Itoa(page+1))
+		} else {
+			end = len(f.tags)
+		}
+		writeJson(w, f.tags[start:end])
+	case r.Method == http.MethodGet && resource == "repository/commits":
+		writeJson(w, []gitlabCommit{{ID: "commit-sha-3"}, {ID: "commit-sha-2"}, {ID: "commit-sha-1"}})
+	case r.Method == http.MethodGet && strings.HasPrefix(resource, "repository/commits/"):
+		writeJson(w, gitlabCommit{ID: strings.TrimPrefix(resource, "repository/commits/")})
+	case r.Method == http.MethodPost && resource == "repository/commits":
+		commit := gitlabNewCommit{}
+		json.NewDecoder(r.Body).Decode(&commit)
+		f.commits = append(f.commits, commit)
+		f.branches[commit.Branch] = "new-commit-sha"
+		writeJson(w, gitlabCommit{ID: "new-commit-sha"})
+	case r.Method == http.MethodGet && strings.HasPrefix(resource, "repository/branches/"):
+		name, _ := url.PathUnescape(strings.TrimPrefix(resource, "repository/branches/"))
+		sha, found := f.branches[name]
+		if !found {
+			http.Error(w, `{"message":"404 Branch Not Found"}`, http.StatusNotFound)
+			return
+		}
+		writeJson(w, gitlabBranch{Name: name, Commit: gitlabCommit{ID: sha}})
+	case r.Method == http.MethodPost && resource == "repository/branches":
+		name, ref := r.URL.Query().Get("branch"), r.URL.Query().Get("ref")
+		f.branches[name] = ref
+		writeJson(w, gitlabBranch{Name: name, Commit: gitlabCommit{ID: ref}})
+	case r.Method == http.MethodHead && strings.HasPrefix(resource, "repository/files/"):
+		path, _ := url.PathUnescape(strings.TrimPrefix(resource, "repository/files/"))
+		if !f.files[path] {
+			http.NotFound(w, r)
+		}
+	case r.Method == http.MethodGet && resource == "merge_requests":
+		writeJson(w, f.mergeRequests)
+	case r.Method == http.MethodPost && resource == "merge_requests":
+		mergeRequest := gitlabMergeRequest{}
+		json.NewDecoder(r.Body).Decode(&mergeRequest)
+		mergeRequest.WebUrl = f.server.URL + "/merge_requests/" + strconv.Itoa(len(f.mergeRequests)+1)
+		f.mergeRequests = append(f.mergeRequests, mergeRequest)
+		writeJson(w, mergeRequest)
+	case r.Method == http.MethodGet && resource == "issues":
+		writeJson(w, f.issues)
+	case r.Method == http.MethodPost && resource == "issues":
+		issue := gitlabIssue{}
+		json.NewDecoder(r.Body).Decode(&issue)
+		f.issues = append(f.issues, issue)
+		writeJson(w, issue)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func writeJson(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
+
+var _ = Describe("Testing forges", func() {
+	type newForgeParams struct {
+		repoUrl         string
+		forgeType       string
+		expectedForge   forge
+		shouldReturnErr bool
+	}
+	DescribeTable("newForge function",
+		func(f newForgeParams) {
+			repoForge, err := newForge(f.repoUrl, f.forgeType, "token")
+			if f.shouldReturnErr {
+				Expect(err).To(HaveOccurred(), "should fail to select forge")
+				return
+			}
+			Expect(err).ToNot(HaveOccurred(), "should not fail to select forge")
+			Expect(repoForge).To(BeAssignableToTypeOf(f.expectedForge))
+		},
+		Entry("Should select GitHub by github.com host", newForgeParams{
+			repoUrl:       "https://github.com/kubevirt/cluster-network-addons-operator",
+			expectedForge: &githubApi{},
+		}),
+		Entry("Should select GitLab by gitlab host", newForgeParams{
+			repoUrl:       "https://gitlab.example.com/mirrors/cluster-network-addons-operator",
+			expectedForge: &gitlabApi{},
+		}),
+		Entry("Should select GitLab explicitly", newForgeParams{
+			repoUrl:       "https://git.example.com/mirrors/cluster-network-addons-operator",
+			forgeType:     forgeGitlab,
+			expectedForge: &gitlabApi{},
+		}),
+		Entry("Should fail on unknown host", newForgeParams{
+			repoUrl:         "https://git.example.com/mirrors/cluster-network-addons-operator",
+			shouldReturnErr: true,
+		}),
+		Entry("Should fail on unknown forge", newForgeParams{
+			repoUrl:         "https://git.example.com/mirrors/cluster-network-addons-operator",
+			forgeType:       "gitea",
+			shouldReturnErr: true,
+		}),
+	)
+
+	It("should share the forge of the CNAO repo with components hosted along with it", func() {
+		cnaoForge, err := newForge("https://gitlab.example.com/mirrors/cluster-network-addons-operator", "", "token")
+		Expect(err).ToNot(HaveOccurred())
+
+		componentForge, err := getComponentForge(cnaoForge, "https://gitlab.example.com/mirrors/cluster-network-addons-operator", "https://gitlab.example.com/mirrors/ovs-cni")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(componentForge).To(BeIdenticalTo(cnaoForge))
+
+		componentForge, err = getComponentForge(cnaoForge, "https://gitlab.example.com/mirrors/cluster-network-addons-operator", "https://github.com/k8snetworkplumbingwg/ovs-cni")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(componentForge).To(BeAssignableToTypeOf(&githubApi{}))
+	})
+
+	Context("GitLab", func() {
+		var (
+			gitlab      *fakeGitlab
+			gitlabForge *gitlabApi
+			cnaoRepo    *gitCnaoRepo
+		)
+		const projectPath = "mirrors/kubevirt/cluster-network-addons-operator"
+
+		BeforeEach(func() {
+			gitlab = newFakeGitlab()
+			repoUrl := gitlab.server.URL + "/" + projectPath
+
+			var err error
+			gitlabForge, err = newGitlabApi(repoUrl, "dummy-token")
+			Expect(err).ToNot(HaveOccurred(), "Should create GitLab api")
+
+			tempDir, err := ioutil.TempDir("/tmp", "gitlab-api-test")
+			Expect(err).ToNot(HaveOccurred(), "Should create temp dir for CNAO repo")
+			repoDir := filepath.Join(tempDir, "testOwner", "testRepo")
+			os.MkdirAll(repoDir, 0777)
+
+			cnaoRepo = &gitCnaoRepo{
+				configParams: &component{Url: repoUrl, Branch: "main"},
+				forge:        gitlabForge,
+				gitRepo:      newLocalGitRepo(repoDir, map[string]string{}),
+			}
+		})
+
+		AfterEach(func() {
+			gitlab.server.Close()
+			os.RemoveAll(cnaoRepo.gitRepo.localDir)
+		})
+
+		It("should list tags of all pages sorted by name", func() {
+			refs, _, err := gitlabForge.listMatchingRefs("kubevirt", "cluster-network-addons-operator", &github.ReferenceListOptions{Ref: "refs/tags"})
+			Expect(err).ToNot(HaveOccurred(), "should list tags")
+
+			var refNames []string
+			for _, ref := range refs {
+				refNames = append(refNames, ref.GetRef())
+			}
+			Expect(refNames).To(Equal([]string{"refs/tags/v0.1.0", "refs/tags/v0.2.0", "refs/tags/v0.3.0"}))
+			Expect(refs[1].GetObject().GetSHA()).To(Equal("tag-sha-2"))
+			Expect(gitlab.projects).To(ConsistOf(projectPath, projectPath), "should address the nested project by its full path")
+			Expect(gitlab.tokens).To(ConsistOf("dummy-token", "dummy-token"), "should authenticate with the token")
+		})
+
+		It("should return the response of a missing branch", func() {
+			_, resp, err := gitlabForge.getBranchRef("kubevirt", "cluster-network-addons-operator", "refs/heads/missing")
+			Expect(err).To(HaveOccurred(), "should fail to get missing branch")
+			Expect(resp.StatusCode).To(Equal(http.StatusNotFound))
+		})
+
+		It("should open a bump merge request", func() {
+			entries := []*github.TreeEntry{
+				{Path: github.String("components.yaml"), Content: github.String("components: {}\n")},
+				{Path: github.String("data/new.yaml"), Content: github.String("new: true\n")},
+				{Path: github.String("data/old.yaml")},
+			}
+
+			pr, err := cnaoRepo.generateBumpPr("bump test-component to v0.3.0", entries)
+			Expect(err).ToNot(HaveOccurred(), "should open bump merge request")
+			Expect(pr.GetHTMLURL()).To(Equal(gitlab.server.URL + "/merge_requests/1"))
+
+			By("Checking the commit")
+			Expect(gitlab.commits).To(HaveLen(1))
+			commit := gitlab.commits[0]
+			Expect(commit.Branch).To(HavePrefix("bump_test-component_to_v0.3.0_"))
+			Expect(commit.CommitMessage).To(HavePrefix("bump test-component to v0.3.0\n\nSigned-off-by: "))
+			Expect(commit.Actions).To(Equal([]gitlabCommitAction{
+				{Action: "update", FilePath: "components.yaml", Content: base64.StdEncoding.EncodeToString([]byte("components: {}\n")), Encoding: "base64"},
+				{Action: "create", FilePath: "data/new.yaml", Content: base64.StdEncoding.EncodeToString([]byte("new: true\n")), Encoding: "base64"},
+				{Action: "delete", FilePath: "data/old.yaml"},
+			}))
+
+			By("Checking the merge request")
+			Expect(gitlab.mergeRequests).To(HaveLen(1))
+			Expect(gitlab.mergeRequests[0].SourceBranch).To(Equal(commit.Branch))
+			Expect(gitlab.mergeRequests[0].TargetBranch).To(Equal("main"))
+
+			isPrAlreadyOpened, err := cnaoRepo.isPrAlreadyOpened("bump test-component to v0.3.0")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(isPrAlreadyOpened).To(BeTrue(), "should find the opened merge request")
+		})
+
+		It("should file a notice issue only once", func() {
+			Expect(cnaoRepo.fileNewerReleaseNotice("test-component", "tagged:^v0", "v1.0.0", false)).To(Succeed())
+			Expect(cnaoRepo.fileNewerReleaseNotice("test-component", "tagged:^v0", "v1.0.0", false)).To(Succeed())
+			Expect(gitlab.issues).To(HaveLen(1))
+			Expect(gitlab.issues[0].Title).To(Equal("test-component v1.0.0 is out of update-policy tagged:^v0"))
+		})
+	})
+})
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/storer"
+	"github.com/google/go-github/v32/github"
+	"github.com/pkg/errors"
+)
+
+var errLocalMode = errors.New("Github API writes are not available in local mode")
+
+// localGitApi implements forge reads on top of a local git repository, so the bumper
+// can resolve releases without reaching Github. Writes are refused.
+type localGitApi struct {
+	repo *git.Repository
+}
+
+func newLocalGitApi(repo *git.Repository) *localGitApi {
+	return &localGitApi{repo: repo}
+}
+
+func (l localGitApi) listMatchingRefs(owner, repo string, opts *github.ReferenceListOptions) ([]*github.Reference, *github.Response, error) {
+	refsIter, err := l.repo.References()
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "Failed to list local references")
+	}
+
+	var refs []*github.Reference
+	err = refsIter.ForEach(func(ref *plumbing.Reference) error {
+		if ref.Type() == plumbing.HashReference && strings.HasPrefix(ref.Name().String(), opts.Ref) {
+			refs = append(refs, newGithubReference(ref.Name().String(), ref.Hash()))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Github lists references sorted by their name
+	sort.Slice(refs, func(i, j int) bool {
+		return refs[i].GetRef() < refs[j].GetRef()
+	})
+	return refs, nil, nil
+}
+
+func (l localGitApi) listCommits(owner, repo string, opts *github.CommitsListOptions) ([]*github.RepositoryCommit, *github.Response, error) {
+	hash, err := l.resolveBranch(opts.SHA)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	commitsIter, err := l.repo.Log(&git.LogOptions{From: hash})
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "Failed to get commit log of %s", opts.SHA)
+	}
+
+	var commits []*github.RepositoryCommit
+	err = commitsIter.ForEach(func(c *object.Commit) error {
+		if opts.PerPage > 0 && len(commits) == opts.PerPage {
+			return storer.ErrStop
+		}
+		commits = append(commits, &github.RepositoryCommit{SHA: github.String(c.Hash.String())})
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return commits, nil, nil
+}
+
+func (l localGitApi) getBranchRef(owner string, repo string, ref string) (*github.Reference, *github.Response, error) {
+	hash, err := l.resolveBranch(strings.TrimPrefix(ref, "refs/heads/"))
+	if err != nil {
+		return nil, nil, err
+	}
+	return newGithubReference(ref, hash), nil, nil
+}
+
+func (l localGitApi) createBranchRef(owner string, repo string, newRef *github.Reference) (*github.Reference, *github.Response, error) {
+	return nil, nil, errLocalMode
+}
+
+func (l localGitApi) createTree(owner string, repo string, baseTree string, entries []*github.TreeEntry) (*github.Tree, *github.Response, error) {
+	return nil, nil, errLocalMode
+}
+
+func (l localGitApi) getCommit(owner string, repo string, sha string) (*github.Commit, *github.Response, error) {
+	return nil, nil, errLocalMode
+}
+
+func (l localGitApi) createCommit(owner string, repo string, commit *github.Commit) (*github.Commit, *github.Response, error) {
+	return nil, nil, errLocalMode
+}
+
+func (l localGitApi) updateRef(owner string, repo string, ref *github.Reference, force bool) (*github.Reference, *github.Response, error) {
+	return nil, nil, errLocalMode
+}
+
+// listPullRequests returns no PRs, these are not known locally
+func (l localGitApi) listPullRequests(owner string, repo string, branch string) ([]*github.PullRequest, *github.Response, error) {
+	return nil, nil, nil
+}
+
+func (l localGitApi) createPullRequest(owner string, repo string, pull *github.NewPullRequest) (*github.PullRequest, *github.Response, error) {
+	return nil, nil, errLocalMode
+}
+
+// listIssues returns no issues, these are not known locally
+func (l localGitApi) listIssues(owner string, repo string) ([]*github.Issue, *github.Response, error) {
+	return nil, nil, nil
+}
+
+func (l localGitApi) createIssue(owner string, repo string, issue *github.IssueRequest) (*github.Issue, *github.Response, error) {
+	return nil, nil, errLocalMode
+}
+
+// resolveBranch finds the head of a local branch, or of a branch of origin the repo was cloned from
+func (l localGitApi) resolveBranch(branch string) (plumbing.Hash, error) {
+	for _, name := range []plumbing.ReferenceName{plumbing.NewBranchReferenceName(branch), plumbing.NewRemoteReferenceName("origin", branch)} {
+		ref, err := l.repo.Reference(name, true)
+		if err == nil {
+			return ref.Hash(), nil
+		}
+		if err != plumbing.ErrReferenceNotFound {
+			return plumbing.ZeroHash, errors.Wrapf(err, "Failed to get reference %s", name)
+		}
+	}
+	return plumbing.ZeroHash, errors.Errorf("branch %s not found", branch)
+}
+
+func newGithubReference(name string, hash plumbing.Hash) *github.Reference {
+	return &github.Reference{
+		Ref:    github.String(name),
+		Object: &github.GitObject{SHA: github.String(hash.String())},
+	}
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/google/go-github/v32/github"
+)
+
+var _ = Describe("Testing local git api", func() {
+	var (
+		gitComponent *gitComponent
+		cnaoRepo     *gitCnaoRepo
+		repoDir      string
+	)
+	expectedTagCommitMap := make(map[string]string)
+
+	BeforeEach(func() {
+		tempDir, err := ioutil.TempDir("/tmp", "local-api-test")
+		Expect(err).ToNot(HaveOccurred(), "Should create temp dir for component")
+
+		repoDir = filepath.Join(tempDir, "testOwner", "testRepo")
+		os.MkdirAll(repoDir, 0777)
+
+		gitComponent = newFakeGitComponent(newFakeGithubApi(repoDir), repoDir, &component{}, expectedTagCommitMap)
+		gitComponent.forge = newLocalGitApi(gitComponent.gitRepo.repo)
+
+		cnaoRepo = &gitCnaoRepo{
+			configParams: &component{Url: repoDir, Branch: "main"},
+			forge:        newLocalGitApi(gitComponent.gitRepo.repo),
+			gitRepo:      gitComponent.gitRepo,
+		}
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(gitComponent.gitRepo.localDir)
+	})
+
+	type localBumpParams struct {
+		comp            *component
+		currentTagKey   string
+		expectedTagKey  string
+		isBumpExpected  bool
+		shouldReturnErr bool
+	}
+	DescribeTable("resolving a bump of a component without github",
+		func(r localBumpParams) {
+			gitComponent.configParams = r.comp
+			gitComponent.configParams.Url = repoDir
+			gitComponent.configParams.Commit = expectedTagCommitMap[r.currentTagKey]
+
+			By("Getting the current release tag from the local repo")
+			currentReleaseTag, err := gitComponent.getCurrentReleaseTag()
+			Expect(err).ToNot(HaveOccurred(), "should not fail to run getCurrentReleaseTag")
+
+			By("Getting the latest release from the local repo")
+			updatedReleaseTag, updatedReleaseCommit, err := gitComponent.getUpdatedReleaseInfo()
+			if r.shouldReturnErr {
+				Expect(err).To(HaveOccurred(), "should fail to run getUpdatedReleaseInfo")
+				return
+			}
+			Expect(err).ToNot(HaveOccurred(), "should not fail to run getUpdatedReleaseInfo")
+
+			expectedCommit := expectedTagCommitMap[r.expectedTagKey]
+			expectedTag, err := describeHash(repoDir, expectedCommit)
+			Expect(err).ToNot(HaveOccurred(), "should not fail to run describeHash")
+			Expect(updatedReleaseTag).To(Equal(expectedTag), "tag should be same as expected")
+			Expect(updatedReleaseCommit).To(Equal(expectedCommit), "commit should be same as expected")
+
+			By("Checking if bump is needed")
+			isBumpNeeded, err := cnaoRepo.isComponentBumpNeeded(currentReleaseTag, updatedReleaseTag, r.comp.Updatepolicy, "dummy PR title")
+			Expect(err).ToNot(HaveOccurred(), "should not fail to run isComponentBumpNeeded")
+			Expect(isBumpNeeded).To(Equal(r.isBumpExpected), "Expect bump result to be equal to expected")
+		},
+		Entry("Update-policy static: should not bump", localBumpParams{
+			comp:           &component{Updatepolicy: updatePolicyStatic, Branch: "main", Metadata: "v0.0.1"},
+			currentTagKey:  "v0.0.1",
+			expectedTagKey: "v0.0.1",
+			isBumpExpected: false,
+		}),
+		Entry("Update-policy tagged: should bump to the latest tag in main branch", localBumpParams{
+			comp:           &component{Updatepolicy: updatePolicyTagged, Branch: "main", Metadata: "v0.0.1"},
+			currentTagKey:  "v0.0.1",
+			expectedTagKey: "v0.0.2",
+			isBumpExpected: true,
+		}),
+		Entry("Update-policy tagged: should not bump when on the latest tag in main branch", localBumpParams{
+			comp:           &component{Updatepolicy: updatePolicyTagged, Branch: "main", Metadata: "v0.0.2"},
+			currentTagKey:  "v0.0.2",
+			expectedTagKey: "v0.0.2",
+			isBumpExpected: false,
+		}),
+		Entry("Update-policy tagged: should bump to the latest annotated tag in release branch", localBumpParams{
+			comp:           &component{Updatepolicy: updatePolicyTagged, Branch: "release-v1.0.0", Metadata: "v1.0.0"},
+			currentTagKey:  "v1.0.0",
+			expectedTagKey: "v1.0.2",
+			isBumpExpected: true,
+		}),
+		Entry("Update-policy tagged: should fail if unknown branch", localBumpParams{
+			comp:            &component{Updatepolicy: updatePolicyTagged, Branch: "release-v2.0.0", Metadata: "v1.0.0"},
+			currentTagKey:   "v1.0.0",
+			shouldReturnErr: true,
+		}),
+		Entry("Update-policy latest: should resolve latest HEAD in release branch", localBumpParams{
+			comp:           &component{Updatepolicy: updatePolicyLatest, Branch: "release-v1.0.0", Metadata: "v1.0.1"},
+			currentTagKey:  "v1.0.1",
+			expectedTagKey: "dummy_tag_latest_release-v1.0.0",
+			isBumpExpected: true,
+		}),
+	)
+
+	It("should refuse to open a bump PR", func() {
+		_, err := cnaoRepo.createPR("dummy PR title", "bump-branch")
+		Expect(err).To(HaveOccurred(), "Should fail creating PR in local mode")
+
+		_, _, err = cnaoRepo.forge.createBranchRef("testOwner", "testRepo", &github.Reference{})
+		Expect(err).To(MatchError(errLocalMode), "Should fail creating branch in local mode")
+	})
+})
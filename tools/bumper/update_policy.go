@@ -0,0 +1,110 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/coreos/go-semver/semver"
+	"github.com/google/go-github/v32/github"
+	"github.com/pkg/errors"
+)
+
+// updatePolicy is the parsed update-policy of a component. The tagged policy can be limited to a
+// range of versions, e.g. "tagged:~v0.29" or "tagged:^v3".
+type updatePolicy struct {
+	name         string
+	versionRange *versionRange
+}
+
+// versionRange allows versions from min up to limit, limit excluded.
+// "~vX.Y" allows patch releases of X.Y, "~vX" and "^vX[.Y[.Z]]" allow releases of major X.
+type versionRange struct {
+	constraint string
+	min        semver.Version
+	limit      semver.Version
+}
+
+func parseUpdatePolicy(policy string) (updatePolicy, error) {
+	policySections := strings.SplitN(policy, ":", 2)
+	name := policySections[0]
+	switch name {
+	case updatePolicyStatic, updatePolicyTagged, updatePolicyLatest:
+	default:
+		return updatePolicy{}, fmt.Errorf("Error: Update strategy %s not supported", policy)
+	}
+	if len(policySections) == 1 {
+		return updatePolicy{name: name}, nil
+	}
+	constraint := policySections[1]
+
+	if name != updatePolicyTagged {
+		return updatePolicy{}, fmt.Errorf("Error: Update strategy %s does not support version ranges", name)
+	}
+	versionRange, err := parseVersionRange(constraint)
+	if err != nil {
+		return updatePolicy{}, errors.Wrapf(err, "Failed to parse update-policy %s", policy)
+	}
+	return updatePolicy{name: name, versionRange: versionRange}, nil
+}
+
+func parseVersionRange(constraint string) (*versionRange, error) {
+	if len(constraint) < 2 || (constraint[0] != '~' && constraint[0] != '^') {
+		return nil, fmt.Errorf("Error: version range %q should be in ~vX.Y or ^vX format", constraint)
+	}
+
+	version := strings.TrimPrefix(constraint[1:], "v")
+	sectionsNum := len(strings.Split(version, "."))
+	if sectionsNum == 1 {
+		version = version + ".0"
+	}
+	min, err := canonicalizeVersion(version)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to digest version range %s to semver", constraint)
+	}
+	if min.PreRelease != "" || min.Metadata != "" {
+		return nil, fmt.Errorf("Error: version range %q should not carry pre-release or metadata", constraint)
+	}
+
+	limit := semver.Version{Major: min.Major + 1}
+	if constraint[0] == '~' && sectionsNum > 1 {
+		limit = semver.Version{Major: min.Major, Minor: min.Minor + 1}
+	}
+	return &versionRange{constraint: constraint, min: *min, limit: limit}, nil
+}
+
+func (r *versionRange) contains(version *semver.Version) bool {
+	return !version.LessThan(r.min) && version.LessThan(r.limit)
+}
+
+func (r *versionRange) isExceededBy(version *semver.Version) bool {
+	return !version.LessThan(r.limit)
+}
+
+// filterTags keeps tags with a version accepted by filter, sorted by the version. Tags which are
+// not versions are dropped.
+func filterTags(tagRefs []*github.Reference, filter func(*semver.Version) bool) []*github.Reference {
+	type versionedTag struct {
+		ref     *github.Reference
+		version *semver.Version
+	}
+
+	var versionedTags []versionedTag
+	for _, tagRef := range tagRefs {
+		version, err := canonicalizeVersion(strings.TrimPrefix(tagRef.GetRef(), "refs/tags/"))
+		if err != nil || !filter(version) {
+			continue
+		}
+		versionedTags = append(versionedTags, versionedTag{ref: tagRef, version: version})
+	}
+
+	sort.SliceStable(versionedTags, func(i, j int) bool {
+		return versionedTags[i].version.LessThan(*versionedTags[j].version)
+	})
+
+	filteredTags := make([]*github.Reference, 0, len(versionedTags))
+	for _, versionedTag := range versionedTags {
+		filteredTags = append(filteredTags, versionedTag.ref)
+	}
+	return filteredTags
+}
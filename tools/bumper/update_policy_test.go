@@ -0,0 +1,128 @@
+package main
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/coreos/go-semver/semver"
+	"github.com/google/go-github/v32/github"
+)
+
+var _ = Describe("Testing update policies", func() {
+	type parseUpdatePolicyParams struct {
+		policy          string
+		expectedName    string
+		expectedMin     string
+		expectedLimit   string
+		shouldReturnErr bool
+	}
+	DescribeTable("parseUpdatePolicy function",
+		func(p parseUpdatePolicyParams) {
+			policy, err := parseUpdatePolicy(p.policy)
+			if p.shouldReturnErr {
+				Expect(err).To(HaveOccurred(), "should fail to parse update policy")
+				return
+			}
+			Expect(err).ToNot(HaveOccurred(), "should not fail to parse update policy")
+			Expect(policy.name).To(Equal(p.expectedName))
+
+			if p.expectedMin == "" {
+				Expect(policy.versionRange).To(BeNil(), "should not have a version range")
+				return
+			}
+			Expect(policy.versionRange).ToNot(BeNil(), "should have a version range")
+			Expect(policy.versionRange.min).To(Equal(*semver.New(p.expectedMin)))
+			Expect(policy.versionRange.limit).To(Equal(*semver.New(p.expectedLimit)))
+		},
+		Entry("Should parse policy without range", parseUpdatePolicyParams{
+			policy:       "tagged",
+			expectedName: updatePolicyTagged,
+		}),
+		Entry("Should parse tilde range of minor version", parseUpdatePolicyParams{
+			policy:        "tagged:~v0.29",
+			expectedName:  updatePolicyTagged,
+			expectedMin:   "0.29.0",
+			expectedLimit: "0.30.0",
+		}),
+		Entry("Should parse tilde range of patch version", parseUpdatePolicyParams{
+			policy:        "tagged:~v0.29.1",
+			expectedName:  updatePolicyTagged,
+			expectedMin:   "0.29.1",
+			expectedLimit: "0.30.0",
+		}),
+		Entry("Should parse tilde range of major version", parseUpdatePolicyParams{
+			policy:        "tagged:~v3",
+			expectedName:  updatePolicyTagged,
+			expectedMin:   "3.0.0",
+			expectedLimit: "4.0.0",
+		}),
+		Entry("Should parse caret range", parseUpdatePolicyParams{
+			policy:        "tagged:^v3.9",
+			expectedName:  updatePolicyTagged,
+			expectedMin:   "3.9.0",
+			expectedLimit: "4.0.0",
+		}),
+		Entry("Should parse range without v prefix", parseUpdatePolicyParams{
+			policy:        "tagged:^3",
+			expectedName:  updatePolicyTagged,
+			expectedMin:   "3.0.0",
+			expectedLimit: "4.0.0",
+		}),
+		Entry("Should fail on unknown policy", parseUpdatePolicyParams{
+			policy:          "newest",
+			shouldReturnErr: true,
+		}),
+		Entry("Should fail on range of latest policy", parseUpdatePolicyParams{
+			policy:          "latest:^v3",
+			shouldReturnErr: true,
+		}),
+		Entry("Should fail on range of static policy", parseUpdatePolicyParams{
+			policy:          "static:^v3",
+			shouldReturnErr: true,
+		}),
+		Entry("Should fail on unknown range operator", parseUpdatePolicyParams{
+			policy:          "tagged:>=v3",
+			shouldReturnErr: true,
+		}),
+		Entry("Should fail on empty range", parseUpdatePolicyParams{
+			policy:          "tagged:",
+			shouldReturnErr: true,
+		}),
+		Entry("Should fail on range which is not a version", parseUpdatePolicyParams{
+			policy:          "tagged:~vX.Y",
+			shouldReturnErr: true,
+		}),
+	)
+
+	Describe("filterTags function", func() {
+		tagRefs := []*github.Reference{
+			{Ref: github.String("refs/tags/v0.29.10")},
+			{Ref: github.String("refs/tags/v0.29.9")},
+			{Ref: github.String("refs/tags/v0.30.0")},
+			{Ref: github.String("refs/tags/latest")},
+			{Ref: github.String("refs/tags/v0.28.0")},
+			{Ref: github.String("refs/tags/v1.0.0")},
+		}
+		tagNames := func(tagRefs []*github.Reference) []string {
+			names := []string{}
+			for _, tagRef := range tagRefs {
+				names = append(names, tagRef.GetRef())
+			}
+			return names
+		}
+
+		It("should keep tags in range sorted by version", func() {
+			versionRange, err := parseVersionRange("~v0.29")
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(tagNames(filterTags(tagRefs, versionRange.contains))).To(Equal([]string{"refs/tags/v0.29.9", "refs/tags/v0.29.10"}))
+		})
+
+		It("should keep tags above range sorted by version", func() {
+			versionRange, err := parseVersionRange("~v0.29")
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(tagNames(filterTags(tagRefs, versionRange.isExceededBy))).To(Equal([]string{"refs/tags/v0.30.0", "refs/tags/v1.0.0"}))
+		})
+	})
+})
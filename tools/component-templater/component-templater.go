@@ -0,0 +1,63 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+)
+
+func main() {
+	configPath := flag.String("config", "components-templating.yaml", "path to templating rules of components")
+	componentName := flag.String("component", "", "name of the component to generate templates of")
+	sourceDir := flag.String("source-dir", "", "path to a checkout of the component repository")
+	outputDir := flag.String("output-dir", ".", "path to the CNAO repository the templates are written to")
+	flag.Parse()
+
+	if *componentName == "" || *sourceDir == "" {
+		flag.Usage()
+		os.Exit(1)
+	}
+
+	if err := generateTemplates(*configPath, *componentName, *sourceDir, *outputDir); err != nil {
+		log.Fatalf("failed to generate templates of %s: %v", *componentName, err)
+	}
+}
+
+// generateTemplates renders all templates of the component from manifests of its repository
+func generateTemplates(configPath, componentName, sourceDir, outputDir string) error {
+	config, err := readTemplatingConfig(configPath)
+	if err != nil {
+		return err
+	}
+	component, found := config.Components[componentName]
+	if !found {
+		return fmt.Errorf("no templating rules found for component %s", componentName)
+	}
+
+	for _, manifest := range component.Manifests {
+		source := []byte{}
+		if manifest.Source != "" {
+			source, err = os.ReadFile(filepath.Join(sourceDir, manifest.Source))
+			if err != nil {
+				return err
+			}
+		}
+
+		rendered, err := renderManifest(manifest, source)
+		if err != nil {
+			return err
+		}
+
+		output := filepath.Join(outputDir, manifest.Output)
+		if err := os.MkdirAll(filepath.Dir(output), 0755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(output, rendered, 0644); err != nil {
+			return err
+		}
+		log.Printf("generated %s", manifest.Output)
+	}
+	return nil
+}
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+// templatingConfig keeps rules turning upstream manifests of components into CNAO templates
+type templatingConfig struct {
+	Components map[string]componentTemplating `yaml:"components"`
+}
+
+type componentTemplating struct {
+	Manifests []manifestTemplating `yaml:"manifests"`
+}
+
+// manifestTemplating describes how a single template under data/ is generated. The template is
+// composed of Prefix, the objects of Source transformed by their rules (or Source as is if no
+// objects are listed), Content and Suffix.
+type manifestTemplating struct {
+	Output       string        `yaml:"output"`
+	Source       string        `yaml:"source,omitempty"`
+	Objects      []objectRules `yaml:"objects,omitempty"`
+	Prefix       string        `yaml:"prefix,omitempty"`
+	Content      string        `yaml:"content,omitempty"`
+	Suffix       string        `yaml:"suffix,omitempty"`
+	Placeholders []string      `yaml:"placeholders,omitempty"`
+}
+
+// objectRules selects an object of the source manifest by its kind and name, objects which are
+// not selected are dropped
+type objectRules struct {
+	Kind  string `yaml:"kind"`
+	Name  string `yaml:"name"`
+	Rules []rule `yaml:"rules,omitempty"`
+}
+
+// rule changes the value found on Path. Path is a dot separated list of keys and [index]
+// selectors, [+] appends to a list.
+type rule struct {
+	Path string `yaml:"path"`
+	// Template is placed to the manifest as is, unquoted
+	Template string `yaml:"template,omitempty"`
+	// Value is a YAML value placed to the manifest
+	Value yaml.Node `yaml:"value,omitempty"`
+	// Create allows the path to be missing, otherwise the value has to exist upstream
+	Create bool `yaml:"create,omitempty"`
+	// Delete removes the value found on the path
+	Delete bool `yaml:"delete,omitempty"`
+}
+
+func (r rule) hasValue() bool {
+	return r.Value.Kind != 0
+}
+
+func readTemplatingConfig(path string) (*templatingConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	config := &templatingConfig{}
+	if err := yaml.Unmarshal(data, config); err != nil {
+		return nil, fmt.Errorf("failed to parse templating rules %s: %w", path, err)
+	}
+	for name, component := range config.Components {
+		for _, manifest := range component.Manifests {
+			if err := manifest.validate(); err != nil {
+				return nil, fmt.Errorf("invalid templating rules of component %s: %w", name, err)
+			}
+		}
+	}
+	return config, nil
+}
+
+func (m manifestTemplating) validate() error {
+	if m.Output == "" {
+		return fmt.Errorf("manifest output is not set")
+	}
+	if m.Source == "" && len(m.Objects) > 0 {
+		return fmt.Errorf("manifest %s selects objects without a source", m.Output)
+	}
+	for _, object := range m.Objects {
+		for _, r := range object.Rules {
+			actions := 0
+			if r.Template != "" {
+				actions++
+			}
+			if r.hasValue() {
+				actions++
+			}
+			if r.Delete {
+				actions++
+			}
+			if actions != 1 {
+				return fmt.Errorf("rule %s of %s %s in manifest %s has to set exactly one of template, value and delete", r.Path, object.Kind, object.Name, m.Output)
+			}
+		}
+	}
+	return nil
+}
+
+var placeholderRegexp = regexp.MustCompile(`\.[A-Z][A-Za-z0-9]*(\.[A-Z][A-Za-z0-9]*)*`)
+
+// requiredPlaceholders lists template fields a manifest has to reference, the ones declared
+// and the ones introduced by its rules
+func (m manifestTemplating) requiredPlaceholders() []string {
+	found := map[string]bool{}
+	for _, placeholder := range m.Placeholders {
+		found[placeholder] = true
+	}
+	texts := []string{m.Prefix, m.Content, m.Suffix}
+	for _, object := range m.Objects {
+		for _, r := range object.Rules {
+			texts = append(texts, r.Template)
+			if r.hasValue() {
+				texts = append(texts, scalarValues(&r.Value)...)
+			}
+		}
+	}
+	for _, text := range texts {
+		for _, placeholder := range templatePlaceholders(text) {
+			found[placeholder] = true
+		}
+	}
+
+	placeholders := []string{}
+	for placeholder := range found {
+		placeholders = append(placeholders, placeholder)
+	}
+	sort.Strings(placeholders)
+	return placeholders
+}
+
+// templatePlaceholders lists fields referenced by template actions in text
+func templatePlaceholders(text string) []string {
+	found := map[string]bool{}
+	placeholders := []string{}
+	for _, action := range templateActionRegexp.FindAllString(text, -1) {
+		for _, placeholder := range placeholderRegexp.FindAllString(action, -1) {
+			if !found[placeholder] {
+				found[placeholder] = true
+				placeholders = append(placeholders, placeholder)
+			}
+		}
+	}
+	sort.Strings(placeholders)
+	return placeholders
+}
+
+func scalarValues(node *yaml.Node) []string {
+	if node.Kind == yaml.ScalarNode {
+		return []string{node.Value}
+	}
+	values := []string{}
+	for _, child := range node.Content {
+		values = append(values, scalarValues(child)...)
+	}
+	return values
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+const repoDir = "../.."
+
+var _ = Describe("Testing templating rules of components", func() {
+	config, err := readTemplatingConfig(filepath.Join(repoDir, "components-templating.yaml"))
+
+	It("should be valid", func() {
+		Expect(err).ToNot(HaveOccurred())
+		Expect(config.Components).ToNot(BeEmpty())
+	})
+
+	if err != nil {
+		return
+	}
+	for name, component := range config.Components {
+		for _, manifest := range component.Manifests {
+			name, manifest := name, manifest
+
+			It("should keep placeholders needed by renderers of "+name+" in "+manifest.Output, func() {
+				template, err := os.ReadFile(filepath.Join(repoDir, manifest.Output))
+				Expect(err).ToNot(HaveOccurred())
+
+				required := manifest.requiredPlaceholders()
+				Expect(templatePlaceholders(string(template))).To(ContainElements(required), "the template lost placeholders introduced by the rules")
+				Expect(required).To(ContainElements(templatePlaceholders(string(template))), "placeholders of the template would be lost once regenerated, add them to the rules")
+			})
+		}
+	}
+})
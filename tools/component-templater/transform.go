@@ -0,0 +1,331 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+var templateActionRegexp = regexp.MustCompile(`{{.*?}}`)
+
+// placeholders replaces template actions with plain tokens, so manifests carrying templates can
+// be handled as YAML, and puts the actions back once the YAML is encoded
+type placeholders struct {
+	actions []string
+}
+
+func (p *placeholders) token(action string) string {
+	p.actions = append(p.actions, action)
+	return fmt.Sprintf("CNAO_TEMPLATE_ACTION_%d_", len(p.actions)-1)
+}
+
+func (p *placeholders) mask(text string) string {
+	return templateActionRegexp.ReplaceAllStringFunc(text, p.token)
+}
+
+func (p *placeholders) maskNode(node *yaml.Node) {
+	if node.Kind == yaml.ScalarNode {
+		node.Value = p.mask(node.Value)
+	}
+	for _, child := range node.Content {
+		p.maskNode(child)
+	}
+}
+
+func (p *placeholders) restore(text string) string {
+	for i := len(p.actions) - 1; i >= 0; i-- {
+		text = strings.ReplaceAll(text, fmt.Sprintf("CNAO_TEMPLATE_ACTION_%d_", i), p.actions[i])
+	}
+	return text
+}
+
+// renderManifest generates the template described by manifest from the source manifest
+func renderManifest(manifest manifestTemplating, source []byte) ([]byte, error) {
+	out := &bytes.Buffer{}
+	out.WriteString(manifest.Prefix)
+
+	if len(manifest.Objects) > 0 {
+		objects, err := transformObjects(manifest.Objects, source)
+		if err != nil {
+			return nil, err
+		}
+		out.Write(objects)
+	} else {
+		out.Write(source)
+	}
+
+	out.WriteString(manifest.Content)
+	out.WriteString(manifest.Suffix)
+
+	rendered := out.Bytes()
+	for _, placeholder := range manifest.requiredPlaceholders() {
+		if !containsPlaceholder(rendered, placeholder) {
+			return nil, fmt.Errorf("manifest %s does not reference %s", manifest.Output, placeholder)
+		}
+	}
+	return rendered, nil
+}
+
+// transformObjects selects objects of the source manifest, applies their rules and encodes them
+// in the selected order
+func transformObjects(selected []objectRules, source []byte) ([]byte, error) {
+	p := &placeholders{}
+	documents, err := decodeDocuments([]byte(p.mask(string(source))))
+	if err != nil {
+		return nil, err
+	}
+
+	out := &bytes.Buffer{}
+	for _, object := range selected {
+		document := findObject(documents, object.Kind, object.Name)
+		if document == nil {
+			return nil, fmt.Errorf("%s %s is not found in the source manifest", object.Kind, object.Name)
+		}
+		for _, r := range object.Rules {
+			if err := applyRule(document, r, p); err != nil {
+				return nil, fmt.Errorf("failed to apply rule %s on %s %s: %w", r.Path, object.Kind, object.Name, err)
+			}
+		}
+
+		out.WriteString("---\n")
+		encoder := yaml.NewEncoder(out)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(document); err != nil {
+			return nil, err
+		}
+		if err := encoder.Close(); err != nil {
+			return nil, err
+		}
+	}
+	return []byte(p.restore(out.String())), nil
+}
+
+func decodeDocuments(source []byte) ([]*yaml.Node, error) {
+	decoder := yaml.NewDecoder(bytes.NewReader(source))
+	documents := []*yaml.Node{}
+	for {
+		document := &yaml.Node{}
+		if err := decoder.Decode(document); err != nil {
+			if errors.Is(err, io.EOF) {
+				return documents, nil
+			}
+			return nil, fmt.Errorf("failed to parse the source manifest: %w", err)
+		}
+		if len(document.Content) > 0 && document.Content[0].Kind == yaml.MappingNode {
+			documents = append(documents, document.Content[0])
+		}
+	}
+}
+
+func findObject(documents []*yaml.Node, kind, name string) *yaml.Node {
+	for _, document := range documents {
+		documentKind := mappingValue(document, "kind")
+		metadata := mappingValue(document, "metadata")
+		if documentKind == nil || metadata == nil {
+			continue
+		}
+		documentName := mappingValue(metadata, "name")
+		if documentKind.Value == kind && documentName != nil && documentName.Value == name {
+			return document
+		}
+	}
+	return nil
+}
+
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+type pathElement struct {
+	key    string
+	index  int
+	append bool
+}
+
+var pathElementRegexp = regexp.MustCompile(`^([^\[\]]*)((?:\[(?:\d+|\+)\])*)$`)
+var pathIndexRegexp = regexp.MustCompile(`\[(\d+|\+)\]`)
+
+func parsePath(path string) ([]pathElement, error) {
+	elements := []pathElement{}
+	for _, part := range strings.Split(path, ".") {
+		match := pathElementRegexp.FindStringSubmatch(part)
+		if match == nil || (match[1] == "" && match[2] == "") {
+			return nil, fmt.Errorf("invalid path %q", path)
+		}
+		if match[1] != "" {
+			elements = append(elements, pathElement{key: match[1], index: -1})
+		}
+		for _, index := range pathIndexRegexp.FindAllStringSubmatch(match[2], -1) {
+			if index[1] == "+" {
+				elements = append(elements, pathElement{index: -1, append: true})
+				continue
+			}
+			i, err := strconv.Atoi(index[1])
+			if err != nil {
+				return nil, err
+			}
+			elements = append(elements, pathElement{index: i})
+		}
+	}
+	return elements, nil
+}
+
+func applyRule(document *yaml.Node, r rule, p *placeholders) error {
+	path, err := parsePath(r.Path)
+	if err != nil {
+		return err
+	}
+
+	parent := document
+	for i, element := range path[:len(path)-1] {
+		child, err := child(parent, element, path[i+1], r.Create && !r.Delete)
+		if err != nil {
+			return err
+		}
+		parent = child
+	}
+	last := path[len(path)-1]
+
+	if r.Delete {
+		return deleteChild(parent, last)
+	}
+
+	var value *yaml.Node
+	if r.hasValue() {
+		value = deepCopy(&r.Value)
+		p.maskNode(value)
+	} else {
+		value = &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: p.token(r.Template)}
+	}
+	return setChild(parent, last, value, r.Create)
+}
+
+// child returns the node found on element of parent, if it is missing and create is set, a new
+// node fitting next element is added
+func child(parent *yaml.Node, element, next pathElement, create bool) (*yaml.Node, error) {
+	existing, err := findChild(parent, element)
+	if err != nil || existing != nil {
+		return existing, err
+	}
+	if !create {
+		return nil, fmt.Errorf("%s is not found", element)
+	}
+
+	node := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	if next.key == "" {
+		node = &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+	}
+	return node, setChild(parent, element, node, true)
+}
+
+func findChild(parent *yaml.Node, element pathElement) (*yaml.Node, error) {
+	if element.key != "" {
+		if parent.Kind != yaml.MappingNode {
+			return nil, fmt.Errorf("%s is not a mapping", element)
+		}
+		return mappingValue(parent, element.key), nil
+	}
+	if parent.Kind != yaml.SequenceNode {
+		return nil, fmt.Errorf("%s is not a list item", element)
+	}
+	if element.append || element.index >= len(parent.Content) {
+		return nil, nil
+	}
+	return parent.Content[element.index], nil
+}
+
+func setChild(parent *yaml.Node, element pathElement, value *yaml.Node, create bool) error {
+	if element.key != "" {
+		if parent.Kind != yaml.MappingNode {
+			return fmt.Errorf("%s is not a mapping", element)
+		}
+		for i := 0; i+1 < len(parent.Content); i += 2 {
+			if parent.Content[i].Value == element.key {
+				parent.Content[i+1] = value
+				return nil
+			}
+		}
+		if !create {
+			return fmt.Errorf("%s is not found", element)
+		}
+		key := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: element.key}
+		parent.Content = append(parent.Content, key, value)
+		return nil
+	}
+
+	if parent.Kind != yaml.SequenceNode {
+		return fmt.Errorf("%s is not a list item", element)
+	}
+	if !element.append && element.index < len(parent.Content) {
+		parent.Content[element.index] = value
+		return nil
+	}
+	if !element.append && !(create && element.index == len(parent.Content)) {
+		return fmt.Errorf("%s is not found", element)
+	}
+	parent.Content = append(parent.Content, value)
+	return nil
+}
+
+func deleteChild(parent *yaml.Node, element pathElement) error {
+	if element.key != "" {
+		if parent.Kind == yaml.MappingNode {
+			for i := 0; i+1 < len(parent.Content); i += 2 {
+				if parent.Content[i].Value == element.key {
+					parent.Content = append(parent.Content[:i], parent.Content[i+2:]...)
+					return nil
+				}
+			}
+		}
+		return fmt.Errorf("%s is not found", element)
+	}
+	if parent.Kind != yaml.SequenceNode || element.append || element.index >= len(parent.Content) {
+		return fmt.Errorf("%s is not found", element)
+	}
+	parent.Content = append(parent.Content[:element.index], parent.Content[element.index+1:]...)
+	return nil
+}
+
+func (e pathElement) String() string {
+	switch {
+	case e.key != "":
+		return e.key
+	case e.append:
+		return "[+]"
+	default:
+		return fmt.Sprintf("[%d]", e.index)
+	}
+}
+
+func deepCopy(node *yaml.Node) *yaml.Node {
+	copied := *node
+	copied.Content = make([]*yaml.Node, len(node.Content))
+	for i, child := range node.Content {
+		copied.Content[i] = deepCopy(child)
+	}
+	return &copied
+}
+
+// containsPlaceholder checks whether a template action of manifest references the placeholder
+func containsPlaceholder(manifest []byte, placeholder string) bool {
+	for _, found := range templatePlaceholders(string(manifest)) {
+		if found == placeholder {
+			return true
+		}
+	}
+	return false
+}
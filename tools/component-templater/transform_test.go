@@ -0,0 +1,203 @@
+package main
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"gopkg.in/yaml.v3"
+	appsv1 "k8s.io/api/apps/v1"
+	sigsyaml "sigs.k8s.io/yaml"
+)
+
+const upstreamManifest = `---
+apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: marker
+  namespace: kube-system
+---
+apiVersion: apps/v1
+kind: DaemonSet
+metadata:
+  name: marker
+  namespace: kube-system
+spec:
+  template:
+    spec:
+      nodeSelector:
+        kubernetes.io/arch: amd64
+      containers:
+        - name: marker
+          image: quay.io/kubevirt/marker:v0.1.0
+          args: ["--log-level", "{{ .LogLevel }}"]
+          resources:
+            limits:
+              cpu: 100m
+      volumes:
+        - name: cnibin
+          hostPath:
+            path: /opt/cni/bin
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: unused
+`
+
+var _ = Describe("Testing manifest templating", func() {
+	parseRules := func(rules string) manifestTemplating {
+		manifest := manifestTemplating{}
+		Expect(yaml.Unmarshal([]byte(rules), &manifest)).To(Succeed())
+		Expect(manifest.validate()).To(Succeed())
+		return manifest
+	}
+
+	It("should select objects in the requested order and apply their rules", func() {
+		manifest := parseRules(`
+output: data/marker/001-marker.yaml
+source: upstream.yaml
+objects:
+- kind: DaemonSet
+  name: marker
+  rules:
+  - path: metadata.namespace
+    template: '{{ .Namespace }}'
+  - path: spec.template.spec.containers[0].image
+    template: '{{ .MarkerImage }}'
+  - path: spec.template.spec.nodeSelector
+    template: '{{ toYaml .Placement.NodeSelector | nindent 8 }}'
+  - path: spec.template.spec.affinity
+    template: '{{ toYaml .Placement.Affinity | nindent 8 }}'
+    create: true
+  - path: spec.template.spec.containers[0].resources.limits
+    delete: true
+  - path: spec.template.spec.volumes[0].hostPath.path
+    value: "{{ .CNIBinDir }}"
+  - path: spec.template.spec.containers[+]
+    value:
+      name: sidecar
+      image: "{{ .MarkerImage }}"
+- kind: ServiceAccount
+  name: marker
+  rules:
+  - path: metadata.namespace
+    template: '{{ .Namespace }}'
+suffix: |
+  {{ if .EnableSCC }}
+  {{ end }}
+`)
+		rendered, err := renderManifest(manifest, []byte(upstreamManifest))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(rendered)).To(Equal(`---
+apiVersion: apps/v1
+kind: DaemonSet
+metadata:
+  name: marker
+  namespace: {{ .Namespace }}
+spec:
+  template:
+    spec:
+      nodeSelector: {{ toYaml .Placement.NodeSelector | nindent 8 }}
+      containers:
+        - name: marker
+          image: {{ .MarkerImage }}
+          args: ["--log-level", "{{ .LogLevel }}"]
+          resources: {}
+        - name: sidecar
+          image: "{{ .MarkerImage }}"
+      volumes:
+        - name: cnibin
+          hostPath:
+            path: "{{ .CNIBinDir }}"
+      affinity: {{ toYaml .Placement.Affinity | nindent 8 }}
+---
+apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: marker
+  namespace: {{ .Namespace }}
+{{ if .EnableSCC }}
+{{ end }}
+`))
+		Expect(manifest.requiredPlaceholders()).To(Equal([]string{".CNIBinDir", ".EnableSCC", ".MarkerImage", ".Namespace", ".Placement.Affinity", ".Placement.NodeSelector"}))
+	})
+
+	It("should keep the source as is when no objects are selected", func() {
+		manifest := parseRules(`
+output: data/marker/000-ns.yaml
+source: upstream.yaml
+prefix: |
+  {{ if .EnableSCC }}
+suffix: |
+  {{ end }}
+placeholders:
+- .Namespace
+`)
+		rendered, err := renderManifest(manifest, []byte("name: {{ .Namespace }}\n"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(rendered)).To(Equal("{{ if .EnableSCC }}\nname: {{ .Namespace }}\n{{ end }}\n"))
+	})
+
+	It("should produce a valid manifest once the template is rendered", func() {
+		manifest := parseRules(`
+output: data/marker/001-marker.yaml
+source: upstream.yaml
+objects:
+- kind: DaemonSet
+  name: marker
+  rules:
+  - path: spec.template.spec.containers[0].args
+    template: '{{ toYaml .Args | nindent 12 }}'
+`)
+		rendered, err := renderManifest(manifest, []byte(upstreamManifest))
+		Expect(err).ToNot(HaveOccurred())
+
+		daemonSet := &appsv1.DaemonSet{}
+		filled := templateActionRegexp.ReplaceAll(rendered, []byte(`["--verbose"]`))
+		Expect(sigsyaml.Unmarshal(filled, daemonSet)).To(Succeed())
+		Expect(daemonSet.Spec.Template.Spec.Containers[0].Args).To(Equal([]string{"--verbose"}))
+	})
+
+	DescribeTable("should fail when upstream manifest does not match the rules",
+		func(rules string) {
+			_, err := renderManifest(parseRules(rules), []byte(upstreamManifest))
+			Expect(err).To(HaveOccurred())
+		},
+		Entry("missing object", `
+output: out.yaml
+source: upstream.yaml
+objects:
+- kind: DaemonSet
+  name: missing
+`),
+		Entry("missing path without create", `
+output: out.yaml
+source: upstream.yaml
+objects:
+- kind: DaemonSet
+  name: marker
+  rules:
+  - path: spec.template.spec.affinity
+    template: '{{ .Affinity }}'
+`),
+		Entry("missing list item", `
+output: out.yaml
+source: upstream.yaml
+objects:
+- kind: DaemonSet
+  name: marker
+  rules:
+  - path: spec.template.spec.containers[3].image
+    template: '{{ .Image }}'
+`),
+		Entry("missing declared placeholder", `
+output: out.yaml
+source: upstream.yaml
+objects:
+- kind: ServiceAccount
+  name: marker
+placeholders:
+- .Namespace
+`),
+	)
+})
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+	"strings"
+
+	apiext "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"sigs.k8s.io/controller-tools/pkg/crd"
+	"sigs.k8s.io/controller-tools/pkg/genall"
+	"sigs.k8s.io/controller-tools/pkg/markers"
+)
+
+// XValidation marks a field or a type with a CEL validation rule. The marker is available in
+// newer controller-tools, this generator provides it until controller-tools is bumped.
+type XValidation struct {
+	Rule    string
+	Message string `marker:",optional"`
+}
+
+// ApplyToSchema implements crd.SchemaMarker
+func (m XValidation) ApplyToSchema(schema *apiext.JSONSchemaProps) error {
+	schema.XValidations = append(schema.XValidations, apiext.ValidationRule{
+		Rule:    m.Rule,
+		Message: m.Message,
+	})
+	return nil
+}
+
+// generator generates CRDs the same way as controller-gen crd generator, extended with
+// markers not available in the vendored controller-tools
+type generator struct {
+	crd.Generator
+}
+
+// RegisterMarkers implements genall.Generator
+func (g generator) RegisterMarkers(into *markers.Registry) error {
+	if err := g.Generator.RegisterMarkers(into); err != nil {
+		return err
+	}
+	for _, target := range []markers.TargetType{markers.DescribesField, markers.DescribesType} {
+		definition, err := markers.MakeDefinition("kubebuilder:validation:XValidation", target, XValidation{})
+		if err != nil {
+			return err
+		}
+		if err := into.Register(definition); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func main() {
+	paths := flag.String("paths", "./pkg/apis/networkaddonsoperator/v1,./pkg/apis/networkaddonsoperator/v1alpha1", "Comma separated packages with API types the CRDs are generated from")
+	outputDir := flag.String("output-dir", "pkg/components/crd", "Directory the CRDs are written to")
+	flag.Parse()
+
+	var gen genall.Generator = generator{}
+	runtime, err := genall.Generators{&gen}.ForRoots(strings.Split(*paths, ",")...)
+	if err != nil {
+		log.Fatalf("failed to load API packages: %v", err)
+	}
+	runtime.OutputRules = genall.OutputRules{Default: genall.OutputToDirectory(*outputDir)}
+
+	if hadErrors := runtime.Run(); hadErrors {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+const cosignSignatureAnnotation = "dev.cosignproject.cosign/signature"
+
+// cosignPayload is the simple signing payload cosign signs, only the fields checked here are kept
+type cosignPayload struct {
+	Critical struct {
+		Image struct {
+			DockerManifestDigest string `json:"docker-manifest-digest"`
+		} `json:"image"`
+	} `json:"critical"`
+}
+
+// readPublicKey reads a PEM encoded public key, as written by cosign generate-key-pair
+func readPublicKey(path string) (crypto.PublicKey, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(content)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM encoded key found in %s", path)
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse public key %s: %w", path, err)
+	}
+	return key, nil
+}
+
+// verifyCosignSignature checks that the image digest is signed by the key. Signatures are looked up the
+// way cosign stores them, as layers of the sha256-<hex>.sig tag of the image repository. Keyless
+// signatures are not supported.
+func (r *registryClient) verifyCosignSignature(name imageName, imageDigest digest.Digest, key crypto.PublicKey) error {
+	signatureTag := fmt.Sprintf("%s-%s.sig", imageDigest.Algorithm(), imageDigest.Hex())
+	body, _, err := r.getManifest(name, signatureTag)
+	if err != nil {
+		if errors.Is(err, errManifestNotFound) {
+			return fmt.Errorf("no cosign signature found for %s", imageDigest)
+		}
+		return err
+	}
+	manifest := ocispec.Manifest{}
+	if err := json.Unmarshal(body, &manifest); err != nil {
+		return fmt.Errorf("failed to parse signature manifest of %s: %w", imageDigest, err)
+	}
+
+	var failures []error
+	for _, layer := range manifest.Layers {
+		if err := r.verifySignatureLayer(name, imageDigest, layer, key); err != nil {
+			failures = append(failures, err)
+			continue
+		}
+		return nil
+	}
+	return fmt.Errorf("no valid cosign signature found for %s: %v", imageDigest, failures)
+}
+
+func (r *registryClient) verifySignatureLayer(name imageName, imageDigest digest.Digest, layer ocispec.Descriptor, key crypto.PublicKey) error {
+	signature, err := base64.StdEncoding.DecodeString(layer.Annotations[cosignSignatureAnnotation])
+	if err != nil || len(signature) == 0 {
+		return fmt.Errorf("layer %s carries no signature", layer.Digest)
+	}
+
+	payload, err := r.getBlob(name, layer.Digest)
+	if err != nil {
+		return err
+	}
+	if err := verifySignature(key, payload, signature); err != nil {
+		return fmt.Errorf("layer %s: %w", layer.Digest, err)
+	}
+
+	signed := cosignPayload{}
+	if err := json.Unmarshal(payload, &signed); err != nil {
+		return fmt.Errorf("failed to parse payload of layer %s: %w", layer.Digest, err)
+	}
+	if signed.Critical.Image.DockerManifestDigest != imageDigest.String() {
+		return fmt.Errorf("layer %s signs %s", layer.Digest, signed.Critical.Image.DockerManifestDigest)
+	}
+	return nil
+}
+
+func verifySignature(key crypto.PublicKey, payload, signature []byte) error {
+	hash := sha256.Sum256(payload)
+	switch k := key.(type) {
+	case *ecdsa.PublicKey:
+		if !ecdsa.VerifyASN1(k, hash[:], signature) {
+			return errors.New("invalid signature")
+		}
+	case *rsa.PublicKey:
+		if err := rsa.VerifyPKCS1v15(k, crypto.SHA256, hash[:], signature); err != nil {
+			return errors.New("invalid signature")
+		}
+	case ed25519.PublicKey:
+		if !ed25519.Verify(k, payload, signature) {
+			return errors.New("invalid signature")
+		}
+	default:
+		return fmt.Errorf("unsupported key type %T", key)
+	}
+	return nil
+}
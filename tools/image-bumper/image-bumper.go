@@ -0,0 +1,104 @@
+package main
+
+import (
+	"crypto"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+)
+
+func main() {
+	image := flag.String("image", "", "image to bump, without tag or digest, e.g. quay.io/kubevirt/ovs-cni-plugin")
+	tag := flag.String("tag", "", "tag of the image to resolve")
+	platforms := flag.String("platforms", "linux/amd64", "comma separated list of platforms the image must be available for")
+	cosignKey := flag.String("cosign-key", "", "path to a cosign public key, when set the image signature is verified")
+	allowMissing := flag.Bool("allow-missing", false, "refer to the image by tag if the tag is not found in the registry")
+	componentsFile := flag.String("components-file", "pkg/components/components.go", "path to the go file with default images of components")
+	releaseFile := flag.String("release-file", "test/releases/99.0.0.go", "path to the go file with containers of the release, empty to leave the release untouched")
+	flag.Parse()
+
+	if *image == "" || *tag == "" {
+		flag.Usage()
+		os.Exit(1)
+	}
+
+	opts, err := newBumpOptions(*platforms, *cosignKey, *allowMissing)
+	if err != nil {
+		log.Fatalf("invalid arguments: %v", err)
+	}
+
+	reference, err := resolveImageReference(newRegistryClient(http.DefaultClient), *image, *tag, opts)
+	if err != nil {
+		log.Fatalf("failed to resolve %s:%s: %v", *image, *tag, err)
+	}
+
+	files := []imageReferenceFile{{path: *componentsFile, constantsOnly: true}}
+	if *releaseFile != "" {
+		files = append(files, imageReferenceFile{path: *releaseFile})
+	}
+	if err := rewriteImageReferences(*image, reference, files); err != nil {
+		log.Fatalf("failed to update references of %s: %v", *image, err)
+	}
+	log.Printf("updated %s to %s", *image, reference)
+}
+
+type bumpOptions struct {
+	platforms    []platform
+	cosignKey    crypto.PublicKey
+	allowMissing bool
+}
+
+func newBumpOptions(platforms, cosignKeyPath string, allowMissing bool) (*bumpOptions, error) {
+	opts := &bumpOptions{allowMissing: allowMissing}
+	for _, text := range strings.Split(platforms, ",") {
+		p, err := parsePlatform(strings.TrimSpace(text))
+		if err != nil {
+			return nil, err
+		}
+		opts.platforms = append(opts.platforms, p)
+	}
+
+	if cosignKeyPath != "" {
+		key, err := readPublicKey(cosignKeyPath)
+		if err != nil {
+			return nil, err
+		}
+		opts.cosignKey = key
+	}
+	return opts, nil
+}
+
+// resolveImageReference returns the reference of the image tag by digest, after checking the image is
+// available for all the platforms and, if a key is given, signed by it
+func resolveImageReference(registry *registryClient, image, tag string, opts *bumpOptions) (string, error) {
+	name, err := parseImageName(image)
+	if err != nil {
+		return "", err
+	}
+
+	resolved, err := registry.resolve(name, tag)
+	if err != nil {
+		if opts.allowMissing && errors.Is(err, errManifestNotFound) {
+			log.Printf("tag %s of %s not found, referring to the image by tag", tag, image)
+			return fmt.Sprintf("%s:%s", image, tag), nil
+		}
+		return "", err
+	}
+
+	if err := resolved.checkPlatforms(opts.platforms); err != nil {
+		return "", err
+	}
+
+	if opts.cosignKey != nil {
+		if err := registry.verifyCosignSignature(name, resolved.digest, opts.cosignKey); err != nil {
+			return "", err
+		}
+		log.Printf("verified cosign signature of %s@%s", image, resolved.digest)
+	}
+
+	return fmt.Sprintf("%s@%s", image, resolved.digest), nil
+}
@@ -0,0 +1,304 @@
+package main
+
+import (
+	_ "crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+const (
+	mediaTypeDockerManifestList = "application/vnd.docker.distribution.manifest.list.v2+json"
+	mediaTypeDockerManifest     = "application/vnd.docker.distribution.manifest.v2+json"
+)
+
+var manifestMediaTypes = []string{
+	ocispec.MediaTypeImageIndex,
+	mediaTypeDockerManifestList,
+	ocispec.MediaTypeImageManifest,
+	mediaTypeDockerManifest,
+}
+
+var errManifestNotFound = errors.New("manifest not found")
+
+// imageName is an image reference without a tag or digest, e.g. quay.io/kubevirt/ovs-cni-plugin
+type imageName struct {
+	registry   string
+	repository string
+}
+
+func parseImageName(image string) (imageName, error) {
+	if strings.ContainsAny(image, "@") || strings.Contains(image[strings.LastIndex(image, "/")+1:], ":") {
+		return imageName{}, fmt.Errorf("image %s should not carry a tag or digest", image)
+	}
+
+	parts := strings.SplitN(image, "/", 2)
+	if len(parts) == 1 || !(strings.ContainsAny(parts[0], ".:") || parts[0] == "localhost") {
+		parts = []string{"docker.io", image}
+	}
+	name := imageName{registry: parts[0], repository: parts[1]}
+	if name.registry == "docker.io" {
+		name.registry = "registry-1.docker.io"
+		if !strings.Contains(name.repository, "/") {
+			name.repository = "library/" + name.repository
+		}
+	}
+	return name, nil
+}
+
+// platform is an os/architecture[/variant] pair, e.g. linux/arm64
+type platform struct {
+	os           string
+	architecture string
+	variant      string
+}
+
+func parsePlatform(text string) (platform, error) {
+	parts := strings.Split(text, "/")
+	if len(parts) < 2 || len(parts) > 3 || parts[0] == "" || parts[1] == "" {
+		return platform{}, fmt.Errorf("platform %q should be in os/architecture[/variant] format", text)
+	}
+	p := platform{os: parts[0], architecture: parts[1]}
+	if len(parts) == 3 {
+		p.variant = parts[2]
+	}
+	return p, nil
+}
+
+func (p platform) String() string {
+	if p.variant != "" {
+		return p.os + "/" + p.architecture + "/" + p.variant
+	}
+	return p.os + "/" + p.architecture
+}
+
+// matches checks whether an image built for os, architecture and variant runs on the platform.
+// A platform without a variant accepts any variant.
+func (p platform) matches(os, architecture, variant string) bool {
+	return p.os == os && p.architecture == architecture && (p.variant == "" || p.variant == variant)
+}
+
+// registryClient talks to container registries using the Docker Registry HTTP API V2, anonymously
+type registryClient struct {
+	client *http.Client
+	// tokens keeps bearer tokens per registry and repository
+	tokens map[string]string
+}
+
+func newRegistryClient(client *http.Client) *registryClient {
+	return &registryClient{client: client, tokens: map[string]string{}}
+}
+
+// resolvedImage is an image tag resolved to the digest of its manifest list, or of its manifest for
+// single platform images
+type resolvedImage struct {
+	digest    digest.Digest
+	platforms []platform
+}
+
+// resolve gets the digest of the tag and the platforms the image is available for
+func (r *registryClient) resolve(name imageName, tag string) (*resolvedImage, error) {
+	body, mediaType, err := r.getManifest(name, tag)
+	if err != nil {
+		return nil, err
+	}
+	resolved := &resolvedImage{digest: digest.FromBytes(body)}
+
+	switch mediaType {
+	case ocispec.MediaTypeImageIndex, mediaTypeDockerManifestList:
+		index := ocispec.Index{}
+		if err := json.Unmarshal(body, &index); err != nil {
+			return nil, fmt.Errorf("failed to parse manifest list of %s:%s: %w", name.repository, tag, err)
+		}
+		for _, manifest := range index.Manifests {
+			if manifest.Platform != nil {
+				resolved.platforms = append(resolved.platforms, platform{os: manifest.Platform.OS, architecture: manifest.Platform.Architecture, variant: manifest.Platform.Variant})
+			}
+		}
+	case ocispec.MediaTypeImageManifest, mediaTypeDockerManifest:
+		manifest := ocispec.Manifest{}
+		if err := json.Unmarshal(body, &manifest); err != nil {
+			return nil, fmt.Errorf("failed to parse manifest of %s:%s: %w", name.repository, tag, err)
+		}
+		configBlob, err := r.getBlob(name, manifest.Config.Digest)
+		if err != nil {
+			return nil, err
+		}
+		config := ocispec.Image{}
+		if err := json.Unmarshal(configBlob, &config); err != nil {
+			return nil, fmt.Errorf("failed to parse image config of %s:%s: %w", name.repository, tag, err)
+		}
+		resolved.platforms = []platform{{os: config.OS, architecture: config.Architecture}}
+	default:
+		return nil, fmt.Errorf("manifest of %s:%s has unsupported media type %q", name.repository, tag, mediaType)
+	}
+
+	return resolved, nil
+}
+
+// checkPlatforms verifies the image is available for all the platforms
+func (i *resolvedImage) checkPlatforms(required []platform) error {
+	var missing []string
+	for _, requiredPlatform := range required {
+		found := false
+		for _, available := range i.platforms {
+			if requiredPlatform.matches(available.os, available.architecture, available.variant) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			missing = append(missing, requiredPlatform.String())
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("image %s is not available for platforms %s", i.digest, strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+func (r *registryClient) getManifest(name imageName, reference string) ([]byte, string, error) {
+	header := http.Header{"Accept": {strings.Join(manifestMediaTypes, ", ")}}
+	resp, body, err := r.get(name, "manifests/"+reference, header)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if expected := resp.Header.Get("Docker-Content-Digest"); expected != "" && digest.Digest(expected).Algorithm() == digest.Canonical {
+		if actual := digest.FromBytes(body); actual.String() != expected {
+			return nil, "", fmt.Errorf("manifest %s:%s has digest %s, registry reports %s", name.repository, reference, actual, expected)
+		}
+	}
+
+	mediaType := resp.Header.Get("Content-Type")
+	if i := strings.Index(mediaType, ";"); i >= 0 {
+		mediaType = mediaType[:i]
+	}
+	if mediaType == "" || mediaType == "application/json" {
+		versioned := struct {
+			MediaType string `json:"mediaType"`
+		}{}
+		json.Unmarshal(body, &versioned)
+		mediaType = versioned.MediaType
+	}
+	return body, strings.TrimSpace(mediaType), nil
+}
+
+func (r *registryClient) getBlob(name imageName, blobDigest digest.Digest) ([]byte, error) {
+	if err := blobDigest.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid blob digest %s: %w", blobDigest, err)
+	}
+	_, body, err := r.get(name, "blobs/"+blobDigest.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	if !verifyDigest(blobDigest, body) {
+		return nil, fmt.Errorf("blob %s of %s does not match its digest", blobDigest, name.repository)
+	}
+	return body, nil
+}
+
+func verifyDigest(expected digest.Digest, content []byte) bool {
+	verifier := expected.Verifier()
+	verifier.Write(content)
+	return verifier.Verified()
+}
+
+// get requests a resource of the repository, authenticating with an anonymous bearer token when
+// the registry asks for it
+func (r *registryClient) get(name imageName, resource string, header http.Header) (*http.Response, []byte, error) {
+	requestUrl := fmt.Sprintf("https://%s/v2/%s/%s", name.registry, name.repository, resource)
+	tokenKey := name.registry + "/" + name.repository
+
+	for attempt := 0; ; attempt++ {
+		req, err := http.NewRequest(http.MethodGet, requestUrl, nil)
+		if err != nil {
+			return nil, nil, err
+		}
+		for key, values := range header {
+			req.Header[key] = values
+		}
+		if token := r.tokens[tokenKey]; token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+
+		resp, err := r.client.Do(req)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get %s: %w", requestUrl, err)
+		}
+		body, err := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read %s: %w", requestUrl, err)
+		}
+
+		switch {
+		case resp.StatusCode == http.StatusUnauthorized && attempt == 0:
+			token, err := r.fetchToken(resp.Header.Get("WWW-Authenticate"))
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to authenticate to %s: %w", name.registry, err)
+			}
+			r.tokens[tokenKey] = token
+		case resp.StatusCode == http.StatusNotFound:
+			return resp, nil, fmt.Errorf("%s: %w", requestUrl, errManifestNotFound)
+		case resp.StatusCode != http.StatusOK:
+			return resp, nil, fmt.Errorf("failed to get %s: %s", requestUrl, resp.Status)
+		default:
+			return resp, body, nil
+		}
+	}
+}
+
+var challengeParamRegexp = regexp.MustCompile(`(\w+)="([^"]*)"`)
+
+// fetchToken gets an anonymous token following the bearer challenge of the registry
+func (r *registryClient) fetchToken(challenge string) (string, error) {
+	if !strings.HasPrefix(strings.ToLower(challenge), "bearer ") {
+		return "", fmt.Errorf("unsupported authentication challenge %q", challenge)
+	}
+
+	params := map[string]string{}
+	for _, match := range challengeParamRegexp.FindAllStringSubmatch(challenge, -1) {
+		params[strings.ToLower(match[1])] = match[2]
+	}
+	realm, err := url.Parse(params["realm"])
+	if err != nil || params["realm"] == "" {
+		return "", fmt.Errorf("invalid realm in authentication challenge %q", challenge)
+	}
+	query := realm.Query()
+	for _, key := range []string{"service", "scope"} {
+		if params[key] != "" {
+			query.Set(key, params[key])
+		}
+	}
+	realm.RawQuery = query.Encode()
+
+	resp, err := r.client.Get(realm.String())
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("token request failed: %s", resp.Status)
+	}
+
+	tokenResponse := struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}{}
+	if err := json.NewDecoder(resp.Body).Decode(&tokenResponse); err != nil {
+		return "", err
+	}
+	if tokenResponse.Token != "" {
+		return tokenResponse.Token, nil
+	}
+	return tokenResponse.AccessToken, nil
+}
@@ -0,0 +1,256 @@
+package main
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// fakeRegistry serves manifests and blobs of a single repository, requiring a bearer token
+type fakeRegistry struct {
+	server    *httptest.Server
+	manifests map[string][]byte
+	mediaType map[string]string
+	blobs     map[digest.Digest][]byte
+}
+
+const fakeRegistryToken = "fake-token"
+
+func newFakeRegistry() *fakeRegistry {
+	registry := &fakeRegistry{
+		manifests: map[string][]byte{},
+		mediaType: map[string]string{},
+		blobs:     map[digest.Digest][]byte{},
+	}
+	registry.server = httptest.NewTLSServer(http.HandlerFunc(registry.serve))
+	return registry
+}
+
+func (f *fakeRegistry) host() string {
+	return strings.TrimPrefix(f.server.URL, "https://")
+}
+
+func (f *fakeRegistry) serve(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == "/token" {
+		json.NewEncoder(w).Encode(map[string]string{"token": fakeRegistryToken})
+		return
+	}
+	if r.Header.Get("Authorization") != "Bearer "+fakeRegistryToken {
+		w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="%s/token",service="fake",scope="repository:kubevirt/test:pull"`, f.server.URL))
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	const prefix = "/v2/kubevirt/test/"
+	resource := strings.TrimPrefix(r.URL.Path, prefix)
+	switch {
+	case strings.HasPrefix(resource, "manifests/"):
+		reference := strings.TrimPrefix(resource, "manifests/")
+		manifest, found := f.manifests[reference]
+		if !found {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", f.mediaType[reference])
+		w.Header().Set("Docker-Content-Digest", digest.FromBytes(manifest).String())
+		w.Write(manifest)
+	case strings.HasPrefix(resource, "blobs/"):
+		blob, found := f.blobs[digest.Digest(strings.TrimPrefix(resource, "blobs/"))]
+		if !found {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write(blob)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func (f *fakeRegistry) addBlob(content []byte) ocispec.Descriptor {
+	blobDigest := digest.FromBytes(content)
+	f.blobs[blobDigest] = content
+	return ocispec.Descriptor{Digest: blobDigest, Size: int64(len(content))}
+}
+
+func (f *fakeRegistry) addManifest(tag, mediaType string, manifest interface{}) digest.Digest {
+	content, err := json.Marshal(manifest)
+	Expect(err).ToNot(HaveOccurred())
+	f.manifests[tag] = content
+	f.mediaType[tag] = mediaType
+	return digest.FromBytes(content)
+}
+
+// addImage adds a single platform image under the tag
+func (f *fakeRegistry) addImage(tag, os, architecture string) digest.Digest {
+	config, err := json.Marshal(ocispec.Image{OS: os, Architecture: architecture})
+	Expect(err).ToNot(HaveOccurred())
+	return f.addManifest(tag, ocispec.MediaTypeImageManifest, ocispec.Manifest{
+		MediaType: ocispec.MediaTypeImageManifest,
+		Config:    f.addBlob(config),
+	})
+}
+
+// addIndex adds a manifest list of the platforms under the tag
+func (f *fakeRegistry) addIndex(tag string, platforms ...ocispec.Platform) digest.Digest {
+	index := ocispec.Index{MediaType: mediaTypeDockerManifestList}
+	for i := range platforms {
+		index.Manifests = append(index.Manifests, ocispec.Descriptor{
+			MediaType: mediaTypeDockerManifest,
+			Digest:    f.addImage(fmt.Sprintf("%s-%d", tag, i), platforms[i].OS, platforms[i].Architecture),
+			Platform:  &platforms[i],
+		})
+	}
+	return f.addManifest(tag, mediaTypeDockerManifestList, index)
+}
+
+// sign adds a cosign signature of the digest made by the key
+func (f *fakeRegistry) sign(imageDigest digest.Digest, key *ecdsa.PrivateKey) {
+	payload := []byte(fmt.Sprintf(`{"critical":{"identity":{"docker-reference":"kubevirt/test"},"image":{"docker-manifest-digest":"%s"},"type":"cosign container image signature"},"optional":null}`, imageDigest))
+	hash := sha256.Sum256(payload)
+	signature, err := ecdsa.SignASN1(rand.Reader, key, hash[:])
+	Expect(err).ToNot(HaveOccurred())
+
+	layer := f.addBlob(payload)
+	layer.Annotations = map[string]string{cosignSignatureAnnotation: base64.StdEncoding.EncodeToString(signature)}
+	f.addManifest(fmt.Sprintf("sha256-%s.sig", imageDigest.Hex()), ocispec.MediaTypeImageManifest, ocispec.Manifest{
+		MediaType: ocispec.MediaTypeImageManifest,
+		Config:    f.addBlob([]byte("{}")),
+		Layers:    []ocispec.Descriptor{layer},
+	})
+}
+
+var _ = Describe("Testing image resolution", func() {
+	var (
+		registry *fakeRegistry
+		client   *registryClient
+		image    string
+	)
+
+	BeforeEach(func() {
+		registry = newFakeRegistry()
+		client = newRegistryClient(registry.server.Client())
+		image = registry.host() + "/kubevirt/test"
+	})
+
+	AfterEach(func() {
+		registry.server.Close()
+	})
+
+	options := func(platforms string, key crypto.PublicKey, allowMissing bool) *bumpOptions {
+		opts, err := newBumpOptions(platforms, "", allowMissing)
+		Expect(err).ToNot(HaveOccurred())
+		opts.cosignKey = key
+		return opts
+	}
+
+	It("should resolve a tag to the digest of its manifest list", func() {
+		indexDigest := registry.addIndex("v1.0.0",
+			ocispec.Platform{OS: "linux", Architecture: "amd64"},
+			ocispec.Platform{OS: "linux", Architecture: "arm64"},
+		)
+
+		reference, err := resolveImageReference(client, image, "v1.0.0", options("linux/amd64,linux/arm64", nil, false))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(reference).To(Equal(image + "@" + indexDigest.String()))
+	})
+
+	It("should fail when a platform is missing in the manifest list", func() {
+		registry.addIndex("v1.0.0", ocispec.Platform{OS: "linux", Architecture: "amd64"})
+
+		_, err := resolveImageReference(client, image, "v1.0.0", options("linux/amd64,linux/s390x", nil, false))
+		Expect(err).To(MatchError(ContainSubstring("linux/s390x")))
+	})
+
+	It("should check the platform of a single platform image", func() {
+		imageDigest := registry.addImage("v1.0.0", "linux", "amd64")
+
+		reference, err := resolveImageReference(client, image, "v1.0.0", options("linux/amd64", nil, false))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(reference).To(Equal(image + "@" + imageDigest.String()))
+
+		_, err = resolveImageReference(client, image, "v1.0.0", options("linux/arm64", nil, false))
+		Expect(err).To(MatchError(ContainSubstring("linux/arm64")))
+	})
+
+	It("should refer to a missing tag by tag only when allowed", func() {
+		_, err := resolveImageReference(client, image, "v2.0.0", options("linux/amd64", nil, false))
+		Expect(err).To(MatchError(errManifestNotFound))
+
+		reference, err := resolveImageReference(client, image, "v2.0.0", options("linux/amd64", nil, true))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(reference).To(Equal(image + ":v2.0.0"))
+	})
+
+	Context("with a cosign key", func() {
+		var key *ecdsa.PrivateKey
+
+		BeforeEach(func() {
+			var err error
+			key, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should accept an image signed by the key", func() {
+			indexDigest := registry.addIndex("v1.0.0", ocispec.Platform{OS: "linux", Architecture: "amd64"})
+			registry.sign(indexDigest, key)
+
+			reference, err := resolveImageReference(client, image, "v1.0.0", options("linux/amd64", &key.PublicKey, false))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(reference).To(Equal(image + "@" + indexDigest.String()))
+		})
+
+		It("should reject an unsigned image", func() {
+			registry.addIndex("v1.0.0", ocispec.Platform{OS: "linux", Architecture: "amd64"})
+
+			_, err := resolveImageReference(client, image, "v1.0.0", options("linux/amd64", &key.PublicKey, false))
+			Expect(err).To(MatchError(ContainSubstring("no cosign signature found")))
+		})
+
+		It("should reject an image signed by another key", func() {
+			otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+			Expect(err).ToNot(HaveOccurred())
+			indexDigest := registry.addIndex("v1.0.0", ocispec.Platform{OS: "linux", Architecture: "amd64"})
+			registry.sign(indexDigest, otherKey)
+
+			_, err = resolveImageReference(client, image, "v1.0.0", options("linux/amd64", &key.PublicKey, false))
+			Expect(err).To(MatchError(ContainSubstring("invalid signature")))
+		})
+
+		It("should reject a signature of another image", func() {
+			indexDigest := registry.addIndex("v1.0.0", ocispec.Platform{OS: "linux", Architecture: "amd64"})
+			otherDigest := registry.addIndex("v0.9.0", ocispec.Platform{OS: "linux", Architecture: "arm64"})
+			registry.sign(otherDigest, key)
+			registry.manifests[fmt.Sprintf("sha256-%s.sig", indexDigest.Hex())] = registry.manifests[fmt.Sprintf("sha256-%s.sig", otherDigest.Hex())]
+			registry.mediaType[fmt.Sprintf("sha256-%s.sig", indexDigest.Hex())] = ocispec.MediaTypeImageManifest
+
+			_, err := resolveImageReference(client, image, "v1.0.0", options("linux/amd64", &key.PublicKey, false))
+			Expect(err).To(MatchError(ContainSubstring("signs " + otherDigest.String())))
+		})
+	})
+
+	DescribeTable("parsing image names",
+		func(image string, expected imageName) {
+			name, err := parseImageName(image)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(name).To(Equal(expected))
+		},
+		Entry("with a registry", "quay.io/kubevirt/ovs-cni-plugin", imageName{registry: "quay.io", repository: "kubevirt/ovs-cni-plugin"}),
+		Entry("with a registry port", "localhost:5000/kubevirt/ovs-cni-plugin", imageName{registry: "localhost:5000", repository: "kubevirt/ovs-cni-plugin"}),
+		Entry("on docker hub", "kubevirt/ovs-cni-plugin", imageName{registry: "registry-1.docker.io", repository: "kubevirt/ovs-cni-plugin"}),
+		Entry("of a docker hub library image", "busybox", imageName{registry: "registry-1.docker.io", repository: "library/busybox"}),
+	)
+})
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// imageReferenceFile is a go source file referring to images by string literals
+type imageReferenceFile struct {
+	path string
+	// constantsOnly limits the rewrite to *ImageDefault constants, and requires exactly one of them
+	// to refer to the image
+	constantsOnly bool
+}
+
+// rewriteImageReferences replaces all references of the image in files with newReference. New
+// contents of all files are computed before any file is written, so either all files are updated
+// or none.
+func rewriteImageReferences(image, newReference string, files []imageReferenceFile) error {
+	updated := make([][]byte, len(files))
+	for i, file := range files {
+		content, err := os.ReadFile(file.path)
+		if err != nil {
+			return err
+		}
+		updated[i], err = replaceImageReferences(file, content, image, newReference)
+		if err != nil {
+			return err
+		}
+	}
+
+	for i, file := range files {
+		if err := os.WriteFile(file.path, updated[i], 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func replaceImageReferences(file imageReferenceFile, content []byte, image, newReference string) ([]byte, error) {
+	fileSet := token.NewFileSet()
+	parsed, err := parser.ParseFile(fileSet, file.path, content, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+
+	var literals []*ast.BasicLit
+	if file.constantsOnly {
+		literals = imageDefaultLiterals(parsed, image)
+		if len(literals) != 1 {
+			return nil, fmt.Errorf("expected a single *ImageDefault constant referring to %s in %s, found %d", image, file.path, len(literals))
+		}
+	} else {
+		ast.Inspect(parsed, func(node ast.Node) bool {
+			if literal, ok := node.(*ast.BasicLit); ok && refersToImage(literal, image) {
+				literals = append(literals, literal)
+			}
+			return true
+		})
+	}
+
+	// Replace from the end of the file so offsets of the remaining literals stay valid
+	sort.Slice(literals, func(i, j int) bool { return literals[i].Pos() > literals[j].Pos() })
+	replaced := append([]byte{}, content...)
+	for _, literal := range literals {
+		start := fileSet.Position(literal.Pos()).Offset
+		end := fileSet.Position(literal.End()).Offset
+		replaced = append(replaced[:start], append([]byte(strconv.Quote(newReference)), replaced[end:]...)...)
+	}
+
+	return format.Source(replaced)
+}
+
+// imageDefaultLiterals returns values of *ImageDefault constants referring to the image
+func imageDefaultLiterals(file *ast.File, image string) []*ast.BasicLit {
+	var literals []*ast.BasicLit
+	for _, decl := range file.Decls {
+		genDecl, ok := decl.(*ast.GenDecl)
+		if !ok || genDecl.Tok != token.CONST {
+			continue
+		}
+		for _, spec := range genDecl.Specs {
+			valueSpec := spec.(*ast.ValueSpec)
+			for i, name := range valueSpec.Names {
+				if !strings.HasSuffix(name.Name, "ImageDefault") || i >= len(valueSpec.Values) {
+					continue
+				}
+				if literal, ok := valueSpec.Values[i].(*ast.BasicLit); ok && refersToImage(literal, image) {
+					literals = append(literals, literal)
+				}
+			}
+		}
+	}
+	return literals
+}
+
+// refersToImage checks whether the literal is the image, optionally followed by a tag or digest
+func refersToImage(literal *ast.BasicLit, image string) bool {
+	if literal.Kind != token.STRING {
+		return false
+	}
+	value, err := strconv.Unquote(literal.Value)
+	if err != nil {
+		return false
+	}
+	return value == image || strings.HasPrefix(value, image+"@") || strings.HasPrefix(value, image+":")
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+const componentsSource = `package components
+
+const (
+	MultusImageDefault = "ghcr.io/k8snetworkplumbingwg/multus-cni@sha256:829c27e9392d013eee5086ca7670d7326d723ebaec526237215e86086b5a3234"
+	OvsCniImageDefault = "quay.io/kubevirt/ovs-cni-plugin@sha256:3654b80dd5e459c3e73dd027d732620ed8b488b8a15dfe7922457d16c7e834c3"
+)
+`
+
+const releaseSource = `package releases
+
+func init() {
+	release := Release{
+		Containers: []cnao.Container{
+			{
+				Name:  "kube-multus",
+				Image: "ghcr.io/k8snetworkplumbingwg/multus-cni@sha256:829c27e9392d013eee5086ca7670d7326d723ebaec526237215e86086b5a3234",
+			},
+			{
+				Name:  "install-multus-binary",
+				Image: "ghcr.io/k8snetworkplumbingwg/multus-cni:v3.8",
+			},
+			{
+				Name:  "ovs-cni-plugin",
+				Image: "quay.io/kubevirt/ovs-cni-plugin@sha256:3654b80dd5e459c3e73dd027d732620ed8b488b8a15dfe7922457d16c7e834c3",
+			},
+		},
+	}
+	releases = append(releases, release)
+}
+`
+
+var _ = Describe("Testing rewrite of image references", func() {
+	const multus = "ghcr.io/k8snetworkplumbingwg/multus-cni"
+	const newMultus = multus + "@sha256:0000000000000000000000000000000000000000000000000000000000000000"
+
+	var (
+		tempDir string
+		files   []imageReferenceFile
+	)
+
+	BeforeEach(func() {
+		var err error
+		tempDir, err = ioutil.TempDir("", "image-bumper-test")
+		Expect(err).ToNot(HaveOccurred())
+
+		files = []imageReferenceFile{
+			{path: filepath.Join(tempDir, "components.go"), constantsOnly: true},
+			{path: filepath.Join(tempDir, "99.0.0.go")},
+		}
+		Expect(os.WriteFile(files[0].path, []byte(componentsSource), 0644)).To(Succeed())
+		Expect(os.WriteFile(files[1].path, []byte(releaseSource), 0644)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(tempDir)
+	})
+
+	readFile := func(path string) string {
+		content, err := os.ReadFile(path)
+		Expect(err).ToNot(HaveOccurred())
+		return string(content)
+	}
+
+	It("should update the default image and all containers of the release", func() {
+		Expect(rewriteImageReferences(multus, newMultus, files)).To(Succeed())
+
+		components := readFile(files[0].path)
+		Expect(components).To(ContainSubstring(`MultusImageDefault = "` + newMultus + `"`))
+		Expect(components).To(ContainSubstring(`OvsCniImageDefault = "quay.io/kubevirt/ovs-cni-plugin@sha256:3654b8`))
+
+		release := readFile(files[1].path)
+		Expect(release).ToNot(ContainSubstring(multus + ":v3.8"))
+		Expect(release).ToNot(ContainSubstring("829c27e9"))
+		Expect(release).To(ContainSubstring("quay.io/kubevirt/ovs-cni-plugin@sha256:3654b8"))
+	})
+
+	It("should not update any file when the default image is not found", func() {
+		const missing = "quay.io/kubevirt/missing"
+		Expect(os.WriteFile(files[1].path, []byte(releaseSource+"\nvar image = \""+missing+":v1\"\n"), 0644)).To(Succeed())
+		releaseBefore := readFile(files[1].path)
+
+		err := rewriteImageReferences(missing, missing+":v2", files)
+		Expect(err).To(MatchError(ContainSubstring("expected a single *ImageDefault constant")))
+		Expect(readFile(files[0].path)).To(Equal(componentsSource))
+		Expect(readFile(files[1].path)).To(Equal(releaseBefore))
+	})
+
+	It("should not match images sharing a prefix", func() {
+		Expect(rewriteImageReferences("quay.io/kubevirt/ovs-cni", "quay.io/kubevirt/ovs-cni:v1", files[1:])).To(Succeed())
+		Expect(readFile(files[1].path)).To(Equal(releaseSource))
+	})
+})
@@ -31,9 +31,14 @@ import (
 
 	"github.com/ghodss/yaml"
 	"github.com/spf13/pflag"
+	rbacv1 "k8s.io/api/rbac/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 
+	cnao "github.com/kubevirt/cluster-network-addons-operator/pkg/apis/networkaddonsoperator/shared"
+	"github.com/kubevirt/cluster-network-addons-operator/pkg/certificates"
 	components "github.com/kubevirt/cluster-network-addons-operator/pkg/components"
+	"github.com/kubevirt/cluster-network-addons-operator/pkg/names"
+	"github.com/kubevirt/cluster-network-addons-operator/pkg/network"
 	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 )
 
@@ -60,8 +65,22 @@ type templateData struct {
 	ImageName       string
 	ContainerTag    string
 	ImagePullPolicy string
-	CNA             *operatorData
-	AddonsImages    *components.AddonsImages
+	DataDir         string
+	// ComponentNamespaces lists namespaces besides Namespace which components may be deployed to
+	ComponentNamespaces []string
+	CNA                 *operatorData
+	AddonsImages        *components.AddonsImages
+}
+
+// splitList splits a comma separated list, empty items are skipped
+func splitList(list string) []string {
+	items := []string{}
+	for _, item := range strings.Split(list, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
 }
 
 func check(err error) {
@@ -149,6 +168,7 @@ func getCNA(data *templateData) {
 		data.ContainerTag,
 		data.ImagePullPolicy,
 		data.AddonsImages,
+		data.ComponentNamespaces,
 	)
 	err := marshallObject(cnadeployment, &writer)
 	check(err)
@@ -177,7 +197,9 @@ func getCNA(data *templateData) {
 
 	// Get CNA ClusterRole
 	writer = strings.Builder{}
-	clusterRole := components.GetClusterRole()
+	manifestRules, err := getManifestRules(data.DataDir, data.Namespace, data.ComponentNamespaces)
+	check(err)
+	clusterRole := components.GetClusterRole(data.Namespace, manifestRules)
 	marshallObject(clusterRole, &writer)
 	clusterRoleString := writer.String()
 
@@ -193,6 +215,7 @@ func getCNA(data *templateData) {
 	// Get CNA CRD
 	writer = strings.Builder{}
 	crd := components.GetCrd()
+	crd.Spec.Conversion = components.GetCrdConversion(data.Namespace, names.CONVERSION_WEBHOOK_PATH, nil)
 	marshallObject(crd, &writer)
 	crdString := writer.String()
 	crdString = addPreserveUnknownFields(crdString)
@@ -225,6 +248,71 @@ func getCNA(data *templateData) {
 	data.CNA = &cnaData
 }
 
+// getManifestRules renders all components with all optional objects, in the operator namespace and in
+// each of componentNamespaces, and computes rules needed to manage them
+func getManifestRules(dataDir, namespace string, componentNamespaces []string) ([]rbacv1.PolicyRule, error) {
+	os.Setenv("OPERAND_NAMESPACE", namespace)
+	os.Setenv("OPERATOR_NAMESPACE", namespace)
+
+	objs := []*unstructured.Unstructured{}
+	for _, componentNamespace := range append([]string{""}, componentNamespaces...) {
+		namespaceObjs, err := renderAllComponents(dataDir, componentNamespace)
+		if err != nil {
+			return nil, err
+		}
+		objs = append(objs, namespaceObjs...)
+	}
+	objs = append(objs, network.SpecialCleanUpObjects()...)
+
+	rules := components.PolicyRulesForObjects(objs)
+	knownCertificateSources, err := network.KubeMacPoolCertificateSources(&cnao.NetworkAddonsConfigSpec{})
+	if err != nil {
+		return nil, err
+	}
+	certificateSources, err := certificates.SourcesForObjects(objs, knownCertificateSources)
+	if err != nil {
+		return nil, err
+	}
+	return append(rules, certificates.PolicyRules(certificateSources)...), nil
+}
+
+// renderAllComponents renders all components into the given namespace, the operand namespace is used
+// if empty, including objects rendered for their removal and clean up of hosts
+func renderAllComponents(dataDir, namespace string) ([]*unstructured.Unstructured, error) {
+	componentNamespace := cnao.ComponentNamespace(namespace)
+	conf := &cnao.NetworkAddonsConfigSpec{
+		Multus:      &cnao.Multus{Namespace: componentNamespace},
+		LinuxBridge: &cnao.LinuxBridge{Namespace: componentNamespace},
+		KubeMacPool: &cnao.KubeMacPool{Namespace: componentNamespace},
+		Ovs:         &cnao.Ovs{Namespace: componentNamespace},
+		MacvtapCni:  &cnao.MacvtapCni{Namespace: componentNamespace},
+	}
+	if err := network.FillDefaults(conf, nil); err != nil {
+		return nil, err
+	}
+	clusterInfo := &network.ClusterInfo{
+		SCCAvailable:         true,
+		MonitoringAvailable:  true,
+		ProxyConfigAvailable: true,
+		Proxy:                &cnao.Proxy{TrustedCA: names.TRUSTED_CA_BUNDLE_CONFIGMAP},
+	}
+
+	objs, err := network.Render(conf, dataDir, nil, clusterInfo)
+	if err != nil {
+		return nil, err
+	}
+	objsToRemove, err := network.RenderObjsToRemove(conf, &cnao.NetworkAddonsConfigSpec{}, nil, dataDir, nil, clusterInfo)
+	if err != nil {
+		return nil, err
+	}
+	objs = append(objs, objsToRemove...)
+	hostCleanupObjs, err := network.RenderHostCleanup(conf, &cnao.NetworkAddonsConfigSpec{}, dataDir, nil, clusterInfo)
+	if err != nil {
+		return nil, err
+	}
+	return append(objs, hostCleanupObjs...), nil
+}
+
 func addPreserveUnknownFields(crdString string) string {
 	// TODO replace this solution with a better one once this issue get resolved:
 	// https://github.com/kubernetes/kubernetes/issues/95702
@@ -249,19 +337,23 @@ func main() {
 	kubeRbacProxyImage := flag.String("kube-rbac-proxy-image", components.KubeRbacProxyImageDefault, "The kube rbac proxy used by CNA")
 	dumpOperatorCRD := flag.Bool("dump-crds", false, "Append operator CRD to bottom of template. Used for csv-generator")
 	inputFile := flag.String("input-file", "", "Not used for csv-generator")
+	dataDir := flag.String("data-dir", "data", "Directory with component manifests, used to compute the operator RBAC")
+	componentNamespaces := flag.String("component-namespaces", "", "Comma separated namespaces, besides the operator namespace, components may be deployed to")
 	pflag.CommandLine.AddGoFlagSet(flag.CommandLine)
 	pflag.CommandLine.ParseErrorsWhitelist.UnknownFlags = true
 	pflag.Parse()
 
 	data := templateData{
-		Version:         *version,
-		VersionReplaces: *versionReplaces,
-		OperatorVersion: *operatorVersion,
-		Namespace:       *namespace,
-		ContainerPrefix: *containerPrefix,
-		ImageName:       *imageName,
-		ContainerTag:    *containerTag,
-		ImagePullPolicy: *imagePullPolicy,
+		Version:             *version,
+		VersionReplaces:     *versionReplaces,
+		OperatorVersion:     *operatorVersion,
+		Namespace:           *namespace,
+		ContainerPrefix:     *containerPrefix,
+		ImageName:           *imageName,
+		ContainerTag:        *containerTag,
+		ImagePullPolicy:     *imagePullPolicy,
+		DataDir:             *dataDir,
+		ComponentNamespaces: splitList(*componentNamespaces),
 		AddonsImages: (&components.AddonsImages{
 			Multus:            *multusImage,
 			LinuxBridgeCni:    *linuxBridgeCniImage,
@@ -125,9 +125,11 @@ func parseTemplateFile() []byte {
 
 	var doc bytes.Buffer
 	test := struct {
-		Namespace string
+		Namespace              string
+		ScrapedNamespacesRegex string
 	}{
-		Namespace: "test",
+		Namespace:              "test",
+		ScrapedNamespacesRegex: "test",
 	}
 
 	err = t.Execute(&doc, test)
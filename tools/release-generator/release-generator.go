@@ -0,0 +1,225 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/blang/semver"
+
+	cnao "github.com/kubevirt/cluster-network-addons-operator/pkg/apis/networkaddonsoperator/shared"
+	"github.com/kubevirt/cluster-network-addons-operator/pkg/components"
+	"github.com/kubevirt/cluster-network-addons-operator/pkg/network"
+)
+
+// upgradableSpec lists components whose upgrade is verified by workflow tests.
+// TODO: add MacvtapCni *once* macvtap upgrade is supported
+func upgradableSpec() *cnao.NetworkAddonsConfigSpec {
+	return &cnao.NetworkAddonsConfigSpec{
+		KubeMacPool: &cnao.KubeMacPool{},
+		LinuxBridge: &cnao.LinuxBridge{},
+		Multus:      &cnao.Multus{},
+		Ovs:         &cnao.Ovs{},
+	}
+}
+
+// operatorImage is rendered in place of the operator image, it is the value of releases.OperatorImage
+const operatorImage = "<operator-image>"
+
+// sharedImages are images not owned by any component, release files refer to them by constant
+var sharedImages = map[string]string{
+	components.KubeRbacProxyImageDefault: "components.KubeRbacProxyImageDefault",
+	operatorImage:                        "OperatorImage",
+}
+
+var releaseTemplate = template.Must(template.New("release").Parse(`package releases
+
+import (
+	cnao "github.com/kubevirt/cluster-network-addons-operator/pkg/apis/networkaddonsoperator/shared"
+{{- if .ImportComponents }}
+	"github.com/kubevirt/cluster-network-addons-operator/pkg/components"
+{{- end }}
+)
+
+func init() {
+	release := Release{
+		Version: {{ printf "%q" .Version }},
+		Containers: []cnao.Container{
+{{- range .Containers }}
+			{
+				ParentName: {{ printf "%q" .ParentName }},
+				ParentKind: {{ printf "%q" .ParentKind }},
+				Name:       {{ printf "%q" .Name }},
+				Image:      {{ .Image }},
+			},
+{{- end }}
+		},
+		SupportedSpec: cnao.NetworkAddonsConfigSpec{
+{{- range .SupportedComponents }}
+			{{ . }}: &cnao.{{ . }}{},
+{{- end }}
+		},
+		Manifests: []string{
+			"network-addons-config.crd.yaml",
+			"operator.yaml",
+		},
+		CrdCleanUp: []string{
+			"network-attachment-definitions.k8s.cni.cncf.io",
+			"networkaddonsconfigs.networkaddonsoperator.network.kubevirt.io",
+		},
+	}
+	releases = append(releases, release)
+}
+`))
+
+type releaseData struct {
+	Version             string
+	Containers          []cnao.Container
+	SupportedComponents []string
+	ImportComponents    bool
+}
+
+func main() {
+	version := flag.String("version", "", "version of the release")
+	dataDir := flag.String("data-dir", "data", "path to the templates of components")
+	outputDir := flag.String("output-dir", "test/releases", "path to the directory release files are written to")
+	flag.Parse()
+
+	if *version == "" {
+		flag.Usage()
+		os.Exit(1)
+	}
+
+	release, err := generateRelease(*version, *dataDir)
+	if err != nil {
+		log.Fatalf("failed to generate release %s: %v", *version, err)
+	}
+
+	output := filepath.Join(*outputDir, *version+".go")
+	if err := os.WriteFile(output, release, 0644); err != nil {
+		log.Fatalf("failed to write release %s: %v", *version, err)
+	}
+	log.Printf("generated %s", output)
+}
+
+// generateRelease renders the upgradable components the way the operator does with default images,
+// and returns the release file listing their containers
+func generateRelease(version, dataDir string) ([]byte, error) {
+	if _, err := semver.Make(version); err != nil {
+		return nil, fmt.Errorf("invalid version %q: %w", version, err)
+	}
+
+	containers, err := renderContainers(version, upgradableSpec(), dataDir)
+	if err != nil {
+		return nil, err
+	}
+
+	data := releaseData{
+		Version:             version,
+		SupportedComponents: supportedComponents(upgradableSpec()),
+	}
+	for _, container := range containers {
+		if constant, found := sharedImages[container.Image]; found {
+			container.Image = constant
+			data.ImportComponents = data.ImportComponents || strings.HasPrefix(constant, "components.")
+		} else {
+			container.Image = strconv.Quote(container.Image)
+		}
+		data.Containers = append(data.Containers, container)
+	}
+
+	source := bytes.Buffer{}
+	if err := releaseTemplate.Execute(&source, data); err != nil {
+		return nil, err
+	}
+	return format.Source(source.Bytes())
+}
+
+// renderContainers lists containers of the components as the operator reports them in the status
+func renderContainers(version string, conf *cnao.NetworkAddonsConfigSpec, dataDir string) ([]cnao.Container, error) {
+	restoreEnv := setOperatorEnv(version)
+	defer restoreEnv()
+
+	if err := network.FillDefaults(conf, nil); err != nil {
+		return nil, err
+	}
+	objs, err := network.Render(conf, dataDir, nil, &network.ClusterInfo{})
+	if err != nil {
+		return nil, err
+	}
+
+	containers := []cnao.Container{}
+	for _, obj := range objs {
+		objContainers, err := network.ObjectContainers(obj)
+		if err != nil {
+			return nil, fmt.Errorf("failed to detect images used in %s %q: %w", obj.GetKind(), obj.GetName(), err)
+		}
+		containers = append(containers, objContainers...)
+	}
+	return containers, nil
+}
+
+// setOperatorEnv sets the environment the operator deployment of the version passes to the operator, including
+// default images of components. It returns a function restoring the previous environment.
+func setOperatorEnv(version string) func() {
+	deployment := components.GetDeployment(version, version, components.Namespace, "", "", "", "", (&components.AddonsImages{}).FillDefaults(), nil)
+	env := map[string]string{
+		"OPERATOR_NAMESPACE": components.Namespace,
+		"OPERAND_NAMESPACE":  components.Namespace,
+	}
+	for _, envVar := range deployment.Spec.Template.Spec.Containers[0].Env {
+		if envVar.ValueFrom == nil {
+			env[envVar.Name] = envVar.Value
+		}
+	}
+	// The operator image depends on where the release is published, release files refer to it by constant
+	env["OPERATOR_IMAGE"] = operatorImage
+
+	previous := map[string]*string{}
+	for name, value := range env {
+		if previousValue, found := os.LookupEnv(name); found {
+			previous[name] = &previousValue
+		} else {
+			previous[name] = nil
+		}
+		os.Setenv(name, value)
+	}
+
+	return func() {
+		for name, value := range previous {
+			if value != nil {
+				os.Setenv(name, *value)
+			} else {
+				os.Unsetenv(name)
+			}
+		}
+	}
+}
+
+// supportedComponents lists names of components enabled in the spec, in the order release files use
+func supportedComponents(conf *cnao.NetworkAddonsConfigSpec) []string {
+	names := []string{}
+	if conf.KubeMacPool != nil {
+		names = append(names, "KubeMacPool")
+	}
+	if conf.LinuxBridge != nil {
+		names = append(names, "LinuxBridge")
+	}
+	if conf.MacvtapCni != nil {
+		names = append(names, "MacvtapCni")
+	}
+	if conf.Multus != nil {
+		names = append(names, "Multus")
+	}
+	if conf.Ovs != nil {
+		names = append(names, "Ovs")
+	}
+	return names
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"os"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Testing release generation", func() {
+	const dataDir = "../../data"
+
+	It("should keep the release under development up to date, run make gen-release VERSION=99.0.0 otherwise", func() {
+		release, err := generateRelease("99.0.0", dataDir)
+		Expect(err).ToNot(HaveOccurred())
+
+		current, err := os.ReadFile("../../test/releases/99.0.0.go")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(release)).To(Equal(string(current)))
+	})
+
+	It("should list containers of all upgradable components", func() {
+		containers, err := renderContainers("1.0.0", upgradableSpec(), dataDir)
+		Expect(err).ToNot(HaveOccurred())
+
+		parents := map[string]bool{}
+		for _, container := range containers {
+			Expect(container.Image).ToNot(BeEmpty(), "container %s of %s should have an image", container.Name, container.ParentName)
+			parents[container.ParentName] = true
+		}
+		Expect(parents).To(HaveKey("multus"))
+		Expect(parents).To(HaveKey("bridge-marker"))
+		Expect(parents).To(HaveKey("kubemacpool-mac-controller-manager"))
+		Expect(parents).To(HaveKey("ovs-cni-amd64"))
+		Expect(parents).ToNot(HaveKey("macvtap-cni"))
+	})
+
+	It("should restore the environment after rendering", func() {
+		Expect(os.Setenv("MULTUS_IMAGE", "test-image")).To(Succeed())
+		DeferCleanup(os.Unsetenv, "MULTUS_IMAGE")
+
+		_, err := renderContainers("1.0.0", upgradableSpec(), dataDir)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(os.Getenv("MULTUS_IMAGE")).To(Equal("test-image"))
+	})
+
+	It("should reject an invalid version", func() {
+		_, err := generateRelease("v1.0", dataDir)
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,93 @@
+// release-renderer prints objects the operator renders for a spec. hack/render-release.sh builds it
+// in the tree of a previous release, so the objects are rendered by the code and templates of that
+// release. It has to use only API available in all releases the upgrade may start from.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"sigs.k8s.io/yaml"
+
+	cnao "github.com/kubevirt/cluster-network-addons-operator/pkg/apis/networkaddonsoperator/shared"
+	"github.com/kubevirt/cluster-network-addons-operator/pkg/components"
+	"github.com/kubevirt/cluster-network-addons-operator/pkg/network"
+)
+
+func main() {
+	dataDir := flag.String("data-dir", "data", "path to the templates of components")
+	specPath := flag.String("spec", "", "path to the NetworkAddonsConfig spec, all upgradable components by default")
+	openshift := flag.Bool("openshift", false, "render objects as for an OpenShift cluster")
+	operatorImage := flag.String("operator-image", "quay.io/kubevirt/cluster-network-addons-operator:latest", "image of the operator, components may run its binaries")
+	flag.Parse()
+
+	os.Setenv("OPERAND_NAMESPACE", components.Namespace)
+	os.Setenv("OPERATOR_NAMESPACE", components.Namespace)
+	setImages(*operatorImage)
+
+	spec, err := readSpec(*specPath)
+	if err != nil {
+		log.Fatalf("failed to read spec: %v", err)
+	}
+
+	clusterInfo := &network.ClusterInfo{}
+	if *openshift {
+		clusterInfo = &network.ClusterInfo{SCCAvailable: true, OpenShift4: true, MonitoringAvailable: true}
+	}
+
+	if err := network.FillDefaults(spec, nil); err != nil {
+		log.Fatalf("failed to fill defaults: %v", err)
+	}
+	objs, err := network.Render(spec, *dataDir, nil, clusterInfo)
+	if err != nil {
+		log.Fatalf("failed to render objects: %v", err)
+	}
+
+	for _, obj := range objs {
+		content, err := yaml.Marshal(obj.Object)
+		if err != nil {
+			log.Fatalf("failed to marshal %s %s: %v", obj.GetKind(), obj.GetName(), err)
+		}
+		fmt.Printf("---\n%s", content)
+	}
+}
+
+// setImages exposes images of components the way the operator Deployment does, using the defaults of
+// the release
+func setImages(operatorImage string) {
+	images := (&components.AddonsImages{}).FillDefaults()
+	os.Setenv("MULTUS_IMAGE", images.Multus)
+	os.Setenv("LINUX_BRIDGE_IMAGE", images.LinuxBridgeCni)
+	os.Setenv("LINUX_BRIDGE_MARKER_IMAGE", images.LinuxBridgeMarker)
+	os.Setenv("OVS_CNI_IMAGE", images.OvsCni)
+	os.Setenv("KUBEMACPOOL_IMAGE", images.KubeMacPool)
+	os.Setenv("MACVTAP_CNI_IMAGE", images.MacvtapCni)
+	os.Setenv("KUBE_RBAC_PROXY_IMAGE", images.KubeRbacProxy)
+	os.Setenv("OPERATOR_IMAGE", operatorImage)
+}
+
+// readSpec reads a NetworkAddonsConfig spec from a YAML file, or returns the spec of all upgradable
+// components if path is empty. Fields unknown to the release are ignored, the spec may be written for
+// a later one.
+func readSpec(path string) (*cnao.NetworkAddonsConfigSpec, error) {
+	if path == "" {
+		return &cnao.NetworkAddonsConfigSpec{
+			KubeMacPool: &cnao.KubeMacPool{},
+			LinuxBridge: &cnao.LinuxBridge{},
+			Multus:      &cnao.Multus{},
+			Ovs:         &cnao.Ovs{},
+		}, nil
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	spec := &cnao.NetworkAddonsConfigSpec{}
+	if err := yaml.Unmarshal(content, spec); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return spec, nil
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"sigs.k8s.io/yaml"
+
+	cnao "github.com/kubevirt/cluster-network-addons-operator/pkg/apis/networkaddonsoperator/shared"
+	"github.com/kubevirt/cluster-network-addons-operator/pkg/components"
+	"github.com/kubevirt/cluster-network-addons-operator/pkg/network"
+)
+
+func main() {
+	fromObjects := flag.String("from-objects", "", "path to objects of the previous release, rendered by hack/render-release.sh")
+	toDataDir := flag.String("to-data-dir", "data", "path to the templates of components of the next release")
+	fromSpecPath := flag.String("from-spec", "", "path to the NetworkAddonsConfig spec used with the previous release, -spec by default")
+	specPath := flag.String("spec", "", "path to the NetworkAddonsConfig spec used with the next release, all upgradable components by default")
+	openshift := flag.Bool("openshift", false, "render objects as for an OpenShift cluster")
+	flag.Parse()
+
+	if *fromObjects == "" {
+		flag.Usage()
+		os.Exit(1)
+	}
+	if *fromSpecPath == "" {
+		*fromSpecPath = *specPath
+	}
+
+	os.Setenv("OPERAND_NAMESPACE", components.Namespace)
+	os.Setenv("OPERATOR_NAMESPACE", components.Namespace)
+
+	fromSpec, err := readSpec(*fromSpecPath)
+	if err != nil {
+		log.Fatalf("failed to read spec of the previous release: %v", err)
+	}
+	previousObjs, err := readObjects(*fromObjects)
+	if err != nil {
+		log.Fatalf("failed to read objects of the previous release: %v", err)
+	}
+	toSpec, err := readSpec(*specPath)
+	if err != nil {
+		log.Fatalf("failed to read spec of the next release: %v", err)
+	}
+
+	clusterInfo := &network.ClusterInfo{}
+	if *openshift {
+		clusterInfo = &network.ClusterInfo{SCCAvailable: true, OpenShift4: true, MonitoringAvailable: true, ProxyConfigAvailable: true}
+	}
+
+	report, err := verifyUpgrade(previousRelease{objs: previousObjs, spec: fromSpec}, release{dataDir: *toDataDir, spec: toSpec}, clusterInfo)
+	if err != nil {
+		log.Fatalf("failed to verify upgrade: %v", err)
+	}
+
+	fmt.Print(report)
+	if report.failed() {
+		os.Exit(1)
+	}
+}
+
+// readSpec reads a NetworkAddonsConfig spec from a YAML file, or returns the spec of all upgradable
+// components if path is empty
+func readSpec(path string) (*cnao.NetworkAddonsConfigSpec, error) {
+	if path == "" {
+		return &cnao.NetworkAddonsConfigSpec{
+			KubeMacPool: &cnao.KubeMacPool{},
+			LinuxBridge: &cnao.LinuxBridge{},
+			Multus:      &cnao.Multus{},
+			Ovs:         &cnao.Ovs{},
+		}, nil
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	spec := &cnao.NetworkAddonsConfigSpec{}
+	if err := yaml.UnmarshalStrict(content, spec); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return spec, nil
+}
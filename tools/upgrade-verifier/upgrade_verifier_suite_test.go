@@ -0,0 +1,21 @@
+package main
+
+import (
+	"os"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/kubevirt/cluster-network-addons-operator/pkg/components"
+)
+
+func TestUpgradeVerifier(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "upgrade-verifier Suite")
+}
+
+var _ = BeforeSuite(func() {
+	os.Setenv("OPERAND_NAMESPACE", components.Namespace)
+	os.Setenv("OPERATOR_NAMESPACE", components.Namespace)
+})
@@ -0,0 +1,207 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/yaml"
+
+	cnao "github.com/kubevirt/cluster-network-addons-operator/pkg/apis/networkaddonsoperator/shared"
+	"github.com/kubevirt/cluster-network-addons-operator/pkg/network"
+)
+
+// release is the version of the operator in the working tree, represented by its component templates
+// and the spec it runs
+type release struct {
+	dataDir string
+	spec    *cnao.NetworkAddonsConfigSpec
+}
+
+// previousRelease is the version of the operator upgraded from, represented by objects it rendered with
+// its own code and templates, and the spec it ran
+type previousRelease struct {
+	objs []*unstructured.Unstructured
+	spec *cnao.NetworkAddonsConfigSpec
+}
+
+// upgradeReport describes what the operator of the next release does to objects of the previous one
+type upgradeReport struct {
+	// Deleted are objects of the previous release removed by the operator
+	Deleted []string
+	// Leftovers are objects of the previous release neither updated nor removed by the operator
+	Leftovers []string
+	// ImmutableChanges are updates rejected by the API server, the operator would fail to reconcile them
+	ImmutableChanges []string
+}
+
+func (r *upgradeReport) failed() bool {
+	return len(r.Leftovers) > 0 || len(r.ImmutableChanges) > 0
+}
+
+func (r *upgradeReport) String() string {
+	builder := strings.Builder{}
+	sections := []struct {
+		title string
+		items []string
+	}{
+		{"Deleted objects", r.Deleted},
+		{"Leftover objects", r.Leftovers},
+		{"Changes of immutable fields", r.ImmutableChanges},
+	}
+	for _, section := range sections {
+		fmt.Fprintf(&builder, "%s: %d\n", section.title, len(section.items))
+		for _, item := range section.items {
+			fmt.Fprintf(&builder, "  %s\n", item)
+		}
+	}
+	return builder.String()
+}
+
+// immutableFields lists fields the API server refuses to update, per kind
+var immutableFields = map[string][][]string{
+	"DaemonSet":          {{"spec", "selector"}},
+	"Deployment":         {{"spec", "selector"}},
+	"StatefulSet":        {{"spec", "selector"}, {"spec", "serviceName"}, {"spec", "volumeClaimTemplates"}},
+	"Job":                {{"spec", "selector"}, {"spec", "template"}},
+	"Service":            {{"spec", "clusterIP"}},
+	"RoleBinding":        {{"roleRef"}},
+	"ClusterRoleBinding": {{"roleRef"}},
+}
+
+// verifyUpgrade renders objects of the next release and checks how its operator handles objects of the
+// previous one, the same way it does when reconciling after an upgrade
+func verifyUpgrade(previous previousRelease, next release, clusterInfo *network.ClusterInfo) (*upgradeReport, error) {
+	if err := network.FillDefaults(previous.spec, nil); err != nil {
+		return nil, fmt.Errorf("failed to fill defaults of the previous spec: %w", err)
+	}
+	nextObjs, err := renderRelease(next, clusterInfo)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render the next release: %w", err)
+	}
+	objsToRemove, err := network.RenderObjsToRemove(previous.spec, next.spec, nextObjs, next.dataDir, nil, clusterInfo)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render objects to remove: %w", err)
+	}
+
+	nextByKey := objectsByKey(nextObjs)
+	removedByKey := objectsByKey(append(objsToRemove, network.SpecialCleanUpObjects()...))
+
+	report := &upgradeReport{}
+	// Components may render the same object, e.g. a shared namespace
+	for key, previousObj := range objectsByKey(previous.objs) {
+		if nextObj, found := nextByKey[key]; found {
+			report.ImmutableChanges = append(report.ImmutableChanges, immutableChanges(key, previousObj, nextObj)...)
+		} else if _, found := removedByKey[key]; found {
+			report.Deleted = append(report.Deleted, key)
+		} else {
+			report.Leftovers = append(report.Leftovers, key)
+		}
+	}
+
+	sort.Strings(report.Deleted)
+	sort.Strings(report.Leftovers)
+	sort.Strings(report.ImmutableChanges)
+	return report, nil
+}
+
+// readObjects reads objects rendered by hack/render-release.sh
+func readObjects(path string) ([]*unstructured.Unstructured, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	objs := []*unstructured.Unstructured{}
+	decoder := yaml.NewYAMLOrJSONDecoder(file, 4096)
+	for {
+		obj := map[string]interface{}{}
+		if err := decoder.Decode(&obj); err != nil {
+			if err == io.EOF {
+				break
+			}
+			return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+		// Documents holding only comments are decoded as empty objects
+		if len(obj) > 0 {
+			objs = append(objs, &unstructured.Unstructured{Object: obj})
+		}
+	}
+	return objs, nil
+}
+
+func renderRelease(r release, clusterInfo *network.ClusterInfo) ([]*unstructured.Unstructured, error) {
+	if err := network.FillDefaults(r.spec, nil); err != nil {
+		return nil, err
+	}
+	return network.Render(r.spec, r.dataDir, nil, clusterInfo)
+}
+
+// objectKey identifies an object regardless of its API version, objects moved to another version of
+// their API group are updated in place
+func objectKey(obj *unstructured.Unstructured) string {
+	if obj.GetNamespace() == "" {
+		return fmt.Sprintf("%s %s", obj.GetKind(), obj.GetName())
+	}
+	return fmt.Sprintf("%s %s/%s", obj.GetKind(), obj.GetNamespace(), obj.GetName())
+}
+
+func objectsByKey(objs []*unstructured.Unstructured) map[string]*unstructured.Unstructured {
+	byKey := map[string]*unstructured.Unstructured{}
+	for _, obj := range objs {
+		byKey[objectKey(obj)] = obj
+	}
+	return byKey
+}
+
+func immutableChanges(key string, previousObj, nextObj *unstructured.Unstructured) []string {
+	changes := []string{}
+	for _, path := range immutableFields[previousObj.GetKind()] {
+		previousValue, previousFound, _ := unstructured.NestedFieldNoCopy(previousObj.Object, path...)
+		nextValue, nextFound, _ := unstructured.NestedFieldNoCopy(nextObj.Object, path...)
+		// A field set by one release only is changed as well, unless the API server defaults it when unset
+		if !previousFound || !nextFound {
+			if previousFound != nextFound && !isDefaultedField(path) {
+				changes = append(changes, fmt.Sprintf("%s: %s", key, strings.Join(path, ".")))
+			}
+			continue
+		}
+		if !reflect.DeepEqual(previousValue, nextValue) {
+			change := fmt.Sprintf("%s: %s", key, strings.Join(path, "."))
+			if isRecreatedByApply(nextObj, path) {
+				change += " (recreated by apply.ApplyObject)"
+			}
+			changes = append(changes, change)
+		}
+	}
+
+	if immutable, _, _ := unstructured.NestedBool(previousObj.Object, "immutable"); immutable {
+		for _, field := range []string{"data", "binaryData", "stringData"} {
+			if !reflect.DeepEqual(previousObj.Object[field], nextObj.Object[field]) {
+				changes = append(changes, fmt.Sprintf("%s: %s", key, field))
+			}
+		}
+	}
+	return changes
+}
+
+// isRecreatedByApply checks whether apply.ApplyObject deletes and creates the object again when the
+// API server refuses to update the field. It does so only for the bridge-marker DaemonSet selector
+// changed by the move of DaemonSets to apps/v1, and it still fails the reconcile doing so.
+func isRecreatedByApply(nextObj *unstructured.Unstructured, path []string) bool {
+	if nextObj.GetKind() != "DaemonSet" || nextObj.GetName() != "bridge-marker" || strings.Join(path, ".") != "spec.selector" {
+		return false
+	}
+	selector, _, _ := unstructured.NestedFieldNoCopy(nextObj.Object, path...)
+	return reflect.DeepEqual(selector, map[string]interface{}{"matchLabels": map[string]interface{}{"name": "bridge-marker"}})
+}
+
+// isDefaultedField checks whether the API server fills the field when it is not set
+func isDefaultedField(path []string) bool {
+	return strings.Join(path, ".") == "spec.clusterIP"
+}
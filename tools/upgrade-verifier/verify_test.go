@@ -0,0 +1,219 @@
+package main
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	cnao "github.com/kubevirt/cluster-network-addons-operator/pkg/apis/networkaddonsoperator/shared"
+	"github.com/kubevirt/cluster-network-addons-operator/pkg/apply"
+	"github.com/kubevirt/cluster-network-addons-operator/pkg/network"
+)
+
+// copyDataDir copies templates of components, so tests can modify them
+func copyDataDir(source, destination string) {
+	err := filepath.Walk(source, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		relative, err := filepath.Rel(source, path)
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return os.MkdirAll(filepath.Join(destination, relative), 0755)
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(filepath.Join(destination, relative), content, 0644)
+	})
+	Expect(err).ToNot(HaveOccurred())
+}
+
+func replaceInTemplate(path, old, new string) {
+	content, err := os.ReadFile(path)
+	Expect(err).ToNot(HaveOccurred())
+	Expect(string(content)).To(ContainSubstring(old))
+	Expect(os.WriteFile(path, []byte(strings.Replace(string(content), old, new, 1)), 0644)).To(Succeed())
+}
+
+// renderPrevious renders objects of the previous release from its templates, as hack/render-release.sh
+// does in the tree of the release
+func renderPrevious(dataDir string, spec *cnao.NetworkAddonsConfigSpec) previousRelease {
+	objs, err := renderRelease(release{dataDir: dataDir, spec: spec}, &network.ClusterInfo{})
+	Expect(err).ToNot(HaveOccurred())
+	return previousRelease{objs: objs, spec: spec}
+}
+
+func upgradableSpec() *cnao.NetworkAddonsConfigSpec {
+	spec, err := readSpec("")
+	Expect(err).ToNot(HaveOccurred())
+	return spec
+}
+
+const leftoverConfigMap = `---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: bridge-marker-config
+  namespace: {{ .Namespace }}
+data:
+  config: ""
+`
+
+var _ = Describe("Testing upgrade verification", func() {
+	var (
+		tempDir         string
+		previousDataDir string
+		next            release
+	)
+
+	BeforeEach(func() {
+		var err error
+		tempDir, err = ioutil.TempDir("", "upgrade-verifier-test")
+		Expect(err).ToNot(HaveOccurred())
+
+		previousDataDir = filepath.Join(tempDir, "previous")
+		next = release{dataDir: filepath.Join(tempDir, "next"), spec: upgradableSpec()}
+		copyDataDir("../../data", previousDataDir)
+		copyDataDir("../../data", next.dataDir)
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(tempDir)
+	})
+
+	It("should pass when nothing changes between releases", func() {
+		report, err := verifyUpgrade(renderPrevious(previousDataDir, upgradableSpec()), next, &network.ClusterInfo{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(report.failed()).To(BeFalse(), report.String())
+		Expect(report.Deleted).To(BeEmpty())
+	})
+
+	It("should report objects dropped from templates as leftovers", func() {
+		Expect(os.WriteFile(filepath.Join(previousDataDir, "linux-bridge", "004-config.yaml"), []byte(leftoverConfigMap), 0644)).To(Succeed())
+
+		report, err := verifyUpgrade(renderPrevious(previousDataDir, upgradableSpec()), next, &network.ClusterInfo{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(report.failed()).To(BeTrue())
+		Expect(report.Leftovers).To(Equal([]string{"ConfigMap cluster-network-addons/bridge-marker-config"}))
+	})
+
+	It("should report a changed Deployment selector", func() {
+		replaceInTemplate(filepath.Join(previousDataDir, "kubemacpool", "kubemacpool.yaml"), "    matchLabels:\n      control-plane: mac-controller-manager\n", "    matchLabels:\n      control-plane: manager\n")
+
+		report, err := verifyUpgrade(renderPrevious(previousDataDir, upgradableSpec()), next, &network.ClusterInfo{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(report.ImmutableChanges).To(Equal([]string{"Deployment cluster-network-addons/kubemacpool-mac-controller-manager: spec.selector"}))
+	})
+
+	It("should report a changed DaemonSet selector", func() {
+		replaceInTemplate(filepath.Join(previousDataDir, "ovs", "001-ovs-cni.yaml"), "      app: ovs-cni\n", "      app: ovs\n")
+
+		report, err := verifyUpgrade(renderPrevious(previousDataDir, upgradableSpec()), next, &network.ClusterInfo{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(report.ImmutableChanges).To(Equal([]string{"DaemonSet cluster-network-addons/ovs-cni-amd64: spec.selector"}))
+	})
+
+	It("should report a changed bridge-marker selector as recreated by apply", func() {
+		replaceInTemplate(filepath.Join(previousDataDir, "linux-bridge", "003-bridge-marker.yaml"), "      name: bridge-marker\n  updateStrategy", "      name: marker\n  updateStrategy")
+
+		report, err := verifyUpgrade(renderPrevious(previousDataDir, upgradableSpec()), next, &network.ClusterInfo{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(report.ImmutableChanges).To(Equal([]string{"DaemonSet cluster-network-addons/bridge-marker: spec.selector (recreated by apply.ApplyObject)"}))
+	})
+
+	It("should report objects of disabled components as deleted", func() {
+		next.spec.Ovs = nil
+
+		report, err := verifyUpgrade(renderPrevious(previousDataDir, upgradableSpec()), next, &network.ClusterInfo{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(report.failed()).To(BeFalse(), report.String())
+		Expect(report.Deleted).To(ContainElement("DaemonSet cluster-network-addons/ovs-cni-amd64"))
+	})
+
+	// Objects of the previous release are rendered by its own code and templates, regenerate them with
+	// make gen-upgrade-testdata after a release
+	DescribeTable("should upgrade objects rendered by the previous release",
+		func(objectsPath string, clusterInfo *network.ClusterInfo) {
+			objs, err := readObjects(objectsPath)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(objs).ToNot(BeEmpty())
+
+			report, err := verifyUpgrade(previousRelease{objs: objs, spec: upgradableSpec()}, release{dataDir: "../../data", spec: upgradableSpec()}, clusterInfo)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(report.failed()).To(BeFalse(), report.String())
+		},
+		Entry("on Kubernetes", "testdata/previous-release-kubernetes.yaml", &network.ClusterInfo{}),
+		Entry("on OpenShift", "testdata/previous-release-openshift.yaml", &network.ClusterInfo{SCCAvailable: true, OpenShift4: true, MonitoringAvailable: true, ProxyConfigAvailable: true}),
+	)
+
+	Context("when applied to a fake API server", func() {
+		var scheme *runtime.Scheme
+
+		BeforeEach(func() {
+			scheme = runtime.NewScheme()
+			Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+			Expect(extv1.AddToScheme(scheme)).To(Succeed())
+		})
+
+		It("should leave exactly the reported leftovers behind", func() {
+			Expect(os.WriteFile(filepath.Join(previousDataDir, "linux-bridge", "004-config.yaml"), []byte(leftoverConfigMap), 0644)).To(Succeed())
+			next.spec.Ovs = nil
+			clusterInfo := &network.ClusterInfo{}
+
+			previous := renderPrevious(previousDataDir, upgradableSpec())
+			report, err := verifyUpgrade(previous, next, clusterInfo)
+			Expect(err).ToNot(HaveOccurred())
+
+			By("Deploying the previous release")
+			client := fake.NewClientBuilder().WithScheme(scheme).Build()
+			for _, obj := range previous.objs {
+				obj.SetOwnerReferences([]metav1.OwnerReference{{APIVersion: "networkaddonsoperator.network.kubevirt.io/v1", Kind: "NetworkAddonsConfig", Name: "cluster", UID: "1"}})
+				Expect(apply.ApplyObject(context.TODO(), client, obj)).To(Succeed())
+			}
+
+			By("Reconciling with the next release")
+			nextObjs, err := renderRelease(next, clusterInfo)
+			Expect(err).ToNot(HaveOccurred())
+			for _, obj := range nextObjs {
+				Expect(apply.ApplyObject(context.TODO(), client, obj)).To(Succeed())
+			}
+			objsToRemove, err := network.RenderObjsToRemove(previous.spec, next.spec, nextObjs, next.dataDir, nil, clusterInfo)
+			Expect(err).ToNot(HaveOccurred())
+			for _, obj := range objsToRemove {
+				Expect(apply.DeleteOwnedObject(context.TODO(), client, obj)).To(Succeed())
+			}
+
+			By("Checking objects of the previous release left in the API server")
+			nextByKey := objectsByKey(nextObjs)
+			leftovers := []string{}
+			for key, obj := range objectsByKey(previous.objs) {
+				if _, found := nextByKey[key]; found {
+					continue
+				}
+				existing := &unstructured.Unstructured{}
+				existing.SetGroupVersionKind(obj.GroupVersionKind())
+				if err := client.Get(context.TODO(), types.NamespacedName{Namespace: obj.GetNamespace(), Name: obj.GetName()}, existing); err == nil {
+					leftovers = append(leftovers, key)
+				}
+			}
+			Expect(leftovers).To(ConsistOf(report.Leftovers))
+		})
+	})
+})